@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sepisoad/robot-challange/all-in-one/stack"
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/simulator/warehouse"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	API_KEYS   = "API_KEYS"
+	JWT_SECRET = "JWT_SECRET"
+)
+
+type allInOneStartOptions struct {
+	dataDir             string
+	natsHost            string
+	natsPort            int
+	port                int
+	eventCodec          string
+	warehouse           string
+	totalRobotNumber    int
+	boardHeight         int
+	boardWidth          int
+	layoutPath          string
+	streamName          string
+	streamRetention     string
+	streamMaxAge        string
+	streamMaxBytes      string
+	streamMaxMsgs       string
+	streamReplicas      string
+	streamStorage       string
+	asyncPublish        bool
+	maxPendingPublishes int
+	coalesceRobotMoves  bool
+	taskAcceptance      string
+	acceptanceTimeout   time.Duration
+	apiKeysFile         string
+	jwtSecretFile       string
+	jwtPublicKeyFile    string
+	insecureNoAuth      bool
+}
+
+func allInOneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "all-in-one",
+		Short: "Run the whole project in a single process",
+		Long:  "Run an embedded NATS server, the API and the simulator in a single process",
+	}
+
+	cmd.AddCommand(
+		allInOneStartCommand(),
+	)
+
+	return cmd
+}
+
+func allInOneStartCommand() *cobra.Command {
+	opt := allInOneStartOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the embedded NATS server, the API and the simulator",
+		Long:  "Start the embedded NATS server, the API and the simulator",
+		Run: func(cmd *cobra.Command, args []string) {
+			logger, err := zap.NewDevelopment()
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			sugarLogger := logger.Sugar()
+
+			if !robotbroker.IsValidToken(opt.warehouse) {
+				sugarLogger.Fatalf("Invalid warehouse name %q", opt.warehouse)
+			}
+
+			layout := warehouse.Layout{
+				Width:  opt.boardWidth,
+				Height: opt.boardHeight,
+			}
+
+			if opt.layoutPath != "" {
+				if layout, err = warehouse.LoadLayout(opt.layoutPath); err != nil {
+					sugarLogger.Fatal(err)
+				}
+			}
+
+			streamConfig, err := robotbroker.NewStreamConfig(
+				opt.streamName,
+				opt.streamRetention,
+				opt.streamMaxAge,
+				opt.streamMaxBytes,
+				opt.streamMaxMsgs,
+				opt.streamReplicas,
+				opt.streamStorage)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			if opt.taskAcceptance != "async" && opt.taskAcceptance != "sync" {
+				sugarLogger.Fatalf("Invalid task acceptance %q, expected async or sync", opt.taskAcceptance)
+			}
+
+			rawApiKeys, err := readSecret(API_KEYS, opt.apiKeysFile)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			apiKeys, err := auth.ParseApiKeys(rawApiKeys)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			jwtSecret, err := readSecret(JWT_SECRET, opt.jwtSecretFile)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			jwtOptions := auth.JwtOptions{
+				Secret: []byte(jwtSecret),
+			}
+
+			if opt.jwtPublicKeyFile != "" {
+				if jwtOptions.PublicKey, err = os.ReadFile(opt.jwtPublicKeyFile); err != nil {
+					sugarLogger.Fatal(err)
+				}
+			}
+
+			robotStack, err := stack.Start(sugarLogger, stack.Options{
+				DataDir:             opt.dataDir,
+				NatsHost:            opt.natsHost,
+				NatsPort:            opt.natsPort,
+				ApiAddress:          fmt.Sprintf(":%d", opt.port),
+				EventCodec:          opt.eventCodec,
+				Warehouse:           opt.warehouse,
+				TotalRobotNumber:    opt.totalRobotNumber,
+				BoardHeight:         layout.Height,
+				BoardWidth:          layout.Width,
+				Obstacles:           layout.Obstacles,
+				SpecialCells:        layout.SpecialCells,
+				StreamConfig:        streamConfig,
+				AsyncPublish:        opt.asyncPublish,
+				MaxPendingPublishes: opt.maxPendingPublishes,
+				CoalesceRobotMoves:  opt.coalesceRobotMoves,
+				Acceptance: robot.AcceptanceOptions{
+					Sync:    opt.taskAcceptance == "sync",
+					Timeout: opt.acceptanceTimeout,
+				},
+				ApiKeys:        apiKeys,
+				Jwt:            jwtOptions,
+				InsecureNoAuth: opt.insecureNoAuth,
+			})
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			defer robotStack.Stop()
+
+			sugarLogger.Infof(
+				"API is listening on %s, NATS is listening on %s",
+				robotStack.ApiAddress(),
+				robotStack.NatsUrl())
+
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+			go func() {
+				<-signals
+				robotStack.Stop()
+			}()
+
+			if err := robotStack.Wait(); err != nil {
+				sugarLogger.Error(err)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&opt.dataDir, "data-dir", "data", "Specify the directory the events are persisted in")
+	cmd.Flags().StringVar(&opt.natsHost, "nats-host", "127.0.0.1", "Specify the host the embedded NATS server listens on")
+	cmd.Flags().IntVar(&opt.natsPort, "nats-port", 4222, "Specify the port the embedded NATS server listens on, -1 picks a random port")
+	cmd.Flags().IntVar(&opt.port, "port", 8080, "Specify the port the API listens on")
+	cmd.Flags().StringVar(&opt.eventCodec, "event-codec", eventcodec.JSON, "Specify the codec events are published with")
+	cmd.Flags().StringVar(&opt.warehouse, "warehouse", robotbroker.DEFAULT_WAREHOUSE, "Specify the warehouse the simulated robots belong to")
+	cmd.Flags().IntVar(&opt.totalRobotNumber, "total-robot-number", 5, "Specify the total number of robots to start simualtion with")
+	cmd.Flags().IntVar(&opt.boardHeight, "board-height", 10, "Specify the board height")
+	cmd.Flags().IntVar(&opt.boardWidth, "board-width", 10, "Specify the board width")
+	cmd.Flags().StringVar(&opt.layoutPath, "layout", "", "Specify a JSON file with the size, obstacles and special cells of the board, it overrides --board-height and --board-width")
+	cmd.Flags().StringVar(&opt.streamName, "stream-name", robotbroker.STREAM_ROBOT, "Specify the name of the stream events are kept in")
+	cmd.Flags().StringVar(&opt.streamRetention, "stream-retention", "limits", "Specify the retention policy of the stream, limits, interest or workqueue")
+	cmd.Flags().StringVar(&opt.streamMaxAge, "stream-max-age", robotbroker.DefaultStreamMaxAge.String(), "Specify how long the stream keeps events, 0 keeps them forever")
+	cmd.Flags().StringVar(&opt.streamMaxBytes, "stream-max-bytes", "-1", "Specify the maximum size of the stream in bytes, -1 is unlimited")
+	cmd.Flags().StringVar(&opt.streamMaxMsgs, "stream-max-msgs", "-1", "Specify the maximum number of events in the stream, -1 is unlimited")
+	cmd.Flags().StringVar(&opt.streamReplicas, "stream-replicas", "1", "Specify the number of replicas of the stream")
+	cmd.Flags().BoolVar(&opt.asyncPublish, "async-publish", false, "Publish the simulator events without waiting for each of them to be persisted")
+	cmd.Flags().IntVar(&opt.maxPendingPublishes, "max-pending-publishes", eventpublisher.DefaultMaxPendingPublishes, "Specify the number of events waiting to be persisted before publishing blocks")
+	cmd.Flags().BoolVar(&opt.coalesceRobotMoves, "coalesce-robot-moves", false, "Only publish the latest position of a robot when NATS falls behind, requires --async-publish")
+	cmd.Flags().StringVar(&opt.taskAcceptance, "task-acceptance", "async", "Specify whether the API waits for the simulator to accept the tasks, async or sync")
+	cmd.Flags().DurationVar(&opt.acceptanceTimeout, "task-acceptance-timeout", robot.DefaultAcceptanceTimeout, "Specify how long the API waits for the simulator to accept a task")
+	cmd.Flags().StringVar(&opt.streamStorage, "stream-storage", "file", "Specify the storage of the stream, file or memory")
+	cmd.Flags().StringVar(&opt.apiKeysFile, "api-keys-file", "", "Specify a file with the API keys as a comma separated list of <name>:<role>:<key>, the role is viewer, operator or admin. API_KEYS is read when it is not given")
+	cmd.Flags().StringVar(&opt.jwtSecretFile, "jwt-secret-file", "", "Specify a file with the secret the JSON web tokens are signed with, JWT_SECRET is read when it is not given")
+	cmd.Flags().StringVar(&opt.jwtPublicKeyFile, "jwt-public-key-file", "", "Specify a PEM file with the public key the JSON web tokens are verified with")
+	cmd.Flags().BoolVar(&opt.insecureNoAuth, "insecure-no-auth", false, "Serve the requests unauthenticated when neither API keys nor a way to verify tokens is given")
+
+	return cmd
+}
+
+// readSecret returns the content of the file at path, or the value of the environment variable
+// env when path is empty. Secrets are not taken as flags, every user of the host can read those
+// in the list of the processes
+func readSecret(env string, path string) (string, error) {
+	if path == "" {
+		return os.Getenv(env), nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
@@ -0,0 +1,7 @@
+/*
+this package defines a set of commands for you to run the whole project in a
+single process. `all-in-one start` starts an embedded NATS server, the api
+server and the simulator
+*/
+
+package commands
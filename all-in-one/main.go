@@ -0,0 +1,16 @@
+package main
+
+import (
+	"log"
+
+	"github.com/sepisoad/robot-challange/all-in-one/commands"
+)
+
+func main() {
+	rootCmd := commands.Root()
+
+	err := rootCmd.Execute()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,7 @@
+/*
+this package starts an embedded NATS server, the api server and the simulator in
+a single process. it is used by the `all-in-one start` command and lets tests
+start the whole project in process
+*/
+
+package stack
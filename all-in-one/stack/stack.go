@@ -0,0 +1,189 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+	"github.com/sepisoad/robot-challange/api/internals/services/idempotency"
+	"github.com/sepisoad/robot-challange/api/server"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/simulator/simulation"
+	"go.uber.org/zap"
+)
+
+// Options configures the stack
+type Options struct {
+	// DataDir is the directory the embedded NATS server persists the streams in
+	DataDir  string
+	NatsHost string
+	// NatsPort is the port of the embedded NATS server, -1 picks a random port
+	NatsPort int
+	// ApiAddress is the address the api listens on, e.g. :8080
+	ApiAddress string
+	// EventCodec is the name of the codec events are published with
+	EventCodec       string
+	Warehouse        string
+	TotalRobotNumber int
+	BoardHeight      int
+	BoardWidth       int
+	// Obstacles and SpecialCells complete the layout of the board, see simulation.Options
+	Obstacles    []eventpublisher.Cell
+	SpecialCells []eventpublisher.SpecialCell
+	// StreamConfig holds the settings of the stream events are kept in
+	StreamConfig robotbroker.StreamConfig
+	// AsyncPublish, MaxPendingPublishes and CoalesceRobotMoves configure how the simulator
+	// publishes its events, see simulation.Options
+	AsyncPublish        bool
+	MaxPendingPublishes int
+	CoalesceRobotMoves  bool
+	// Acceptance configures whether the api waits for the simulator to accept the tasks
+	Acceptance robot.AcceptanceOptions
+	// ApiKeys, Jwt and InsecureNoAuth configure how the api authenticates the requests, see
+	// server.Options
+	ApiKeys        []auth.ApiKey
+	Jwt            auth.JwtOptions
+	InsecureNoAuth bool
+}
+
+// Stack is a running NATS server, api and simulator
+type Stack struct {
+	logger                      *zap.SugaredLogger
+	embeddedNatsService         embeddednats.EmbeddedNatsInterface
+	apiRobotBrokerService       robotbroker.RobotBrokerInterface
+	simulatorRobotBrokerService robotbroker.RobotBrokerInterface
+	apiServer                   *server.Server
+	robotSimulation             *simulation.Simulation
+	stopOnce                    *sync.Once
+}
+
+// Start starts the embedded NATS server, the api and the simulator. The api and the simulator
+// connect to the NATS server the same way they do when they run in their own process
+func Start(logger *zap.SugaredLogger, options Options) (*Stack, error) {
+	stack := &Stack{
+		logger:   logger,
+		stopOnce: &sync.Once{},
+	}
+
+	if err := stack.start(options); err != nil {
+		stack.Stop()
+
+		return nil, err
+	}
+
+	return stack, nil
+}
+
+// NatsUrl returns the url of the embedded NATS server
+func (s *Stack) NatsUrl() string {
+	return s.embeddedNatsService.ClientURL()
+}
+
+// ApiAddress returns the address the api listens on
+func (s *Stack) ApiAddress() string {
+	return s.apiServer.Address()
+}
+
+// Wait blocks until the api stops
+func (s *Stack) Wait() error {
+	return s.apiServer.Wait()
+}
+
+// Stop stops the simulator, the api and the embedded NATS server in this order
+func (s *Stack) Stop() {
+	s.stopOnce.Do(func() {
+		if s.robotSimulation != nil {
+			s.robotSimulation.Stop()
+		}
+
+		if s.apiServer != nil {
+			s.apiServer.Stop()
+		}
+
+		if s.simulatorRobotBrokerService != nil {
+			s.simulatorRobotBrokerService.Close()
+		}
+
+		if s.apiRobotBrokerService != nil {
+			s.apiRobotBrokerService.Close()
+		}
+
+		if s.embeddedNatsService != nil {
+			s.embeddedNatsService.Shutdown()
+		}
+	})
+}
+
+func (s *Stack) start(options Options) (err error) {
+	if err = os.MkdirAll(options.DataDir, 0755); err != nil {
+		return err
+	}
+
+	if s.embeddedNatsService, err = embeddednats.NewEmbeddedNatsService(
+		s.logger,
+		options.DataDir,
+		options.NatsHost,
+		options.NatsPort); err != nil {
+		return err
+	}
+
+	if s.apiRobotBrokerService, err = robotbroker.NewRobotBrokerService(
+		s.logger,
+		"api",
+		s.NatsUrl(),
+		robotbroker.ConnectionConfig{},
+		options.StreamConfig); err != nil {
+		return err
+	}
+
+	if s.simulatorRobotBrokerService, err = robotbroker.NewRobotBrokerService(
+		s.logger,
+		"simulator",
+		s.NatsUrl(),
+		robotbroker.ConnectionConfig{},
+		options.StreamConfig); err != nil {
+		return err
+	}
+
+	if s.apiServer, err = server.Start(
+		s.logger,
+		server.Options{
+			Address:          options.ApiAddress,
+			EventCodec:       options.EventCodec,
+			OutboxPath:       filepath.Join(options.DataDir, "api-outbox.jsonl"),
+			StorePath:        filepath.Join(options.DataDir, "api-store.jsonl"),
+			NodeId:           1,
+			IdempotencyStore: idempotency.StoreNats,
+			Acceptance:       options.Acceptance,
+			ApiKeys:          options.ApiKeys,
+			Jwt:              options.Jwt,
+			InsecureNoAuth:   options.InsecureNoAuth,
+		},
+		s.apiRobotBrokerService); err != nil {
+		return err
+	}
+
+	if s.robotSimulation, err = simulation.Start(
+		s.logger,
+		simulation.Options{
+			Warehouse:           options.Warehouse,
+			TotalRobotNumber:    options.TotalRobotNumber,
+			BoardHeight:         options.BoardHeight,
+			BoardWidth:          options.BoardWidth,
+			Obstacles:           options.Obstacles,
+			SpecialCells:        options.SpecialCells,
+			EventCodec:          options.EventCodec,
+			AsyncPublish:        options.AsyncPublish,
+			MaxPendingPublishes: options.MaxPendingPublishes,
+			CoalesceRobotMoves:  options.CoalesceRobotMoves,
+		},
+		s.simulatorRobotBrokerService); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,417 @@
+package stack_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/all-in-one/stack"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+var moveEast = robotapiserver.MoveRobotRequest{
+	MoveSequences: []robotapiserver.MoveRobotRequestMoveSequences{robotapiserver.MoveRobotRequestMoveSequencesE},
+}
+
+func Test_Start_Should_Run_The_Whole_Project_In_Process(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startStack(t, func(options *stack.Options) {
+		options.TotalRobotNumber = 3
+	})
+
+	var health robotapiserver.Health
+
+	response := send(g, sut.ApiAddress(), http.MethodGet, "/api/health", nil, &health)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusOK))
+	g.Expect(health.Broker.State).Should(Equal(robotapiserver.BrokerHealthStateConnected))
+	g.Expect(health.PublishCircuit).Should(Equal(robotapiserver.HealthPublishCircuitClosed))
+}
+
+func Test_Start_Should_Wait_For_The_Simulator_To_Accept_Tasks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startStack(t, func(options *stack.Options) {
+		options.Acceptance = robot.AcceptanceOptions{
+			Sync: true,
+		}
+	})
+
+	g.Eventually(func() int {
+		return send(g, sut.ApiAddress(), http.MethodPut, "/api/robots/0", moveEast, nil).StatusCode
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal(http.StatusCreated))
+
+	var moveRobotResponse robotapiserver.MoveRobotResponse
+
+	response := send(g, sut.ApiAddress(), http.MethodPut, "/api/robots/0?acceptance=async", moveEast, &moveRobotResponse)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusAccepted))
+
+	// The task leaves the outbox once JetStream has persisted it
+	g.Eventually(func() robotapiserver.TaskStatus {
+		var task robotapiserver.Task
+		send(g, sut.ApiAddress(), http.MethodGet, fmt.Sprintf("/api/tasks/%d", moveRobotResponse.Task.Id), nil, &task)
+
+		return task.Status
+	}, 10*time.Second, 100*time.Millisecond).Should(BeElementOf(
+		robotapiserver.TaskStatusCreated,
+		robotapiserver.TaskStatusInProgress,
+		robotapiserver.TaskStatusCompleted))
+
+	// The task accepted synchronously was enqueued on acceptance, its created event does not
+	// move the robot a second time
+	getRobotPosition := func() int {
+		var robot robotapiserver.Robot
+		send(g, sut.ApiAddress(), http.MethodGet, "/api/robots/0", nil, &robot)
+
+		return robot.XPosition
+	}
+
+	g.Eventually(getRobotPosition, 10*time.Second, 100*time.Millisecond).Should(Equal(2))
+	g.Consistently(getRobotPosition, time.Second, 100*time.Millisecond).Should(Equal(2))
+}
+
+func Test_Start_Should_Report_The_Trajectory_Of_A_Task(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startStack(t, nil)
+
+	var moveRobotResponse robotapiserver.MoveRobotResponse
+
+	response := send(g, sut.ApiAddress(), http.MethodPut, "/api/robots/0", moveEast, &moveRobotResponse)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusAccepted))
+	g.Expect(moveRobotResponse.Task.RobotId).Should(Equal(0))
+	g.Expect(moveRobotResponse.Task.MoveSequences).Should(Equal([]robotapiserver.TaskMoveSequences{
+		robotapiserver.TaskMoveSequencesE,
+	}))
+	g.Expect(moveRobotResponse.Task.CreatedAt).ShouldNot(BeZero())
+
+	var task robotapiserver.Task
+
+	g.Eventually(func() robotapiserver.TaskStatus {
+		send(g, sut.ApiAddress(), http.MethodGet, fmt.Sprintf("/api/tasks/%d", moveRobotResponse.Task.Id), nil, &task)
+
+		return task.Status
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal(robotapiserver.TaskStatusCompleted))
+
+	// The robots start on the first row, one next to the other
+	g.Expect(task.StartedAt).ShouldNot(BeNil())
+	g.Expect(task.FinishedAt).ShouldNot(BeNil())
+	g.Expect(task.FailureReason).Should(BeNil())
+	g.Expect(task.Trajectory).Should(Equal([]robotapiserver.Cell{{XPosition: 1, YPosition: 0}}))
+	g.Expect(task.Position).Should(Equal(&robotapiserver.Cell{XPosition: 1, YPosition: 0}))
+}
+
+func Test_Start_Should_Serve_The_Warehouse_Layout(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startStack(t, func(options *stack.Options) {
+		options.BoardHeight = 4
+		options.BoardWidth = 6
+		options.Obstacles = []eventpublisher.Cell{{X: 3, Y: 3}}
+	})
+
+	var warehouse robotapiserver.Warehouse
+
+	g.Eventually(func() int {
+		return send(g, sut.ApiAddress(), http.MethodGet, "/api/warehouse", nil, &warehouse).StatusCode
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal(http.StatusOK))
+
+	g.Expect(warehouse.Name).Should(Equal(robotbroker.DEFAULT_WAREHOUSE))
+	g.Expect(warehouse.Width).Should(Equal(6))
+	g.Expect(warehouse.Height).Should(Equal(4))
+	g.Expect(warehouse.Obstacles).Should(Equal([]robotapiserver.Cell{{XPosition: 3, YPosition: 3}}))
+
+	// The moves are checked against the layout the simulator published
+	east := robotapiserver.MoveRobotRequestMoveSequencesE
+
+	g.Expect(send(g, sut.ApiAddress(), http.MethodPut, "/api/robots/0", robotapiserver.MoveRobotRequest{
+		MoveSequences: []robotapiserver.MoveRobotRequestMoveSequences{east, east, east, east, east, east},
+	}, nil).StatusCode).Should(Equal(http.StatusUnprocessableEntity))
+}
+
+func Test_Start_Should_Keep_The_Tasks_Across_Restarts(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dataDir := t.TempDir()
+	configure := func(options *stack.Options) {
+		options.DataDir = dataDir
+	}
+
+	sut := startStack(t, configure)
+
+	var moveRobotResponse robotapiserver.MoveRobotResponse
+
+	response := send(g, sut.ApiAddress(), http.MethodPut, "/api/robots/0", moveEast, &moveRobotResponse)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusAccepted))
+	g.Expect(response.Header.Get("Task-Id-Format")).Should(Equal("snowflake"))
+	g.Expect(moveRobotResponse.Task.IdString).Should(Equal(fmt.Sprint(moveRobotResponse.Task.Id)))
+
+	path := fmt.Sprintf("/api/tasks/%d", moveRobotResponse.Task.Id)
+
+	g.Eventually(func() robotapiserver.TaskStatus {
+		var task robotapiserver.Task
+		send(g, sut.ApiAddress(), http.MethodGet, path, nil, &task)
+
+		return task.Status
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal(robotapiserver.TaskStatusCompleted))
+
+	sut.Stop()
+
+	sut = startStack(t, configure)
+
+	var task robotapiserver.Task
+
+	response = send(g, sut.ApiAddress(), http.MethodGet, path, nil, &task)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusOK))
+	g.Expect(task.Status).Should(Equal(robotapiserver.TaskStatusCompleted))
+	g.Expect(task.Trajectory).Should(Equal([]robotapiserver.Cell{{XPosition: 1, YPosition: 0}}))
+
+	// The ids of the tasks created after a restart do not collide with the stored ones
+	var nextMoveRobotResponse robotapiserver.MoveRobotResponse
+
+	response = send(g, sut.ApiAddress(), http.MethodPut, "/api/robots/0", moveEast, &nextMoveRobotResponse)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusAccepted))
+	g.Expect(nextMoveRobotResponse.Task.Id).Should(BeNumerically(">", moveRobotResponse.Task.Id))
+}
+
+func Test_Start_Should_Report_The_Operational_Status_Of_The_Robots(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startStack(t, func(options *stack.Options) {
+		options.Obstacles = []eventpublisher.Cell{{X: 1, Y: 0}}
+	})
+
+	getRobot := func() robotapiserver.Robot {
+		var robot robotapiserver.Robot
+		send(g, sut.ApiAddress(), http.MethodGet, "/api/robots/0", nil, &robot)
+
+		return robot
+	}
+
+	g.Eventually(getRobot, 10*time.Second, 100*time.Millisecond).Should(And(
+		HaveField("State", robotapiserver.RobotStateIdle),
+		HaveField("QueuedTaskCount", 0),
+		HaveField("HasCrate", false),
+		HaveField("LastSeenAt", Not(BeZero()))))
+
+	// The api refuses to move a robot onto an obstacle, a task published straight to the broker
+	// moves the robot which starts at (0, 0) next to the obstacle
+	g.Eventually(func() int {
+		return send(g, sut.ApiAddress(), http.MethodPut, "/api/robots/0", moveEast, nil).StatusCode
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal(http.StatusUnprocessableEntity))
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		sut.NatsUrl(),
+		robotbroker.ConnectionConfig{},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	defer robotBrokerService.Close()
+
+	codec, err := eventcodec.NewCodec(eventcodec.JSON)
+	g.Expect(err).Should(BeNil())
+
+	eventPublisherService, err := eventpublisher.NewEventPublisherService(sugarLogger, "test", codec, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(eventPublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCreated,
+		Id:        1,
+		Data: eventpublisher.TaskData{
+			RobotId:        0,
+			MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.EAST},
+		},
+	})).Should(Succeed())
+
+	lastError := "robot hit an obstacle"
+
+	g.Eventually(getRobot, 10*time.Second, 100*time.Millisecond).Should(And(
+		HaveField("State", robotapiserver.RobotStateFailed),
+		HaveField("CurrentTaskId", BeNil()),
+		HaveField("LastError", Equal(&lastError))))
+}
+
+func Test_Start_Should_Create_And_Cancel_Batches_Of_Tasks(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startStack(t, func(options *stack.Options) {
+		options.TotalRobotNumber = 2
+	})
+
+	var batch robotapiserver.TaskBatch
+
+	g.Expect(send(g, sut.ApiAddress(), http.MethodPost, "/api/tasks:batch", json.RawMessage(
+		`{"tasks":[{"robotId":0,"moveSequences":["N","N","N"]},{"robotId":1,"moveSequences":["N","N","N"]}]}`),
+		&batch).StatusCode).Should(Equal(http.StatusAccepted))
+	g.Expect(batch.Tasks).Should(HaveLen(2))
+
+	g.Expect(send(g, sut.ApiAddress(), http.MethodDelete, "/api/batches/"+batch.Id, nil, nil).StatusCode).
+		Should(Equal(http.StatusNoContent))
+
+	var cancelled robotapiserver.TaskBatch
+	g.Expect(send(g, sut.ApiAddress(), http.MethodGet, "/api/batches/"+batch.Id, nil, &cancelled).StatusCode).
+		Should(Equal(http.StatusOK))
+	g.Expect(cancelled.Tasks).Should(HaveLen(2))
+
+	for _, task := range cancelled.Tasks {
+		g.Expect(task.Status).Should(BeElementOf(
+			robotapiserver.TaskStatusCancelled,
+			robotapiserver.TaskStatusCompleted))
+	}
+
+	// Robot 1 waits for robot 0 to make the moves it was given before the batch
+	east := robotapiserver.MoveRobotRequestMoveSequencesE
+
+	var moved robotapiserver.MoveRobotResponse
+	g.Expect(send(g, sut.ApiAddress(), http.MethodPut, "/api/robots/0", robotapiserver.MoveRobotRequest{
+		MoveSequences: []robotapiserver.MoveRobotRequestMoveSequences{east, east, east, east},
+	}, &moved).StatusCode).Should(Equal(http.StatusAccepted))
+
+	var syncBatch robotapiserver.TaskBatch
+	g.Expect(send(g, sut.ApiAddress(), http.MethodPost, "/api/tasks:batch?acceptance=sync", json.RawMessage(
+		`{"tasks":[{"robotId":0,"moveSequences":["N"]},{"robotId":1,"moveSequences":["N"]}]}`),
+		&syncBatch).StatusCode).Should(Equal(http.StatusCreated))
+	g.Expect(syncBatch.Tasks).Should(HaveLen(2))
+
+	var finished robotapiserver.TaskBatch
+	g.Eventually(func() []robotapiserver.TaskStatus {
+		send(g, sut.ApiAddress(), http.MethodGet, "/api/batches/"+syncBatch.Id, nil, &finished)
+
+		statuses := make([]robotapiserver.TaskStatus, 0)
+		for _, task := range finished.Tasks {
+			statuses = append(statuses, task.Status)
+		}
+
+		return statuses
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal([]robotapiserver.TaskStatus{
+		robotapiserver.TaskStatusCompleted,
+		robotapiserver.TaskStatusCompleted,
+	}))
+
+	var previous robotapiserver.Task
+	g.Expect(send(g, sut.ApiAddress(), http.MethodGet, fmt.Sprintf("/api/tasks/%d", moved.Task.Id), nil, &previous).StatusCode).
+		Should(Equal(http.StatusOK))
+	g.Expect(previous.StartedAt).ShouldNot(BeNil())
+
+	// The four moves of robot 0 take 400ms at least
+	for _, task := range finished.Tasks {
+		g.Expect(task.StartedAt).ShouldNot(BeNil())
+		g.Expect(task.StartedAt.Sub(*previous.StartedAt)).Should(BeNumerically(">=", 300*time.Millisecond))
+	}
+}
+
+func Test_Start_Should_Serve_The_Version_2_Of_The_Api_Alongside_The_Version_1(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startStack(t, nil)
+
+	var task robotapiv2server.Task
+
+	response := send(g, sut.ApiAddress(), http.MethodPost, "/api/v2/tasks", robotapiv2server.TaskRequest{
+		RobotId: "0",
+		Moves:   []robotapiv2server.Move{robotapiv2server.E},
+	}, &task)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusAccepted))
+
+	g.Eventually(func() robotapiv2server.Task {
+		var current robotapiv2server.Task
+		send(g, sut.ApiAddress(), http.MethodGet, "/api/v2/tasks/"+task.Id, nil, &current)
+
+		return current
+	}, 10*time.Second, 100*time.Millisecond).Should(And(
+		HaveField("Status", robotapiv2server.TaskStatusCompleted),
+		HaveField("Position", Equal(&robotapiv2server.Position{X: 1, Y: 0}))))
+
+	// v1 serves the same task
+	var taskV1 robotapiserver.Task
+
+	response = send(g, sut.ApiAddress(), http.MethodGet, "/api/tasks/"+task.Id, nil, &taskV1)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusOK))
+	g.Expect(taskV1.IdString).Should(Equal(task.Id))
+	g.Expect(taskV1.Status).Should(Equal(robotapiserver.TaskStatusCompleted))
+}
+
+// startStack starts the whole project with one robot on a 10x10 board, configure changes these
+// options unless it is nil. It returns once the api knows every robot and stops the stack when
+// the test is done
+func startStack(t *testing.T, configure func(options *stack.Options)) *stack.Stack {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	options := stack.Options{
+		DataDir:          t.TempDir(),
+		NatsHost:         "127.0.0.1",
+		NatsPort:         -1,
+		ApiAddress:       "127.0.0.1:0",
+		EventCodec:       eventcodec.JSON,
+		Warehouse:        robotbroker.DEFAULT_WAREHOUSE,
+		TotalRobotNumber: 1,
+		BoardHeight:      10,
+		BoardWidth:       10,
+		StreamConfig:     robotbroker.DefaultStreamConfig(),
+		InsecureNoAuth:   true,
+	}
+
+	if configure != nil {
+		configure(&options)
+	}
+
+	sut, err := stack.Start(sugarLogger, options)
+	g.Expect(err).Should(BeNil())
+	t.Cleanup(sut.Stop)
+
+	g.Eventually(func() int {
+		robots := make([]robotapiserver.Robot, 0)
+		send(g, sut.ApiAddress(), http.MethodGet, "/api/robots", nil, &robots)
+
+		return len(robots)
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal(options.TotalRobotNumber))
+
+	return sut
+}
+
+// send sends body as JSON to the api unless it is nil and decodes the JSON response into result
+// unless it is nil
+func send(g *WithT, address string, method string, path string, body interface{}, result interface{}) *http.Response {
+	buf := []byte{}
+
+	if body != nil {
+		var err error
+
+		buf, err = json.Marshal(body)
+		g.Expect(err).Should(BeNil())
+	}
+
+	request, err := http.NewRequest(method, "http://"+address+path, bytes.NewReader(buf))
+	g.Expect(err).Should(BeNil())
+
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	g.Expect(err).Should(BeNil())
+	defer response.Body.Close()
+
+	if result != nil {
+		_ = json.NewDecoder(response.Body).Decode(result)
+	}
+
+	return response
+}
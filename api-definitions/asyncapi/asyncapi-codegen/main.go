@@ -0,0 +1,448 @@
+// asyncapi-codegen generates the Go event types and the subject constants of the robot
+// warehouse events from their AsyncAPI document, the same way oapi-codegen generates the
+// REST models from the OpenAPI document.
+//
+// Usage:
+//
+//	asyncapi-codegen --config config.yaml spec.yaml
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	robotevents "github.com/sepisoad/robot-challange/api-definitions/asyncapi"
+	"gopkg.in/yaml.v3"
+)
+
+// config mirrors the configuration file of oapi-codegen
+type config struct {
+	Package  string `yaml:"package"`
+	Generate struct {
+		Models   bool `yaml:"models"`
+		Subjects bool `yaml:"subjects"`
+	} `yaml:"generate"`
+	Output string `yaml:"output"`
+}
+
+func main() {
+	configPath := flag.String("config", "", "path to the configuration file")
+	flag.Parse()
+
+	if *configPath == "" || flag.NArg() != 1 {
+		log.Fatal("usage: asyncapi-codegen --config config.yaml spec.yaml")
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	specPath := flag.Arg(0)
+
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	doc, err := robotevents.Load(data)
+	if err != nil {
+		log.Fatalf("failed to load %s: %v", specPath, err)
+	}
+
+	order, err := loadOrder(data)
+	if err != nil {
+		log.Fatalf("failed to load %s: %v", specPath, err)
+	}
+
+	g := &generator{
+		doc:     doc,
+		order:   order,
+		imports: make(map[string]bool),
+	}
+
+	if cfg.Generate.Models {
+		if err := g.generateModels(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if cfg.Generate.Subjects {
+		g.generateSubjects()
+	}
+
+	buf, err := g.source(cfg.Package, filepath.Base(specPath))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(cfg.Output, buf, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Package == "" || cfg.Output == "" {
+		return nil, fmt.Errorf("%s: package and output are required", path)
+	}
+
+	return cfg, nil
+}
+
+// schemaOrder keeps the order schemas and their properties are declared in, so that the
+// generated code reads like the document
+type schemaOrder struct {
+	schemas    []string
+	properties map[string][]string
+}
+
+func loadOrder(data []byte) (*schemaOrder, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	order := &schemaOrder{properties: make(map[string][]string)}
+
+	if len(root.Content) == 0 {
+		return order, nil
+	}
+
+	schemas := lookup(lookup(root.Content[0], "components"), "schemas")
+	if schemas == nil {
+		return order, nil
+	}
+
+	for idx := 0; idx+1 < len(schemas.Content); idx += 2 {
+		name := schemas.Content[idx].Value
+		order.schemas = append(order.schemas, name)
+
+		properties := lookup(schemas.Content[idx+1], "properties")
+		if properties == nil {
+			continue
+		}
+
+		for propertyIdx := 0; propertyIdx+1 < len(properties.Content); propertyIdx += 2 {
+			order.properties[name] = append(order.properties[name], properties.Content[propertyIdx].Value)
+		}
+	}
+
+	return order, nil
+}
+
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value == key {
+			return node.Content[idx+1]
+		}
+	}
+
+	return nil
+}
+
+type generator struct {
+	doc     *robotevents.Document
+	order   *schemaOrder
+	imports map[string]bool
+	body    bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.body, format, args...)
+}
+
+func (g *generator) source(packageName string, specName string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by asyncapi-codegen from %s. DO NOT EDIT.\n\n", specName)
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
+
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			imports = append(imports, path)
+		}
+
+		sort.Strings(imports)
+
+		buf.WriteString("import (\n")
+		for _, path := range imports {
+			fmt.Fprintf(&buf, "\t%q\n", path)
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	buf.Write(g.body.Bytes())
+
+	return format.Source(buf.Bytes())
+}
+
+func (g *generator) generateModels() error {
+	for _, name := range g.order.schemas {
+		schemaRef, found := g.doc.Components.Schemas[name]
+		if !found || schemaRef.Value == nil {
+			return fmt.Errorf("schema %s not found", name)
+		}
+
+		schema := schemaRef.Value
+
+		// Schemas bound to hand written types are not generated
+		if _, found := schema.Extensions["x-go-type"]; found {
+			continue
+		}
+
+		switch {
+		case schema.Type == openapi3.TypeString && len(schema.Enum) > 0:
+			if err := g.generateEnum(name, schema); err != nil {
+				return err
+			}
+		case schema.Type == openapi3.TypeObject:
+			if err := g.generateStruct(name, schema); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("schema %s: unsupported type %q", name, schema.Type)
+		}
+	}
+
+	return nil
+}
+
+func (g *generator) generateEnum(name string, schema *openapi3.Schema) error {
+	var varNames, descriptions []string
+	if err := extension(schema, "x-enum-varnames", &varNames); err != nil {
+		return fmt.Errorf("schema %s: %w", name, err)
+	}
+
+	if err := extension(schema, "x-enum-descriptions", &descriptions); err != nil {
+		return fmt.Errorf("schema %s: %w", name, err)
+	}
+
+	g.printComment(name, schema.Description)
+	g.printf("type %s string\n\n", name)
+	g.printf("const (\n")
+
+	for idx, value := range schema.Enum {
+		varName := name + exportedName(fmt.Sprint(value))
+		if idx < len(varNames) {
+			varName = varNames[idx]
+		}
+
+		if idx < len(descriptions) {
+			g.printComment(varName, descriptions[idx])
+		}
+
+		g.printf("%s %s = %q\n", varName, name, fmt.Sprint(value))
+	}
+
+	g.printf(")\n\n")
+
+	return nil
+}
+
+func (g *generator) generateStruct(name string, schema *openapi3.Schema) error {
+	required := make(map[string]bool)
+	for _, property := range schema.Required {
+		required[property] = true
+	}
+
+	properties := g.order.properties[name]
+	if len(properties) != len(schema.Properties) {
+		properties = make([]string, 0, len(schema.Properties))
+		for property := range schema.Properties {
+			properties = append(properties, property)
+		}
+
+		sort.Strings(properties)
+	}
+
+	g.printComment(name, schema.Description)
+	g.printf("type %s struct {\n", name)
+
+	for _, property := range properties {
+		goType, err := g.goType(schema.Properties[property])
+		if err != nil {
+			return fmt.Errorf("schema %s, property %s: %w", name, property, err)
+		}
+
+		tag := property
+		if !required[property] {
+			tag += ",omitempty"
+		}
+
+		// Referenced schemas are documented on their own type
+		if description := schema.Properties[property].Value.Description; schema.Properties[property].Ref == "" && description != "" {
+			g.printf("// %s\n", description)
+		}
+
+		g.printf("%s %s `json:%q`\n", exportedName(property), goType, tag)
+	}
+
+	g.printf("}\n\n")
+
+	return nil
+}
+
+func (g *generator) goType(schemaRef *openapi3.SchemaRef) (string, error) {
+	if schemaRef.Ref != "" {
+		return schemaRef.Ref[strings.LastIndex(schemaRef.Ref, "/")+1:], nil
+	}
+
+	schema := schemaRef.Value
+
+	switch schema.Type {
+	case openapi3.TypeString:
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+
+			return "time.Time", nil
+		}
+
+		return "string", nil
+	case openapi3.TypeInteger:
+		switch schema.Format {
+		case "int32":
+			return "int32", nil
+		case "int64":
+			return "int64", nil
+		default:
+			return "int", nil
+		}
+	case openapi3.TypeNumber:
+		if schema.Format == "float" {
+			return "float32", nil
+		}
+
+		return "float64", nil
+	case openapi3.TypeBoolean:
+		return "bool", nil
+	case openapi3.TypeArray:
+		itemType, err := g.goType(schema.Items)
+		if err != nil {
+			return "", err
+		}
+
+		return "[]" + itemType, nil
+	case openapi3.TypeObject:
+		return "map[string]interface{}", nil
+	}
+
+	return "", fmt.Errorf("unsupported type %q", schema.Type)
+}
+
+func (g *generator) generateSubjects() {
+	roots := make(map[string]bool)
+	channels := g.doc.ChannelNames()
+
+	for _, name := range channels {
+		roots[strings.Split(name, ".")[0]] = true
+	}
+
+	sortedRoots := make([]string, 0, len(roots))
+	for root := range roots {
+		sortedRoots = append(sortedRoots, root)
+	}
+
+	sort.Strings(sortedRoots)
+
+	g.printf("// Defines the first token of the subjects.\n")
+	g.printf("const (\n")
+
+	for _, root := range sortedRoots {
+		g.printf("SUBJECT_%s = %q\n", strings.ToUpper(root), root)
+	}
+
+	g.printf(")\n\n")
+
+	g.printf("// Defines the channels events are published on, parameters are enclosed in braces.\n")
+	g.printf("const (\n")
+
+	for _, name := range channels {
+		channel := g.doc.Channels[name]
+
+		constantName := "CHANNEL_" + strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+		if message := channel.Message(); message != nil && message.Name != "" {
+			constantName = "CHANNEL_" + upperSnakeCase(message.Name)
+		}
+
+		if channel.Description != "" {
+			g.printf("// %s: %s\n", constantName, channel.Description)
+		}
+
+		g.printf("%s = %q\n", constantName, name)
+	}
+
+	g.printf(")\n")
+}
+
+func (g *generator) printComment(name string, description string) {
+	if description == "" {
+		return
+	}
+
+	g.printf("// %s %s\n", name, description)
+}
+
+func extension(schema *openapi3.Schema, name string, value interface{}) error {
+	raw, found := schema.Extensions[name]
+	if !found {
+		return nil
+	}
+
+	// Extensions hold the raw JSON of their value
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(buf, value)
+}
+
+func exportedName(name string) string {
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return name
+	}
+
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
+
+func upperSnakeCase(name string) string {
+	var buf strings.Builder
+
+	for idx, r := range name {
+		if idx > 0 && unicode.IsUpper(r) {
+			buf.WriteRune('_')
+		}
+
+		buf.WriteRune(unicode.ToUpper(r))
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,4 @@
+package robotevents
+
+//go:generate go run ./asyncapi-codegen --config eventpublisher-config.yaml ./Robot_Events.yaml
+//go:generate go run ./asyncapi-codegen --config robotbroker-config.yaml ./Robot_Events.yaml
@@ -0,0 +1,194 @@
+package robotevents
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+)
+
+//go:embed Robot_Events.yaml
+var robotEventsSpec []byte
+
+const (
+	messageRefPrefix   = "#/components/messages/"
+	parameterRefPrefix = "#/components/parameters/"
+)
+
+// Document is the subset of an AsyncAPI 2 document needed to generate code from and to
+// validate messages against
+type Document struct {
+	AsyncAPI           string              `json:"asyncapi"`
+	Info               Info                `json:"info"`
+	DefaultContentType string              `json:"defaultContentType,omitempty"`
+	Channels           map[string]*Channel `json:"channels"`
+	Components         Components          `json:"components"`
+}
+
+// Info describes the document
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Channel describes a subject and the message published on it
+type Channel struct {
+	Description string                `json:"description,omitempty"`
+	Parameters  map[string]*Parameter `json:"parameters,omitempty"`
+	Subscribe   *Operation            `json:"subscribe,omitempty"`
+	Publish     *Operation            `json:"publish,omitempty"`
+}
+
+// Parameter describes a parameter of a channel
+type Parameter struct {
+	Ref         string              `json:"$ref,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Schema      *openapi3.SchemaRef `json:"schema,omitempty"`
+}
+
+// Operation describes an operation of a channel
+type Operation struct {
+	OperationId string   `json:"operationId,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Message     *Message `json:"message"`
+}
+
+// Message describes the payload of a message
+type Message struct {
+	Ref         string              `json:"$ref,omitempty"`
+	Name        string              `json:"name,omitempty"`
+	Title       string              `json:"title,omitempty"`
+	ContentType string              `json:"contentType,omitempty"`
+	Payload     *openapi3.SchemaRef `json:"payload,omitempty"`
+}
+
+// Components holds the reusable objects of a document
+type Components struct {
+	Schemas    openapi3.Schemas      `json:"schemas,omitempty"`
+	Messages   map[string]*Message   `json:"messages,omitempty"`
+	Parameters map[string]*Parameter `json:"parameters,omitempty"`
+}
+
+// GetSpec returns the AsyncAPI document of the robot warehouse events
+func GetSpec() (*Document, error) {
+	return Load(robotEventsSpec)
+}
+
+// Load parses an AsyncAPI document and resolves its references
+func Load(data []byte) (*Document, error) {
+	buf, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	if err := json.Unmarshal(buf, doc); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(doc.AsyncAPI, "2.") {
+		return nil, fmt.Errorf("unsupported asyncapi version %q", doc.AsyncAPI)
+	}
+
+	if err := doc.resolveRefs(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// ChannelNames returns the names of the channels of the document in order
+func (d *Document) ChannelNames() []string {
+	names := make([]string, 0, len(d.Channels))
+	for name := range d.Channels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Message returns the message published on a channel
+func (c *Channel) Message() *Message {
+	if c.Subscribe != nil && c.Subscribe.Message != nil {
+		return c.Subscribe.Message
+	}
+
+	if c.Publish != nil {
+		return c.Publish.Message
+	}
+
+	return nil
+}
+
+// resolveRefs replaces message and parameter references by the components they point at and
+// resolves the schema references with the help of an openapi document
+func (d *Document) resolveRefs() error {
+	schemas := openapi3.Schemas{}
+	for name, schema := range d.Components.Schemas {
+		schemas[name] = schema
+	}
+
+	for name, message := range d.Components.Messages {
+		if message.Payload != nil {
+			schemas["asyncapi-message-"+name] = message.Payload
+		}
+	}
+
+	for name, parameter := range d.Components.Parameters {
+		if parameter.Schema != nil {
+			schemas["asyncapi-parameter-"+name] = parameter.Schema
+		}
+	}
+
+	for channelName, channel := range d.Channels {
+		for _, operation := range []*Operation{channel.Subscribe, channel.Publish} {
+			if operation == nil || operation.Message == nil {
+				continue
+			}
+
+			if operation.Message.Ref == "" {
+				if operation.Message.Payload != nil {
+					schemas["asyncapi-channel-"+channelName] = operation.Message.Payload
+				}
+
+				continue
+			}
+
+			message, found := d.Components.Messages[strings.TrimPrefix(operation.Message.Ref, messageRefPrefix)]
+			if !found {
+				return fmt.Errorf("channel %s: unresolved message %s", channelName, operation.Message.Ref)
+			}
+
+			operation.Message = message
+		}
+
+		for parameterName, parameter := range channel.Parameters {
+			if parameter.Ref == "" {
+				continue
+			}
+
+			resolved, found := d.Components.Parameters[strings.TrimPrefix(parameter.Ref, parameterRefPrefix)]
+			if !found {
+				return fmt.Errorf("channel %s: unresolved parameter %s", channelName, parameter.Ref)
+			}
+
+			channel.Parameters[parameterName] = resolved
+		}
+	}
+
+	openapi := &openapi3.T{
+		OpenAPI:    "3.0.3",
+		Info:       &openapi3.Info{},
+		Paths:      openapi3.Paths{},
+		Components: openapi3.Components{Schemas: schemas},
+	}
+
+	return openapi3.NewLoader().ResolveRefsIn(openapi, nil)
+}
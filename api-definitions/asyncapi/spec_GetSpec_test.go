@@ -0,0 +1,26 @@
+package robotevents_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	robotevents "github.com/sepisoad/robot-challange/api-definitions/asyncapi"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+)
+
+func Test_GetSpec_Should_Announce_The_Content_Types_The_Codecs_Send(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := robotevents.GetSpec()
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.DefaultContentType).Should(Equal(eventcodec.CONTENT_TYPE_JSON))
+
+	for _, contentType := range []string{
+		eventcodec.CONTENT_TYPE_JSON,
+		eventcodec.CONTENT_TYPE_PROTOBUF,
+		eventcodec.CONTENT_TYPE_MSGPACK,
+	} {
+		g.Expect(sut.Info.Description).Should(ContainSubstring("`"+contentType+"`"), contentType)
+	}
+}
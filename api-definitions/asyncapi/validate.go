@@ -0,0 +1,75 @@
+package robotevents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ErrUnknownSubject is returned when a subject does not match any channel of the document
+var ErrUnknownSubject = errors.New("subject does not match any channel")
+
+// MatchChannel returns the name of the channel a subject is published on. A channel
+// parameter, e.g. {taskId}, matches exactly one subject token
+func (d *Document) MatchChannel(subject string) (string, error) {
+	subjectTokens := strings.Split(subject, ".")
+
+	for _, name := range d.ChannelNames() {
+		channelTokens := strings.Split(name, ".")
+		if len(channelTokens) != len(subjectTokens) {
+			continue
+		}
+
+		matched := true
+		for idx, channelToken := range channelTokens {
+			if isParameter(channelToken) {
+				continue
+			}
+
+			if channelToken != subjectTokens[idx] {
+				matched = false
+
+				break
+			}
+		}
+
+		if matched {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnknownSubject, subject)
+}
+
+// ValidateMessage checks that a JSON encoded message published on the given subject
+// matches the payload of its channel
+func (d *Document) ValidateMessage(subject string, payload []byte) error {
+	name, err := d.MatchChannel(subject)
+	if err != nil {
+		return err
+	}
+
+	message := d.Channels[name].Message()
+	if message == nil || message.Payload == nil || message.Payload.Value == nil {
+		return fmt.Errorf("channel %s does not define a payload", name)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return err
+	}
+
+	if err := message.Payload.Value.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		return fmt.Errorf("message published on %s is not a valid %s: %w", subject, message.Name, err)
+	}
+
+	return nil
+}
+
+// isParameter reports whether a channel token is a parameter, e.g. {robotId}
+func isParameter(token string) bool {
+	return strings.HasPrefix(token, "{") && strings.HasSuffix(token, "}")
+}
@@ -0,0 +1,73 @@
+package robotevents_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	robotevents "github.com/sepisoad/robot-challange/api-definitions/asyncapi"
+)
+
+func newEnvelope(envelopeType string, data string) []byte {
+	buf, _ := json.Marshal(map[string]interface{}{
+		"specversion":   "1.0",
+		"id":            cuid.New(),
+		"source":        cuid.New(),
+		"time":          time.Now().UTC(),
+		"type":          envelopeType,
+		"schemaversion": 1,
+		"data":          json.RawMessage(data),
+	})
+
+	return buf
+}
+
+func Test_ValidateMessage_Should_Accept_Valid_Message(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := robotevents.GetSpec()
+	g.Expect(err).Should(BeNil())
+
+	err = sut.ValidateMessage(
+		"robot.default.3.moved",
+		newEnvelope("robots.robot", `{"EventType":"Moved","Id":3,"Data":{"X":1,"Y":2}}`))
+	g.Expect(err).Should(BeNil())
+}
+
+func Test_ValidateMessage_Should_Return_Error_For_Unknown_Subject(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := robotevents.GetSpec()
+	g.Expect(err).Should(BeNil())
+
+	err = sut.ValidateMessage(
+		"robot.3.moved",
+		newEnvelope("robots.robot", `{"EventType":"Moved","Id":3,"Data":{"X":1,"Y":2}}`))
+	g.Expect(err).Should(MatchError(robotevents.ErrUnknownSubject))
+}
+
+func Test_ValidateMessage_Should_Return_Error_For_Event_Published_On_Wrong_Channel(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := robotevents.GetSpec()
+	g.Expect(err).Should(BeNil())
+
+	err = sut.ValidateMessage(
+		"task.7.created",
+		newEnvelope("robots.task", `{"EventType":"Completed","Id":7,"Data":{"RobotId":1,"MoveSequeneces":null}}`))
+	g.Expect(err).Should(Not(BeNil()))
+}
+
+func Test_ValidateMessage_Should_Return_Error_For_Undocumented_Property(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := robotevents.GetSpec()
+	g.Expect(err).Should(BeNil())
+
+	err = sut.ValidateMessage(
+		"task.7.created",
+		newEnvelope("robots.task", `{"EventType":"Created","Id":7,"Priority":1,"Data":{"RobotId":1,"MoveSequeneces":["N"]}}`))
+	g.Expect(err).Should(Not(BeNil()))
+}
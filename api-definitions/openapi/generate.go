@@ -1,3 +1,4 @@
 package robotapi
 
 //go:generate oapi-codegen --config robotapi-server-config.yaml ./Robot_V1.yaml
+//go:generate oapi-codegen --config robotapiv2-server-config.yaml ./Robot_V2.yaml
@@ -12,28 +12,128 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/deepmap/oapi-codegen/pkg/runtime"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	AccessTokenScopes = "accessToken.Scopes"
+	ApiKeyScopes      = "apiKey.Scopes"
+	BearerAuthScopes  = "bearerAuth.Scopes"
+)
+
+// Defines values for BrokerHealthState.
+const (
+	BrokerHealthStateClosed       BrokerHealthState = "closed"
+	BrokerHealthStateConnected    BrokerHealthState = "connected"
+	BrokerHealthStateDisconnected BrokerHealthState = "disconnected"
+)
+
+// Defines values for HealthPublishCircuit.
+const (
+	HealthPublishCircuitClosed   HealthPublishCircuit = "closed"
+	HealthPublishCircuitHalfOpen HealthPublishCircuit = "halfOpen"
+	HealthPublishCircuitOpen     HealthPublishCircuit = "open"
+)
+
 // Defines values for MoveRobotRequestMoveSequences.
 const (
-	E MoveRobotRequestMoveSequences = "E"
-	N MoveRobotRequestMoveSequences = "N"
-	S MoveRobotRequestMoveSequences = "S"
-	W MoveRobotRequestMoveSequences = "W"
+	MoveRobotRequestMoveSequencesE MoveRobotRequestMoveSequences = "E"
+	MoveRobotRequestMoveSequencesN MoveRobotRequestMoveSequences = "N"
+	MoveRobotRequestMoveSequencesS MoveRobotRequestMoveSequences = "S"
+	MoveRobotRequestMoveSequencesW MoveRobotRequestMoveSequences = "W"
+)
+
+// Defines values for RobotState.
+const (
+	RobotStateBusy   RobotState = "busy"
+	RobotStateFailed RobotState = "failed"
+	RobotStateIdle   RobotState = "idle"
+)
+
+// Defines values for SpecialCellKind.
+const (
+	Charger SpecialCellKind = "Charger"
+	Dropoff SpecialCellKind = "Dropoff"
+	Pickup  SpecialCellKind = "Pickup"
+)
+
+// Defines values for TaskMoveSequences.
+const (
+	TaskMoveSequencesE TaskMoveSequences = "E"
+	TaskMoveSequencesN TaskMoveSequences = "N"
+	TaskMoveSequencesS TaskMoveSequences = "S"
+	TaskMoveSequencesW TaskMoveSequences = "W"
+)
+
+// Defines values for TaskBatchRequestTasksMoveSequences.
+const (
+	E TaskBatchRequestTasksMoveSequences = "E"
+	N TaskBatchRequestTasksMoveSequences = "N"
+	S TaskBatchRequestTasksMoveSequences = "S"
+	W TaskBatchRequestTasksMoveSequences = "W"
 )
 
 // Defines values for TaskStatus.
 const (
-	CANCELLED  TaskStatus = "CANCELLED"
-	COMPLETED  TaskStatus = "COMPLETED"
-	CREATED    TaskStatus = "CREATED"
-	INPROGRESS TaskStatus = "INPROGRESS"
+	TaskStatusCancelled         TaskStatus = "Cancelled"
+	TaskStatusCompleted         TaskStatus = "Completed"
+	TaskStatusCreated           TaskStatus = "Created"
+	TaskStatusFailed            TaskStatus = "Failed"
+	TaskStatusInProgress        TaskStatus = "InProgress"
+	TaskStatusQueuedForDispatch TaskStatus = "QueuedForDispatch"
+)
+
+// Defines values for Acceptance.
+const (
+	Async Acceptance = "async"
+	Sync  Acceptance = "sync"
+)
+
+// Defines values for Order.
+const (
+	Asc  Order = "asc"
+	Desc Order = "desc"
 )
 
+// Defines values for RobotSort.
+const (
+	RobotSortId        RobotSort = "id"
+	RobotSortXPosition RobotSort = "xPosition"
+	RobotSortYPosition RobotSort = "yPosition"
+)
+
+// Defines values for TaskSort.
+const (
+	TaskSortCreatedAt TaskSort = "createdAt"
+	TaskSortId        TaskSort = "id"
+)
+
+// BrokerHealth defines model for brokerHealth.
+type BrokerHealth struct {
+	Disconnects int64             `json:"disconnects"`
+	InBytes     int64             `json:"inBytes"`
+	InMsgs      int64             `json:"inMsgs"`
+	LastError   *string           `json:"lastError,omitempty"`
+	OutBytes    int64             `json:"outBytes"`
+	OutMsgs     int64             `json:"outMsgs"`
+	Reconnects  int64             `json:"reconnects"`
+	Since       time.Time         `json:"since"`
+	State       BrokerHealthState `json:"state"`
+}
+
+// BrokerHealthState defines model for BrokerHealth.State.
+type BrokerHealthState string
+
+// Cell defines model for cell.
+type Cell struct {
+	XPosition int `json:"xPosition"`
+	YPosition int `json:"yPosition"`
+}
+
 // Error defines model for error.
 type Error struct {
 	// Error code
@@ -43,6 +143,17 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// Health defines model for health.
+type Health struct {
+	Broker BrokerHealth `json:"broker"`
+
+	// State of the circuit breaker between the api and NATS
+	PublishCircuit HealthPublishCircuit `json:"publishCircuit"`
+}
+
+// State of the circuit breaker between the api and NATS
+type HealthPublishCircuit string
+
 // MoveRobotRequest defines model for moveRobotRequest.
 type MoveRobotRequest struct {
 	MoveSequences []MoveRobotRequestMoveSequences `json:"moveSequences"`
@@ -58,55 +169,321 @@ type MoveRobotResponse struct {
 
 // Robot defines model for robot.
 type Robot struct {
-	Id        int `json:"id"`
-	XPosition int `json:"xPosition"`
-	YPosition int `json:"yPosition"`
+	// The task the robot is making the moves of
+	CurrentTaskId *int64 `json:"currentTaskId,omitempty"`
+
+	// Whether the robot carries a crate
+	HasCrate bool `json:"hasCrate"`
+	Id       int  `json:"id"`
+
+	// Why the robot last failed to move
+	LastError *string `json:"lastError,omitempty"`
+
+	// When the robot last reported, whether it moved or not
+	LastSeenAt time.Time `json:"lastSeenAt"`
+
+	// Number of the other tasks of the robot which are not finished
+	QueuedTaskCount int `json:"queuedTaskCount"`
+
+	// Failed while the last move of the robot failed, busy while one of its tasks is not finished
+	State     RobotState `json:"state"`
+	XPosition int        `json:"xPosition"`
+	YPosition int        `json:"yPosition"`
+}
+
+// Failed while the last move of the robot failed, busy while one of its tasks is not finished
+type RobotState string
+
+// SpecialCell defines model for specialCell.
+type SpecialCell struct {
+	Kind      SpecialCellKind `json:"kind"`
+	XPosition int             `json:"xPosition"`
+	YPosition int             `json:"yPosition"`
 }
 
+// SpecialCellKind defines model for SpecialCell.Kind.
+type SpecialCellKind string
+
 // Task defines model for task.
 type Task struct {
-	Id     int        `json:"id"`
-	Status TaskStatus `json:"status"`
+	// The batch the task was created with
+	BatchId   *string   `json:"batchId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Why the robot could not make some of the moves of a failed task
+	FailureReason *string `json:"failureReason,omitempty"`
+
+	// When the robot was done with the moves
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// A 64-bit snowflake id, see the Task-Id-Format header. The ids exceed 2^53, the
+	// integers JavaScript numbers hold exactly, so such clients round them silently and
+	// must read idString instead
+	Id int64 `json:"id"`
+
+	// The id of the task as a decimal string
+	IdString      string              `json:"idString"`
+	MoveSequences []TaskMoveSequences `json:"moveSequences"`
+	Position      *Cell               `json:"position,omitempty"`
+	RobotId       int                 `json:"robotId"`
+
+	// When the robot made its first move
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	Status    TaskStatus `json:"status"`
+
+	// The positions of the robot while it executed the task, known once the task is finished
+	Trajectory []Cell `json:"trajectory"`
 }
 
-// TaskStatus defines model for Task.Status.
+// TaskMoveSequences defines model for Task.MoveSequences.
+type TaskMoveSequences string
+
+// TaskBatch defines model for taskBatch.
+type TaskBatch struct {
+	Id    string `json:"id"`
+	Tasks []Task `json:"tasks"`
+}
+
+// TaskBatchRequest defines model for taskBatchRequest.
+type TaskBatchRequest struct {
+	Tasks []struct {
+		MoveSequences []TaskBatchRequestTasksMoveSequences `json:"moveSequences"`
+		RobotId       int                                  `json:"robotId"`
+	} `json:"tasks"`
+}
+
+// TaskBatchRequestTasksMoveSequences defines model for TaskBatchRequest.Tasks.MoveSequences.
+type TaskBatchRequestTasksMoveSequences string
+
+// TaskStatus defines model for taskStatus.
 type TaskStatus string
 
+// Warehouse defines model for warehouse.
+type Warehouse struct {
+	// Number of rows, y goes from 0 to height - 1
+	Height       int           `json:"height"`
+	Name         string        `json:"name"`
+	Obstacles    []Cell        `json:"obstacles"`
+	SpecialCells []SpecialCell `json:"specialCells"`
+
+	// Number of columns, x goes from 0 to width - 1
+	Width int `json:"width"`
+}
+
+// Acceptance defines model for acceptance.
+type Acceptance string
+
+// BatchId defines model for batchId.
+type BatchId = string
+
+// Busy defines model for busy.
+type Busy = bool
+
+// CreatedAfter defines model for createdAfter.
+type CreatedAfter = time.Time
+
+// CreatedBefore defines model for createdBefore.
+type CreatedBefore = time.Time
+
+// Cursor defines model for cursor.
+type Cursor = string
+
+// IdempotencyKey defines model for idempotencyKey.
+type IdempotencyKey = string
+
+// Limit defines model for limit.
+type Limit = int
+
+// MaxX defines model for maxX.
+type MaxX = int
+
+// MaxY defines model for maxY.
+type MaxY = int
+
+// MinX defines model for minX.
+type MinX = int
+
+// MinY defines model for minY.
+type MinY = int
+
+// Order defines model for order.
+type Order string
+
 // RobotId defines model for robotId.
 type RobotId = int
 
+// RobotSort defines model for robotSort.
+type RobotSort string
+
+// Status defines model for status.
+type Status = TaskStatus
+
 // TaskId defines model for taskId.
-type TaskId = int
+type TaskId = int64
+
+// TaskRobotId defines model for taskRobotId.
+type TaskRobotId = int
+
+// TaskSort defines model for taskSort.
+type TaskSort string
+
+// Forbidden defines model for forbidden.
+type Forbidden = Error
+
+// Unauthorized defines model for unauthorized.
+type Unauthorized = Error
+
+// GetAllRobotsParams defines parameters for GetAllRobots.
+type GetAllRobotsParams struct {
+	// Only lists the robots with an x position greater than or equal to this one
+	MinX *MinX `form:"minX,omitempty" json:"minX,omitempty"`
+
+	// Only lists the robots with an x position less than or equal to this one
+	MaxX *MaxX `form:"maxX,omitempty" json:"maxX,omitempty"`
+
+	// Only lists the robots with a y position greater than or equal to this one
+	MinY *MinY `form:"minY,omitempty" json:"minY,omitempty"`
+
+	// Only lists the robots with a y position less than or equal to this one
+	MaxY *MaxY `form:"maxY,omitempty" json:"maxY,omitempty"`
+
+	// Only lists the robots which have, or have not, a task which is not finished yet
+	Busy *Busy `form:"busy,omitempty" json:"busy,omitempty"`
+
+	// The field robots are sorted by, defaults to id
+	Sort *GetAllRobotsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// The order items are sorted in, defaults to asc
+	Order *GetAllRobotsParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// The maximum number of items of the page, defaults to 100 when a cursor is given. Every
+	// item is listed when neither a limit nor a cursor is given
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Where the page starts, the X-Next-Cursor header of the previous page. The other parameters
+	// must not change from one page to the next
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetAllRobotsParamsSort defines parameters for GetAllRobots.
+type GetAllRobotsParamsSort string
+
+// GetAllRobotsParamsOrder defines parameters for GetAllRobots.
+type GetAllRobotsParamsOrder string
 
 // MoveRobotJSONBody defines parameters for MoveRobot.
 type MoveRobotJSONBody = MoveRobotRequest
 
+// MoveRobotParams defines parameters for MoveRobot.
+type MoveRobotParams struct {
+	// Whether to wait for the simulator to accept the task, defaults to the acceptance
+	// configured on the api
+	Acceptance *MoveRobotParamsAcceptance `form:"acceptance,omitempty" json:"acceptance,omitempty"`
+
+	// Makes the request safe to retry, the response of the first request sent with the key is
+	// replayed to the repeats with an Idempotent-Replayed header
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// MoveRobotParamsAcceptance defines parameters for MoveRobot.
+type MoveRobotParamsAcceptance string
+
+// GetAllTasksParams defines parameters for GetAllTasks.
+type GetAllTasksParams struct {
+	// Only lists the tasks with this status
+	Status *Status `form:"status,omitempty" json:"status,omitempty"`
+
+	// Only lists the tasks of this robot
+	RobotId *TaskRobotId `form:"robotId,omitempty" json:"robotId,omitempty"`
+
+	// Only lists the tasks created at or after this time
+	CreatedAfter *CreatedAfter `form:"createdAfter,omitempty" json:"createdAfter,omitempty"`
+
+	// Only lists the tasks created before this time
+	CreatedBefore *CreatedBefore `form:"createdBefore,omitempty" json:"createdBefore,omitempty"`
+
+	// The field tasks are sorted by, defaults to id
+	Sort *GetAllTasksParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// The order items are sorted in, defaults to asc
+	Order *GetAllTasksParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// The maximum number of items of the page, defaults to 100 when a cursor is given. Every
+	// item is listed when neither a limit nor a cursor is given
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Where the page starts, the X-Next-Cursor header of the previous page. The other parameters
+	// must not change from one page to the next
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetAllTasksParamsSort defines parameters for GetAllTasks.
+type GetAllTasksParamsSort string
+
+// GetAllTasksParamsOrder defines parameters for GetAllTasks.
+type GetAllTasksParamsOrder string
+
+// CreateTaskBatchJSONBody defines parameters for CreateTaskBatch.
+type CreateTaskBatchJSONBody = TaskBatchRequest
+
+// CreateTaskBatchParams defines parameters for CreateTaskBatch.
+type CreateTaskBatchParams struct {
+	// Whether to wait for the simulator to accept the task, defaults to the acceptance
+	// configured on the api
+	Acceptance *CreateTaskBatchParamsAcceptance `form:"acceptance,omitempty" json:"acceptance,omitempty"`
+
+	// Makes the request safe to retry, the response of the first request sent with the key is
+	// replayed to the repeats with an Idempotent-Replayed header
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateTaskBatchParamsAcceptance defines parameters for CreateTaskBatch.
+type CreateTaskBatchParamsAcceptance string
+
 // MoveRobotJSONRequestBody defines body for MoveRobot for application/json ContentType.
 type MoveRobotJSONRequestBody = MoveRobotJSONBody
 
+// CreateTaskBatchJSONRequestBody defines body for CreateTaskBatch for application/json ContentType.
+type CreateTaskBatchJSONRequestBody = CreateTaskBatchJSONBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 	// Returns a web dashboard
 	// (GET /)
 	Dashboard(ctx echo.Context) error
+	// Cancel the tasks of a batch
+	// (DELETE /api/batches/{batchId})
+	CancelTaskBatch(ctx echo.Context, batchId BatchId) error
+	// Get the tasks of a batch
+	// (GET /api/batches/{batchId})
+	GetTaskBatch(ctx echo.Context, batchId BatchId) error
+	// Returns the health of the api and its connection to NATS
+	// (GET /api/health)
+	GetHealth(ctx echo.Context) error
 	// Return the list of all robots with their current status
 	// (GET /api/robots)
-	GetAllRobots(ctx echo.Context) error
+	GetAllRobots(ctx echo.Context, params GetAllRobotsParams) error
 	// Get robot
 	// (GET /api/robots/{robotId})
 	GetRobot(ctx echo.Context, robotId RobotId) error
 	// Move robot
 	// (PUT /api/robots/{robotId})
-	MoveRobot(ctx echo.Context, robotId RobotId) error
+	MoveRobot(ctx echo.Context, robotId RobotId, params MoveRobotParams) error
 	// Get all tasks
 	// (GET /api/tasks)
-	GetAllTasks(ctx echo.Context) error
+	GetAllTasks(ctx echo.Context, params GetAllTasksParams) error
 	// Cancel task
 	// (DELETE /api/tasks/{taskId})
 	CancelTask(ctx echo.Context, taskId TaskId) error
 	// Get task
 	// (GET /api/tasks/{taskId})
 	GetTask(ctx echo.Context, taskId TaskId) error
+	// Create a batch of tasks
+	// (POST /api/tasks:batch)
+	CreateTaskBatch(ctx echo.Context, params CreateTaskBatchParams) error
+	// Returns the size and the layout of the warehouse the simulator was started with
+	// (GET /api/warehouse)
+	GetWarehouse(ctx echo.Context) error
 	// Returns a websocket that streams the robots status
 	// (GET /ws/robots)
 	RobotsWebsocket(ctx echo.Context) error
@@ -129,12 +506,130 @@ func (w *ServerInterfaceWrapper) Dashboard(ctx echo.Context) error {
 	return err
 }
 
+// CancelTaskBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) CancelTaskBatch(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "batchId" -------------
+	var batchId BatchId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "batchId", runtime.ParamLocationPath, ctx.Param("batchId"), &batchId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter batchId: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{"operator"})
+
+	ctx.Set(BearerAuthScopes, []string{"operator"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CancelTaskBatch(ctx, batchId)
+	return err
+}
+
+// GetTaskBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTaskBatch(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "batchId" -------------
+	var batchId BatchId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "batchId", runtime.ParamLocationPath, ctx.Param("batchId"), &batchId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter batchId: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetTaskBatch(ctx, batchId)
+	return err
+}
+
+// GetHealth converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetHealth(ctx)
+	return err
+}
+
 // GetAllRobots converts echo context to params.
 func (w *ServerInterfaceWrapper) GetAllRobots(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAllRobotsParams
+	// ------------- Optional query parameter "minX" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minX", ctx.QueryParams(), &params.MinX)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter minX: %s", err))
+	}
+
+	// ------------- Optional query parameter "maxX" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxX", ctx.QueryParams(), &params.MaxX)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter maxX: %s", err))
+	}
+
+	// ------------- Optional query parameter "minY" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minY", ctx.QueryParams(), &params.MinY)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter minY: %s", err))
+	}
+
+	// ------------- Optional query parameter "maxY" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxY", ctx.QueryParams(), &params.MaxY)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter maxY: %s", err))
+	}
+
+	// ------------- Optional query parameter "busy" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "busy", ctx.QueryParams(), &params.Busy)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter busy: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", ctx.QueryParams(), &params.Order)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetAllRobots(ctx)
+	err = w.Handler.GetAllRobots(ctx, params)
 	return err
 }
 
@@ -149,6 +644,10 @@ func (w *ServerInterfaceWrapper) GetRobot(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter robotId: %s", err))
 	}
 
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.GetRobot(ctx, robotId)
 	return err
@@ -165,8 +664,38 @@ func (w *ServerInterfaceWrapper) MoveRobot(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter robotId: %s", err))
 	}
 
+	ctx.Set(ApiKeyScopes, []string{"operator"})
+
+	ctx.Set(BearerAuthScopes, []string{"operator"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params MoveRobotParams
+	// ------------- Optional query parameter "acceptance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "acceptance", ctx.QueryParams(), &params.Acceptance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter acceptance: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.MoveRobot(ctx, robotId)
+	err = w.Handler.MoveRobot(ctx, robotId, params)
 	return err
 }
 
@@ -174,8 +703,70 @@ func (w *ServerInterfaceWrapper) MoveRobot(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) GetAllTasks(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAllTasksParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "robotId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "robotId", ctx.QueryParams(), &params.RobotId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter robotId: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdAfter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdAfter", ctx.QueryParams(), &params.CreatedAfter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdAfter: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdBefore" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdBefore", ctx.QueryParams(), &params.CreatedBefore)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdBefore: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", ctx.QueryParams(), &params.Order)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshalled arguments
-	err = w.Handler.GetAllTasks(ctx)
+	err = w.Handler.GetAllTasks(ctx, params)
 	return err
 }
 
@@ -190,6 +781,10 @@ func (w *ServerInterfaceWrapper) CancelTask(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter taskId: %s", err))
 	}
 
+	ctx.Set(ApiKeyScopes, []string{"operator"})
+
+	ctx.Set(BearerAuthScopes, []string{"operator"})
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.CancelTask(ctx, taskId)
 	return err
@@ -206,15 +801,77 @@ func (w *ServerInterfaceWrapper) GetTask(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter taskId: %s", err))
 	}
 
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.GetTask(ctx, taskId)
 	return err
 }
 
+// CreateTaskBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateTaskBatch(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{"operator"})
+
+	ctx.Set(BearerAuthScopes, []string{"operator"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateTaskBatchParams
+	// ------------- Optional query parameter "acceptance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "acceptance", ctx.QueryParams(), &params.Acceptance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter acceptance: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateTaskBatch(ctx, params)
+	return err
+}
+
+// GetWarehouse converts echo context to params.
+func (w *ServerInterfaceWrapper) GetWarehouse(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetWarehouse(ctx)
+	return err
+}
+
 // RobotsWebsocket converts echo context to params.
 func (w *ServerInterfaceWrapper) RobotsWebsocket(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(AccessTokenScopes, []string{"viewer"})
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.RobotsWebsocket(ctx)
 	return err
@@ -224,6 +881,12 @@ func (w *ServerInterfaceWrapper) RobotsWebsocket(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) TasksWebsocket(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(AccessTokenScopes, []string{"viewer"})
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
 	// Invoke the callback with all the unmarshalled arguments
 	err = w.Handler.TasksWebsocket(ctx)
 	return err
@@ -258,12 +921,17 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	}
 
 	router.GET(baseURL+"/", wrapper.Dashboard)
+	router.DELETE(baseURL+"/api/batches/:batchId", wrapper.CancelTaskBatch)
+	router.GET(baseURL+"/api/batches/:batchId", wrapper.GetTaskBatch)
+	router.GET(baseURL+"/api/health", wrapper.GetHealth)
 	router.GET(baseURL+"/api/robots", wrapper.GetAllRobots)
 	router.GET(baseURL+"/api/robots/:robotId", wrapper.GetRobot)
 	router.PUT(baseURL+"/api/robots/:robotId", wrapper.MoveRobot)
 	router.GET(baseURL+"/api/tasks", wrapper.GetAllTasks)
 	router.DELETE(baseURL+"/api/tasks/:taskId", wrapper.CancelTask)
 	router.GET(baseURL+"/api/tasks/:taskId", wrapper.GetTask)
+	router.POST(baseURL+"/api/tasks:batch", wrapper.CreateTaskBatch)
+	router.GET(baseURL+"/api/warehouse", wrapper.GetWarehouse)
 	router.GET(baseURL+"/ws/robots", wrapper.RobotsWebsocket)
 	router.GET(baseURL+"/ws/tasks", wrapper.TasksWebsocket)
 
@@ -272,22 +940,76 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xYS2/bOBD+K8TsHoXI22YvvnkdITCQuoYdoIciB1oa22wlUiFHyRqB/vuCpB6OJT+y",
-	"TRcGtpfE1gw538x885BfIFZZriRKMjB8gZxrniGhdt+0WiqaJPZjgibWIiehJAzhfoPMCVkhxWOBTCQo",
-	"SawEaghAWJWc0wYCkDxDGDY3BaDxsRAaExiSLjAAE28w49YEbXOrKiThGjWUZQDEzfdD9q3sXPPVPW+y",
-	"XtZSFwrUWmkXIa1y1CTQPY5Vgl14kVVmThZ0Lg4gQ2P4+uC5WtwcNaSFXLuAtPi/QnV/rf7Q6KvlN4zJ",
-	"WVJPOLehn+NjgYa6DliNhRXK2D8QhJn3WBaZNTOFABYQQAQBfNmxUqNqHnCt+baD8rWBEyBNrqTBLkqb",
-	"P/v/d40rGMJvYcvasEpS6HT2rbuHfUYdIbuGRNLHhQD+nikjfJb6xNtj4j1IwvKwvW/3cB/Q2vXzcBri",
-	"VLxK33geje6jGwhgMp3NP9/Oo4XN5vjzp9ld5AXj0XQc3d1FNz3Z7QNfGemitdpCrpTDJii1MpdZNppN",
-	"IIAn1MYzfXA1uPrDAlY5Sp4LGMLHq8HVRwhc5ToPQvtnjS5L1nduY2S7Adxws1kqrn1Fe9K4Ix8Gg25V",
-	"zZEKLQ3j7BmXLGnOugovsozr7QmtkOcidIwxBzHdIo3SdO6V+mHFShJKd5bneSpidzr8Zjxv2l7U1OAx",
-	"vnsGd8uvDPb9d5hYgsRFauyJP9+I5xgM3xh7zE4koZY8ZQvUT6hZVCl2g85ogywVhphaMZ6mfrQY9ixo",
-	"Y2VCs7jQGiWxinp7SQlfqvlSHkuPi4MjWDvjvvY716qE9eQqH34wqWfk8kDudlN3Pbj++ambKmIrVcjk",
-	"oshyi8Qa0udFT5I/1ZPkB7PsZuVfKtm+m+OdQVy+7qx2Gyk7BPvwM+xXM7YnCTZ8zDOuVftfU85FpC7O",
-	"quXYgXxqDNw7nf9iCvit5/QQcIgucgbYsrZN38f1dZjDF7+5l36qp0jYDfmYyxhdyN9c9tV7QU9vv+7u",
-	"EVPF6mhdUvy8+6wmwiFWvnN83s/5am/vZeyvydeUSPN6Ez6bU7uo3/i+4NKo+DvSWVvyWCMnNHb7rU61",
-	"Efi3i121Tfv7GG24XeA08sy4la9a83Z3umdzor+6PnZpnu06VW+qupBSyHXT1tyr3NpXXd+ON5pN2CLH",
-	"uP3Rwm8y5UP5TwAAAP//UBqFT6URAAA=",
+	"H4sIAAAAAAAC/+w8XXPbOJJ/BcW7R9pWHGerzm+OZ2bPczeZnO0qz1aUu4HIlokxCTAAaFmb0n+/6gbA",
+	"D5GU6Intyu76JbGEj250N/ob+holqiiVBGlNdPo1yoCnoOnPa27uDi7Sg5+ULrjFb1IwiRalFUpGp9F1",
+	"BmxJY0wtmc2AWW7umEhNzIxUq2XO72gG+8vJwULY1pciNYcM9zcs0cAtpGyViRxwl7kUqWEr0MAMfKlA",
+	"WsFzdgdQ4qjQDgCCc7iyJOPyFgxbZSDhHjSzHcQkrHAJSxWYuYziyCQZFBzPA7IqotNPUY1Y9DmO7LqE",
+	"6DQyVgt5G202cfTbwQd4sAfnlTZKD9MhobFABwkPlpX8FmJWCGOEvGVK0kjOjRvp4DEE8lpZnh+cq0qO",
+	"kF5WxQIIpLBQGFZwm2QIio4vcgvaDEER0sIt6GiDcEqueQHWc5wnCZSWywT6IG8ysBkSV7EVF5b4ipCM",
+	"KKqcW0UjboNaFmKWwpJXuTU4iN82EOYyUXIpbisNaaAOLwVxSCDALxXodRRHkheIdrNykIXcrGWCI/jf",
+	"IBcXSJ+LdJiYNMgqKb5UKJ0odEsBOuBScps1qISd4kjDl0poSKNTqyvYzdJFZdZ94L/KfM1yYZBEGTCt",
+	"FsqiKIskYxm/h5gpTX8wqWzMuLtjblwY/JIthRQmg5StwY4Qj2APoLdQKgcuCT9/Ec+WFvRePG3n7uJF",
+	"04zjSmYzYZgVBYyg0gHTRmnp1UyUcgsHfoc+Gf3697BUGh6J54IWTUXRg/gzOI5oipsMCDyQCmDGcm29",
+	"LuvomKDZvDopNdwLVRladchQXhXdxeb2zmVRGUvi4NQhW2pVMCU9KKtqxTR6wzzWu6VYpFCUyoJM1v8F",
+	"A/L8C78DL8qovI1lhi8Jvgar17EfMqWSBsIBl0Ib2ywAadlK2IzG7mDNhJlLDWXO15CGo2gogVvjJnLJ",
+	"LgJi9uAyTHVkbA7sPjcnvmgOc4CnaR+94A//DfLWZtHp8bt3Q1zORSFGdHPBH0RRFT0dHRhKpqGtG9/M",
+	"ZmS/GA+mRBh2K+5BHrIf70Gv5xJ3wG9RuMlegmQSBAkCZ4QMk0r3dxhluDvA1qER7+j0zWw2i6NCSP8x",
+	"7luPGGf/NlmjeTY9sFIZgVNZDgancInKA75UPHe8FQbldgRngrnTqhFef3scXmz97Wj9bS9aQn4LuW5J",
+	"K+nHo4Zg96P2DRT7Bsz2Ek3pFEZ8LhryF4trYEZpvBhCdq8WN8kIfLf3sDeBaxDksC9BpBjzJWhwqi8R",
+	"dprgS7TJQsuulB5zywXkaWBYizaLdZc2Ih0hDc4fpAytePjoWR/F0br+e5BSxnJbmYl22it9YZhfNoJc",
+	"GGzQ+3cNy+g0+rejJpo5cqPmCPe+cksQI/w4xjoc63MuZgZkSniK9IrO1ol4uGHCDLPXA9vF3dqnENL+",
+	"5SQa1LS4zeWYzA2SkvATxsnACB0b2dspa0S/PaLmgD69pAVv0Q5JF94D70kYT8mFSFOQ+CFR0oKLnXhZ",
+	"5iLhiPTRH0bR8DTRAa2Vj5SG7nleezAoIKCDR87zXK3ASYwqQRPoaBNHleSVzZQWf4f0hZD0TlXCtRaA",
+	"6LFEQ+qiaoPaWsh7nguMwQCFuJUAuLm5OTirbIaTE26hi1KPG3TdHU44vtDqDvR/As/Rh/oalRpJYYXj",
+	"VSpMoqSExJpJlwCF5/3awvTZv5jbqZMxKv+RqNg/Vxypyj4GsqrsI0BreCQZjPDh+ZRYxOlfaF8rDw7w",
+	"djVMoI9JrgykIxav1l+f/J4BlbjDy86JajY0VGn42CJsA1Et/oDEIuIJ5HlfbBrLM6Cr2sZoxGy2TrHP",
+	"iDWoQBCNLi6JSgcCUJIjRmND7CvAGH47ui4M72OB3z9MH8I6G7l57l7uUyqd24uJomqRC5OdC51UQ1EP",
+	"GthaGyZuFlto4Heg2QLsCqDO8TAuU/bh7PoqihuxdLIXR6oE5EjG8+Wv+OdeafTn6aE4RJNC3QPZ0Uun",
+	"FvvUwRlXOCgT9wX5l+0L9CGKI8T8xyiObgbQq7/gWvN1D90ugD1IOuPWxxLN7RS3pwedvhwC6vyEvoxX",
+	"WoO01/t8pjo+QBNY8LuQh8SzoDMSxVN0W8bNufb6aiT7WMMJBo2zRDt1tJ3TwmTFsJro6PttQOsWEJzI",
+	"llzkLu+ApxlSsjjtCkCe2UHM5faOGkrykmK28ucSljZP0SBLZdvk2qnev1RQQYrsGckUf6gzELZOG7U8",
+	"xICWSyhyDZ2M4rABsoMc+slRqU7ju5PiobqQHDVjhilJP1tJcCkS41HbSmy21IRIc6SDz2e6vQbv4BMa",
+	"ih0hT1zbwm1GtIS5Ix9Dd8+UkAienw9avDsh07b6+SiSu6qM4ugHrUq1xJt1nnGNiD8zHcZIQBgOnSto",
+	"qS37sz8PX0dWK94qEAkKrHonbEKEyU4Ryk2l4RK4d7R36YBEVXlK8lhg6cqoopbooN0Yr5UEHnkIohfl",
+	"CRoCz5zilahToARmskoQA6Q9GyrBYVjrrmq30ufTpi7RjJUzeEgAUnb8v+/exr5C50TEsJ/5Pb8iQD7X",
+	"aVim8pTBA09svo6ZUcxUScaSXIC0hmlVuVC6YEbkIG2+RnfA56818LSJsYU0Fng6l+2z73D5/bphyRJp",
+	"4FqI2TlLIREFz5kn3gAxn94diKOydeV2GXDygLvJpkFtrO0kuSp4CqRjXbbd27LpUURl9iHcTrTEkdUc",
+	"FYHS62GGBDL0LVGOeDJ4gKSyPop21cQ7qVaSKZlAw0hh2naiZs8Uwu7001zCIohU3CSdmqxJVzjamqhz",
+	"+jHV+B51XV8/dpyWlhShZexI4H7nb8oR3b47cRx1lvs4PbczvfM2bB1vjFGDPjd/uHC4vfGFj/BxDw13",
+	"k++qvjfhwP9DfsJPSv8gTEkCEEfnTm6iOLqQH7W61WBQnM5VUebgBpx3hV9yieI74vWsuIZMVUMhQwbi",
+	"NtvpIGq1MjFbs1sFxpUPZ+j0uoXsgL0Z1LoujTeUNlkYy5Mcpgvt8L3s+EfTN2stGtpzJVKb7aJGovKq",
+	"kCZmD9sEoaUj9NiSDiJOABYHHrRps3W6viDh+SGptLDrKzwa1J0axlyrOxjwX84kRdlYOaV64M9Xv35g",
+	"K1gwi/PjunVjBQujkjuoGw4WKAKgDVMlSPI/VBXcAXM4l9e+g8MYlqs6B46wNKQ8sYYJe7izg8OY/yMk",
+	"GtLxUmDpdROHv/rHoWqASMKhfEXUV4ZdjTPEENbnZQ27F7ACHfv8q9JEirTA6hCnKXMpTJ2pXWXcthb7",
+	"RgFhGde+4h6YwDx/C3Jn0PeTTY6X0vBzGTbCDfx0czhehv7t4OzjhS9A96iyAK5BYxqWnGf6FDqyop9v",
+	"rqO4R64uv9k9aCxjYE4+8CsmR4BQVNqfOcm5KIiapqaED8vm0lQkjodNAxWF2oRNg3VmbekS0UIuVZ+T",
+	"7zEfhJ6d61E4rRvGKPIc6hJjfIERZO16shxDHWOZv3GMz2XPByUX1AQf9JCde9/TCXnJtXH+w+8i/d15",
+	"78ElJFeV+23msvZQndeK4uCcV8lwAd4jLpuYmgRl7ZzY34Pr8HtwY2ktFZKEncsFT3zhiDp8KgPs/hjT",
+	"ARoYkHiLFKWbe+fUUd4Km0N0GlF6iJ19vIji6B60ceSdHc4O35DqLUHyUkSn0dvD2eHbKKaqFCmOI/zn",
+	"FsgQ1GKL5jT6gZtsobh21apWWeV4Nuuz8hJspSXih4KW1mvb+io6/fQ5jkxVFFyv96w54qU4ougPzNFX",
+	"HyNuHFw0gn18nSW8rh2pbjPbp2Hr0Ew58jCizefeeU8GDINioViziaOT2Zsx+1NvddQp9tCit/sXNTUs",
+	"WnHy/FWiD5hpQflGiO9ms+eHeCEtaMlzdgUa+zVdKo6gv32B855dX+HNqiS/5yLnixziTsNSwiVbAPUr",
+	"odYMbW3AUsj52tsbr0svwer1ATWzsVqtt0porfFdzoaBRMnU1M2V3v5Qy5QPP3a0cW7dua+1Jf0UBesX",
+	"fd7EXzu2pDPWuabuYnVrydxlZpBJg8rjr2Cf5SY+nTQ2EdeATNAAS8FykW8VQftd0ENQ/IKjrdmbzauy",
+	"eB5lsWlL7F/BjohrsCxNMWxMfH2p6xkl0OMwUqhH51YYVtdjURdQjeyJ9eIkLKiPtI/JBNvu+/Jzm7XD",
+	"A05eT3069JTbmxKLXLfSLhad5fmlm/RYDUM9cJt4/zz+MG0edq5N22/SPKpnTJjXtH1NmOza2yZMdH2g",
+	"Eyb6DuFv1tOTAng660AOqye3TiaGtXfv6cYu5d2dPPQKY/fq9uSX1PzfjR52SsDFacLQ6xue553GUfeE",
+	"x5eVQ5vflg44+upTd5td2uDSd7Y9ThP4nZ/X1/CiOyKqtaS++gYv5BvUqqSsBjKgLv1qum9rXGmvLksc",
+	"sptQjfNdjYxeHLUeNLVLEnPp6sFk+I5nx2hTNV0OSJmxmlNKla/42m88tBcaTowGTEjYzeWux1YES3sz",
+	"fDx7E7OT42P3TEBYh8Afzp4rzd7NTtzQ1rapcKVOLs0KNBOS3skcsjPpwfkaJ+4H4ZCqXWbixoGby7DA",
+	"pS669/eX0OPy5y/wfnPV0HPK7K2XLU5BUET4XqXrJxPmXgvSZrPZbg/e9HTTm+eA7yCMuYKNUDSc96L2",
+	"1BHS8ez4Zc+H4sdoFqunvUZ90zT7yew/nh/iWZ2NqbsgDC+Abb3YQjW0AMwol1olYIwn4/Hxy3RXN3ek",
+	"Vq5NtTq0bvhnzKjVm8dxJHtq6UIkSoMyX5XZPiL2g1TGN8DU+WYdtMdrzu4fM2eHlDt5aSkdNu/REycQ",
+	"SbsGH9h79nWrwI7g/prmPNYhCDHEfgvffkUzYXrnpfL0+f7Z8ER8/kWC+ZF+lL64UgbvGRKx8Wsq4MXD",
+	"Loz93b3vqoGjr+5Z3MTy3qM1gtv9ey7svZrs1zJbKLN5zbirrPaEN+BpC2pjKvy1lPYPXUoLL4calX26",
+	"qJtVldmfQ8OEFfAkq+McF+bgHq6bqu6cd70ubhrXwJIMkrv6p1rmkst1nXDyDlbcbIbfhp/ACEkK6uxe",
+	"ZaG3ycdlDiydZS5Nxv3vsDRd4bQfbb0mRAxIG35rpJ12u3UPdnwjkNAOd9zUcm1dZ3s9i1qVW4QQ2639",
+	"hBDzeTR35ibVOJejucZvyCnOZSepOPoDTo6Ejsx7UotzOZZb3Bt7DCUHnTD9+X6C7zLv1+umfuG8387+",
+	"hx35voVnwXeb8Nt7sM5zNy/PGvyle7VQ4/biV9l9u2coFyVfk4GPSgZ6w8IbE+Bc28L9FBl3vcudRCHO",
+	"Hsoc8te84Wve8J83b+jsfmjeItXTzR50nreMBUw39aRnDH4aTAZoV2Pwz1rp7wpH5o2C//0FcK1eNYXo",
+	"ZymH+kSMdxD/DsGbZjlfq6qOF5otup4kajb/7pGUmxOQldnXQuYahW7Cq5dJfe4hsKnfyrx8lmhyENe5",
+	"ie0HQp8i9xrGXcbmkra/7V7RemS8gd+RAy2YZcZq4EXnZ+LarT0rsyf/T2nfV8Y8KWPaPAn9VrqSkn6a",
+	"w+vVcf09DQPLb11ENtTmdPbxgl2VkDQPnS5dTejz5v8HAMqXoN12WgAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,973 @@
+// Package robotapiv2server provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/deepmap/oapi-codegen version (devel) DO NOT EDIT.
+package robotapiv2server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/deepmap/oapi-codegen/pkg/runtime"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ApiKeyScopes     = "apiKey.Scopes"
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for BrokerHealthState.
+const (
+	BrokerHealthStateClosed       BrokerHealthState = "closed"
+	BrokerHealthStateConnected    BrokerHealthState = "connected"
+	BrokerHealthStateDisconnected BrokerHealthState = "disconnected"
+)
+
+// Defines values for HealthPublishCircuit.
+const (
+	HealthPublishCircuitClosed   HealthPublishCircuit = "closed"
+	HealthPublishCircuitHalfOpen HealthPublishCircuit = "halfOpen"
+	HealthPublishCircuitOpen     HealthPublishCircuit = "open"
+)
+
+// Defines values for Move.
+const (
+	E Move = "E"
+	N Move = "N"
+	S Move = "S"
+	W Move = "W"
+)
+
+// Defines values for RobotState.
+const (
+	RobotStateBusy   RobotState = "busy"
+	RobotStateFailed RobotState = "failed"
+	RobotStateIdle   RobotState = "idle"
+)
+
+// Defines values for SpecialCellKind.
+const (
+	Charger SpecialCellKind = "charger"
+	Dropoff SpecialCellKind = "dropoff"
+	Pickup  SpecialCellKind = "pickup"
+)
+
+// Defines values for TaskStatus.
+const (
+	TaskStatusCancelled         TaskStatus = "cancelled"
+	TaskStatusCompleted         TaskStatus = "completed"
+	TaskStatusCreated           TaskStatus = "created"
+	TaskStatusFailed            TaskStatus = "failed"
+	TaskStatusInProgress        TaskStatus = "inProgress"
+	TaskStatusQueuedForDispatch TaskStatus = "queuedForDispatch"
+)
+
+// Defines values for Acceptance.
+const (
+	Async Acceptance = "async"
+	Sync  Acceptance = "sync"
+)
+
+// Defines values for Order.
+const (
+	Asc  Order = "asc"
+	Desc Order = "desc"
+)
+
+// Defines values for RobotSort.
+const (
+	RobotSortId RobotSort = "id"
+	RobotSortX  RobotSort = "x"
+	RobotSortY  RobotSort = "y"
+)
+
+// Defines values for TaskSort.
+const (
+	TaskSortCreatedAt TaskSort = "createdAt"
+	TaskSortId        TaskSort = "id"
+)
+
+// BrokerHealth defines model for brokerHealth.
+type BrokerHealth struct {
+	Disconnects int64             `json:"disconnects"`
+	InBytes     int64             `json:"inBytes"`
+	InMsgs      int64             `json:"inMsgs"`
+	LastError   *string           `json:"lastError,omitempty"`
+	OutBytes    int64             `json:"outBytes"`
+	OutMsgs     int64             `json:"outMsgs"`
+	Reconnects  int64             `json:"reconnects"`
+	Since       time.Time         `json:"since"`
+	State       BrokerHealthState `json:"state"`
+}
+
+// BrokerHealthState defines model for BrokerHealth.State.
+type BrokerHealthState string
+
+// Health defines model for health.
+type Health struct {
+	Broker BrokerHealth `json:"broker"`
+
+	// State of the circuit breaker between the api and NATS
+	PublishCircuit HealthPublishCircuit `json:"publishCircuit"`
+}
+
+// State of the circuit breaker between the api and NATS
+type HealthPublishCircuit string
+
+// Move defines model for move.
+type Move string
+
+// A cell of the warehouse
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Problem details, see RFC 7807
+type Problem struct {
+	// What went wrong with this request
+	Detail *string `json:"detail,omitempty"`
+
+	// The path of the request
+	Instance *string `json:"instance,omitempty"`
+
+	// The http status code
+	Status int `json:"status"`
+
+	// A short summary of the problem type
+	Title string `json:"title"`
+
+	// A URI reference which identifies the problem type, about:blank when the status is enough
+	Type string `json:"type"`
+}
+
+// Robot defines model for robot.
+type Robot struct {
+	// The task the robot is making the moves of
+	CurrentTaskId *string `json:"currentTaskId,omitempty"`
+
+	// Whether the robot carries a crate
+	HasCrate bool   `json:"hasCrate"`
+	Id       string `json:"id"`
+
+	// Why the robot last failed to move
+	LastError *string `json:"lastError,omitempty"`
+
+	// When the robot last reported, whether it moved or not
+	LastSeenAt time.Time `json:"lastSeenAt"`
+
+	// A cell of the warehouse
+	Position Position `json:"position"`
+
+	// Number of the other tasks of the robot which are not finished
+	QueuedTaskCount int `json:"queuedTaskCount"`
+
+	// Failed while the last move of the robot failed, busy while one of its tasks is not finished
+	State RobotState `json:"state"`
+}
+
+// RobotPage defines model for robotPage.
+type RobotPage struct {
+	Items []Robot `json:"items"`
+
+	// The cursor of the next page, missing on the last page
+	NextCursor *string `json:"nextCursor,omitempty"`
+
+	// The number of robots matching the filters
+	TotalCount int `json:"totalCount"`
+}
+
+// Failed while the last move of the robot failed, busy while one of its tasks is not finished
+type RobotState string
+
+// SpecialCell defines model for specialCell.
+type SpecialCell struct {
+	Kind SpecialCellKind `json:"kind"`
+
+	// A cell of the warehouse
+	Position Position `json:"position"`
+}
+
+// SpecialCellKind defines model for SpecialCell.Kind.
+type SpecialCellKind string
+
+// Task defines model for task.
+type Task struct {
+	// The batch the task was created with
+	BatchId   *string   `json:"batchId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Why the robot could not make some of the moves of a failed task
+	Failure *TaskFailure `json:"failure,omitempty"`
+
+	// When the robot was done with the moves
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Id         string     `json:"id"`
+	Moves      []Move     `json:"moves"`
+
+	// A cell of the warehouse
+	Position *Position `json:"position,omitempty"`
+	RobotId  string    `json:"robotId"`
+
+	// When the robot made its first move
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	Status    TaskStatus `json:"status"`
+
+	// The positions of the robot while it executed the task, known once the task is finished
+	Trajectory []Position `json:"trajectory"`
+}
+
+// TaskBatch defines model for taskBatch.
+type TaskBatch struct {
+	Id    string `json:"id"`
+	Tasks []Task `json:"tasks"`
+}
+
+// TaskBatchRequest defines model for taskBatchRequest.
+type TaskBatchRequest struct {
+	Tasks []TaskRequest `json:"tasks"`
+}
+
+// Why the robot could not make some of the moves of a failed task
+type TaskFailure struct {
+	// A cell of the warehouse
+	Position *Position `json:"position,omitempty"`
+	Reason   string    `json:"reason"`
+}
+
+// TaskPage defines model for taskPage.
+type TaskPage struct {
+	Items []Task `json:"items"`
+
+	// The cursor of the next page, missing on the last page
+	NextCursor *string `json:"nextCursor,omitempty"`
+
+	// The number of tasks matching the filters
+	TotalCount int `json:"totalCount"`
+}
+
+// TaskRequest defines model for taskRequest.
+type TaskRequest struct {
+	Moves   []Move `json:"moves"`
+	RobotId string `json:"robotId"`
+}
+
+// TaskStatus defines model for taskStatus.
+type TaskStatus string
+
+// Warehouse defines model for warehouse.
+type Warehouse struct {
+	// Number of rows, y goes from 0 to height - 1
+	Height       int           `json:"height"`
+	Name         string        `json:"name"`
+	Obstacles    []Position    `json:"obstacles"`
+	SpecialCells []SpecialCell `json:"specialCells"`
+
+	// Number of columns, x goes from 0 to width - 1
+	Width int `json:"width"`
+}
+
+// Acceptance defines model for acceptance.
+type Acceptance string
+
+// BatchId defines model for batchId.
+type BatchId = string
+
+// CreatedAfter defines model for createdAfter.
+type CreatedAfter = time.Time
+
+// CreatedBefore defines model for createdBefore.
+type CreatedBefore = time.Time
+
+// Cursor defines model for cursor.
+type Cursor = string
+
+// IdempotencyKey defines model for idempotencyKey.
+type IdempotencyKey = string
+
+// Limit defines model for limit.
+type Limit = int
+
+// Order defines model for order.
+type Order string
+
+// RobotId defines model for robotId.
+type RobotId = string
+
+// RobotSort defines model for robotSort.
+type RobotSort string
+
+// TaskBatchId defines model for taskBatchId.
+type TaskBatchId = string
+
+// TaskId defines model for taskId.
+type TaskId = string
+
+// TaskRobotId defines model for taskRobotId.
+type TaskRobotId = string
+
+// TaskSort defines model for taskSort.
+type TaskSort string
+
+// ListRobotsParams defines parameters for ListRobots.
+type ListRobotsParams struct {
+	// Only lists the robots in this state
+	State *RobotState `form:"state,omitempty" json:"state,omitempty"`
+
+	// The field robots are sorted by, defaults to id
+	Sort *ListRobotsParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// The order items are sorted in, defaults to asc
+	Order *ListRobotsParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// The maximum number of items of the page, defaults to 100
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Where the page starts, the nextCursor of the previous page. The other parameters must not
+	// change from one page to the next
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListRobotsParamsSort defines parameters for ListRobots.
+type ListRobotsParamsSort string
+
+// ListRobotsParamsOrder defines parameters for ListRobots.
+type ListRobotsParamsOrder string
+
+// ListTasksParams defines parameters for ListTasks.
+type ListTasksParams struct {
+	// Only lists the tasks with this status
+	Status *TaskStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Only lists the tasks of this robot
+	RobotId *TaskRobotId `form:"robotId,omitempty" json:"robotId,omitempty"`
+
+	// Only lists the tasks of this batch
+	BatchId *TaskBatchId `form:"batchId,omitempty" json:"batchId,omitempty"`
+
+	// Only lists the tasks created at or after this time
+	CreatedAfter *CreatedAfter `form:"createdAfter,omitempty" json:"createdAfter,omitempty"`
+
+	// Only lists the tasks created before this time
+	CreatedBefore *CreatedBefore `form:"createdBefore,omitempty" json:"createdBefore,omitempty"`
+
+	// The field tasks are sorted by, defaults to createdAt
+	Sort *ListTasksParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// The order items are sorted in, defaults to asc
+	Order *ListTasksParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// The maximum number of items of the page, defaults to 100
+	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Where the page starts, the nextCursor of the previous page. The other parameters must not
+	// change from one page to the next
+	Cursor *Cursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListTasksParamsSort defines parameters for ListTasks.
+type ListTasksParamsSort string
+
+// ListTasksParamsOrder defines parameters for ListTasks.
+type ListTasksParamsOrder string
+
+// CreateTaskJSONBody defines parameters for CreateTask.
+type CreateTaskJSONBody = TaskRequest
+
+// CreateTaskParams defines parameters for CreateTask.
+type CreateTaskParams struct {
+	// Whether to wait for the simulator to accept the task, defaults to the acceptance
+	// configured on the api
+	Acceptance *CreateTaskParamsAcceptance `form:"acceptance,omitempty" json:"acceptance,omitempty"`
+
+	// Makes the request safe to retry, the response of the first request sent with the key is
+	// replayed to the repeats with an Idempotent-Replayed header
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateTaskParamsAcceptance defines parameters for CreateTask.
+type CreateTaskParamsAcceptance string
+
+// CreateTaskBatchJSONBody defines parameters for CreateTaskBatch.
+type CreateTaskBatchJSONBody = TaskBatchRequest
+
+// CreateTaskBatchParams defines parameters for CreateTaskBatch.
+type CreateTaskBatchParams struct {
+	// Whether to wait for the simulator to accept the task, defaults to the acceptance
+	// configured on the api
+	Acceptance *CreateTaskBatchParamsAcceptance `form:"acceptance,omitempty" json:"acceptance,omitempty"`
+
+	// Makes the request safe to retry, the response of the first request sent with the key is
+	// replayed to the repeats with an Idempotent-Replayed header
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// CreateTaskBatchParamsAcceptance defines parameters for CreateTaskBatch.
+type CreateTaskBatchParamsAcceptance string
+
+// CreateTaskJSONRequestBody defines body for CreateTask for application/json ContentType.
+type CreateTaskJSONRequestBody = CreateTaskJSONBody
+
+// CreateTaskBatchJSONRequestBody defines body for CreateTaskBatch for application/json ContentType.
+type CreateTaskBatchJSONRequestBody = CreateTaskBatchJSONBody
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Get the tasks of a batch
+	// (GET /api/v2/batches/{batchId})
+	GetTaskBatch(ctx echo.Context, batchId BatchId) error
+	// Cancel the tasks of a batch which are not finished yet
+	// (POST /api/v2/batches/{batchId}/cancel)
+	CancelTaskBatch(ctx echo.Context, batchId BatchId) error
+	// Returns the health of the api and its connection to NATS
+	// (GET /api/v2/health)
+	GetHealth(ctx echo.Context) error
+	// Returns a page of the robots with their current status
+	// (GET /api/v2/robots)
+	ListRobots(ctx echo.Context, params ListRobotsParams) error
+	// Get robot
+	// (GET /api/v2/robots/{robotId})
+	GetRobot(ctx echo.Context, robotId RobotId) error
+	// Returns a page of the tasks
+	// (GET /api/v2/tasks)
+	ListTasks(ctx echo.Context, params ListTasksParams) error
+	// Create a task which moves a robot
+	// (POST /api/v2/tasks)
+	CreateTask(ctx echo.Context, params CreateTaskParams) error
+	// Get task
+	// (GET /api/v2/tasks/{taskId})
+	GetTask(ctx echo.Context, taskId TaskId) error
+	// Cancel a task
+	// (POST /api/v2/tasks/{taskId}/cancel)
+	CancelTask(ctx echo.Context, taskId TaskId) error
+	// Create a batch of tasks
+	// (POST /api/v2/tasks:batch)
+	CreateTaskBatch(ctx echo.Context, params CreateTaskBatchParams) error
+	// Returns the size and the layout of the warehouse the simulator was started with
+	// (GET /api/v2/warehouse)
+	GetWarehouse(ctx echo.Context) error
+}
+
+// ServerInterfaceWrapper converts echo contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler ServerInterface
+}
+
+// GetTaskBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTaskBatch(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "batchId" -------------
+	var batchId BatchId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "batchId", runtime.ParamLocationPath, ctx.Param("batchId"), &batchId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter batchId: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetTaskBatch(ctx, batchId)
+	return err
+}
+
+// CancelTaskBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) CancelTaskBatch(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "batchId" -------------
+	var batchId BatchId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "batchId", runtime.ParamLocationPath, ctx.Param("batchId"), &batchId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter batchId: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{"operator"})
+
+	ctx.Set(BearerAuthScopes, []string{"operator"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CancelTaskBatch(ctx, batchId)
+	return err
+}
+
+// GetHealth converts echo context to params.
+func (w *ServerInterfaceWrapper) GetHealth(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetHealth(ctx)
+	return err
+}
+
+// ListRobots converts echo context to params.
+func (w *ServerInterfaceWrapper) ListRobots(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListRobotsParams
+	// ------------- Optional query parameter "state" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "state", ctx.QueryParams(), &params.State)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter state: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", ctx.QueryParams(), &params.Order)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListRobots(ctx, params)
+	return err
+}
+
+// GetRobot converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRobot(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "robotId" -------------
+	var robotId RobotId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "robotId", runtime.ParamLocationPath, ctx.Param("robotId"), &robotId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter robotId: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetRobot(ctx, robotId)
+	return err
+}
+
+// ListTasks converts echo context to params.
+func (w *ServerInterfaceWrapper) ListTasks(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListTasksParams
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", ctx.QueryParams(), &params.Status)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "robotId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "robotId", ctx.QueryParams(), &params.RobotId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter robotId: %s", err))
+	}
+
+	// ------------- Optional query parameter "batchId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "batchId", ctx.QueryParams(), &params.BatchId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter batchId: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdAfter" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdAfter", ctx.QueryParams(), &params.CreatedAfter)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdAfter: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdBefore" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdBefore", ctx.QueryParams(), &params.CreatedBefore)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdBefore: %s", err))
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", ctx.QueryParams(), &params.Order)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter order: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.ListTasks(ctx, params)
+	return err
+}
+
+// CreateTask converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateTask(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{"operator"})
+
+	ctx.Set(BearerAuthScopes, []string{"operator"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateTaskParams
+	// ------------- Optional query parameter "acceptance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "acceptance", ctx.QueryParams(), &params.Acceptance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter acceptance: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateTask(ctx, params)
+	return err
+}
+
+// GetTask converts echo context to params.
+func (w *ServerInterfaceWrapper) GetTask(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "taskId" -------------
+	var taskId TaskId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "taskId", runtime.ParamLocationPath, ctx.Param("taskId"), &taskId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter taskId: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetTask(ctx, taskId)
+	return err
+}
+
+// CancelTask converts echo context to params.
+func (w *ServerInterfaceWrapper) CancelTask(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "taskId" -------------
+	var taskId TaskId
+
+	err = runtime.BindStyledParameterWithLocation("simple", false, "taskId", runtime.ParamLocationPath, ctx.Param("taskId"), &taskId)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter taskId: %s", err))
+	}
+
+	ctx.Set(ApiKeyScopes, []string{"operator"})
+
+	ctx.Set(BearerAuthScopes, []string{"operator"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CancelTask(ctx, taskId)
+	return err
+}
+
+// CreateTaskBatch converts echo context to params.
+func (w *ServerInterfaceWrapper) CreateTaskBatch(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{"operator"})
+
+	ctx.Set(BearerAuthScopes, []string{"operator"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateTaskBatchParams
+	// ------------- Optional query parameter "acceptance" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "acceptance", ctx.QueryParams(), &params.Acceptance)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter acceptance: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKey
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for Idempotency-Key, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter Idempotency-Key: %s", err))
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+	}
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.CreateTaskBatch(ctx, params)
+	return err
+}
+
+// GetWarehouse converts echo context to params.
+func (w *ServerInterfaceWrapper) GetWarehouse(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(ApiKeyScopes, []string{"viewer"})
+
+	ctx.Set(BearerAuthScopes, []string{"viewer"})
+
+	// Invoke the callback with all the unmarshalled arguments
+	err = w.Handler.GetWarehouse(ctx)
+	return err
+}
+
+// This is a simple interface which specifies echo.Route addition functions which
+// are present on both echo.Echo and echo.Group, since we want to allow using
+// either of them for path registration
+type EchoRouter interface {
+	CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+	TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route
+}
+
+// RegisterHandlers adds each server route to the EchoRouter.
+func RegisterHandlers(router EchoRouter, si ServerInterface) {
+	RegisterHandlersWithBaseURL(router, si, "")
+}
+
+// Registers handlers, and prepends BaseURL to the paths, so that the paths
+// can be served under a prefix.
+func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL string) {
+
+	wrapper := ServerInterfaceWrapper{
+		Handler: si,
+	}
+
+	router.GET(baseURL+"/api/v2/batches/:batchId", wrapper.GetTaskBatch)
+	router.POST(baseURL+"/api/v2/batches/:batchId/cancel", wrapper.CancelTaskBatch)
+	router.GET(baseURL+"/api/v2/health", wrapper.GetHealth)
+	router.GET(baseURL+"/api/v2/robots", wrapper.ListRobots)
+	router.GET(baseURL+"/api/v2/robots/:robotId", wrapper.GetRobot)
+	router.GET(baseURL+"/api/v2/tasks", wrapper.ListTasks)
+	router.POST(baseURL+"/api/v2/tasks", wrapper.CreateTask)
+	router.GET(baseURL+"/api/v2/tasks/:taskId", wrapper.GetTask)
+	router.POST(baseURL+"/api/v2/tasks/:taskId/cancel", wrapper.CancelTask)
+	router.POST(baseURL+"/api/v2/tasks:batch", wrapper.CreateTaskBatch)
+	router.GET(baseURL+"/api/v2/warehouse", wrapper.GetWarehouse)
+
+}
+
+// Base64 encoded, gzipped, json marshaled Swagger object
+var swaggerSpec = []string{
+
+	"H4sIAAAAAAAC/+xcW3PbuPX/Khj8/w/tlLFlbdJt/eakSevtbjZje5vORHmAyCMRKxLgAqBlrkffvXNw",
+	"4UUiJcrrONnLUywSl985ODh35p7GMi+kAGE0Pb+nBVMsBwPK/mJxDIVhIgb8lYCOFS8Ml4Ke0/cpmBQU",
+	"MZKsGTdkIRUxKRDN8zJjRto3bgH73DC9ikgCC1ZmRuNLfNrsMBOxFAu+LBUkRAr3tuAzQSPKccOfSlAV",
+	"jahgOdDzNraI6jiFnCFIEGVOzz9QpisR4xv852NETVXgLG0UF0u62UR0zkycXia7lN2kQOxLUgr+UwmE",
+	"JyAMX3BQAUvBTNpACStFVMFPJVeQ0HOjSmjj2t0/VsAMJBcLA2oXxPciq0jGtdE19zTxUwgzRCrCcCYx",
+	"KdfE8BwG+NTZpo1oIVXODD2nCTPwzK8wCPMlLKSCI3HO7aSxEP0WD8FYKi1Vr4za7YEUbAlEG6aMjuwD",
+	"AXfmlZ1G5MINUXDLZant2BOCUiCthDd3guSlNkRIMxNxysQSyELJnEjhNzCyXntQbj3W/bLBE8gLaUDE",
+	"1b+h2iXsO7YCx3CUONCGaLaw+yswqor8K11IoSEQuOBKm2YCCEPW3KT23QoqwvVMKCgyVkESSFFQADPa",
+	"DWSCXAZg5tlVGJoCS0A1BLvfDcWXDTHPkJo26Tm7+xbE0qT0fPriRd/ZZjznpv+S5uyO52VORJnPwZ4j",
+	"N5Dr+kDZEroa52wyGTgVt8sWMlycnp9NJpOI5lz4nzVILgwsQVmUUiWg+lHaVx4ZU0C0VHg3uOhiYzoe",
+	"wObW7ldyOAe37FdxSs6lGVJx9uVYFRdWOk7F2VnXUg2c34JDljgcHdbMqy5reDLAGRzfyxg7445GtNrD",
+	"mGvDzGGN5uFx4dSYtpMG4Ph3DZ7/V7Cg5/T/Thsre+re6tMWBoSEmvPlkEnq1bJWzLl2tmoAUmOa9p0T",
+	"rjckJvhurJT4dY4TEpx0NSSoewm3HBwgvBHYQ3sfkk+35x7xDBbWHCulnYlJv6RahIaZUo9kjlfpXlRL",
+	"vUdWS92BtU9YWzA29gJ522JRzVly5awK/oqlMCDsn6woMh4zhHtaKDnPIP/Ljxqx34/c189ym/boMG/M",
+	"OF7QW5bxhKI7IMUi4/GTormosdQ2VbMcyJbxQ6Rz4GJJCiVj0Bos4oVUc54kIJ6cgTKr/QMNwloqTYQ0",
+	"hGWZXKMjgCasAGVBINYlM7Bm1Q3PQZZPfuJNeJHwxAEVeo2whXMx0XsSBpRg2TWoW1CvlZLqKWFe+u2J",
+	"2584AJuICmneyFIkTwnmrcTQDDfdRFSDuuUx/CDYLeMZm2fwpFAubq5Ruspm+6jjw8ZMkDlYF5ZjlOPj",
+	"GyAJZKwiS34Lgswr++gK/dxnNqohtcPp/rA6qfV+V3G+rR1GDbEUia6jWB+xWC8aVXCP+WjcPiSxFKw0",
+	"qVT8Z0g+l/aLmVIc8NqiLbI2mmUaQ0SvFIkUoLv8ef/+/bOL0qQ4OPZ+0LCldJR6lYUH91oYbqrPd/kV",
+	"/Aix8erJpRYYyeUtEI1METE+xRiJeT9XLpySm0umkqh+jDGPk6vcRapM2HCuu5RPbWzr8jXTpNSQhOjI",
+	"hYv+VGbC2m9PmTWTSq5A/QtYhuHOPS2ULEAZ7oxownUshYDY6E7ky4X563O6G3SgmntZGRg/+ju9HDs4",
+	"Y9rUenNLGCIqS3PMzrI0R2yt4Eg2aO7zU2OSBZF30ttemNsOEhq1DsH+jDOpYcAxa1zcD43jz106qn2W",
+	"HYrqY2i40pxji7HNjnKOgo7A0wHBcWJ16GZ1hG8T0aKcZ1ynr7iKy7742gYmwTOI3SgyV8BWoMgczBqg",
+	"ztERJhKC2p1GDVcd6yIqCxCoeli2+B7/PMhMT88OxD6e4DVtn+VbGlFE8ZpG9H3PVhEtpOaOxPsdDy6G",
+	"LAskr5mCVJYaaLTF77s+W4CRZs/jLdK2I9KGkKD4dlC9cy9IAobxTEdEA5CrN6/I13+bfL2DzY3qS4Qx",
+	"Q9Y246OkWLaCBK+t+m4KF3og93tjsysmDczas4geiF1widSYwkcpJJYJ9F5vw00GfaelU6kM0WWeM1U1",
+	"STzHLrtODxz3YHexH64uiYIFKKvv1ymP0yba1TtLR4TNZWnO5xkTK7JO/V3wtHBNQMhymdJDsh5gWhpr",
+	"ZvUJiIt1dy5/XCoFwtwcCuDrTAaCy9kKIxB8hhcIA+o+XqVMv1LM7Mv916sGF4SRWDld6JebS5kBs4ED",
+	"T3qtScfWbO9TtfbAgWTBeObSk/buR/3rXQOIC9MLXGyvqKCwcX1E1p4sbuziCdp9YTMM40xLW7fs9XLC",
+	"uE2EYXkJCZ7fK1kKs89XNXVGupUGCaQ4kWUKbEy04ILrFJIGZdtgBgt4RHaqLbQ2uVbTENXWb5uUlgR1",
+	"TmVQvN+xJeyKuM2cdv44iLu56ZQpxSr83ST8+69J3CkG4GifQM651nhdfE3Kygy+6VUv0rBs4CBxkyZT",
+	"7dOKOebnwmVc8MyA0jQ6ZEccJzrbDTJ1IMv5xt2jdcozaOiybm9Hrtx9i8i81JUfLQW4XLv2gsj1ttA1",
+	"eVir13Ay3iK7Vq9Z1gXEnGWvIMt2JWDFXcwcVi14vCoLGtFEyUIuUHnFKVPIqY+Pci232N2SdQulj9fI",
+	"iR7f7HCNMQQwNpoIhTO0z33y1WQMR/u7yPRSwZgE3xs/FGf5sxyhRBF3gjJRJ76sURmtNQfMgltk7LXH",
+	"0X23/iEquVU16XNnlBnFlpwlYO+IK7t5azU+Rin1MTnZiBrFUBalqgb8NU/hrt3IECeBO4jLbkS9EnIt",
+	"iLTBtH+Id711z0edTZu33fPpMyytxH1IUQd5aufLW+QOXUdbTemxJ/0nizPGyxuOHkePW3cvxlbyvAv1",
+	"eExhKbxA7O7STTvzBczw8wDs/YjfNPpkn7MWyzJz+dmcrYBomdeGJfichNXeHHJzO5Z50NUF5vNN+/1u",
+	"P26IxsdwRPol5IvzQ5wF/6RuSFsud5j6MDW/R5j36e9tKahVjUMxBL6pvwUPxLm6b6T6B9eFL7967WQT",
+	"Ou+UXCrQVmnJvMjAvfAOUERjJjDZMOAMNbmHHXalwJfp3ihBybWOSEWWErRrT5lgtOQmkmfkrDcmcFXB",
+	"vlzfXBsWZ0ec0bC677h54xdsTepbc80Tk+7jSCyzMhc6InfbTLFTB3iyJSuWQWGzKJxDmz9b1O0KE9IP",
+	"cam4qa6RNMdTVvDeFp8Lm03gsU2wraA6Ia9vQVW+U8cXQ7wrbnwlT5NbDmtQka/YoR5RhCU5Fy7lncFM",
+	"cF3X9taYFmom++IHN4Qp3wEVABPPixxPxupu0VQFbQ16JsJCuIAfrk+G24L+++zi3aVvCApn6niBzXnA",
+	"FCgsUFg32v56E3ynb97f0N366zfX378la5gTI1cgyC0ozN0koWDECh5Zf8xClMrTHGeM55abuuaEj25m",
+	"Qpf26BwRVhxtSsOiaVBjGstVLLhYyN2T/A8ojVyadj0vVvATYhsfdBSaDETiogJwDQd1BspS4S6ljlys",
+	"PxNxxu1prAAKwiz8CqdF9jxwqF0EbUTi+lew/JFUdhtQSio/oJtinIk/hfzin09IAH8WkdKWh08dIzVJ",
+	"oFAQuz6/0lgUmqylWgUjJYtAsGY5zISvPnqZ8Hk9ajlALt5d0ojeus3oOZ2eTE4mVv8UIFjB6Tn96mRy",
+	"8hWNbMuJvTmI5PR2euo5dnrvo60NvlyC1ZK1jKI9oP8EcxOcLrtS0+z6oV8HNUNO/ep083Gr/WE6mewp",
+	"hB1XAGv81p4SmH0RjgmZ83xyNrRgjfC0U6G0k746PKlpSLAznh+eURe3NxF9MZkcntBXpbc60mV03XF1",
+	"u36Yux122ODpnzrj6l3IHil4Zd//mgWhyR7Y4KzxJn6TMoFzR8DraW5om1x7ssHYfqDBQNKPm+i+Y246",
+	"7z625dEJTq9IDuRfSQWmI61NEW9IQfkS3ScULY9hQK7Q3eCa1GVQ9JNsba85h6dDIaTpQ9I91s4ZXYEp",
+	"lXC23G0RjFAoVKIP4NfkUnQWDafkkrODp/Qt18YZ7qN1RzutHo0cLZUZM9i1CY8Y6HqdRwz0reqfVM81",
+	"if/epjrbVl+ny53iGaFHWh2JT6cSH8nqBQlmNfGtNuSQY+WK+NJfaPPcld/Tex/g7nWIrnwT7QPk+FPb",
+	"QF/L2ZUL5zP+vhyhwIzmlOvs4KCSurEjjj3bTl551OjQvj1yeGhzHzG88/XS+PH+U6KReH7bGrZOaO5V",
+	"sE6Yfr/6taY/xAxbye1Q2/KN/8R+X9j6fLFTIXHpQettTCdTYttscFNIiDaK2UwcW7PqZCbswn1robuC",
+	"Lal61JeVdi/lKZtOzqKZeD6dus4UbhwC3zEpFXkxed5qWjnQUX1CLoTfDpKZCDSC8FTKdtWLab9dmOCC",
+	"/a0AzF7SG5/0P0o3NUwac722PqNz18yK7UuZVI96w+rrsNlsf/6y2bncZ4+69eFu2XAYtbigoE8n0yeB",
+	"0b0UX7aKOdrAP5/8/fCE+nsUnDCdjiFkt9X6c4XQOHUEU7a+CnnsyNtqDMJ8p4SNtV0ZkQ05Rqf37mu0",
+	"g9nAB/lHT5H+6b1QSP/vLAPoWDF0vD3pvi7LXNZGd4WH6508DVZI6t92qDas0rVJ2zVjdR7x1yVCQSf/",
+	"kTt8+twhGxDn83ndrNIvxFYB1kKMLiGwOK0/bnHFHVzD1e3qHguwRUM3jCkgcQrxqv6vGWaCiaq20D54",
+	"iprF8Clw23Na+xAMUazTUEXzbqXbtv5WFqs9woT/RaDtuS5djy36qy6P4TMbtrMKH+XNICuhLTrxa51u",
+	"q5nb0HuijqYTcthZ3/LKZ6LrlpNH8Moth2bCsXHbOye/1DmfieCd7/WvH1bg+GKd7E631GfwtPcWZAbc",
+	"bV+xenx/ey+YToe4l0EFf/jgf/jgv9wH95XHOmfUsmWdzqUhp/t9PegTej8Nkp4bUiP48l3pzVBxS/Of",
+	"IVhRkrFKlmbn27UtE7Jm3syGDvPNHhFxnUR9AlK/wRYntnQmpS9Bf/HuklwXEDc9P1cuVvu4+d8Awh1d",
+	"MnhMAAA=",
+}
+
+// GetSwagger returns the content of the embedded swagger specification file
+// or error if failed to decode
+func decodeSpec() ([]byte, error) {
+	zipped, err := base64.StdEncoding.DecodeString(strings.Join(swaggerSpec, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error base64 decoding spec: %s", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(zipped))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(zr)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing spec: %s", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var rawSpec = decodeSpecCached()
+
+// a naive cached of a decoded swagger spec
+func decodeSpecCached() func() ([]byte, error) {
+	data, err := decodeSpec()
+	return func() ([]byte, error) {
+		return data, err
+	}
+}
+
+// Constructs a synthetic filesystem for resolving external references when loading openapi specifications.
+func PathToRawSpec(pathToFile string) map[string]func() ([]byte, error) {
+	var res = make(map[string]func() ([]byte, error))
+	if len(pathToFile) > 0 {
+		res[pathToFile] = rawSpec
+	}
+
+	return res
+}
+
+// GetSwagger returns the Swagger specification corresponding to the generated code
+// in this file. The external references of Swagger specification are resolved.
+// The logic of resolving external references is tightly connected to "import-mapping" feature.
+// Externally referenced files must be embedded in the corresponding golang packages.
+// Urls can be supported but this task was out of the scope.
+func GetSwagger() (swagger *openapi3.T, err error) {
+	var resolvePath = PathToRawSpec("")
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, url *url.URL) ([]byte, error) {
+		var pathToFile = url.String()
+		pathToFile = path.Clean(pathToFile)
+		getSpec, ok := resolvePath[pathToFile]
+		if !ok {
+			err1 := fmt.Errorf("path not found: %s", pathToFile)
+			return nil, err1
+		}
+		return getSpec()
+	}
+	var specData []byte
+	specData, err = rawSpec()
+	if err != nil {
+		return
+	}
+	swagger, err = loader.LoadFromData(specData)
+	if err != nil {
+		return
+	}
+	return
+}
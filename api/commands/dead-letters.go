@@ -0,0 +1,172 @@
+package commands
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sepisoad/robot-challange/api/internals/services/config"
+	"github.com/sepisoad/robot-challange/shared/services/deadletter"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type deadLettersOptions struct {
+	showData bool
+	all      bool
+}
+
+func deadLettersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dead-letters",
+		Short: "Inspect and republish dead letters",
+		Long:  "Inspect and republish the messages which could not be processed by the consumers",
+	}
+
+	cmd.AddCommand(
+		deadLettersListCommand(),
+		deadLettersRepublishCommand(),
+	)
+
+	return cmd
+}
+
+func deadLettersListCommand() *cobra.Command {
+	opt := deadLettersOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List dead letters",
+		Long:  "List all the dead letters which are kept in the dead letter stream",
+		Run: func(cmd *cobra.Command, args []string) {
+			deadLetterService, sugarLogger, closer := createDeadLetterService()
+			defer closer()
+
+			deadLetters, err := deadLetterService.List()
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(writer, "SEQUENCE\tSUBJECT\tORIGINAL SEQUENCE\tCONSUMER\tDELIVERIES\tTIME\tERROR")
+
+			for _, deadLetter := range deadLetters {
+				fmt.Fprintf(
+					writer,
+					"%d\t%s\t%d\t%s\t%d\t%s\t%s\n",
+					deadLetter.Sequence,
+					deadLetter.OriginalSubject,
+					deadLetter.OriginalSequence,
+					deadLetter.Consumer,
+					deadLetter.Deliveries,
+					deadLetter.Time.Format(time.RFC3339),
+					deadLetter.Error)
+
+				if opt.showData {
+					fmt.Fprintf(writer, "\t%s\n", string(deadLetter.Data))
+				}
+			}
+
+			writer.Flush()
+		},
+	}
+
+	cmd.Flags().BoolVar(&opt.showData, "show-data", false, "Print the payload of every dead letter")
+
+	return cmd
+}
+
+func deadLettersRepublishCommand() *cobra.Command {
+	opt := deadLettersOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "republish [sequence...]",
+		Short: "Republish dead letters",
+		Long:  "Republish dead letters to the subject they were originally published on",
+		Run: func(cmd *cobra.Command, args []string) {
+			if !opt.all && len(args) == 0 {
+				log.Fatal("Either specify the dead letter sequences or use --all")
+			}
+
+			deadLetterService, sugarLogger, closer := createDeadLetterService()
+			defer closer()
+
+			sequences := make([]uint64, 0)
+
+			if opt.all {
+				deadLetters, err := deadLetterService.List()
+				if err != nil {
+					sugarLogger.Fatal(err)
+				}
+
+				for _, deadLetter := range deadLetters {
+					sequences = append(sequences, deadLetter.Sequence)
+				}
+			}
+
+			for _, arg := range args {
+				sequence, err := strconv.ParseUint(arg, 10, 64)
+				if err != nil {
+					sugarLogger.Fatalf("Invalid dead letter sequence: %s", arg)
+				}
+
+				sequences = append(sequences, sequence)
+			}
+
+			for _, sequence := range sequences {
+				if err := deadLetterService.Republish(sequence); err != nil {
+					sugarLogger.Fatal(err)
+				}
+
+				sugarLogger.Infof("Republished dead letter %d", sequence)
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&opt.all, "all", false, "Republish all the dead letters")
+
+	return cmd
+}
+
+func createDeadLetterService() (deadletter.DeadLetterInterface, *zap.SugaredLogger, func()) {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sugarLogger := logger.Sugar()
+
+	configService, err := config.NewConfigService()
+	if err != nil {
+		sugarLogger.Fatal(err)
+	}
+
+	streamConfig, err := configService.GetStreamConfig()
+	if err != nil {
+		sugarLogger.Fatal(err)
+	}
+
+	robotBrokerService, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"api-dead-letters",
+		configService.GetNatsUrl(),
+		configService.GetConnectionConfig(),
+		streamConfig)
+	if err != nil {
+		sugarLogger.Fatal(err)
+	}
+
+	deadLetterService, err := deadletter.NewDeadLetterService(
+		sugarLogger,
+		robotBrokerService)
+	if err != nil {
+		robotBrokerService.Close()
+		sugarLogger.Fatal(err)
+	}
+
+	return deadLetterService, sugarLogger, robotBrokerService.Close
+}
@@ -1,7 +1,7 @@
 /*
 this package defines a set of commands for you to interact with the
-api server. so far the only exposed command is `start` where you can
-use to start the api server
+api server. `start` starts the api server and `dead-letters` lets you
+inspect and republish the messages which could not be processed
 */
 
 package commands
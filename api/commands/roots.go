@@ -21,6 +21,8 @@ func Root() *cobra.Command {
 	// Register all commands
 	cmd.AddCommand(
 		startCommand(),
+		deadLettersCommand(),
+		streamCommand(),
 	)
 
 	return cmd
@@ -4,22 +4,17 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/bwmarrin/snowflake"
-	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
 	"github.com/sepisoad/robot-challange/api/internals/services/config"
-	"github.com/sepisoad/robot-challange/api/processors"
+	"github.com/sepisoad/robot-challange/api/server"
 	"github.com/sepisoad/robot-challange/api/transport/robot"
-	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
-	"github.com/sepisoad/robot-challange/shared/services/idgenerator"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
-	"github.com/deepmap/oapi-codegen/pkg/middleware"
-	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
 func startCommand() *cobra.Command {
+	var insecureNoAuth bool
+
 	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "Start the API",
@@ -37,86 +32,100 @@ func startCommand() *cobra.Command {
 				sugarLogger.Fatal(err)
 			}
 
-			robotBrokerService, err := robotbroker.NewRobotBrokerService(
-				sugarLogger,
-				"api",
-				configService.GetNatsUrl())
+			streamConfig, err := configService.GetStreamConfig()
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
-			defer robotBrokerService.Close()
 
-			eventPublisherService, err := eventpublisher.NewEventPublisherService(
-				sugarLogger,
-				robotBrokerService)
+			syncAcceptance, err := configService.GetSyncAcceptance()
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
 
-			robotProcessor, robotStatusChannel, err := processors.StartRobotProcessor(
-				sugarLogger,
-				robotBrokerService)
+			acceptanceTimeout, err := configService.GetAcceptanceTimeout()
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
 
-			defer robotProcessor.Stop()
+			idempotencyStore, err := configService.GetIdempotencyStore()
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
 
-			taskProcessor, taskStatusChannel, err := processors.StartTaskProcessor(
-				sugarLogger,
-				robotBrokerService)
+			idempotencyRetention, err := configService.GetIdempotencyRetention()
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
 
-			defer taskProcessor.Stop()
+			taskRetention, err := configService.GetTaskRetention()
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
 
-			swaggerSpec, err := robotapiserver.GetSwagger()
+			nodeId, err := configService.GetNodeId()
 			if err != nil {
-				sugarLogger.Fatalf("Error loading swagger spec\n: %v", err)
+				sugarLogger.Fatal(err)
 			}
 
-			swaggerSpec.Servers = nil //TODO:sepi
+			apiKeys, err := configService.GetApiKeys()
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
 
-			snowflakeNode, err := snowflake.NewNode(1)
+			jwtOptions, err := configService.GetJwtOptions()
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
 
-			idGeneratorService, err := idgenerator.NewIdGeneratorService(snowflakeNode)
+			insecureNoAuthEnv, err := configService.GetInsecureNoAuth()
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
 
-			robotService, err := robot.NewRobotService(
+			robotBrokerService, err := robotbroker.NewRobotBrokerService(
 				sugarLogger,
-				robotStatusChannel,
-				taskStatusChannel,
-				eventPublisherService,
-				idGeneratorService)
+				"api",
+				configService.GetNatsUrl(),
+				configService.GetConnectionConfig(),
+				streamConfig)
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
+			defer robotBrokerService.Close()
 
-			e := echo.New()
-			e.Use(echomiddleware.CORSWithConfig(
-				echomiddleware.CORSConfig{
-					AllowOrigins: []string{"*"},
-					AllowHeaders: []string{
-						echo.HeaderOrigin,
-						echo.HeaderContentType,
-						echo.HeaderAccept},
-				}))
-			e.Use(echomiddleware.Logger()) //TODO:sepi
-			e.Use(middleware.OapiRequestValidator(swaggerSpec))
-
-			robotapiserver.RegisterHandlers(e, robotService)
+			apiServer, err := server.Start(
+				sugarLogger,
+				server.Options{
+					Address:              fmt.Sprintf(":%d", configService.GetListeningPort()),
+					EventCodec:           configService.GetEventCodec(),
+					OutboxPath:           configService.GetOutboxPath(),
+					StorePath:            configService.GetStorePath(),
+					TaskRetention:        taskRetention,
+					NodeId:               nodeId,
+					IdempotencyStore:     idempotencyStore,
+					IdempotencyRetention: idempotencyRetention,
+					ApiKeys:              apiKeys,
+					Jwt:                  jwtOptions,
+					InsecureNoAuth:       insecureNoAuth || insecureNoAuthEnv,
+					Acceptance: robot.AcceptanceOptions{
+						Sync:    syncAcceptance,
+						Timeout: acceptanceTimeout,
+					},
+				},
+				robotBrokerService)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
 
-			e.File("/", "index.html")
+			defer apiServer.Stop()
 
-			e.Logger.Fatal(e.Start(fmt.Sprintf(":%d", configService.GetListeningPort())))
+			if err := apiServer.Wait(); err != nil {
+				sugarLogger.Error(err)
+			}
 		},
 	}
 
+	cmd.Flags().BoolVar(&insecureNoAuth, "insecure-no-auth", false, "Serve the requests unauthenticated when neither API_KEYS nor a way to verify tokens is given, like INSECURE_NO_AUTH")
+
 	return cmd
 }
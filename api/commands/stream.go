@@ -0,0 +1,443 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sepisoad/robot-challange/api/internals/services/config"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/shared/services/streaminspector"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type streamFilterOptions struct {
+	subject       string
+	firstSequence uint64
+	lastSequence  uint64
+	since         string
+	until         string
+	robotId       int64
+	taskId        int64
+	limit         int
+}
+
+type streamInspectOptions struct {
+	filter   streamFilterOptions
+	showData bool
+	json     bool
+}
+
+type streamExportOptions struct {
+	filter streamFilterOptions
+	output string
+}
+
+type streamImportOptions struct {
+	stream   string
+	rewrites []string
+	force    bool
+}
+
+type streamReplayOptions struct {
+	filter        streamFilterOptions
+	subjectPrefix string
+	stream        string
+}
+
+func streamCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stream",
+		Short: "Inspect and replay the events of the stream",
+		Long:  "Inspect the events kept in the stream and replay them to a scratch stream for debugging",
+	}
+
+	cmd.AddCommand(
+		streamInfoCommand(),
+		streamInspectCommand(),
+		streamReplayCommand(),
+		streamExportCommand(),
+		streamImportCommand(),
+	)
+
+	return cmd
+}
+
+func streamInfoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Print the configuration and the state of the stream",
+		Long:  "Print the configuration and the state of the stream",
+		Run: func(cmd *cobra.Command, args []string) {
+			streamInspectorService, sugarLogger, closer := createStreamInspectorService()
+			defer closer()
+
+			streamInfo, err := streamInspectorService.Info()
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintf(writer, "Name\t%s\n", streamInfo.Config.Name)
+			fmt.Fprintf(writer, "Subjects\t%v\n", streamInfo.Config.Subjects)
+			fmt.Fprintf(writer, "Retention\t%s\n", streamInfo.Config.Retention)
+			fmt.Fprintf(writer, "Storage\t%s\n", streamInfo.Config.Storage)
+			fmt.Fprintf(writer, "Replicas\t%d\n", streamInfo.Config.Replicas)
+			fmt.Fprintf(writer, "Max age\t%v\n", streamInfo.Config.MaxAge)
+			fmt.Fprintf(writer, "Max bytes\t%d\n", streamInfo.Config.MaxBytes)
+			fmt.Fprintf(writer, "Max messages\t%d\n", streamInfo.Config.MaxMsgs)
+			fmt.Fprintf(writer, "Messages\t%d\n", streamInfo.State.Msgs)
+			fmt.Fprintf(writer, "Bytes\t%d\n", streamInfo.State.Bytes)
+			fmt.Fprintf(writer, "First sequence\t%d\t%s\n", streamInfo.State.FirstSeq, streamInfo.State.FirstTime.Format(time.RFC3339))
+			fmt.Fprintf(writer, "Last sequence\t%d\t%s\n", streamInfo.State.LastSeq, streamInfo.State.LastTime.Format(time.RFC3339))
+			fmt.Fprintf(writer, "Consumers\t%d\n", streamInfo.State.Consumers)
+			writer.Flush()
+		},
+	}
+
+	return cmd
+}
+
+func streamInspectCommand() *cobra.Command {
+	opt := streamInspectOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Print the events of the stream",
+		Long:  "Print the events of the stream decoded into task, robot and warehouse events",
+		Run: func(cmd *cobra.Command, args []string) {
+			streamInspectorService, sugarLogger, closer := createStreamInspectorService()
+			defer closer()
+
+			filter, err := opt.filter.toFilter(cmd)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			events, err := streamInspectorService.Events(filter)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			if opt.json {
+				encoder := json.NewEncoder(os.Stdout)
+				for _, event := range events {
+					if err := encoder.Encode(event); err != nil {
+						sugarLogger.Fatal(err)
+					}
+				}
+
+				return
+			}
+
+			writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(writer, "SEQUENCE\tTIME\tSUBJECT\tSOURCE\tEVENT")
+
+			for _, event := range events {
+				fmt.Fprintf(
+					writer,
+					"%d\t%s\t%s\t%s\t%s\n",
+					event.Sequence,
+					event.Time.Format(time.RFC3339Nano),
+					event.Subject,
+					eventSource(event),
+					eventSummary(event))
+
+				if opt.showData {
+					fmt.Fprintf(writer, "\t%s\n", string(event.Data))
+				}
+			}
+
+			writer.Flush()
+		},
+	}
+
+	opt.filter.addFlags(cmd)
+	cmd.Flags().BoolVar(&opt.showData, "show-data", false, "Print the raw payload of every event")
+	cmd.Flags().BoolVar(&opt.json, "json", false, "Print every event as a JSON line")
+
+	return cmd
+}
+
+func streamReplayCommand() *cobra.Command {
+	opt := streamReplayOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay events to a scratch stream",
+		Long: "Republish the selected events on <subject prefix>.<original subject> and keep them in a " +
+			"scratch stream, the consumers of the project do not receive them",
+		Run: func(cmd *cobra.Command, args []string) {
+			streamInspectorService, sugarLogger, closer := createStreamInspectorService()
+			defer closer()
+
+			filter, err := opt.filter.toFilter(cmd)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			replayed, err := streamInspectorService.Replay(filter, streaminspector.ReplayTarget{
+				SubjectPrefix: opt.subjectPrefix,
+				Stream:        opt.stream,
+			})
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			sugarLogger.Infof(
+				"Replayed %d events to %s.> in stream %s",
+				replayed,
+				opt.subjectPrefix,
+				opt.stream)
+		},
+	}
+
+	opt.filter.addFlags(cmd)
+	cmd.Flags().StringVar(&opt.subjectPrefix, "to-subject", streaminspector.DefaultReplaySubjectPrefix, "Specify the subject prefix the events are replayed on")
+	cmd.Flags().StringVar(&opt.stream, "to-stream", streaminspector.DefaultReplayStream, "Specify the scratch stream the replayed events are kept in, it is created when missing")
+
+	return cmd
+}
+
+func streamExportCommand() *cobra.Command {
+	opt := streamExportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export events as NDJSON",
+		Long:  "Export the selected events as newline delimited JSON with their original time and sequence",
+		Run: func(cmd *cobra.Command, args []string) {
+			streamInspectorService, sugarLogger, closer := createStreamInspectorService()
+			defer closer()
+
+			filter, err := opt.filter.toFilter(cmd)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			var writer io.Writer = os.Stdout
+
+			if opt.output != "-" {
+				file, err := os.Create(opt.output)
+				if err != nil {
+					sugarLogger.Fatal(err)
+				}
+
+				defer file.Close()
+				writer = file
+			}
+
+			exported, err := streamInspectorService.Export(filter, writer)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			sugarLogger.Infof("Exported %d events", exported)
+		},
+	}
+
+	opt.filter.addFlags(cmd)
+	cmd.Flags().StringVarP(&opt.output, "output", "o", "-", "Specify the file the events are written to, - writes them to stdout")
+
+	return cmd
+}
+
+func streamImportCommand() *cobra.Command {
+	opt := streamImportOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import events exported as NDJSON",
+		Long:  "Import the events of an export into an empty stream, - reads them from stdin",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			streamInspectorService, sugarLogger, closer := createStreamInspectorService()
+			defer closer()
+
+			importOptions := streaminspector.ImportOptions{
+				Stream: opt.stream,
+				Force:  opt.force,
+			}
+
+			for _, rewrite := range opt.rewrites {
+				parts := strings.SplitN(rewrite, "=", 2)
+				if len(parts) != 2 {
+					sugarLogger.Fatalf("Invalid subject rewrite %s, expected <from>=<to>", rewrite)
+				}
+
+				importOptions.Rewrites = append(importOptions.Rewrites, streaminspector.SubjectRewrite{
+					From: parts[0],
+					To:   parts[1],
+				})
+			}
+
+			var reader io.Reader = os.Stdin
+
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					sugarLogger.Fatal(err)
+				}
+
+				defer file.Close()
+				reader = file
+			}
+
+			imported, err := streamInspectorService.Import(reader, importOptions)
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
+			sugarLogger.Infof("Imported %d events", imported)
+		},
+	}
+
+	cmd.Flags().StringVar(&opt.stream, "to-stream", "", "Specify the empty stream the events are imported into, defaults to the configured stream")
+	cmd.Flags().StringArrayVar(&opt.rewrites, "rewrite", nil, "Rewrite the leading tokens of the subjects, e.g. robot.default=robot.staging, can be repeated")
+	cmd.Flags().BoolVar(&opt.force, "force", false, "Import into a stream which has consumers, they process the imported events")
+
+	return cmd
+}
+
+func (o *streamFilterOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.subject, "subject", "", "Select the events published on a subject, wildcards are allowed")
+	cmd.Flags().Uint64Var(&o.firstSequence, "from-seq", 0, "Select the events from this stream sequence")
+	cmd.Flags().Uint64Var(&o.lastSequence, "to-seq", 0, "Select the events up to this stream sequence")
+	cmd.Flags().StringVar(&o.since, "since", "", "Select the events stored since a RFC3339 time or a duration ago, e.g. 1h")
+	cmd.Flags().StringVar(&o.until, "until", "", "Select the events stored until a RFC3339 time or a duration ago, e.g. 10m")
+	cmd.Flags().Int64Var(&o.robotId, "robot", 0, "Select the events of a robot")
+	cmd.Flags().Int64Var(&o.taskId, "task", 0, "Select the events of a task")
+	cmd.Flags().IntVar(&o.limit, "limit", 0, "Select at most this number of events")
+}
+
+func (o *streamFilterOptions) toFilter(cmd *cobra.Command) (streaminspector.Filter, error) {
+	filter := streaminspector.Filter{
+		Subject:       o.subject,
+		FirstSequence: o.firstSequence,
+		LastSequence:  o.lastSequence,
+		Limit:         o.limit,
+	}
+
+	var err error
+
+	if filter.Since, err = parseTime(o.since); err != nil {
+		return filter, err
+	}
+
+	if filter.Until, err = parseTime(o.until); err != nil {
+		return filter, err
+	}
+
+	// Robot and task ids start at zero, only filter on them when asked to
+	if cmd.Flags().Changed("robot") {
+		filter.RobotId = &o.robotId
+	}
+
+	if cmd.Flags().Changed("task") {
+		filter.TaskId = &o.taskId
+	}
+
+	return filter, nil
+}
+
+// parseTime reads a RFC3339 time or a duration before now
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if ago, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-ago), nil
+	}
+
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected a RFC3339 time or a duration", value)
+	}
+
+	return at, nil
+}
+
+func eventSource(event streaminspector.Event) string {
+	if event.Envelope == nil {
+		return "-"
+	}
+
+	return event.Envelope.Source
+}
+
+func eventSummary(event streaminspector.Event) string {
+	var decoded interface{}
+
+	switch {
+	case event.TaskEvent != nil:
+		decoded = event.TaskEvent
+	case event.RobotEvent != nil:
+		decoded = event.RobotEvent
+	case event.WarehouseEvent != nil:
+		decoded = event.WarehouseEvent
+	default:
+		return "! " + event.DecodeError
+	}
+
+	buf, err := json.Marshal(decoded)
+	if err != nil {
+		return "! " + err.Error()
+	}
+
+	return string(buf)
+}
+
+func createStreamInspectorService() (streaminspector.StreamInspectorInterface, *zap.SugaredLogger, func()) {
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sugarLogger := logger.Sugar()
+
+	configService, err := config.NewConfigService()
+	if err != nil {
+		sugarLogger.Fatal(err)
+	}
+
+	streamConfig, err := configService.GetStreamConfig()
+	if err != nil {
+		sugarLogger.Fatal(err)
+	}
+
+	robotBrokerService, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"api-stream",
+		configService.GetNatsUrl(),
+		configService.GetConnectionConfig(),
+		streamConfig)
+	if err != nil {
+		sugarLogger.Fatal(err)
+	}
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	if err != nil {
+		robotBrokerService.Close()
+		sugarLogger.Fatal(err)
+	}
+
+	streamInspectorService, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		streamConfig.Name)
+	if err != nil {
+		robotBrokerService.Close()
+		sugarLogger.Fatal(err)
+	}
+
+	return streamInspectorService, sugarLogger, robotBrokerService.Close
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// apiKeyAuthenticatorService implements AuthenticatorInterface contract for static api keys
+type apiKeyAuthenticatorService struct {
+	// principals are indexed by the digest of their key so keys are not compared byte by byte
+	principals map[[sha256.Size]byte]Principal
+}
+
+// NewApiKeyAuthenticatorService creates a concrete instance of AuthenticatorInterface which
+// knows apiKeys
+func NewApiKeyAuthenticatorService(apiKeys []ApiKey) (AuthenticatorInterface, error) {
+	principals := make(map[[sha256.Size]byte]Principal, len(apiKeys))
+
+	for _, apiKey := range apiKeys {
+		if apiKey.Key == "" {
+			return nil, fmt.Errorf("the api key of %q is empty", apiKey.Name)
+		}
+
+		if _, err := ParseRole(string(apiKey.Role)); err != nil {
+			return nil, fmt.Errorf("the api key of %q: %w", apiKey.Name, err)
+		}
+
+		digest := sha256.Sum256([]byte(apiKey.Key))
+		if _, found := principals[digest]; found {
+			return nil, fmt.Errorf("the api key of %q is given to someone else already", apiKey.Name)
+		}
+
+		principals[digest] = Principal{
+			Subject: apiKey.Name,
+			Role:    apiKey.Role,
+		}
+	}
+
+	return &apiKeyAuthenticatorService{
+		principals: principals,
+	}, nil
+}
+
+// Authenticate returns the principal the api key was given to
+func (s *apiKeyAuthenticatorService) Authenticate(credential string) (Principal, error) {
+	principal, found := s.principals[sha256.Sum256([]byte(credential))]
+	if !found {
+		return Principal{}, fmt.Errorf("%w: unknown api key", ErrInvalidCredentials)
+	}
+
+	return principal, nil
+}
+
+// ParseApiKeys parses a comma separated list of api keys, each given as <name>:<role>:<key>
+func ParseApiKeys(val string) ([]ApiKey, error) {
+	apiKeys := make([]ApiKey, 0)
+
+	for idx, entry := range strings.Split(val, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// The key comes last so it may hold colons
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			// The entry is not echoed as it may hold a key
+			return nil, fmt.Errorf("invalid api key #%d, expected <name>:<role>:<key>", idx+1)
+		}
+
+		role, err := ParseRole(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid api key %q: %w", parts[0], err)
+		}
+
+		apiKeys = append(apiKeys, ApiKey{
+			Name: parts[0],
+			Role: role,
+			Key:  parts[2],
+		})
+	}
+
+	return apiKeys, nil
+}
@@ -0,0 +1,45 @@
+package auth_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+)
+
+func Test_Authenticate_Should_Return_The_Principal_An_Api_Key_Was_Given_To(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := auth.NewApiKeyAuthenticatorService([]auth.ApiKey{
+		{Name: "dashboard", Role: auth.RoleViewer, Key: "viewer-key"},
+		{Name: "dispatcher", Role: auth.RoleOperator, Key: "operator-key"},
+	})
+	g.Expect(err).Should(BeNil())
+
+	principal, err := sut.Authenticate("operator-key")
+	g.Expect(err).Should(BeNil())
+	g.Expect(principal).Should(Equal(auth.Principal{
+		Subject: "dispatcher",
+		Role:    auth.RoleOperator,
+	}))
+
+	_, err = sut.Authenticate("unknown-key")
+	g.Expect(errors.Is(err, auth.ErrInvalidCredentials)).Should(BeTrue())
+}
+
+func Test_NewApiKeyAuthenticatorService_Should_Reject_Invalid_Api_Keys(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, apiKeys := range [][]auth.ApiKey{
+		{{Name: "dashboard", Role: auth.RoleViewer, Key: ""}},
+		{{Name: "dashboard", Role: "guest", Key: "key"}},
+		{
+			{Name: "dashboard", Role: auth.RoleViewer, Key: "key"},
+			{Name: "dispatcher", Role: auth.RoleOperator, Key: "key"},
+		},
+	} {
+		_, err := auth.NewApiKeyAuthenticatorService(apiKeys)
+		g.Expect(err).ShouldNot(BeNil())
+	}
+}
@@ -0,0 +1,37 @@
+package auth_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+)
+
+func Test_ParseApiKeys_Should_Return_The_Api_Keys_With_Their_Names_And_Roles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	apiKeys, err := auth.ParseApiKeys("dashboard:viewer:abc, dispatcher:operator:d:e:f,")
+	g.Expect(err).Should(BeNil())
+	g.Expect(apiKeys).Should(Equal([]auth.ApiKey{
+		{Name: "dashboard", Role: auth.RoleViewer, Key: "abc"},
+		{Name: "dispatcher", Role: auth.RoleOperator, Key: "d:e:f"},
+	}))
+
+	apiKeys, err = auth.ParseApiKeys("")
+	g.Expect(err).Should(BeNil())
+	g.Expect(apiKeys).Should(BeEmpty())
+}
+
+func Test_ParseApiKeys_Should_Reject_Malformed_Api_Keys(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, val := range []string{
+		"dashboard:viewer",
+		"dashboard:viewer:",
+		":viewer:abc",
+		"dashboard:guest:abc",
+	} {
+		_, err := auth.ParseApiKeys(val)
+		g.Expect(err).ShouldNot(BeNil(), val)
+	}
+}
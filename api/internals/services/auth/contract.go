@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Role is what a principal is allowed to do, a role is allowed everything the roles below it are
+type Role string
+
+const (
+	// RoleViewer reads robots, tasks and the warehouse
+	RoleViewer Role = "viewer"
+	// RoleOperator moves the robots and cancels tasks
+	RoleOperator Role = "operator"
+	// RoleAdmin is allowed everything
+	RoleAdmin Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// ParseRole returns the role named val
+func ParseRole(val string) (Role, error) {
+	role := Role(val)
+	if _, found := roleRanks[role]; !found {
+		return "", fmt.Errorf("unknown role %q, must be one of %s, %s or %s", val, RoleViewer, RoleOperator, RoleAdmin)
+	}
+
+	return role, nil
+}
+
+// Includes tells whether the role is allowed what other is allowed
+func (r Role) Includes(other Role) bool {
+	rank, found := roleRanks[r]
+
+	return found && rank >= roleRanks[other]
+}
+
+// Principal is who sent a request
+type Principal struct {
+	// Subject names the principal, the name of its api key or the subject of its token
+	Subject string
+	Role    Role
+}
+
+// ApiKey is a static key clients send to authenticate
+type ApiKey struct {
+	// Name tells who the key was given to
+	Name string
+	Role Role
+	Key  string
+}
+
+// JwtOptions configures how the JSON web tokens are verified, a token is signed either with
+// Secret using HMAC or with the private key of PublicKey using RSA or ECDSA
+type JwtOptions struct {
+	Secret []byte
+	// PublicKey is PEM encoded
+	PublicKey []byte
+	// Issuer and Audience are checked against the iss and aud claims unless they are empty
+	Issuer   string
+	Audience string
+}
+
+// Enabled tells whether the tokens can be verified
+func (o JwtOptions) Enabled() bool {
+	return len(o.Secret) > 0 || len(o.PublicKey) > 0
+}
+
+// ErrInvalidCredentials is returned for a credential which is unknown, expired or malformed
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// AuthenticatorInterface defines the contract for telling who a credential belongs to
+type AuthenticatorInterface interface {
+	// Authenticate returns the principal credential belongs to, the error wraps
+	// ErrInvalidCredentials when it does not belong to anyone
+	Authenticate(credential string) (Principal, error)
+}
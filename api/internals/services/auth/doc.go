@@ -0,0 +1,6 @@
+/*
+auth package tells who sent a request to the api from the credential it carries, either a static
+api key or a JSON web token verified locally, and which role the sender is given
+*/
+
+package auth
@@ -1,3 +1,3 @@
-package nats
+package auth
 
 //go:generate mockgen -source=contract.go -destination=mock/mock-contract.go
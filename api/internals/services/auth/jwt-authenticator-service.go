@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	// claimRole holds the role of the subject of a token
+	claimRole = "role"
+	// claimRoles holds several roles, the subject is given the highest of them
+	claimRoles = "roles"
+)
+
+// jwtAuthenticatorService implements AuthenticatorInterface contract for JSON web tokens, they
+// are verified locally so no identity provider is called on a request
+type jwtAuthenticatorService struct {
+	options JwtOptions
+	parser  *jwt.Parser
+	key     interface{}
+}
+
+// NewJwtAuthenticatorService creates a concrete instance of AuthenticatorInterface which
+// verifies tokens with options
+func NewJwtAuthenticatorService(options JwtOptions) (AuthenticatorInterface, error) {
+	service := &jwtAuthenticatorService{
+		options: options,
+	}
+
+	switch {
+	case len(options.Secret) > 0 && len(options.PublicKey) > 0:
+		return nil, errors.New("tokens are verified either with a secret or with a public key")
+	case len(options.Secret) > 0:
+		service.key = options.Secret
+		service.parser = &jwt.Parser{ValidMethods: []string{"HS256", "HS384", "HS512"}}
+	case len(options.PublicKey) > 0:
+		if key, err := jwt.ParseRSAPublicKeyFromPEM(options.PublicKey); err == nil {
+			service.key = key
+			service.parser = &jwt.Parser{ValidMethods: []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}}
+		} else if key, err := jwt.ParseECPublicKeyFromPEM(options.PublicKey); err == nil {
+			service.key = key
+			service.parser = &jwt.Parser{ValidMethods: []string{"ES256", "ES384", "ES512"}}
+		} else {
+			return nil, errors.New("the public key tokens are verified with is neither an RSA nor an ECDSA PEM encoded key")
+		}
+	default:
+		return nil, errors.New("tokens are verified with a secret or with a public key, none was given")
+	}
+
+	return service, nil
+}
+
+// Authenticate verifies the token and returns its subject with the role it is given
+func (s *jwtAuthenticatorService) Authenticate(credential string) (Principal, error) {
+	claims := jwt.MapClaims{}
+
+	// The expiry and not before claims are checked by the parser
+	if _, err := s.parser.ParseWithClaims(credential, claims, func(*jwt.Token) (interface{}, error) {
+		return s.key, nil
+	}); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	if s.options.Issuer != "" && !claims.VerifyIssuer(s.options.Issuer, true) {
+		return Principal{}, fmt.Errorf("%w: the token is not issued by %s", ErrInvalidCredentials, s.options.Issuer)
+	}
+
+	if s.options.Audience != "" && !claims.VerifyAudience(s.options.Audience, true) {
+		return Principal{}, fmt.Errorf("%w: the token is not meant for %s", ErrInvalidCredentials, s.options.Audience)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return Principal{}, fmt.Errorf("%w: the token has no subject", ErrInvalidCredentials)
+	}
+
+	role, err := getRole(claims)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	return Principal{
+		Subject: subject,
+		Role:    role,
+	}, nil
+}
+
+// getRole returns the role given in the role claim or the highest of those in the roles claim
+func getRole(claims jwt.MapClaims) (Role, error) {
+	var names []interface{}
+
+	switch val := claims[claimRole].(type) {
+	case string:
+		names = append(names, val)
+	case nil:
+	default:
+		return "", fmt.Errorf("the %s claim is not a string", claimRole)
+	}
+
+	switch val := claims[claimRoles].(type) {
+	case []interface{}:
+		names = append(names, val...)
+	case nil:
+	default:
+		return "", fmt.Errorf("the %s claim is not an array", claimRoles)
+	}
+
+	var role Role
+
+	// Roles an older token was given and which are unknown by now are ignored
+	for _, name := range names {
+		if name, ok := name.(string); ok {
+			if parsed, err := ParseRole(name); err == nil && !role.Includes(parsed) {
+				role = parsed
+			}
+		}
+	}
+
+	if role == "" {
+		return "", errors.New("the token is given no known role")
+	}
+
+	return role, nil
+}
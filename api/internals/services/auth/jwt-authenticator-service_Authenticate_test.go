@@ -0,0 +1,133 @@
+package auth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+)
+
+func Test_Authenticate_Should_Return_The_Subject_Of_A_Token_With_Its_Highest_Role(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	secret := []byte("secret")
+
+	sut, err := auth.NewJwtAuthenticatorService(auth.JwtOptions{
+		Secret:   secret,
+		Issuer:   "https://issuer.example",
+		Audience: "robots",
+	})
+	g.Expect(err).Should(BeNil())
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "alice",
+		"iss":   "https://issuer.example",
+		"aud":   []string{"robots", "dashboard"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"viewer", "operator", "unknown"},
+	}).SignedString(secret)
+	g.Expect(err).Should(BeNil())
+
+	principal, err := sut.Authenticate(token)
+	g.Expect(err).Should(BeNil())
+	g.Expect(principal).Should(Equal(auth.Principal{
+		Subject: "alice",
+		Role:    auth.RoleOperator,
+	}))
+}
+
+func Test_Authenticate_Should_Verify_Tokens_With_A_Public_Key(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).Should(BeNil())
+
+	publicKey, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	g.Expect(err).Should(BeNil())
+
+	sut, err := auth.NewJwtAuthenticatorService(auth.JwtOptions{
+		PublicKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}),
+	})
+	g.Expect(err).Should(BeNil())
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"sub":  "bob",
+		"role": "admin",
+	}).SignedString(privateKey)
+	g.Expect(err).Should(BeNil())
+
+	principal, err := sut.Authenticate(token)
+	g.Expect(err).Should(BeNil())
+	g.Expect(principal).Should(Equal(auth.Principal{
+		Subject: "bob",
+		Role:    auth.RoleAdmin,
+	}))
+
+	// A token signed with the secret cannot pass for one signed with the private key
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "bob",
+		"role": "admin",
+	}).SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}))
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.Authenticate(token)
+	g.Expect(errors.Is(err, auth.ErrInvalidCredentials)).Should(BeTrue())
+}
+
+func Test_Authenticate_Should_Reject_Invalid_Tokens(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	secret := []byte("secret")
+
+	sut, err := auth.NewJwtAuthenticatorService(auth.JwtOptions{
+		Secret:   secret,
+		Issuer:   "https://issuer.example",
+		Audience: "robots",
+	})
+	g.Expect(err).Should(BeNil())
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub":  "alice",
+			"iss":  "https://issuer.example",
+			"aud":  "robots",
+			"exp":  time.Now().Add(time.Hour).Unix(),
+			"role": "viewer",
+		}
+	}
+
+	for name, claims := range map[string]func(jwt.MapClaims){
+		"expired":        func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"other issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://other.example" },
+		"other audience": func(claims jwt.MapClaims) { claims["aud"] = "dashboard" },
+		"no subject":     func(claims jwt.MapClaims) { delete(claims, "sub") },
+		"no role":        func(claims jwt.MapClaims) { delete(claims, "role") },
+		"unknown role":   func(claims jwt.MapClaims) { claims["role"] = "guest" },
+	} {
+		tokenClaims := valid()
+		claims(tokenClaims)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tokenClaims).SignedString(secret)
+		g.Expect(err).Should(BeNil())
+
+		_, err = sut.Authenticate(token)
+		g.Expect(errors.Is(err, auth.ErrInvalidCredentials)).Should(BeTrue(), name)
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString([]byte("other secret"))
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.Authenticate(token)
+	g.Expect(errors.Is(err, auth.ErrInvalidCredentials)).Should(BeTrue())
+
+	_, err = sut.Authenticate("not a token")
+	g.Expect(errors.Is(err, auth.ErrInvalidCredentials)).Should(BeTrue())
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_auth is a generated GoMock package.
+package mock_auth
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	auth "github.com/sepisoad/robot-challange/api/internals/services/auth"
+)
+
+// MockAuthenticatorInterface is a mock of AuthenticatorInterface interface.
+type MockAuthenticatorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthenticatorInterfaceMockRecorder
+}
+
+// MockAuthenticatorInterfaceMockRecorder is the mock recorder for MockAuthenticatorInterface.
+type MockAuthenticatorInterfaceMockRecorder struct {
+	mock *MockAuthenticatorInterface
+}
+
+// NewMockAuthenticatorInterface creates a new mock instance.
+func NewMockAuthenticatorInterface(ctrl *gomock.Controller) *MockAuthenticatorInterface {
+	mock := &MockAuthenticatorInterface{ctrl: ctrl}
+	mock.recorder = &MockAuthenticatorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthenticatorInterface) EXPECT() *MockAuthenticatorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAuthenticatorInterface) Authenticate(credential string) (auth.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", credential)
+	ret0, _ := ret[0].(auth.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAuthenticatorInterfaceMockRecorder) Authenticate(credential interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAuthenticatorInterface)(nil).Authenticate), credential)
+}
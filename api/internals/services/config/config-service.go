@@ -1,15 +1,58 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/nats-io/nats.go"
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+	"github.com/sepisoad/robot-challange/api/internals/services/idempotency"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 )
 
 const (
-	PORT     = "PORT"
-	NATS_URL = "NATS_URL"
+	PORT        = "PORT"
+	NATS_URL    = "NATS_URL"
+	EVENT_CODEC = "EVENT_CODEC"
+	OUTBOX_PATH = "OUTBOX_PATH"
+	STORE_PATH  = "STORE_PATH"
+	NODE_ID     = "NODE_ID"
+
+	TASK_RETENTION = "TASK_RETENTION"
+
+	TASK_ACCEPTANCE         = "TASK_ACCEPTANCE"
+	TASK_ACCEPTANCE_TIMEOUT = "TASK_ACCEPTANCE_TIMEOUT"
+
+	IDEMPOTENCY_STORE     = "IDEMPOTENCY_STORE"
+	IDEMPOTENCY_RETENTION = "IDEMPOTENCY_RETENTION"
+
+	API_KEYS            = "API_KEYS"
+	JWT_SECRET          = "JWT_SECRET"
+	JWT_PUBLIC_KEY_FILE = "JWT_PUBLIC_KEY_FILE"
+	JWT_ISSUER          = "JWT_ISSUER"
+	JWT_AUDIENCE        = "JWT_AUDIENCE"
+	INSECURE_NO_AUTH    = "INSECURE_NO_AUTH"
+
+	NATS_TLS_CA    = "NATS_TLS_CA"
+	NATS_TLS_CERT  = "NATS_TLS_CERT"
+	NATS_TLS_KEY   = "NATS_TLS_KEY"
+	NATS_USER      = "NATS_USER"
+	NATS_PASSWORD  = "NATS_PASSWORD"
+	NATS_TOKEN     = "NATS_TOKEN"
+	NATS_NKEY_SEED = "NATS_NKEY_SEED"
+	NATS_CREDS     = "NATS_CREDS"
+
+	STREAM_NAME      = "STREAM_NAME"
+	STREAM_RETENTION = "STREAM_RETENTION"
+	STREAM_MAX_AGE   = "STREAM_MAX_AGE"
+	STREAM_MAX_BYTES = "STREAM_MAX_BYTES"
+	STREAM_MAX_MSGS  = "STREAM_MAX_MSGS"
+	STREAM_REPLICAS  = "STREAM_REPLICAS"
+	STREAM_STORAGE   = "STREAM_STORAGE"
 )
 
 // configService implements ConfigInterface contract
@@ -45,3 +88,200 @@ func (p *configService) GetNatsUrl() string {
 
 	return val
 }
+
+// GetEventCodec returns the name of the codec events are published with
+func (p *configService) GetEventCodec() string {
+	val := os.Getenv(EVENT_CODEC)
+	if val == "" {
+		return eventcodec.JSON
+	}
+
+	return val
+}
+
+// GetOutboxPath returns the path of the journal task events are stored in until NATS has
+// persisted them, the outbox is disabled when OUTBOX_PATH is set to an empty value
+func (p *configService) GetOutboxPath() string {
+	val, found := os.LookupEnv(OUTBOX_PATH)
+	if !found {
+		return "outbox.jsonl"
+	}
+
+	return val
+}
+
+// GetStorePath returns the path of the file tasks and robots are stored in, they are kept in
+// memory only when STORE_PATH is set to an empty value
+func (p *configService) GetStorePath() string {
+	val, found := os.LookupEnv(STORE_PATH)
+	if !found {
+		return "store.jsonl"
+	}
+
+	return val
+}
+
+// GetTaskRetention returns how long the store keeps the finished tasks, TASK_RETENTION is a
+// duration and 0 keeps them forever
+func (p *configService) GetTaskRetention() (time.Duration, error) {
+	val := os.Getenv(TASK_RETENTION)
+	if val == "" {
+		return repository.DefaultTaskRetention, nil
+	}
+
+	retention, err := time.ParseDuration(val)
+	if err != nil || retention < 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a duration, 0 keeps the tasks forever", TASK_RETENTION, val)
+	}
+
+	return retention, nil
+}
+
+// GetNodeId returns the snowflake node task ids are generated with, every replica of the api
+// needs its own NODE_ID so their task ids do not collide, it defaults to 1
+func (p *configService) GetNodeId() (int64, error) {
+	val := os.Getenv(NODE_ID)
+	if val == "" {
+		return 1, nil
+	}
+
+	nodeId, err := strconv.ParseInt(val, 10, 64)
+	if err != nil || nodeId < 0 || nodeId > 1023 {
+		return 0, fmt.Errorf("invalid %s %q, expected a number between 0 and 1023", NODE_ID, val)
+	}
+
+	return nodeId, nil
+}
+
+// GetSyncAcceptance reports whether MoveRobot waits for the simulator to accept the tasks unless
+// a request asks otherwise, TASK_ACCEPTANCE is either async, the default, or sync
+func (p *configService) GetSyncAcceptance() (bool, error) {
+	switch val := os.Getenv(TASK_ACCEPTANCE); val {
+	case "", "async":
+		return false, nil
+	case "sync":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid %s %q, expected async or sync", TASK_ACCEPTANCE, val)
+	}
+}
+
+// GetAcceptanceTimeout returns how long MoveRobot waits for the simulator to accept a task, zero
+// means the default timeout
+func (p *configService) GetAcceptanceTimeout() (time.Duration, error) {
+	val := os.Getenv(TASK_ACCEPTANCE_TIMEOUT)
+	if val == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(val)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a positive duration", TASK_ACCEPTANCE_TIMEOUT, val)
+	}
+
+	return timeout, nil
+}
+
+// GetIdempotencyStore returns where the responses of the requests sent with an Idempotency-Key
+// header are kept, IDEMPOTENCY_STORE is either nats, the default, or memory
+func (p *configService) GetIdempotencyStore() (string, error) {
+	switch val := os.Getenv(IDEMPOTENCY_STORE); val {
+	case "", idempotency.StoreNats:
+		return idempotency.StoreNats, nil
+	case idempotency.StoreMemory:
+		return idempotency.StoreMemory, nil
+	default:
+		return "", fmt.Errorf("invalid %s %q, expected nats or memory", IDEMPOTENCY_STORE, val)
+	}
+}
+
+// GetIdempotencyRetention returns how long the responses of the requests sent with an
+// Idempotency-Key header are kept
+func (p *configService) GetIdempotencyRetention() (time.Duration, error) {
+	val := os.Getenv(IDEMPOTENCY_RETENTION)
+	if val == "" {
+		return idempotency.DefaultRetention, nil
+	}
+
+	retention, err := time.ParseDuration(val)
+	if err != nil || retention <= 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a positive duration", IDEMPOTENCY_RETENTION, val)
+	}
+
+	return retention, nil
+}
+
+// GetApiKeys returns the static api keys clients authenticate with, API_KEYS is a comma
+// separated list of <name>:<role>:<key>
+func (p *configService) GetApiKeys() ([]auth.ApiKey, error) {
+	apiKeys, err := auth.ParseApiKeys(os.Getenv(API_KEYS))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", API_KEYS, err)
+	}
+
+	return apiKeys, nil
+}
+
+// GetJwtOptions returns how the JSON web tokens clients authenticate with are verified, either
+// with JWT_SECRET or with the PEM encoded key in JWT_PUBLIC_KEY_FILE. Tokens are not accepted
+// when neither is set
+func (p *configService) GetJwtOptions() (auth.JwtOptions, error) {
+	options := auth.JwtOptions{
+		Secret:   []byte(os.Getenv(JWT_SECRET)),
+		Issuer:   os.Getenv(JWT_ISSUER),
+		Audience: os.Getenv(JWT_AUDIENCE),
+	}
+
+	if path := os.Getenv(JWT_PUBLIC_KEY_FILE); path != "" {
+		publicKey, err := os.ReadFile(path)
+		if err != nil {
+			return auth.JwtOptions{}, fmt.Errorf("invalid %s: %w", JWT_PUBLIC_KEY_FILE, err)
+		}
+
+		options.PublicKey = publicKey
+	}
+
+	return options, nil
+}
+
+// GetInsecureNoAuth reports whether the requests are served unauthenticated when there are
+// neither api keys nor a way to verify tokens, INSECURE_NO_AUTH is a boolean and defaults to false
+func (p *configService) GetInsecureNoAuth() (bool, error) {
+	val := os.Getenv(INSECURE_NO_AUTH)
+	if val == "" {
+		return false, nil
+	}
+
+	insecureNoAuth, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q, expected true or false", INSECURE_NO_AUTH, val)
+	}
+
+	return insecureNoAuth, nil
+}
+
+// GetConnectionConfig returns the settings needed to connect to a secured NATS server
+func (p *configService) GetConnectionConfig() robotbroker.ConnectionConfig {
+	return robotbroker.ConnectionConfig{
+		TLSCAFile:    os.Getenv(NATS_TLS_CA),
+		TLSCertFile:  os.Getenv(NATS_TLS_CERT),
+		TLSKeyFile:   os.Getenv(NATS_TLS_KEY),
+		User:         os.Getenv(NATS_USER),
+		Password:     os.Getenv(NATS_PASSWORD),
+		Token:        os.Getenv(NATS_TOKEN),
+		NKeySeedFile: os.Getenv(NATS_NKEY_SEED),
+		CredsFile:    os.Getenv(NATS_CREDS),
+	}
+}
+
+// GetStreamConfig returns the settings of the stream events are kept in
+func (p *configService) GetStreamConfig() (robotbroker.StreamConfig, error) {
+	return robotbroker.NewStreamConfig(
+		os.Getenv(STREAM_NAME),
+		os.Getenv(STREAM_RETENTION),
+		os.Getenv(STREAM_MAX_AGE),
+		os.Getenv(STREAM_MAX_BYTES),
+		os.Getenv(STREAM_MAX_MSGS),
+		os.Getenv(STREAM_REPLICAS),
+		os.Getenv(STREAM_STORAGE))
+}
@@ -1,7 +1,28 @@
 package config
 
+import (
+	"time"
+
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
 // ConfigInterface defines the contracts for a configuration service
 type ConfigInterface interface {
 	GetListeningPort() int
 	GetNatsUrl() string
+	GetEventCodec() string
+	GetOutboxPath() string
+	GetStorePath() string
+	GetTaskRetention() (time.Duration, error)
+	GetNodeId() (int64, error)
+	GetSyncAcceptance() (bool, error)
+	GetAcceptanceTimeout() (time.Duration, error)
+	GetIdempotencyStore() (string, error)
+	GetIdempotencyRetention() (time.Duration, error)
+	GetApiKeys() ([]auth.ApiKey, error)
+	GetJwtOptions() (auth.JwtOptions, error)
+	GetInsecureNoAuth() (bool, error)
+	GetConnectionConfig() robotbroker.ConnectionConfig
+	GetStreamConfig() (robotbroker.StreamConfig, error)
 }
@@ -6,8 +6,11 @@ package mock_config
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
+	auth "github.com/sepisoad/robot-challange/api/internals/services/auth"
+	robotbroker "github.com/sepisoad/robot-challange/shared/services/robotbroker"
 )
 
 // MockConfigInterface is a mock of ConfigInterface interface.
@@ -33,6 +36,124 @@ func (m *MockConfigInterface) EXPECT() *MockConfigInterfaceMockRecorder {
 	return m.recorder
 }
 
+// GetAcceptanceTimeout mocks base method.
+func (m *MockConfigInterface) GetAcceptanceTimeout() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAcceptanceTimeout")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAcceptanceTimeout indicates an expected call of GetAcceptanceTimeout.
+func (mr *MockConfigInterfaceMockRecorder) GetAcceptanceTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAcceptanceTimeout", reflect.TypeOf((*MockConfigInterface)(nil).GetAcceptanceTimeout))
+}
+
+// GetApiKeys mocks base method.
+func (m *MockConfigInterface) GetApiKeys() ([]auth.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApiKeys")
+	ret0, _ := ret[0].([]auth.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApiKeys indicates an expected call of GetApiKeys.
+func (mr *MockConfigInterfaceMockRecorder) GetApiKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApiKeys", reflect.TypeOf((*MockConfigInterface)(nil).GetApiKeys))
+}
+
+// GetConnectionConfig mocks base method.
+func (m *MockConfigInterface) GetConnectionConfig() robotbroker.ConnectionConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConnectionConfig")
+	ret0, _ := ret[0].(robotbroker.ConnectionConfig)
+	return ret0
+}
+
+// GetConnectionConfig indicates an expected call of GetConnectionConfig.
+func (mr *MockConfigInterfaceMockRecorder) GetConnectionConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConnectionConfig", reflect.TypeOf((*MockConfigInterface)(nil).GetConnectionConfig))
+}
+
+// GetEventCodec mocks base method.
+func (m *MockConfigInterface) GetEventCodec() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventCodec")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetEventCodec indicates an expected call of GetEventCodec.
+func (mr *MockConfigInterfaceMockRecorder) GetEventCodec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventCodec", reflect.TypeOf((*MockConfigInterface)(nil).GetEventCodec))
+}
+
+// GetIdempotencyRetention mocks base method.
+func (m *MockConfigInterface) GetIdempotencyRetention() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyRetention")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyRetention indicates an expected call of GetIdempotencyRetention.
+func (mr *MockConfigInterfaceMockRecorder) GetIdempotencyRetention() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyRetention", reflect.TypeOf((*MockConfigInterface)(nil).GetIdempotencyRetention))
+}
+
+// GetIdempotencyStore mocks base method.
+func (m *MockConfigInterface) GetIdempotencyStore() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdempotencyStore")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdempotencyStore indicates an expected call of GetIdempotencyStore.
+func (mr *MockConfigInterfaceMockRecorder) GetIdempotencyStore() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdempotencyStore", reflect.TypeOf((*MockConfigInterface)(nil).GetIdempotencyStore))
+}
+
+// GetInsecureNoAuth mocks base method.
+func (m *MockConfigInterface) GetInsecureNoAuth() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInsecureNoAuth")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInsecureNoAuth indicates an expected call of GetInsecureNoAuth.
+func (mr *MockConfigInterfaceMockRecorder) GetInsecureNoAuth() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInsecureNoAuth", reflect.TypeOf((*MockConfigInterface)(nil).GetInsecureNoAuth))
+}
+
+// GetJwtOptions mocks base method.
+func (m *MockConfigInterface) GetJwtOptions() (auth.JwtOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJwtOptions")
+	ret0, _ := ret[0].(auth.JwtOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJwtOptions indicates an expected call of GetJwtOptions.
+func (mr *MockConfigInterfaceMockRecorder) GetJwtOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJwtOptions", reflect.TypeOf((*MockConfigInterface)(nil).GetJwtOptions))
+}
+
 // GetListeningPort mocks base method.
 func (m *MockConfigInterface) GetListeningPort() int {
 	m.ctrl.T.Helper()
@@ -60,3 +181,91 @@ func (mr *MockConfigInterfaceMockRecorder) GetNatsUrl() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNatsUrl", reflect.TypeOf((*MockConfigInterface)(nil).GetNatsUrl))
 }
+
+// GetNodeId mocks base method.
+func (m *MockConfigInterface) GetNodeId() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeId")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeId indicates an expected call of GetNodeId.
+func (mr *MockConfigInterfaceMockRecorder) GetNodeId() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeId", reflect.TypeOf((*MockConfigInterface)(nil).GetNodeId))
+}
+
+// GetOutboxPath mocks base method.
+func (m *MockConfigInterface) GetOutboxPath() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutboxPath")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetOutboxPath indicates an expected call of GetOutboxPath.
+func (mr *MockConfigInterfaceMockRecorder) GetOutboxPath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutboxPath", reflect.TypeOf((*MockConfigInterface)(nil).GetOutboxPath))
+}
+
+// GetStorePath mocks base method.
+func (m *MockConfigInterface) GetStorePath() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStorePath")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetStorePath indicates an expected call of GetStorePath.
+func (mr *MockConfigInterfaceMockRecorder) GetStorePath() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStorePath", reflect.TypeOf((*MockConfigInterface)(nil).GetStorePath))
+}
+
+// GetStreamConfig mocks base method.
+func (m *MockConfigInterface) GetStreamConfig() (robotbroker.StreamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamConfig")
+	ret0, _ := ret[0].(robotbroker.StreamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamConfig indicates an expected call of GetStreamConfig.
+func (mr *MockConfigInterfaceMockRecorder) GetStreamConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamConfig", reflect.TypeOf((*MockConfigInterface)(nil).GetStreamConfig))
+}
+
+// GetSyncAcceptance mocks base method.
+func (m *MockConfigInterface) GetSyncAcceptance() (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSyncAcceptance")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSyncAcceptance indicates an expected call of GetSyncAcceptance.
+func (mr *MockConfigInterfaceMockRecorder) GetSyncAcceptance() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSyncAcceptance", reflect.TypeOf((*MockConfigInterface)(nil).GetSyncAcceptance))
+}
+
+// GetTaskRetention mocks base method.
+func (m *MockConfigInterface) GetTaskRetention() (time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaskRetention")
+	ret0, _ := ret[0].(time.Duration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaskRetention indicates an expected call of GetTaskRetention.
+func (mr *MockConfigInterfaceMockRecorder) GetTaskRetention() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaskRetention", reflect.TypeOf((*MockConfigInterface)(nil).GetTaskRetention))
+}
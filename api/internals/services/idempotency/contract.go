@@ -0,0 +1,44 @@
+package idempotency
+
+import (
+	"time"
+)
+
+const (
+	// StoreMemory keeps the keys in the memory of a single api
+	StoreMemory = "memory"
+	// StoreNats keeps the keys in a NATS key value bucket shared by the replicas of the api
+	StoreNats = "nats"
+)
+
+// DefaultRetention is how long the response of a request is kept unless told otherwise
+const DefaultRetention = 24 * time.Hour
+
+// ReservationTimeout is how long a request holds its key before a retry can take it over,
+// e.g. when the api which was processing the request stopped
+const ReservationTimeout = time.Minute
+
+// Record is what is kept for an idempotency key
+type Record struct {
+	// Fingerprint identifies the request the key was first sent with
+	Fingerprint string `json:"fingerprint"`
+	// ReservedAt is when the request started being processed
+	ReservedAt time.Time `json:"reservedAt"`
+	// Completed is false while the request is being processed
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// KeyStoreInterface defines contract for storing the idempotency keys, records are forgotten
+// once the retention of the store is over
+type KeyStoreInterface interface {
+	// Reserve stores an uncompleted record for key unless it has a record already, which is
+	// returned with true. A reservation older than ReservationTimeout is taken over
+	Reserve(key string, fingerprint string) (Record, bool, error)
+	// Complete stores the response of the request key was reserved for
+	Complete(key string, record Record) error
+	// Release forgets key so the request can be retried
+	Release(key string) error
+}
@@ -0,0 +1,6 @@
+/*
+idempotency package stores the responses of the requests sent with an Idempotency-Key header
+so a retried request gets the same response instead of being processed twice
+*/
+
+package idempotency
@@ -0,0 +1,96 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryKeyStoreService implements KeyStoreInterface contract, the keys are only known to
+// the api which stores them
+type memoryKeyStoreService struct {
+	mutex     *sync.Mutex
+	retention time.Duration
+	records   map[string]memoryRecord
+	purgedAt  time.Time
+}
+
+type memoryRecord struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// NewMemoryKeyStoreService creates a concrete instance of KeyStoreInterface which keeps the
+// keys in memory for retention
+func NewMemoryKeyStoreService(retention time.Duration) (KeyStoreInterface, error) {
+	return &memoryKeyStoreService{
+		mutex:     &sync.Mutex{},
+		retention: retention,
+		records:   make(map[string]memoryRecord),
+	}, nil
+}
+
+// Reserve stores an uncompleted record for key unless it has a record already
+func (s *memoryKeyStoreService) Reserve(key string, fingerprint string) (Record, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now().UTC()
+	s.purge(now)
+
+	if stored, found := s.records[key]; found &&
+		now.Before(stored.expiresAt) &&
+		(stored.record.Completed || now.Sub(stored.record.ReservedAt) < ReservationTimeout) {
+		return stored.record, true, nil
+	}
+
+	s.records[key] = memoryRecord{
+		record: Record{
+			Fingerprint: fingerprint,
+			ReservedAt:  now,
+		},
+		expiresAt: now.Add(s.retention),
+	}
+
+	return Record{}, false, nil
+}
+
+// Complete stores the response of the request key was reserved for
+func (s *memoryKeyStoreService) Complete(key string, record Record) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record.Completed = true
+
+	s.records[key] = memoryRecord{
+		record:    record,
+		expiresAt: time.Now().UTC().Add(s.retention),
+	}
+
+	return nil
+}
+
+// Release forgets key
+func (s *memoryKeyStoreService) Release(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
+
+// purge forgets the records whose retention is over once a minute at most, it must be called
+// with the lock held
+func (s *memoryKeyStoreService) purge(now time.Time) {
+	if now.Sub(s.purgedAt) < time.Minute {
+		return
+	}
+
+	s.purgedAt = now
+
+	for key, stored := range s.records {
+		if !now.Before(stored.expiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package idempotency_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/idempotency"
+)
+
+func Test_Reserve_Should_Return_The_Record_Of_A_Key_Used_Already(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := idempotency.NewMemoryKeyStoreService(time.Hour)
+	g.Expect(err).Should(BeNil())
+
+	_, found, err := sut.Reserve("key", "fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeFalse())
+
+	record, found, err := sut.Reserve("key", "other fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeTrue())
+	g.Expect(record.Fingerprint).Should(Equal("fingerprint"))
+	g.Expect(record.Completed).Should(BeFalse())
+
+	g.Expect(sut.Complete("key", idempotency.Record{
+		Fingerprint: "fingerprint",
+		StatusCode:  202,
+	})).Should(Succeed())
+
+	record, found, err = sut.Reserve("key", "fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeTrue())
+	g.Expect(record.Completed).Should(BeTrue())
+	g.Expect(record.StatusCode).Should(Equal(202))
+}
+
+func Test_Reserve_Should_Forget_The_Keys_Once_The_Retention_Is_Over(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := idempotency.NewMemoryKeyStoreService(10 * time.Millisecond)
+	g.Expect(err).Should(BeNil())
+
+	_, found, err := sut.Reserve("key", "fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeFalse())
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, found, err = sut.Reserve("key", "fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeFalse())
+}
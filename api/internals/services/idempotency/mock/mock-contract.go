@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_idempotency is a generated GoMock package.
+package mock_idempotency
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	idempotency "github.com/sepisoad/robot-challange/api/internals/services/idempotency"
+)
+
+// MockKeyStoreInterface is a mock of KeyStoreInterface interface.
+type MockKeyStoreInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockKeyStoreInterfaceMockRecorder
+}
+
+// MockKeyStoreInterfaceMockRecorder is the mock recorder for MockKeyStoreInterface.
+type MockKeyStoreInterfaceMockRecorder struct {
+	mock *MockKeyStoreInterface
+}
+
+// NewMockKeyStoreInterface creates a new mock instance.
+func NewMockKeyStoreInterface(ctrl *gomock.Controller) *MockKeyStoreInterface {
+	mock := &MockKeyStoreInterface{ctrl: ctrl}
+	mock.recorder = &MockKeyStoreInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockKeyStoreInterface) EXPECT() *MockKeyStoreInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Complete mocks base method.
+func (m *MockKeyStoreInterface) Complete(key string, record idempotency.Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", key, record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockKeyStoreInterfaceMockRecorder) Complete(key, record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockKeyStoreInterface)(nil).Complete), key, record)
+}
+
+// Release mocks base method.
+func (m *MockKeyStoreInterface) Release(key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockKeyStoreInterfaceMockRecorder) Release(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockKeyStoreInterface)(nil).Release), key)
+}
+
+// Reserve mocks base method.
+func (m *MockKeyStoreInterface) Reserve(key, fingerprint string) (idempotency.Record, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", key, fingerprint)
+	ret0, _ := ret[0].(idempotency.Record)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockKeyStoreInterfaceMockRecorder) Reserve(key, fingerprint interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockKeyStoreInterface)(nil).Reserve), key, fingerprint)
+}
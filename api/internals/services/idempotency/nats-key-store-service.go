@@ -0,0 +1,110 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+// KeyStoreBucket is the NATS key value bucket the idempotency keys are stored in
+const KeyStoreBucket = "IdempotencyKeys"
+
+// natsKeyStoreService implements KeyStoreInterface contract, the keys are stored in a NATS
+// key value bucket so every replica of the api knows them
+type natsKeyStoreService struct {
+	keyValue nats.KeyValue
+}
+
+// NewNatsKeyStoreService creates a concrete instance of KeyStoreInterface which keeps the keys
+// in a NATS key value bucket for retention, the retention of a bucket which exists already is
+// left as it is
+func NewNatsKeyStoreService(
+	robotBrokerService robotbroker.JetStreamBrokerInterface,
+	retention time.Duration) (KeyStoreInterface, error) {
+	jetStream, err := robotBrokerService.CreateNewJetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	keyValue, err := jetStream.KeyValue(KeyStoreBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		keyValue, err = jetStream.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      KeyStoreBucket,
+			Description: "Responses of the requests sent to the api with an Idempotency-Key header",
+			TTL:         retention,
+			Storage:     nats.FileStorage,
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &natsKeyStoreService{
+		keyValue: keyValue,
+	}, nil
+}
+
+// Reserve stores an uncompleted record for key unless it has a record already, the replicas
+// race for a key through the revision of its entry
+func (s *natsKeyStoreService) Reserve(key string, fingerprint string) (Record, bool, error) {
+	value, err := json.Marshal(Record{
+		Fingerprint: fingerprint,
+		ReservedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	_, createErr := s.keyValue.Create(key, value)
+	if createErr == nil {
+		return Record{}, false, nil
+	}
+
+	entry, err := s.keyValue.Get(key)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return Record{}, false, createErr
+	}
+
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	var record Record
+	if err := json.Unmarshal(entry.Value(), &record); err != nil {
+		return Record{}, false, err
+	}
+
+	if record.Completed || time.Since(record.ReservedAt) < ReservationTimeout {
+		return record, true, nil
+	}
+
+	// The request which reserved the key is given up on, another replica may take it over first
+	if _, err := s.keyValue.Update(key, value, entry.Revision()); err != nil {
+		return record, true, nil
+	}
+
+	return Record{}, false, nil
+}
+
+// Complete stores the response of the request key was reserved for
+func (s *natsKeyStoreService) Complete(key string, record Record) error {
+	record.Completed = true
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.keyValue.Put(key, value)
+
+	return err
+}
+
+// Release forgets key
+func (s *natsKeyStoreService) Release(key string) error {
+	return s.keyValue.Delete(key)
+}
@@ -0,0 +1,74 @@
+package idempotency_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/idempotency"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_Reserve_Should_Share_The_Keys_Between_Replicas(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	defer embeddedNatsService.Shutdown()
+
+	newReplica := func() idempotency.KeyStoreInterface {
+		robotBrokerService, err := robotbroker.NewRobotBrokerService(
+			sugarLogger,
+			"test",
+			embeddedNatsService.ClientURL(),
+			robotbroker.ConnectionConfig{},
+			robotbroker.DefaultStreamConfig())
+		g.Expect(err).Should(BeNil())
+		t.Cleanup(robotBrokerService.Close)
+
+		sut, err := idempotency.NewNatsKeyStoreService(robotBrokerService, time.Hour)
+		g.Expect(err).Should(BeNil())
+
+		return sut
+	}
+
+	first, second := newReplica(), newReplica()
+
+	_, found, err := first.Reserve("key", "fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeFalse())
+
+	// The request is being processed by the first replica
+	record, found, err := second.Reserve("key", "fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeTrue())
+	g.Expect(record.Completed).Should(BeFalse())
+
+	g.Expect(first.Complete("key", idempotency.Record{
+		Fingerprint: "fingerprint",
+		StatusCode:  202,
+		ContentType: "application/json",
+		Body:        []byte(`{"id":1}`),
+	})).Should(Succeed())
+
+	record, found, err = second.Reserve("key", "fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeTrue())
+	g.Expect(record).Should(And(
+		HaveField("Completed", true),
+		HaveField("StatusCode", 202),
+		HaveField("Body", []byte(`{"id":1}`))))
+
+	// A released key can be reserved again
+	g.Expect(second.Release("key")).Should(Succeed())
+
+	_, found, err = first.Reserve("key", "fingerprint")
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeFalse())
+}
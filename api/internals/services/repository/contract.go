@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+)
+
+// TaskStatus defines running tasks status
+type TaskStatus string
+
+const (
+	// TaskStatusQueuedForDispatch is used to denote a task which is waiting in the outbox
+	TaskStatusQueuedForDispatch TaskStatus = "QueuedForDispatch"
+	// TaskStatusCreated is used to denote a Created task
+	TaskStatusCreated TaskStatus = "Created"
+	// TaskStatusInProgress is used to denote an InProgress task
+	TaskStatusInProgress TaskStatus = "InProgress"
+	// TaskStatusCompleted is used to denote a Completed task
+	TaskStatusCompleted TaskStatus = "Completed"
+	// TaskStatusFailed is used to denote a task the robot could not make some of the moves of
+	TaskStatusFailed TaskStatus = "Failed"
+	// TaskStatusCancelled is used to denote a Cancelled task
+	TaskStatusCancelled TaskStatus = "Cancelled"
+)
+
+// Position is a cell of the warehouse
+type Position struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Task is what is known about a task
+type Task struct {
+	Id            int64                                         `json:"id"`
+	RobotId       int64                                         `json:"robotId"`
+	BatchId       string                                        `json:"batchId,omitempty"`
+	MoveSequences []eventpublisher.MoveRobotRequestMoveSequence `json:"moveSequences,omitempty"`
+	Status        TaskStatus                                    `json:"status"`
+	CreatedAt     time.Time                                     `json:"createdAt"`
+	StartedAt     *time.Time                                    `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time                                    `json:"finishedAt,omitempty"`
+	// FailureReason tells why the robot could not make some of the moves of a Failed task
+	FailureReason string `json:"failureReason,omitempty"`
+	// Trajectory lists the positions of the robot while it executed the task
+	Trajectory []Position `json:"trajectory,omitempty"`
+}
+
+// Robot is the last known state of a robot
+type Robot struct {
+	Id       int64 `json:"id"`
+	X        int   `json:"x"`
+	Y        int   `json:"y"`
+	HasCrate bool  `json:"hasCrate,omitempty"`
+	// Failed tells whether the last move of the robot failed
+	Failed bool `json:"failed,omitempty"`
+	// LastError is the reason the robot last failed to move
+	LastError string `json:"lastError,omitempty"`
+	// UpdatedAt is when the robot last reported, whether it moved or not
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DefaultTaskRetention is how long finished tasks are kept unless told otherwise
+const DefaultTaskRetention = 7 * 24 * time.Hour
+
+// Options configures a repository
+type Options struct {
+	// TaskRetention is how long a task is kept once it is finished, the tasks past it are evicted
+	// when the journal of a file is compacted, and every 1000 changes of the tasks when they are
+	// kept in memory. Tasks are kept forever when it is zero
+	TaskRetention time.Duration
+}
+
+// RepositoryInterface defines contract for storing the tasks and the robots known to the api,
+// the returned tasks and robots are copies
+type RepositoryInterface interface {
+	GetTask(id int64) (Task, bool, error)
+	// ListTasks returns the tasks in no particular order
+	ListTasks() ([]Task, error)
+	// SaveTask stores a task, replacing the one with the same id
+	SaveTask(task Task) error
+	// MergeTask stores what update knows about a task into the stored one, see Task.Merge
+	MergeTask(update Task) (Task, error)
+	DeleteTask(id int64) error
+	GetRobot(id int64) (Robot, bool, error)
+	// ListRobots returns the robots in no particular order
+	ListRobots() ([]Robot, error)
+	// SaveRobot stores a robot, replacing the one with the same id
+	SaveRobot(robot Robot) error
+	Close() error
+}
+
+// Position returns where the robot stopped once the task is finished
+func (t Task) Position() *Position {
+	if t.FinishedAt == nil || len(t.Trajectory) == 0 {
+		return nil
+	}
+
+	position := t.Trajectory[len(t.Trajectory)-1]
+
+	return &position
+}
+
+// Merge returns the task updated with what update knows about it, events of a task are
+// delivered by different subscriptions so the status of a task never goes back
+func (t Task) Merge(update Task) Task {
+	if t.Id == 0 {
+		t.Id = update.Id
+		t.RobotId = update.RobotId
+	}
+
+	// Only the created event of a task tells its batch
+	if t.BatchId == "" {
+		t.BatchId = update.BatchId
+	}
+
+	if update.MoveSequences != nil {
+		t.MoveSequences = update.MoveSequences
+	}
+
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = update.CreatedAt
+	}
+
+	if update.StartedAt != nil {
+		t.StartedAt = update.StartedAt
+	}
+
+	if update.FinishedAt != nil {
+		t.FinishedAt = update.FinishedAt
+		t.FailureReason = update.FailureReason
+		t.Trajectory = update.Trajectory
+	}
+
+	if taskStatusRanks[update.Status] > taskStatusRanks[t.Status] {
+		t.Status = update.Status
+	}
+
+	return t
+}
+
+// IsFinished reports whether the robot is done with the task
+func (s TaskStatus) IsFinished() bool {
+	return taskStatusRanks[s] == taskStatusRanks[TaskStatusCompleted]
+}
+
+var taskStatusRanks = map[TaskStatus]int{
+	TaskStatusQueuedForDispatch: 1,
+	TaskStatusCreated:           2,
+	TaskStatusInProgress:        3,
+	TaskStatusCompleted:         4,
+	TaskStatusFailed:            4,
+	TaskStatusCancelled:         4,
+}
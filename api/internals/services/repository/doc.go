@@ -0,0 +1,5 @@
+/*
+repository package stores the tasks and the robots known to the api
+*/
+
+package repository
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/journal"
+	"go.uber.org/zap"
+)
+
+const (
+	recordTask       = "task"
+	recordDeleteTask = "deleteTask"
+	recordRobot      = "robot"
+)
+
+// minRecordsBeforeCompaction is the number of records the journal holds at least before it is
+// rewritten with the latest version of every task and robot
+const minRecordsBeforeCompaction = 1000
+
+// record is a line of the journal the repository is persisted in
+type record struct {
+	Op    string `json:"op"`
+	Task  *Task  `json:"task,omitempty"`
+	Robot *Robot `json:"robot,omitempty"`
+	Id    int64  `json:"id,omitempty"`
+}
+
+// fileRepositoryService implements RepositoryInterface contract, it keeps everything in memory
+// and appends every change to a journal file which is replayed when the api starts
+type fileRepositoryService struct {
+	logger  *zap.SugaredLogger
+	path    string
+	options Options
+	mutex   *sync.Mutex
+	state   *memoryRepositoryService
+	journal journal.JournalInterface
+	records int
+}
+
+// NewFileRepositoryService creates a concrete instance of RepositoryInterface which persists
+// the tasks and the robots in a journal file at path
+func NewFileRepositoryService(
+	logger *zap.SugaredLogger,
+	path string,
+	options Options) (RepositoryInterface, error) {
+	s := &fileRepositoryService{
+		logger:  logger,
+		path:    path,
+		options: options,
+		mutex:   &sync.Mutex{},
+		state:   newMemoryRepositoryService(),
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	logger.Infof(
+		"Loaded %d tasks and %d robots from %s",
+		len(s.state.tasks),
+		len(s.state.robots),
+		path)
+
+	return s, nil
+}
+
+// GetTask returns a task by its id
+func (s *fileRepositoryService) GetTask(id int64) (Task, bool, error) {
+	return s.state.GetTask(id)
+}
+
+// ListTasks returns all the tasks
+func (s *fileRepositoryService) ListTasks() ([]Task, error) {
+	return s.state.ListTasks()
+}
+
+// SaveTask stores a task, it returns once the task is on disk
+func (s *fileRepositoryService) SaveTask(task Task) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.write(record{Op: recordTask, Task: &task}, true); err != nil {
+		return err
+	}
+
+	return s.state.SaveTask(task)
+}
+
+// MergeTask merges update into the stored task, it returns once the task is on disk
+func (s *fileRepositoryService) MergeTask(update Task) (Task, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	task, _, _ := s.state.GetTask(update.Id)
+	task = task.Merge(update)
+
+	if err := s.write(record{Op: recordTask, Task: &task}, true); err != nil {
+		return Task{}, err
+	}
+
+	return task, s.state.SaveTask(task)
+}
+
+// DeleteTask forgets a task, it returns once the deletion is on disk
+func (s *fileRepositoryService) DeleteTask(id int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.write(record{Op: recordDeleteTask, Id: id}, true); err != nil {
+		return err
+	}
+
+	return s.state.DeleteTask(id)
+}
+
+// GetRobot returns a robot by its id
+func (s *fileRepositoryService) GetRobot(id int64) (Robot, bool, error) {
+	return s.state.GetRobot(id)
+}
+
+// ListRobots returns all the robots
+func (s *fileRepositoryService) ListRobots() ([]Robot, error) {
+	return s.state.ListRobots()
+}
+
+// SaveRobot stores a robot without waiting for it to reach the disk, it gets there with the
+// next task or when the repository is closed. The robots report every move so a crash loses at
+// most the last known positions, which the next moves tell again
+func (s *fileRepositoryService) SaveRobot(robot Robot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.write(record{Op: recordRobot, Robot: &robot}, false); err != nil {
+		return err
+	}
+
+	return s.state.SaveRobot(robot)
+}
+
+// Close closes the journal
+func (s *fileRepositoryService) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.journal == nil {
+		return nil
+	}
+
+	err := s.journal.Close()
+	s.journal = nil
+
+	return err
+}
+
+// write appends a record to the journal and, when sync is set, waits for it to reach the disk
+// with the records written before. The journal is compacted once most of its records are
+// outdated. It must be called with the lock held
+func (s *fileRepositoryService) write(r record, sync bool) error {
+	if s.journal == nil {
+		return os.ErrClosed
+	}
+
+	if err := s.journal.Append(r, sync); err != nil {
+		return err
+	}
+
+	s.records++
+
+	live := len(s.state.tasks) + len(s.state.robots)
+	if s.records >= minRecordsBeforeCompaction && s.records > 2*live {
+		if err := s.journal.Close(); err != nil {
+			return err
+		}
+
+		s.journal = nil
+
+		// The record is on disk already, the journal is rewritten on the next start at worst
+		if err := s.compact(); err != nil {
+			s.logger.Errorf("Failed to compact the repository %s. Error: %v", s.path, err)
+
+			return s.reopen()
+		}
+	}
+
+	return nil
+}
+
+// open replays the journal and compacts it
+func (s *fileRepositoryService) open() error {
+	if err := readJournal(s.path, s.state); err != nil {
+		return err
+	}
+
+	return s.compact()
+}
+
+// compact evicts the tasks which are past their retention, then rewrites the journal with a
+// record for every task and robot and opens it for appending
+func (s *fileRepositoryService) compact() error {
+	if s.options.TaskRetention > 0 {
+		if evicted := s.state.evictTasks(time.Now().Add(-s.options.TaskRetention)); evicted > 0 {
+			s.logger.Infof("Evicted %d tasks finished more than %v ago from %s", evicted, s.options.TaskRetention, s.path)
+		}
+	}
+
+	records := make([]interface{}, 0, len(s.state.tasks)+len(s.state.robots))
+
+	for _, task := range s.state.tasks {
+		task := task
+
+		records = append(records, record{Op: recordTask, Task: &task})
+	}
+
+	for _, robot := range s.state.robots {
+		robot := robot
+
+		records = append(records, record{Op: recordRobot, Robot: &robot})
+	}
+
+	if err := journal.Rewrite(s.path, records); err != nil {
+		return err
+	}
+
+	s.records = len(records)
+
+	return s.reopen()
+}
+
+func (s *fileRepositoryService) reopen() (err error) {
+	s.journal, err = journal.NewJournalService(s.logger, s.path)
+
+	return err
+}
+
+func readJournal(path string, state *memoryRepositoryService) error {
+	return journal.Read(path, func(buf []byte) error {
+		var r record
+		if err := json.Unmarshal(buf, &r); err != nil {
+			return err
+		}
+
+		switch {
+		case r.Op == recordTask && r.Task != nil:
+			state.tasks[r.Task.Id] = *r.Task
+		case r.Op == recordDeleteTask:
+			delete(state.tasks, r.Id)
+		case r.Op == recordRobot && r.Robot != nil:
+			state.robots[r.Robot.Id] = *r.Robot
+		default:
+			return fmt.Errorf("unknown record %q", r.Op)
+		}
+
+		return nil
+	})
+}
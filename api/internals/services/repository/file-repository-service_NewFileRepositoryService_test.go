@@ -0,0 +1,152 @@
+package repository_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"go.uber.org/zap"
+)
+
+func Test_NewFileRepositoryService_Should_Load_What_A_Previous_Run_Stored(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "store.jsonl")
+	createdAt := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	finishedAt := createdAt.Add(time.Second)
+
+	sut, err := repository.NewFileRepositoryService(sugarLogger, path, repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.SaveTask(repository.Task{
+		Id:        1,
+		RobotId:   2,
+		Status:    repository.TaskStatusCreated,
+		CreatedAt: createdAt,
+	})).Should(Succeed())
+	g.Expect(sut.SaveTask(repository.Task{Id: 2, Status: repository.TaskStatusCreated})).Should(Succeed())
+	g.Expect(sut.DeleteTask(2)).Should(Succeed())
+
+	_, err = sut.MergeTask(repository.Task{
+		Id:         1,
+		Status:     repository.TaskStatusCompleted,
+		FinishedAt: &finishedAt,
+		Trajectory: []repository.Position{{X: 1, Y: 0}},
+	})
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.SaveRobot(repository.Robot{Id: 2, X: 1, UpdatedAt: finishedAt})).Should(Succeed())
+	g.Expect(sut.Close()).Should(Succeed())
+
+	sut, err = repository.NewFileRepositoryService(sugarLogger, path, repository.Options{})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	tasks, err := sut.ListTasks()
+	g.Expect(err).Should(BeNil())
+	g.Expect(tasks).Should(Equal([]repository.Task{{
+		Id:         1,
+		RobotId:    2,
+		Status:     repository.TaskStatusCompleted,
+		CreatedAt:  createdAt,
+		FinishedAt: &finishedAt,
+		Trajectory: []repository.Position{{X: 1, Y: 0}},
+	}}))
+
+	robot, found, err := sut.GetRobot(2)
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeTrue())
+	g.Expect(robot).Should(Equal(repository.Robot{Id: 2, X: 1, UpdatedAt: finishedAt}))
+}
+
+func Test_NewFileRepositoryService_Should_Skip_A_Truncated_Last_Line(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "store.jsonl")
+	g.Expect(os.WriteFile(
+		path,
+		[]byte("{\"op\":\"task\",\"task\":{\"id\":1,\"status\":\"Created\"}}\n{\"op\":\"task\",\"ta"),
+		0600)).Should(Succeed())
+
+	sut, err := repository.NewFileRepositoryService(sugarLogger, path, repository.Options{})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	_, found, err := sut.GetTask(1)
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeTrue())
+}
+
+func Test_NewFileRepositoryService_Should_Fail_When_The_Journal_Is_Corrupted(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "store.jsonl")
+	g.Expect(os.WriteFile(
+		path,
+		[]byte("not json\n{\"op\":\"task\",\"task\":{\"id\":1,\"status\":\"Created\"}}\n"),
+		0600)).Should(Succeed())
+
+	_, err = repository.NewFileRepositoryService(sugarLogger, path, repository.Options{})
+	g.Expect(err).ShouldNot(BeNil())
+}
+
+func Test_NewFileRepositoryService_Should_Evict_The_Tasks_Past_Their_Retention(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "store.jsonl")
+	now := time.Now().UTC()
+	longAgo := now.Add(-48 * time.Hour)
+
+	sut, err := repository.NewFileRepositoryService(sugarLogger, path, repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	tasks := []repository.Task{
+		{Id: 1, Status: repository.TaskStatusCompleted, CreatedAt: longAgo, FinishedAt: &longAgo},
+		{Id: 2, Status: repository.TaskStatusCancelled, CreatedAt: longAgo},
+		{Id: 3, Status: repository.TaskStatusCreated, CreatedAt: longAgo},
+		{Id: 4, Status: repository.TaskStatusFailed, CreatedAt: longAgo, FinishedAt: &now},
+	}
+
+	for _, task := range tasks {
+		g.Expect(sut.SaveTask(task)).Should(Succeed())
+	}
+
+	g.Expect(sut.SaveRobot(repository.Robot{Id: 2, X: 1, UpdatedAt: longAgo})).Should(Succeed())
+	g.Expect(sut.Close()).Should(Succeed())
+
+	sut, err = repository.NewFileRepositoryService(sugarLogger, path, repository.Options{
+		TaskRetention: 24 * time.Hour,
+	})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	kept, err := sut.ListTasks()
+	g.Expect(err).Should(BeNil())
+	g.Expect(kept).Should(ConsistOf(
+		HaveField("Id", int64(3)),
+		HaveField("Id", int64(4))))
+
+	// Robots are never evicted
+	_, found, err := sut.GetRobot(2)
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeTrue())
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryRepositoryService implements RepositoryInterface contract, everything it stores is lost
+// when the api stops
+type memoryRepositoryService struct {
+	mutex   *sync.Mutex
+	options Options
+	tasks   map[int64]Task
+	robots  map[int64]Robot
+	// writes is the number of changes of the tasks since the tasks were last evicted
+	writes int
+}
+
+// NewMemoryRepositoryService creates a concrete instance of RepositoryInterface which keeps
+// everything in memory
+func NewMemoryRepositoryService(options Options) (RepositoryInterface, error) {
+	s := newMemoryRepositoryService()
+	s.options = options
+
+	return s, nil
+}
+
+func newMemoryRepositoryService() *memoryRepositoryService {
+	return &memoryRepositoryService{
+		mutex:  &sync.Mutex{},
+		tasks:  make(map[int64]Task),
+		robots: make(map[int64]Robot),
+	}
+}
+
+// GetTask returns a task by its id
+func (s *memoryRepositoryService) GetTask(id int64) (Task, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	task, found := s.tasks[id]
+
+	return task, found, nil
+}
+
+// ListTasks returns all the tasks
+func (s *memoryRepositoryService) ListTasks() ([]Task, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	tasks := make([]Task, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+// SaveTask stores a task
+func (s *memoryRepositoryService) SaveTask(task Task) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tasks[task.Id] = task
+	s.written()
+
+	return nil
+}
+
+// MergeTask merges update into the stored task
+func (s *memoryRepositoryService) MergeTask(update Task) (Task, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	task := s.tasks[update.Id].Merge(update)
+	s.tasks[task.Id] = task
+	s.written()
+
+	return task, nil
+}
+
+// DeleteTask forgets a task
+func (s *memoryRepositoryService) DeleteTask(id int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.tasks, id)
+
+	return nil
+}
+
+// GetRobot returns a robot by its id
+func (s *memoryRepositoryService) GetRobot(id int64) (Robot, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	robot, found := s.robots[id]
+
+	return robot, found, nil
+}
+
+// ListRobots returns all the robots
+func (s *memoryRepositoryService) ListRobots() ([]Robot, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	robots := make([]Robot, 0, len(s.robots))
+	for _, robot := range s.robots {
+		robots = append(robots, robot)
+	}
+
+	return robots, nil
+}
+
+// SaveRobot stores a robot
+func (s *memoryRepositoryService) SaveRobot(robot Robot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.robots[robot.Id] = robot
+
+	return nil
+}
+
+// written evicts the tasks past their retention once the tasks changed as many times as a
+// journal holds at least before it is compacted. It must be called with the lock held
+func (s *memoryRepositoryService) written() {
+	if s.options.TaskRetention <= 0 {
+		return
+	}
+
+	s.writes++
+	if s.writes < minRecordsBeforeCompaction {
+		return
+	}
+
+	s.writes = 0
+	s.evict(time.Now().Add(-s.options.TaskRetention))
+}
+
+// evictTasks forgets the tasks finished before, or cancelled before when the simulator never
+// reported they finished, and returns how many it forgot
+func (s *memoryRepositoryService) evictTasks(before time.Time) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.evict(before)
+}
+
+// evict is evictTasks, it must be called with the lock held
+func (s *memoryRepositoryService) evict(before time.Time) int {
+	evicted := 0
+
+	for id, task := range s.tasks {
+		if !task.Status.IsFinished() {
+			continue
+		}
+
+		finishedAt := task.CreatedAt
+		if task.FinishedAt != nil {
+			finishedAt = *task.FinishedAt
+		}
+
+		if finishedAt.Before(before) {
+			delete(s.tasks, id)
+			evicted++
+		}
+	}
+
+	return evicted
+}
+
+// Close does nothing
+func (s *memoryRepositoryService) Close() error {
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+)
+
+func Test_MergeTask_Should_Never_Move_The_Status_Back(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	finishedAt := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	// The completion is delivered before the creation
+	_, err = sut.MergeTask(repository.Task{
+		Id:         1,
+		RobotId:    2,
+		Status:     repository.TaskStatusCompleted,
+		FinishedAt: &finishedAt,
+		Trajectory: []repository.Position{{X: 1, Y: 0}},
+	})
+	g.Expect(err).Should(BeNil())
+
+	task, err := sut.MergeTask(repository.Task{Id: 1, RobotId: 3, Status: repository.TaskStatusCreated})
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(task.RobotId).Should(Equal(int64(2)))
+	g.Expect(task.Status).Should(Equal(repository.TaskStatusCompleted))
+	g.Expect(task.Position()).Should(Equal(&repository.Position{X: 1, Y: 0}))
+}
+
+func Test_MergeTask_Should_Not_Cancel_A_Finished_Task(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.SaveTask(repository.Task{Id: 1, Status: repository.TaskStatusFailed})).Should(Succeed())
+
+	task, err := sut.MergeTask(repository.Task{Id: 1, Status: repository.TaskStatusCancelled})
+	g.Expect(err).Should(BeNil())
+	g.Expect(task.Status).Should(Equal(repository.TaskStatusFailed))
+}
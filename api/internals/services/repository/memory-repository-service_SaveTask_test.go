@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+)
+
+func Test_SaveTask_Should_Evict_The_Tasks_Past_Their_Retention(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := repository.NewMemoryRepositoryService(repository.Options{TaskRetention: 24 * time.Hour})
+	g.Expect(err).Should(BeNil())
+
+	longAgo := time.Now().UTC().Add(-48 * time.Hour)
+
+	g.Expect(sut.SaveTask(repository.Task{
+		Id:         1,
+		Status:     repository.TaskStatusCompleted,
+		CreatedAt:  longAgo,
+		FinishedAt: &longAgo,
+	})).Should(Succeed())
+
+	// The tasks are evicted as often as a journal is compacted at most
+	for id := int64(2); id <= 1000; id++ {
+		_, found, err := sut.GetTask(1)
+		g.Expect(err).Should(BeNil())
+		g.Expect(found).Should(BeTrue())
+
+		g.Expect(sut.SaveTask(repository.Task{
+			Id:        id,
+			Status:    repository.TaskStatusCreated,
+			CreatedAt: longAgo,
+		})).Should(Succeed())
+	}
+
+	_, found, err := sut.GetTask(1)
+	g.Expect(err).Should(BeNil())
+	g.Expect(found).Should(BeFalse())
+
+	// Nor are the tasks which are not finished
+	tasks, err := sut.ListTasks()
+	g.Expect(err).Should(BeNil())
+	g.Expect(tasks).Should(HaveLen(999))
+}
@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	repository "github.com/sepisoad/robot-challange/api/internals/services/repository"
+)
+
+// MockRepositoryInterface is a mock of RepositoryInterface interface.
+type MockRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryInterfaceMockRecorder
+}
+
+// MockRepositoryInterfaceMockRecorder is the mock recorder for MockRepositoryInterface.
+type MockRepositoryInterfaceMockRecorder struct {
+	mock *MockRepositoryInterface
+}
+
+// NewMockRepositoryInterface creates a new mock instance.
+func NewMockRepositoryInterface(ctrl *gomock.Controller) *MockRepositoryInterface {
+	mock := &MockRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockRepositoryInterface) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockRepositoryInterfaceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRepositoryInterface)(nil).Close))
+}
+
+// DeleteTask mocks base method.
+func (m *MockRepositoryInterface) DeleteTask(id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteTask(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteTask), id)
+}
+
+// GetRobot mocks base method.
+func (m *MockRepositoryInterface) GetRobot(id int64) (repository.Robot, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRobot", id)
+	ret0, _ := ret[0].(repository.Robot)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRobot indicates an expected call of GetRobot.
+func (mr *MockRepositoryInterfaceMockRecorder) GetRobot(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRobot", reflect.TypeOf((*MockRepositoryInterface)(nil).GetRobot), id)
+}
+
+// GetTask mocks base method.
+func (m *MockRepositoryInterface) GetTask(id int64) (repository.Task, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTask", id)
+	ret0, _ := ret[0].(repository.Task)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTask indicates an expected call of GetTask.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTask(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTask", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTask), id)
+}
+
+// ListRobots mocks base method.
+func (m *MockRepositoryInterface) ListRobots() ([]repository.Robot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRobots")
+	ret0, _ := ret[0].([]repository.Robot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRobots indicates an expected call of ListRobots.
+func (mr *MockRepositoryInterfaceMockRecorder) ListRobots() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRobots", reflect.TypeOf((*MockRepositoryInterface)(nil).ListRobots))
+}
+
+// ListTasks mocks base method.
+func (m *MockRepositoryInterface) ListTasks() ([]repository.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTasks")
+	ret0, _ := ret[0].([]repository.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockRepositoryInterfaceMockRecorder) ListTasks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockRepositoryInterface)(nil).ListTasks))
+}
+
+// MergeTask mocks base method.
+func (m *MockRepositoryInterface) MergeTask(update repository.Task) (repository.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTask", update)
+	ret0, _ := ret[0].(repository.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeTask indicates an expected call of MergeTask.
+func (mr *MockRepositoryInterfaceMockRecorder) MergeTask(update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTask", reflect.TypeOf((*MockRepositoryInterface)(nil).MergeTask), update)
+}
+
+// SaveRobot mocks base method.
+func (m *MockRepositoryInterface) SaveRobot(robot repository.Robot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRobot", robot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRobot indicates an expected call of SaveRobot.
+func (mr *MockRepositoryInterfaceMockRecorder) SaveRobot(robot interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRobot", reflect.TypeOf((*MockRepositoryInterface)(nil).SaveRobot), robot)
+}
+
+// SaveTask mocks base method.
+func (m *MockRepositoryInterface) SaveTask(task repository.Task) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTask", task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTask indicates an expected call of SaveTask.
+func (mr *MockRepositoryInterfaceMockRecorder) SaveTask(task interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTask", reflect.TypeOf((*MockRepositoryInterface)(nil).SaveTask), task)
+}
@@ -1,102 +1,150 @@
 package processors
 
 import (
-	"encoding/json"
+	"sync"
+	"time"
 
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
-	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 )
 
-// RobotStatus defines robot status
-type RobotStatus struct {
-	X int
-	Y int
-}
-
 type robotProcessor struct {
-	logger             *zap.SugaredLogger
-	robotSubscriber    *nats.Subscription
-	robotsStatus       map[int64]RobotStatus
-	robotStatusChannel chan map[int64]RobotStatus
+	logger              *zap.SugaredLogger
+	robotSubscribers    []robotbroker.SubscriptionInterface
+	eventDecoderService eventpublisher.EventDecoderInterface
+	repositoryService   repository.RepositoryInterface
+	robotStatusChannel  chan repository.Robot
+	mutex               *sync.Mutex
 }
 
-// creates an instance of robotProcessor and starts it
+// creates an instance of robotProcessor and starts it, it stores the robots in the repository
+// and the channel receives every robot which reported
 func StartRobotProcessor(
 	logger *zap.SugaredLogger,
-	robotBrokerService robotbroker.RobotBrokerInterface) (
+	robotBrokerService robotbroker.RobotBrokerInterface,
+	eventDecoderService eventpublisher.EventDecoderInterface,
+	repositoryService repository.RepositoryInterface) (
 	processor *robotProcessor,
-	robotStatusChannel chan map[int64]RobotStatus,
+	robotStatusChannel chan repository.Robot,
 	err error) {
-	var jetStream nats.JetStreamContext
-
-	if jetStream, err = robotBrokerService.CreateNewJetStream(); err != nil {
-		return
-	}
-
 	processor = &robotProcessor{
-		logger:             logger,
-		robotsStatus:       make(map[int64]RobotStatus),
-		robotStatusChannel: make(chan map[int64]RobotStatus),
+		logger:              logger,
+		eventDecoderService: eventDecoderService,
+		repositoryService:   repositoryService,
+		robotStatusChannel:  make(chan repository.Robot),
+		mutex:               &sync.Mutex{},
 	}
 
-	if processor.robotSubscriber, err = jetStream.QueueSubscribe(
-		robotbroker.SUBJECT_ROBOT,
-		"api-move-"+robotbroker.SUBJECT_ROBOT,
-		processor.handleRobotMovedEventRaised); err != nil {
-		processor.Stop()
-
-		return
+	for _, eventType := range []eventpublisher.RobotMovedEventType{
+		eventpublisher.RobotMoved,
+		eventpublisher.RobotFailedToMove,
+	} {
+		subscriber, err := robotBrokerService.QueueSubscribe(
+			robotbroker.RobotSubject(
+				robotbroker.SUBJECT_WILDCARD,
+				robotbroker.SUBJECT_WILDCARD,
+				eventType.SubjectToken()),
+			"api-robot-"+eventType.SubjectToken(),
+			processor.handleRobotEventRaised)
+		if err != nil {
+			processor.Stop()
+
+			return nil, nil, err
+		}
+
+		processor.robotSubscribers = append(processor.robotSubscribers, subscriber)
 	}
 
 	return processor, processor.robotStatusChannel, nil
 }
 
 func (s *robotProcessor) Stop() {
-	if s.robotSubscriber != nil {
-		_ = s.robotSubscriber.Unsubscribe()
-		s.robotSubscriber = nil
+	for _, subscriber := range s.robotSubscribers {
+		_ = subscriber.Unsubscribe()
 	}
 
+	s.robotSubscribers = nil
+
 	close(s.robotStatusChannel)
 }
 
-func (s *robotProcessor) handleRobotMovedEventRaised(msg *nats.Msg) {
+func (s *robotProcessor) handleRobotEventRaised(msg *robotbroker.Message) error {
 	s.logEnter(msg)
 
 	event := eventpublisher.RobotEvent{}
-	if err := json.Unmarshal(msg.Data, &event); err != nil {
+	envelope, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event)
+	if err != nil {
 		s.logger.Errorf(
 			"Failed to de-serialize RobotEvent message. Error: %v",
 			err)
 
-		return
+		return robotbroker.Permanent(err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	robot, _, err := s.repositoryService.GetRobot(event.Id)
+	if err != nil {
+		s.logger.Errorf(
+			"Failed to load robot %d. Error: %v",
+			event.Id,
+			err)
+
+		return err
 	}
 
-	if event.EventType != eventpublisher.RobotMoved {
-		return
+	// Moves and failures are delivered by different subscriptions, the older one is outdated
+	if envelope.Time.Before(robot.UpdatedAt) {
+		return nil
 	}
 
-	s.robotsStatus[event.Id] = RobotStatus{
-		X: event.Data.X,
-		Y: event.Data.Y,
+	robot = applyRobotEvent(robot, event, envelope.Time)
+
+	if err := s.repositoryService.SaveRobot(robot); err != nil {
+		s.logger.Errorf(
+			"Failed to store robot %d. Error: %v",
+			event.Id,
+			err)
+
+		return err
 	}
 
-	s.robotStatusChannel <- s.robotsStatus
-}
+	s.robotStatusChannel <- robot
 
-func (s *robotProcessor) logEnter(msg *nats.Msg) {
-	metadata, err := msg.Metadata()
-	if err != nil {
-		s.logger.Infof("Received message from subject: %s", msg.Subject)
+	return nil
+}
 
-		return
+// applyRobotEvent returns the robot updated with what a robot event tells about it, a robot
+// stays failed until it moves again
+func applyRobotEvent(robot repository.Robot, event eventpublisher.RobotEvent, at time.Time) repository.Robot {
+	robot.Id = event.Id
+	robot.UpdatedAt = at
+
+	switch event.EventType {
+	case eventpublisher.RobotMoved:
+		robot.X = event.Data.X
+		robot.Y = event.Data.Y
+		robot.HasCrate = event.Data.HasCrate
+		robot.Failed = false
+	case eventpublisher.RobotFailedToMove:
+		robot.Failed = true
+		robot.LastError = event.ErrorMessage
 	}
 
+	return robot
+}
+
+func (s *robotProcessor) logEnter(msg *robotbroker.Message) {
 	s.logger.Infof(
-		"Stream: %v, Sequence: %v. Received message from subject: %s",
-		metadata.Sequence.Stream,
-		metadata.Sequence.Consumer,
+		"Sequence: %v, Delivery: %v. Received message from subject: %s",
+		msg.Sequence,
+		msg.Deliveries,
 		msg.Subject)
 }
@@ -1,109 +1,164 @@
 package processors
 
 import (
-	"encoding/json"
+	"time"
 
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
-	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 )
 
-// TaskStatus defines running tasks status
-type TaskStatus string
-
-const (
-	// TaskStatusCreated is used to denote a Created task
-	TaskStatusCreated TaskStatus = "Created"
-	// TaskStatusInProgress is used to denote an InProgress task
-	TaskStatusInProgress TaskStatus = "InProgress"
-	// TaskStatusCompleted is used to denote a Completed task
-	TaskStatusCompleted TaskStatus = "Completed"
-	// TaskStatusCancelled is used to denote a Cancelled task
-	TaskStatusCancelled TaskStatus = "Cancelled"
-)
-
 type taskProcessor struct {
-	logger            *zap.SugaredLogger
-	robotSubscriber   *nats.Subscription
-	tasksStatus       map[int64]TaskStatus
-	taskStatusChannel chan map[int64]TaskStatus
+	logger              *zap.SugaredLogger
+	taskSubscribers     []robotbroker.SubscriptionInterface
+	eventDecoderService eventpublisher.EventDecoderInterface
+	repositoryService   repository.RepositoryInterface
 }
 
-// creates an instance of taskProcessor
+// creates an instance of taskProcessor which merges the task events into the repository
 func StartTaskProcessor(
 	logger *zap.SugaredLogger,
-	robotBrokerService robotbroker.RobotBrokerInterface) (
+	robotBrokerService robotbroker.RobotBrokerInterface,
+	eventDecoderService eventpublisher.EventDecoderInterface,
+	repositoryService repository.RepositoryInterface) (
 	processor *taskProcessor,
-	robotStatusChannel chan map[int64]TaskStatus,
 	err error) {
-	var jetStream nats.JetStreamContext
-
-	if jetStream, err = robotBrokerService.CreateNewJetStream(); err != nil {
-		return
-	}
-
 	processor = &taskProcessor{
-		logger:            logger,
-		tasksStatus:       make(map[int64]TaskStatus),
-		taskStatusChannel: make(chan map[int64]TaskStatus),
+		logger:              logger,
+		eventDecoderService: eventDecoderService,
+		repositoryService:   repositoryService,
 	}
 
-	if processor.robotSubscriber, err = jetStream.QueueSubscribe(
-		robotbroker.SUBJECT_TASK,
-		"api-"+robotbroker.SUBJECT_TASK,
-		processor.handleTaskEventRaised); err != nil {
-		processor.Stop()
-
-		return
+	for _, eventType := range []eventpublisher.TaskEventType{
+		eventpublisher.TaskCreated,
+		eventpublisher.TaskBatchCreated,
+		eventpublisher.TaskStarted,
+		eventpublisher.TaskCompleted,
+		eventpublisher.TaskFailed,
+	} {
+		subscriber, err := robotBrokerService.QueueSubscribe(
+			robotbroker.TaskSubject(
+				robotbroker.SUBJECT_WILDCARD,
+				eventType.SubjectToken()),
+			"api-task-"+eventType.SubjectToken(),
+			processor.handleTaskEventRaised)
+		if err != nil {
+			processor.Stop()
+
+			return nil, err
+		}
+
+		processor.taskSubscribers = append(processor.taskSubscribers, subscriber)
 	}
 
-	return processor, processor.taskStatusChannel, nil
+	return processor, nil
 }
 
 // Stops the the process
 func (s *taskProcessor) Stop() {
-	if s.robotSubscriber != nil {
-		_ = s.robotSubscriber.Unsubscribe()
-		s.robotSubscriber = nil
+	for _, subscriber := range s.taskSubscribers {
+		_ = subscriber.Unsubscribe()
 	}
 
-	close(s.taskStatusChannel)
+	s.taskSubscribers = nil
 }
 
-func (s *taskProcessor) handleTaskEventRaised(msg *nats.Msg) {
+func (s *taskProcessor) handleTaskEventRaised(msg *robotbroker.Message) error {
 	s.logEnter(msg)
 
 	event := eventpublisher.TaskEvent{}
-	if err := json.Unmarshal(msg.Data, &event); err != nil {
+	envelope, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event)
+	if err != nil {
 		s.logger.Errorf(
 			"Failed to de-serialize TaskEvent message. Error: %v",
 			err)
 
-		return
+		return robotbroker.Permanent(err)
 	}
 
-	switch event.EventType {
-	case eventpublisher.TaskCreated:
-		s.tasksStatus[int64(event.Id)] = TaskStatus(event.EventType)
-	case eventpublisher.TaskCompleted:
-		delete(s.tasksStatus, int64(event.Id))
+	// Events of a task are delivered by different subscriptions, merging them does not depend
+	// on their order
+	for _, task := range NewTasks(event, envelope.Time) {
+		if _, err := s.repositoryService.MergeTask(task); err != nil {
+			s.logger.Errorf(
+				"Failed to store task %d. Error: %v",
+				task.Id,
+				err)
+
+			return err
+		}
 	}
 
-	s.taskStatusChannel <- s.tasksStatus
+	return nil
 }
 
-func (s *taskProcessor) logEnter(msg *nats.Msg) {
-	metadata, err := msg.Metadata()
-	if err != nil {
-		s.logger.Infof("Received message from subject: %s", msg.Subject)
+// NewTasks returns what a task event tells about its task, or about every task of its batch
+func NewTasks(event eventpublisher.TaskEvent, at time.Time) []repository.Task {
+	if event.EventType != eventpublisher.TaskBatchCreated {
+		return []repository.Task{NewTask(event, at)}
+	}
 
-		return
+	tasks := make([]repository.Task, 0, len(event.Data.Tasks))
+	for _, task := range event.Data.Tasks {
+		tasks = append(tasks, repository.Task{
+			Id:            task.Id,
+			RobotId:       task.RobotId,
+			BatchId:       event.Data.BatchId,
+			Status:        repository.TaskStatusCreated,
+			MoveSequences: task.MoveSequeneces,
+			CreatedAt:     at,
+		})
 	}
 
+	return tasks
+}
+
+// NewTask returns what a task event tells about its task
+func NewTask(event eventpublisher.TaskEvent, at time.Time) repository.Task {
+	task := repository.Task{
+		Id:      event.Id,
+		RobotId: event.Data.RobotId,
+		BatchId: event.Data.BatchId,
+	}
+
+	switch event.EventType {
+	case eventpublisher.TaskCreated:
+		task.Status = repository.TaskStatusCreated
+		task.MoveSequences = event.Data.MoveSequeneces
+		task.CreatedAt = at
+	case eventpublisher.TaskStarted:
+		task.Status = repository.TaskStatusInProgress
+		task.StartedAt = &at
+	case eventpublisher.TaskCompleted, eventpublisher.TaskFailed:
+		task.Status = repository.TaskStatusCompleted
+		if event.EventType == eventpublisher.TaskFailed {
+			task.Status = repository.TaskStatusFailed
+		}
+
+		task.FinishedAt = &at
+		task.FailureReason = event.Data.FailureReason
+		task.Trajectory = make([]repository.Position, 0, len(event.Data.Trajectory))
+
+		for _, position := range event.Data.Trajectory {
+			task.Trajectory = append(task.Trajectory, repository.Position{
+				X: position.X,
+				Y: position.Y,
+			})
+		}
+	}
+
+	return task
+}
+
+func (s *taskProcessor) logEnter(msg *robotbroker.Message) {
 	s.logger.Infof(
-		"Stream: %v, Sequence: %v. Received message from subject: %s",
-		metadata.Sequence.Stream,
-		metadata.Sequence.Consumer,
+		"Sequence: %v, Delivery: %v. Received message from subject: %s",
+		msg.Sequence,
+		msg.Deliveries,
 		msg.Subject)
 }
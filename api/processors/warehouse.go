@@ -0,0 +1,99 @@
+package processors
+
+import (
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+// Warehouse defines the size and the layout of a warehouse
+type Warehouse struct {
+	Name         string
+	Width        int
+	Height       int
+	Obstacles    []eventpublisher.Cell
+	SpecialCells []eventpublisher.SpecialCell
+}
+
+type warehouseProcessor struct {
+	logger              *zap.SugaredLogger
+	warehouseSubscriber robotbroker.SubscriptionInterface
+	eventDecoderService eventpublisher.EventDecoderInterface
+	warehouseChannel    chan Warehouse
+}
+
+// creates an instance of warehouseProcessor and starts it
+func StartWarehouseProcessor(
+	logger *zap.SugaredLogger,
+	robotBrokerService robotbroker.RobotBrokerInterface,
+	eventDecoderService eventpublisher.EventDecoderInterface) (
+	processor *warehouseProcessor,
+	warehouseChannel chan Warehouse,
+	err error) {
+	processor = &warehouseProcessor{
+		logger:              logger,
+		eventDecoderService: eventDecoderService,
+		warehouseChannel:    make(chan Warehouse),
+	}
+
+	// Every replica needs the layout, including those started after the simulator published it
+	if processor.warehouseSubscriber, err = robotBrokerService.SubscribeLast(
+		robotbroker.WarehouseSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			eventpublisher.WarehouseConfigured.SubjectToken()),
+		processor.handleWarehouseConfiguredEventRaised); err != nil {
+		processor.Stop()
+
+		return
+	}
+
+	return processor, processor.warehouseChannel, nil
+}
+
+func (s *warehouseProcessor) Stop() {
+	if s.warehouseSubscriber != nil {
+		_ = s.warehouseSubscriber.Unsubscribe()
+		s.warehouseSubscriber = nil
+	}
+
+	close(s.warehouseChannel)
+}
+
+func (s *warehouseProcessor) handleWarehouseConfiguredEventRaised(msg *robotbroker.Message) error {
+	s.logEnter(msg)
+
+	event := eventpublisher.WarehouseEvent{}
+	if _, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
+		s.logger.Errorf(
+			"Failed to de-serialize WarehouseEvent message. Error: %v",
+			err)
+
+		return robotbroker.Permanent(err)
+	}
+
+	if event.EventType != eventpublisher.WarehouseConfigured {
+		return nil
+	}
+
+	s.warehouseChannel <- Warehouse{
+		Name:         event.Warehouse,
+		Width:        event.Data.Width,
+		Height:       event.Data.Height,
+		Obstacles:    event.Data.Obstacles,
+		SpecialCells: event.Data.SpecialCells,
+	}
+
+	return nil
+}
+
+func (s *warehouseProcessor) logEnter(msg *robotbroker.Message) {
+	s.logger.Infof(
+		"Sequence: %v, Delivery: %v. Received message from subject: %s",
+		msg.Sequence,
+		msg.Deliveries,
+		msg.Subject)
+}
@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/deepmap/oapi-codegen/pkg/middleware"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+	"go.uber.org/zap"
+)
+
+const (
+	// The security schemes declared by the specs
+	securitySchemeApiKey      = "apiKey"
+	securitySchemeBearer      = "bearerAuth"
+	securitySchemeAccessToken = "accessToken"
+
+	headerApiKey = "X-API-Key"
+
+	// queryAccessToken is the query parameter of the accessToken security scheme
+	queryAccessToken = "access_token"
+	// redactedAccessToken replaces the credentials the access log would otherwise show
+	redactedAccessToken = "REDACTED"
+
+	// contextKeyPrincipal holds the auth.Principal who sent a request in the echo context
+	contextKeyPrincipal = "principal"
+)
+
+// errNoCredentials is returned for a security scheme whose credential the request does not
+// carry, another scheme of the operation may still authenticate it
+var errNoCredentials = errors.New("no credentials")
+
+// ErrNoAuthentication is returned by Start when it is given neither api keys nor a way to verify
+// tokens, and is not told to serve the requests unauthenticated
+var ErrNoAuthentication = errors.New(
+	"requests would not be authenticated, give api keys or a way to verify tokens, or opt out explicitly")
+
+// authenticator checks the requests against the security requirements of the specs, the roles
+// an operation requires are the scopes of its requirements
+type authenticator struct {
+	logger *zap.SugaredLogger
+	// schemes holds the authenticators each security scheme is checked with
+	schemes map[string][]auth.AuthenticatorInterface
+	// challenges are sent in the WWW-Authenticate header of the 401 responses
+	challenges []string
+}
+
+// newAuthenticator creates the authenticator of the api, requests are not authenticated when
+// it is nil
+func newAuthenticator(logger *zap.SugaredLogger, options Options) (*authenticator, error) {
+	a := &authenticator{
+		logger:  logger,
+		schemes: make(map[string][]auth.AuthenticatorInterface),
+	}
+
+	if len(options.ApiKeys) > 0 {
+		apiKeyAuthenticatorService, err := auth.NewApiKeyAuthenticatorService(options.ApiKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		a.schemes[securitySchemeApiKey] = append(a.schemes[securitySchemeApiKey], apiKeyAuthenticatorService)
+		a.schemes[securitySchemeAccessToken] = append(a.schemes[securitySchemeAccessToken], apiKeyAuthenticatorService)
+		a.challenges = append(a.challenges, fmt.Sprintf(`ApiKey realm="api", header="%s"`, headerApiKey))
+	}
+
+	if options.Jwt.Enabled() {
+		jwtAuthenticatorService, err := auth.NewJwtAuthenticatorService(options.Jwt)
+		if err != nil {
+			return nil, err
+		}
+
+		a.schemes[securitySchemeBearer] = append(a.schemes[securitySchemeBearer], jwtAuthenticatorService)
+		a.schemes[securitySchemeAccessToken] = append(a.schemes[securitySchemeAccessToken], jwtAuthenticatorService)
+		a.challenges = append(a.challenges, `Bearer realm="api"`)
+	}
+
+	if len(a.challenges) == 0 {
+		return nil, nil
+	}
+
+	return a, nil
+}
+
+// authenticate is the openapi3filter.AuthenticationFunc of the request validators, it is called
+// for each security scheme of an operation until one authenticates the request
+func (a *authenticator) authenticate(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+	credential := getCredential(input.RequestValidationInput.Request, input.SecurityScheme)
+	if credential == "" {
+		return errNoCredentials
+	}
+
+	var principal auth.Principal
+
+	err := fmt.Errorf("%w: %s is not accepted", auth.ErrInvalidCredentials, input.SecuritySchemeName)
+	for _, authenticatorService := range a.schemes[input.SecuritySchemeName] {
+		if principal, err = authenticatorService.Authenticate(credential); err == nil {
+			break
+		}
+	}
+
+	if err != nil {
+		a.logger.Debugf("Failed to authenticate with %s. Error: %v", input.SecuritySchemeName, err)
+
+		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid credentials")
+	}
+
+	if !isAllowed(principal.Role, input.Scopes) {
+		return echo.NewHTTPError(
+			http.StatusForbidden,
+			fmt.Sprintf("%s is %s, the operation requires %s", principal.Subject, principal.Role, strings.Join(input.Scopes, " or ")))
+	}
+
+	if echoCtx := middleware.GetEchoContext(ctx); echoCtx != nil {
+		echoCtx.Set(contextKeyPrincipal, principal)
+	}
+
+	return nil
+}
+
+// handleError answers the requests which none of the security schemes of the operation found
+// credentials in with 401 rather than 403, 401 responses tell how to authenticate
+func (a *authenticator) handleError(ctx echo.Context, err *echo.HTTPError) error {
+	var securityErr *openapi3filter.SecurityRequirementsError
+	if errors.As(err.Internal, &securityErr) {
+		err = &echo.HTTPError{
+			Code:     http.StatusUnauthorized,
+			Message:  "Authentication required",
+			Internal: err.Internal,
+		}
+	}
+
+	if err.Code == http.StatusUnauthorized {
+		for _, challenge := range a.challenges {
+			ctx.Response().Header().Add(echo.HeaderWWWAuthenticate, challenge)
+		}
+	}
+
+	return err
+}
+
+// getCredential returns the credential of a security scheme a request carries
+func getCredential(request *http.Request, securityScheme *openapi3.SecurityScheme) string {
+	switch {
+	case securityScheme.Type == "http" && strings.EqualFold(securityScheme.Scheme, "bearer"):
+		authorization := request.Header.Get(echo.HeaderAuthorization)
+		if len(authorization) > len("Bearer ") && strings.EqualFold(authorization[:len("Bearer ")], "Bearer ") {
+			return authorization[len("Bearer "):]
+		}
+	case securityScheme.Type == "apiKey" && securityScheme.In == "header":
+		return request.Header.Get(securityScheme.Name)
+	case securityScheme.Type == "apiKey" && securityScheme.In == "query":
+		return request.URL.Query().Get(securityScheme.Name)
+	}
+
+	return ""
+}
+
+// isAllowed tells whether role is allowed an operation which requires one of roles, any role is
+// allowed an operation which requires none
+func isAllowed(role auth.Role, roles []string) bool {
+	if len(roles) == 0 {
+		return true
+	}
+
+	for _, required := range roles {
+		if role.Includes(auth.Role(required)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// accessTokenRedactionMiddleware hides the access_token query parameter from the access log,
+// which writes the request uri. The credential is read from the url of the request, which is
+// left as it is
+func accessTokenRedactionMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			request := ctx.Request()
+
+			query := request.URL.Query()
+			if query.Has(queryAccessToken) {
+				query.Set(queryAccessToken, redactedAccessToken)
+
+				redacted := *request.URL
+				redacted.RawQuery = query.Encode()
+				request.RequestURI = redacted.RequestURI()
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// getPrincipal returns who sent a request, it is not known when requests are not authenticated
+func getPrincipal(ctx echo.Context) (auth.Principal, bool) {
+	principal, found := ctx.Get(contextKeyPrincipal).(auth.Principal)
+
+	return principal, found
+}
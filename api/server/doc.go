@@ -0,0 +1,6 @@
+/*
+this package wires the processors and the REST api together so that the api
+can be started by the `start` command as well as by the all-in-one binary
+*/
+
+package server
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/idempotency"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+	"go.uber.org/zap"
+)
+
+const (
+	headerIdempotencyKey = "Idempotency-Key"
+	// headerIdempotentReplayed is set on the responses which are replayed from the key store
+	headerIdempotentReplayed = "Idempotent-Replayed"
+)
+
+// idempotencyMiddleware makes the requests which create tasks, i.e. every PUT and POST, sent
+// with an Idempotency-Key header safe to retry. The response of the first request is replayed
+// to the repeats, a key sent with another request is answered with 422 and a repeat sent
+// while the first request is being processed with 409
+func idempotencyMiddleware(
+	logger *zap.SugaredLogger,
+	keyStoreService idempotency.KeyStoreInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			request := ctx.Request()
+
+			idempotencyKey := request.Header.Get(headerIdempotencyKey)
+			if idempotencyKey == "" ||
+				(request.Method != http.MethodPut && request.Method != http.MethodPost) {
+				return next(ctx)
+			}
+
+			body, err := io.ReadAll(request.Body)
+			if err != nil {
+				return getIdempotencyError(ctx, http.StatusBadRequest, "Failed to read the request body")
+			}
+
+			request.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Keys are hashed so clients can send any string, they are scoped to a route and to
+			// the principal who sent them so nobody is replayed the response of someone else
+			scope := request.Method + " " + request.URL.Path
+			if principal, found := getPrincipal(ctx); found {
+				scope = principal.Subject + " " + scope
+			}
+
+			key := digest([]byte(scope + " " + idempotencyKey))
+			fingerprint := digest(append([]byte(request.URL.RawQuery+" "), body...))
+
+			record, found, err := keyStoreService.Reserve(key, fingerprint)
+			if err != nil {
+				logger.Errorf("Failed to reserve idempotency key. Error: %v", err)
+
+				return getIdempotencyError(
+					ctx,
+					http.StatusInternalServerError,
+					"Failed to access the idempotency key store")
+			}
+
+			switch {
+			case found && record.Fingerprint != fingerprint:
+				return getIdempotencyError(
+					ctx,
+					http.StatusUnprocessableEntity,
+					"The Idempotency-Key was already used with another request")
+			case found && !record.Completed:
+				return getIdempotencyError(
+					ctx,
+					http.StatusConflict,
+					"A request with the same Idempotency-Key is being processed")
+			case found:
+				ctx.Response().Header().Set(headerIdempotentReplayed, "true")
+
+				return ctx.Blob(record.StatusCode, record.ContentType, record.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: ctx.Response().Writer}
+			ctx.Response().Writer = recorder
+
+			err = next(ctx)
+
+			// Failures of the api are not kept so the request can be retried
+			status := ctx.Response().Status
+			if err != nil || status >= http.StatusInternalServerError {
+				if err := keyStoreService.Release(key); err != nil {
+					logger.Errorf("Failed to release idempotency key. Error: %v", err)
+				}
+
+				return err
+			}
+
+			if err := keyStoreService.Complete(key, idempotency.Record{
+				Fingerprint: fingerprint,
+				StatusCode:  status,
+				ContentType: ctx.Response().Header().Get(echo.HeaderContentType),
+				Body:        recorder.body.Bytes(),
+			}); err != nil {
+				logger.Errorf("Failed to store the response of idempotency key. Error: %v", err)
+			}
+
+			return nil
+		}
+	}
+}
+
+// responseRecorder keeps a copy of the body of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(buf []byte) (int, error) {
+	r.body.Write(buf)
+
+	return r.ResponseWriter.Write(buf)
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func getIdempotencyError(ctx echo.Context, code int, message string) error {
+	if isApiV2Path(ctx.Request().URL.Path) {
+		return robot.GetProblem(ctx, code, message)
+	}
+
+	return ctx.JSON(
+		code,
+		robotapiserver.Error{
+			Code:    code,
+			Message: message,
+		})
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// customMethodRouter registers the routes of the api on echo, echo takes every colon for the
+// start of a path parameter so the colon of a custom method such as /api/tasks:batch is escaped
+type customMethodRouter struct {
+	echo *echo.Echo
+}
+
+func (r customMethodRouter) CONNECT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.CONNECT(escapeCustomMethods(path), h, m...)
+}
+
+func (r customMethodRouter) DELETE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.DELETE(escapeCustomMethods(path), h, m...)
+}
+
+func (r customMethodRouter) GET(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.GET(escapeCustomMethods(path), h, m...)
+}
+
+func (r customMethodRouter) HEAD(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.HEAD(escapeCustomMethods(path), h, m...)
+}
+
+func (r customMethodRouter) OPTIONS(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.OPTIONS(escapeCustomMethods(path), h, m...)
+}
+
+func (r customMethodRouter) PATCH(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.PATCH(escapeCustomMethods(path), h, m...)
+}
+
+func (r customMethodRouter) POST(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.POST(escapeCustomMethods(path), h, m...)
+}
+
+func (r customMethodRouter) PUT(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.PUT(escapeCustomMethods(path), h, m...)
+}
+
+func (r customMethodRouter) TRACE(path string, h echo.HandlerFunc, m ...echo.MiddlewareFunc) *echo.Route {
+	return r.echo.TRACE(escapeCustomMethods(path), h, m...)
+}
+
+// escapeCustomMethods escapes the colons which do not start a path segment, those which do
+// start a path parameter
+func escapeCustomMethods(path string) string {
+	segments := strings.Split(path, "/")
+	for idx, segment := range segments {
+		if len(segment) > 1 {
+			segments[idx] = segment[:1] + strings.ReplaceAll(segment[1:], ":", `\:`)
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,478 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+	"github.com/sepisoad/robot-challange/api/internals/services/idempotency"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/api/processors"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+	"github.com/sepisoad/robot-challange/shared/services/circuitbreaker"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/idgenerator"
+	"github.com/sepisoad/robot-challange/shared/services/outbox"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+// Options configures the api
+type Options struct {
+	// Address is the address the http server listens on, e.g. :8080
+	Address string
+	// EventCodec is the name of the codec events are published with
+	EventCodec string
+	// OutboxPath is the journal events are stored in until the broker has persisted them,
+	// events are published straight to the broker when it is empty
+	OutboxPath string
+	// StorePath is the file tasks and robots are stored in so they survive restarts, they are
+	// kept in memory only when it is empty
+	StorePath string
+	// TaskRetention is how long the repository keeps the finished tasks, forever when it is zero
+	TaskRetention time.Duration
+	// NodeId is the snowflake node task ids are generated with, the replicas of the api need
+	// distinct ones so their task ids do not collide
+	NodeId int64
+	// IdempotencyStore is where the responses of the requests sent with an Idempotency-Key header
+	// are kept, idempotency.StoreNats shares them between the replicas of the api. They are kept
+	// in memory when it is empty
+	IdempotencyStore string
+	// IdempotencyRetention is how long they are kept, idempotency.DefaultRetention when it is zero
+	IdempotencyRetention time.Duration
+	// ApiKeys are the static keys clients authenticate with
+	ApiKeys []auth.ApiKey
+	// Jwt configures how the JSON web tokens clients authenticate with are verified
+	Jwt auth.JwtOptions
+	// InsecureNoAuth serves the requests unauthenticated when there are neither api keys nor a way
+	// to verify tokens, the api refuses to start without them otherwise
+	InsecureNoAuth bool
+	// Acceptance configures whether tasks wait for the simulator to accept them
+	Acceptance robot.AcceptanceOptions
+	// CircuitBreaker configures the circuit breaker which fails the calls to the broker fast
+	// while it is unavailable
+	CircuitBreaker circuitbreaker.Options
+}
+
+type stopper interface {
+	Stop()
+}
+
+// Server is a running api
+type Server struct {
+	logger     *zap.SugaredLogger
+	echo       *echo.Echo
+	processors []stopper
+	outbox     outbox.OutboxInterface
+	repository repository.RepositoryInterface
+	breaker    circuitbreaker.CircuitBreakerInterface
+	dispatched chan int64
+	done       chan error
+}
+
+// Start starts the event processors and the http server of the api on top of a broker, it
+// returns once the http server is listening
+func Start(
+	logger *zap.SugaredLogger,
+	options Options,
+	robotBrokerService robotbroker.RobotBrokerInterface) (*Server, error) {
+	server := &Server{
+		logger: logger,
+		done:   make(chan error, 1),
+	}
+
+	if err := server.start(options, robotBrokerService); err != nil {
+		server.Stop()
+
+		return nil, err
+	}
+
+	return server, nil
+}
+
+// Address returns the address the http server listens on
+func (s *Server) Address() string {
+	return s.echo.ListenerAddr().String()
+}
+
+// Wait blocks until the http server stops
+func (s *Server) Wait() error {
+	return <-s.done
+}
+
+// Stop stops the http server and the event processors
+func (s *Server) Stop() {
+	if s.echo != nil {
+		if err := s.echo.Shutdown(context.Background()); err != nil {
+			s.logger.Errorf("Failed to shutdown the http server. Error: %v", err)
+		}
+	}
+
+	for _, processor := range s.processors {
+		processor.Stop()
+	}
+
+	s.processors = nil
+
+	// Messages left in the outbox are published on the next start
+	if s.outbox != nil {
+		// The relay must not block on a dispatched task nobody listens to any more
+		go func(dispatched chan int64) {
+			for range dispatched {
+			}
+		}(s.dispatched)
+
+		if err := s.outbox.Close(); err != nil {
+			s.logger.Errorf("Failed to close the outbox. Error: %v", err)
+		}
+
+		close(s.dispatched)
+		s.outbox = nil
+	}
+
+	if s.breaker != nil {
+		s.breaker.Close()
+		s.breaker = nil
+	}
+
+	if s.repository != nil {
+		if err := s.repository.Close(); err != nil {
+			s.logger.Errorf("Failed to close the repository. Error: %v", err)
+		}
+
+		s.repository = nil
+	}
+}
+
+func (s *Server) start(
+	options Options,
+	robotBrokerService robotbroker.RobotBrokerInterface) error {
+	// Nothing is started unless the requests can be authenticated
+	authenticator, err := newAuthenticator(s.logger, options)
+	if err != nil {
+		return err
+	}
+
+	if authenticator == nil {
+		if !options.InsecureNoAuth {
+			return ErrNoAuthentication
+		}
+
+		s.logger.Warn("Requests are not authenticated, there are neither api keys nor a way to verify tokens")
+	}
+
+	eventCodec, err := eventcodec.NewCodec(options.EventCodec)
+	if err != nil {
+		return err
+	}
+
+	if s.breaker, err = circuitbreaker.NewCircuitBreakerService(
+		s.logger,
+		robotBrokerService,
+		options.CircuitBreaker); err != nil {
+		return err
+	}
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	if err != nil {
+		return err
+	}
+
+	if options.StorePath != "" {
+		s.repository, err = repository.NewFileRepositoryService(
+			s.logger,
+			options.StorePath,
+			repository.Options{TaskRetention: options.TaskRetention})
+	} else {
+		s.repository, err = repository.NewMemoryRepositoryService(
+			repository.Options{TaskRetention: options.TaskRetention})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The outbox keeps accepting tasks while the broker is unavailable
+	var publisher robotbroker.PublisherInterface = s.breaker
+	var queuedTasks []repository.Task
+
+	if options.OutboxPath != "" {
+		// The relay goes through the breaker too, its failures open the circuit and it backs off
+		// while the circuit is open
+		if publisher, queuedTasks, err = s.openOutbox(
+			options.OutboxPath,
+			s.breaker,
+			eventDecoderService); err != nil {
+			return err
+		}
+	}
+
+	eventPublisherService, err := eventpublisher.NewEventPublisherService(
+		s.logger,
+		"api",
+		eventCodec,
+		publisher)
+	if err != nil {
+		return err
+	}
+
+	taskRequesterService, err := eventpublisher.NewTaskRequesterService(
+		s.logger,
+		"api",
+		eventCodec,
+		s.breaker)
+	if err != nil {
+		return err
+	}
+
+	robotProcessor, robotStatusChannel, err := processors.StartRobotProcessor(
+		s.logger,
+		robotBrokerService,
+		eventDecoderService,
+		s.repository)
+	if err != nil {
+		return err
+	}
+
+	s.processors = append(s.processors, robotProcessor)
+
+	taskProcessor, err := processors.StartTaskProcessor(
+		s.logger,
+		robotBrokerService,
+		eventDecoderService,
+		s.repository)
+	if err != nil {
+		return err
+	}
+
+	s.processors = append(s.processors, taskProcessor)
+
+	warehouseProcessor, warehouseChannel, err := processors.StartWarehouseProcessor(
+		s.logger,
+		robotBrokerService,
+		eventDecoderService)
+	if err != nil {
+		return err
+	}
+
+	s.processors = append(s.processors, warehouseProcessor)
+
+	keyStoreService, err := newKeyStoreService(options, robotBrokerService)
+	if err != nil {
+		return err
+	}
+
+	requestValidators, err := requestValidators(authenticator)
+	if err != nil {
+		return err
+	}
+
+	snowflakeNode, err := snowflake.NewNode(options.NodeId)
+	if err != nil {
+		return err
+	}
+
+	idGeneratorService, err := idgenerator.NewIdGeneratorService(snowflakeNode)
+	if err != nil {
+		return err
+	}
+
+	robotService, robotServiceV2, err := robot.NewRobotService(
+		s.logger,
+		robotStatusChannel,
+		warehouseChannel,
+		queuedTasks,
+		s.dispatched,
+		s.repository,
+		eventPublisherService,
+		taskRequesterService,
+		idGeneratorService,
+		robotBrokerService,
+		s.breaker,
+		options.Acceptance)
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", options.Address)
+	if err != nil {
+		return err
+	}
+
+	e := echo.New()
+	e.HideBanner = true
+	e.Listener = listener
+	e.HTTPErrorHandler = httpErrorHandler(e)
+	e.Use(echomiddleware.CORSWithConfig(
+		echomiddleware.CORSConfig{
+			AllowOrigins: []string{"*"},
+			AllowHeaders: []string{
+				echo.HeaderOrigin,
+				echo.HeaderContentType,
+				echo.HeaderAccept,
+				echo.HeaderAuthorization,
+				headerApiKey,
+				headerIdempotencyKey},
+			ExposeHeaders: []string{
+				echo.HeaderRetryAfter,
+				echo.HeaderWWWAuthenticate,
+				headerIdempotentReplayed,
+				headerTaskIdFormat,
+				headerDeprecation,
+				headerLink,
+				"X-Total-Count",
+				"X-Next-Cursor"},
+		}))
+	e.Use(accessTokenRedactionMiddleware())
+	e.Use(echomiddleware.Logger()) //TODO:sepi
+	e.Use(deprecationMiddleware())
+	e.Use(taskIdFormatMiddleware())
+	e.Use(requestValidators...)
+	e.Use(idempotencyMiddleware(s.logger, keyStoreService))
+
+	robotapiserver.RegisterHandlers(customMethodRouter{echo: e}, robotService)
+	robotapiv2server.RegisterHandlers(customMethodRouter{echo: e}, robotServiceV2)
+
+	e.File("/", "index.html")
+
+	s.echo = e
+
+	go func() {
+		err := e.Start(options.Address)
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+
+		s.done <- err
+	}()
+
+	return nil
+}
+
+// newKeyStoreService creates the store of the idempotency keys
+func newKeyStoreService(
+	options Options,
+	robotBrokerService robotbroker.RobotBrokerInterface) (idempotency.KeyStoreInterface, error) {
+	retention := options.IdempotencyRetention
+	if retention == 0 {
+		retention = idempotency.DefaultRetention
+	}
+
+	switch options.IdempotencyStore {
+	case "", idempotency.StoreMemory:
+		return idempotency.NewMemoryKeyStoreService(retention)
+	case idempotency.StoreNats:
+		jetStreamBrokerService, ok := robotBrokerService.(robotbroker.JetStreamBrokerInterface)
+		if !ok {
+			return nil, errors.New("the idempotency keys can only be stored in NATS when the broker is backed by JetStream")
+		}
+
+		return idempotency.NewNatsKeyStoreService(jetStreamBrokerService, retention)
+	default:
+		return nil, fmt.Errorf("unknown idempotency store %q", options.IdempotencyStore)
+	}
+}
+
+// openOutbox opens the outbox task events go through and returns the tasks it still holds
+func (s *Server) openOutbox(
+	path string,
+	publisher robotbroker.PublisherInterface,
+	eventDecoderService eventpublisher.EventDecoderInterface) (outbox.OutboxInterface, []repository.Task, error) {
+	dispatched := make(chan int64, 64)
+
+	outboxService, err := outbox.NewOutboxService(
+		s.logger,
+		path,
+		publisher,
+		outbox.Options{
+			OnDispatched: func(msg *robotbroker.Message) {
+				tasks, err := createdTasks(eventDecoderService, msg, time.Time{})
+				if err != nil {
+					s.logger.Errorf("Failed to de-serialize dispatched TaskEvent. Error: %v", err)
+				}
+
+				for _, task := range tasks {
+					dispatched <- task.Id
+				}
+			},
+			// The tasks which were never published would otherwise stay queued forever
+			OnDeadLettered: func(msg *robotbroker.Message, err error) {
+				tasks, decodeErr := createdTasks(eventDecoderService, msg, time.Time{})
+				if decodeErr != nil {
+					s.logger.Errorf("Failed to de-serialize dead lettered TaskEvent. Error: %v", decodeErr)
+				}
+
+				failureReason := fmt.Sprintf("the task could not be dispatched: %v", err)
+
+				for _, task := range tasks {
+					if _, err := s.repository.MergeTask(repository.Task{
+						Id:            task.Id,
+						Status:        repository.TaskStatusFailed,
+						FailureReason: failureReason,
+					}); err != nil {
+						s.logger.Errorf("Failed to store task %d as failed. Error: %v", task.Id, err)
+					}
+				}
+			},
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.outbox = outboxService
+	s.dispatched = dispatched
+
+	queuedTasks := make([]repository.Task, 0)
+	for _, entry := range outboxService.Pending() {
+		tasks, err := createdTasks(
+			eventDecoderService,
+			&robotbroker.Message{
+				Subject: entry.Subject,
+				Header:  entry.Header,
+				Data:    entry.Data,
+			},
+			entry.CreatedAt)
+		if err != nil {
+			s.logger.Errorf("Failed to de-serialize queued TaskEvent %s. Error: %v", entry.Id, err)
+
+			continue
+		}
+
+		queuedTasks = append(queuedTasks, tasks...)
+	}
+
+	return outboxService, queuedTasks, nil
+}
+
+// createdTasks returns the tasks a TaskCreated or a TaskBatchCreated event creates, it returns
+// none for the other messages
+func createdTasks(
+	eventDecoderService eventpublisher.EventDecoderInterface,
+	msg *robotbroker.Message,
+	at time.Time) ([]repository.Task, error) {
+	_, eventType, ok := robotbroker.ParseTaskSubject(msg.Subject)
+	if !ok || (eventType != eventpublisher.TaskCreated.SubjectToken() &&
+		eventType != eventpublisher.TaskBatchCreated.SubjectToken()) {
+		return nil, nil
+	}
+
+	event := eventpublisher.TaskEvent{}
+	if _, err := eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
+		return nil, err
+	}
+
+	return processors.NewTasks(event, at), nil
+}
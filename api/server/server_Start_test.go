@@ -0,0 +1,224 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	. "github.com/onsi/gomega"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/auth"
+	"github.com/sepisoad/robot-challange/api/server"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+func Test_Start_Should_Replay_The_Response_Of_A_Retried_Request(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startServer(t, g, server.Options{InsecureNoAuth: true}, http.Header{})
+
+	moveRobot := func(idempotencyKey string, body string) (*http.Response, []byte) {
+		return send(g, http.MethodPut, "http://"+sut.Address()+"/api/robots/0", http.Header{
+			"Idempotency-Key": []string{idempotencyKey},
+		}, body)
+	}
+
+	first, firstBody := moveRobot("retried", `{"moveSequences":["E"]}`)
+	g.Expect(first.StatusCode).Should(Equal(http.StatusAccepted))
+
+	second, secondBody := moveRobot("retried", `{"moveSequences":["E"]}`)
+	g.Expect(second.StatusCode).Should(Equal(http.StatusAccepted))
+	g.Expect(second.Header.Get("Idempotent-Replayed")).Should(Equal("true"))
+	g.Expect(secondBody).Should(Equal(firstBody))
+
+	response, _ := send(g, http.MethodGet, "http://"+sut.Address()+"/api/tasks", http.Header{}, "")
+	g.Expect(response.Header.Get("X-Total-Count")).Should(Equal("1"))
+
+	// The key is scoped to the route and the body it was first sent with
+	response, _ = moveRobot("retried", `{"moveSequences":["N"]}`)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusUnprocessableEntity))
+}
+
+func Test_Start_Should_Validate_The_Requests(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut := startServer(t, g, server.Options{InsecureNoAuth: true}, http.Header{})
+
+	response, _ := send(g, http.MethodGet, "http://"+sut.Address()+"/api/tasks?status=Unknown", http.Header{}, "")
+	g.Expect(response.StatusCode).Should(Equal(http.StatusBadRequest))
+
+	// Errors of v2 are problem details
+	response, buf := send(g, http.MethodPost, "http://"+sut.Address()+"/api/v2/tasks", http.Header{}, `{"robotId":"0","moves":[]}`)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusBadRequest))
+	g.Expect(response.Header.Get("Content-Type")).Should(Equal(robot.MIMEApplicationProblemJSON))
+	g.Expect(response.Header.Get("Deprecation")).Should(BeEmpty())
+
+	var problem robotapiv2server.Problem
+	g.Expect(json.Unmarshal(buf, &problem)).Should(Succeed())
+	g.Expect(problem.Status).Should(Equal(http.StatusBadRequest))
+
+	// v1 tells it is deprecated
+	response, _ = send(g, http.MethodGet, "http://"+sut.Address()+"/api/tasks", http.Header{}, "")
+	g.Expect(response.StatusCode).Should(Equal(http.StatusOK))
+	g.Expect(response.Header.Get("Deprecation")).Should(Equal("true"))
+	g.Expect(response.Header.Get("Link")).Should(Equal(`</api/v2>; rel="successor-version"`))
+}
+
+func Test_Start_Should_Authenticate_And_Authorize_The_Requests(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	secret := []byte("secret")
+
+	viewer := http.Header{"X-Api-Key": []string{"viewer-key"}}
+	operator := http.Header{"X-Api-Key": []string{"operator-key"}}
+
+	sut := startServer(t, g, server.Options{
+		ApiKeys: []auth.ApiKey{
+			{Name: "dashboard", Role: auth.RoleViewer, Key: "viewer-key"},
+			{Name: "dispatcher", Role: auth.RoleOperator, Key: "operator-key"},
+		},
+		Jwt: auth.JwtOptions{
+			Secret: secret,
+		},
+	}, viewer)
+
+	url := "http://" + sut.Address()
+
+	response, _ := send(g, http.MethodGet, url+"/api/health", http.Header{}, "")
+	g.Expect(response.StatusCode).Should(Equal(http.StatusOK))
+
+	response, _ = send(g, http.MethodGet, url+"/api/robots", http.Header{}, "")
+	g.Expect(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+	g.Expect(response.Header.Values("WWW-Authenticate")).Should(ConsistOf(
+		`ApiKey realm="api", header="X-API-Key"`,
+		`Bearer realm="api"`))
+
+	response, _ = send(g, http.MethodGet, url+"/api/robots", http.Header{"X-Api-Key": []string{"unknown-key"}}, "")
+	g.Expect(response.StatusCode).Should(Equal(http.StatusUnauthorized))
+
+	response, _ = send(g, http.MethodGet, url+"/api/robots", viewer.Clone(), "")
+	g.Expect(response.StatusCode).Should(Equal(http.StatusOK))
+
+	response, _ = send(g, http.MethodPut, url+"/api/robots/0", viewer.Clone(), `{"moveSequences":["E"]}`)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusForbidden))
+
+	response, _ = send(g, http.MethodPut, url+"/api/robots/0", operator.Clone(), `{"moveSequences":["E"]}`)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusAccepted))
+
+	// Tokens are verified locally
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "alice",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"role": "admin",
+	}).SignedString(secret)
+	g.Expect(err).Should(BeNil())
+
+	bearer := http.Header{"Authorization": []string{"Bearer " + token}}
+
+	response, _ = send(g, http.MethodPost, url+"/api/v2/tasks", bearer, `{"robotId":"0","moves":["E"]}`)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusAccepted))
+
+	response, _ = send(g, http.MethodPost, url+"/api/v2/tasks", viewer.Clone(), `{"robotId":"0","moves":["E"]}`)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusForbidden))
+	g.Expect(response.Header.Get("Content-Type")).Should(Equal(robot.MIMEApplicationProblemJSON))
+
+	// Browsers open the websockets without headers
+	origin := url + "/"
+
+	_, err = websocket.Dial("ws://"+sut.Address()+"/ws/robots", "", origin)
+	g.Expect(err).ShouldNot(BeNil())
+
+	ws, err := websocket.Dial("ws://"+sut.Address()+"/ws/robots?access_token="+token, "", origin)
+	g.Expect(err).Should(BeNil())
+	ws.Close()
+}
+
+func Test_Start_Should_Refuse_To_Serve_Unauthenticated_Requests_Unless_Told_To(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	t.Cleanup(robotBrokerService.Close)
+
+	_, err = server.Start(sugarLogger, server.Options{
+		Address:    "127.0.0.1:0",
+		EventCodec: eventcodec.JSON,
+	}, robotBrokerService)
+	g.Expect(err).Should(MatchError(server.ErrNoAuthentication))
+
+	// Anyone who reaches the api is allowed everything once it is told to
+	sut := startServer(t, g, server.Options{InsecureNoAuth: true}, http.Header{})
+
+	response, _ := send(g, http.MethodPut, "http://"+sut.Address()+"/api/robots/0", http.Header{}, `{"moveSequences":["E"]}`)
+	g.Expect(response.StatusCode).Should(Equal(http.StatusAccepted))
+}
+
+// startServer starts the api on top of a broker kept in memory and waits until it knows robot 0,
+// it asks for the robot with the credentials in header
+func startServer(t *testing.T, g *WithT, options server.Options, header http.Header) *server.Server {
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	t.Cleanup(robotBrokerService.Close)
+
+	options.Address = "127.0.0.1:0"
+	options.EventCodec = eventcodec.JSON
+
+	sut, err := server.Start(sugarLogger, options, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+	t.Cleanup(sut.Stop)
+
+	codec, err := eventcodec.NewCodec(eventcodec.JSON)
+	g.Expect(err).Should(BeNil())
+
+	eventPublisherService, err := eventpublisher.NewEventPublisherService(sugarLogger, "test", codec, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(eventPublisherService.PublishRobotEvent(eventpublisher.RobotEvent{
+		EventType: eventpublisher.RobotMoved,
+		Id:        0,
+		Warehouse: robotbroker.DEFAULT_WAREHOUSE,
+	})).Should(Succeed())
+
+	g.Eventually(func() int {
+		response, _ := send(g, http.MethodGet, "http://"+sut.Address()+"/api/v2/robots/0", header.Clone(), "")
+
+		return response.StatusCode
+	}, 10*time.Second, 100*time.Millisecond).Should(Equal(http.StatusOK))
+
+	return sut
+}
+
+// send sends a request with a JSON body and returns the response with its body
+func send(g *WithT, method string, url string, header http.Header, body string) (*http.Response, []byte) {
+	request, err := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	g.Expect(err).Should(BeNil())
+
+	request.Header = header
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	g.Expect(err).Should(BeNil())
+	defer response.Body.Close()
+
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(response.Body)
+	g.Expect(err).Should(BeNil())
+
+	return response, buf.Bytes()
+}
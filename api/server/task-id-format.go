@@ -0,0 +1,28 @@
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// headerTaskIdFormat tells the clients the format of the task ids of the api, it changes
+	// whenever the format of the ids does
+	headerTaskIdFormat = "Task-Id-Format"
+	// taskIdFormat are 64-bit snowflake ids, tasks created before they were introduced keep their
+	// sequential ids
+	taskIdFormat = "snowflake"
+)
+
+// taskIdFormatMiddleware sets the Task-Id-Format header on the responses of v1, v2 ids are
+// strings clients do not parse
+func taskIdFormatMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if isApiV1Path(ctx.Request().URL.Path) {
+				ctx.Response().Header().Set(headerTaskIdFormat, taskIdFormat)
+			}
+
+			return next(ctx)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/deepmap/oapi-codegen/pkg/middleware"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+)
+
+const (
+	// apiV2Path is where v2 of the api is mounted, v1 is served from the rest of /api
+	apiV2Path = "/api/v2"
+
+	// headerDeprecation marks the responses of v1, see
+	// https://datatracker.ietf.org/doc/draft-ietf-httpapi-deprecation-header
+	headerDeprecation = "Deprecation"
+	headerLink        = "Link"
+)
+
+func isApiV2Path(path string) bool {
+	return path == apiV2Path || strings.HasPrefix(path, apiV2Path+"/")
+}
+
+func isApiV1Path(path string) bool {
+	return strings.HasPrefix(path, "/api/") && !isApiV2Path(path)
+}
+
+// deprecationMiddleware tells the clients of v1 it is deprecated and where v2 is
+func deprecationMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			if isApiV1Path(ctx.Request().URL.Path) {
+				ctx.Response().Header().Set(headerDeprecation, "true")
+				ctx.Response().Header().Set(headerLink, fmt.Sprintf("<%s>; rel=\"successor-version\"", apiV2Path))
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+// requestValidators validates the requests of each version against its own spec, including its
+// security requirements unless authenticator is nil
+func requestValidators(authenticator *authenticator) ([]echo.MiddlewareFunc, error) {
+	swaggerSpec, err := robotapiserver.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading swagger spec: %w", err)
+	}
+
+	swaggerSpec.Servers = nil //TODO:sepi
+
+	swaggerSpecV2, err := robotapiv2server.GetSwagger()
+	if err != nil {
+		return nil, fmt.Errorf("error loading v2 swagger spec: %w", err)
+	}
+
+	swaggerSpecV2.Servers = nil
+
+	filterOptions := openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	var errorHandler middleware.ErrorHandler
+
+	if authenticator != nil {
+		filterOptions.AuthenticationFunc = authenticator.authenticate
+		errorHandler = authenticator.handleError
+	}
+
+	return []echo.MiddlewareFunc{
+		middleware.OapiRequestValidatorWithOptions(swaggerSpec, &middleware.Options{
+			Options:      filterOptions,
+			ErrorHandler: errorHandler,
+			Skipper: func(ctx echo.Context) bool {
+				return isApiV2Path(ctx.Request().URL.Path)
+			},
+		}),
+		middleware.OapiRequestValidatorWithOptions(swaggerSpecV2, &middleware.Options{
+			Options:      filterOptions,
+			ErrorHandler: errorHandler,
+			Skipper: func(ctx echo.Context) bool {
+				return !isApiV2Path(ctx.Request().URL.Path)
+			},
+		}),
+	}, nil
+}
+
+// httpErrorHandler answers the errors of v2 with problem details, and those of v1 as echo does
+func httpErrorHandler(e *echo.Echo) echo.HTTPErrorHandler {
+	return func(err error, ctx echo.Context) {
+		if ctx.Response().Committed || !isApiV2Path(ctx.Request().URL.Path) {
+			e.DefaultHTTPErrorHandler(err, ctx)
+
+			return
+		}
+
+		code := http.StatusInternalServerError
+		detail := err.Error()
+
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			code = httpErr.Code
+			detail = fmt.Sprint(httpErr.Message)
+		}
+
+		if err := robot.GetProblem(ctx, code, detail); err != nil {
+			e.Logger.Error(err)
+		}
+	}
+}
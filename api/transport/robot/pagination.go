@@ -0,0 +1,223 @@
+package robot
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+)
+
+// DefaultPageLimit is the number of items of a page unless told otherwise
+const DefaultPageLimit = 100
+
+const (
+	headerTotalCount = "X-Total-Count"
+	headerNextCursor = "X-Next-Cursor"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageItem is an item of a listing, items with the same value are ordered by id
+type pageItem struct {
+	value int64
+	id    int64
+	index int
+}
+
+func (i pageItem) before(other pageItem, descending bool) bool {
+	if i.value != other.value {
+		return (i.value < other.value) != descending
+	}
+
+	if i.id != other.id {
+		return (i.id < other.id) != descending
+	}
+
+	return false
+}
+
+// paginate sorts the items by value and returns the indexes of the items of the page which
+// starts after the cursor, with the cursor of the next page which is empty on the last one.
+// The page holds every item after the cursor when limit is 0
+func paginate(
+	items []pageItem,
+	by string,
+	descending bool,
+	limit int,
+	cursor *string) ([]int, string, error) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].before(items[j], descending)
+	})
+
+	start := 0
+
+	if cursor != nil {
+		after, err := decodeCursor(*cursor, by, descending)
+		if err != nil {
+			return nil, "", err
+		}
+
+		start = sort.Search(len(items), func(i int) bool {
+			return after.before(items[i], descending)
+		})
+	}
+
+	end := len(items)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	indexes := make([]int, 0, end-start)
+	for _, item := range items[start:end] {
+		indexes = append(indexes, item.index)
+	}
+
+	next := ""
+	if end < len(items) {
+		next = encodeCursor(items[end-1], by, descending)
+	}
+
+	return indexes, next, nil
+}
+
+// pageRobotStatuses returns the page of the robots sorted by id, xPosition or yPosition which
+// starts after the cursor, with the cursor of the next page
+func pageRobotStatuses(
+	robots []robotStatus,
+	by string,
+	descending bool,
+	limit int,
+	cursor *string) ([]robotStatus, string, error) {
+	items := make([]pageItem, 0, len(robots))
+	for idx, robot := range robots {
+		item := pageItem{value: robot.robot.Id, id: robot.robot.Id, index: idx}
+
+		switch by {
+		case "xPosition":
+			item.value = int64(robot.robot.X)
+		case "yPosition":
+			item.value = int64(robot.robot.Y)
+		}
+
+		items = append(items, item)
+	}
+
+	indexes, next, err := paginate(items, by, descending, limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := make([]robotStatus, 0, len(indexes))
+	for _, idx := range indexes {
+		page = append(page, robots[idx])
+	}
+
+	return page, next, nil
+}
+
+// pageTasks returns the page of the tasks sorted by id or createdAt which starts after the
+// cursor, with the cursor of the next page
+func pageTasks(
+	tasks []repository.Task,
+	by string,
+	descending bool,
+	limit int,
+	cursor *string) ([]repository.Task, string, error) {
+	items := make([]pageItem, 0, len(tasks))
+	for idx, task := range tasks {
+		item := pageItem{value: task.Id, id: task.Id, index: idx}
+		if by == "createdAt" {
+			item.value = task.CreatedAt.UnixNano()
+		}
+
+		items = append(items, item)
+	}
+
+	indexes, next, err := paginate(items, by, descending, limit, cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := make([]repository.Task, 0, len(indexes))
+	for _, idx := range indexes {
+		page = append(page, tasks[idx])
+	}
+
+	return page, next, nil
+}
+
+// getPageLimit returns the number of items of a page of the v2 listings, which are always paged
+func getPageLimit(limit *int) int {
+	if limit == nil {
+		return DefaultPageLimit
+	}
+
+	return *limit
+}
+
+// getV1PageLimit returns the number of items of a page of the v1 listings, they list every item
+// unless a limit or a cursor is given, as they did before they were paged
+func getV1PageLimit(limit *int, cursor *string) int {
+	if limit == nil && cursor == nil {
+		return 0
+	}
+
+	return getPageLimit(limit)
+}
+
+// encodeCursor returns an opaque cursor which remembers the last item of a page and how the
+// items are sorted
+func encodeCursor(item pageItem, by string, descending bool) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(
+		"%s:%t:%d:%d",
+		by,
+		descending,
+		item.value,
+		item.id)))
+}
+
+func decodeCursor(cursor string, by string, descending bool) (pageItem, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return pageItem{}, errInvalidCursor
+	}
+
+	parts := strings.SplitN(string(buf), ":", 4)
+	if len(parts) != 4 || parts[0] != by || parts[1] != strconv.FormatBool(descending) {
+		return pageItem{}, errInvalidCursor
+	}
+
+	value, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return pageItem{}, errInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return pageItem{}, errInvalidCursor
+	}
+
+	return pageItem{value: value, id: id}, nil
+}
+
+// setPageHeaders tells the client how many items match the filters and where the next page starts
+func setPageHeaders(ctx echo.Context, total int, next string) {
+	ctx.Response().Header().Set(headerTotalCount, strconv.Itoa(total))
+
+	if next != "" {
+		ctx.Response().Header().Set(headerNextCursor, next)
+	}
+}
+
+func getInvalidCursorError(ctx echo.Context) error {
+	return getError(
+		ctx,
+		http.StatusBadRequest,
+		"Invalid cursor, it has to be the X-Next-Cursor header of a listing sorted the same way")
+}
@@ -0,0 +1,536 @@
+package robot
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"golang.org/x/net/websocket"
+)
+
+// robotServiceV1 serves the deprecated v1 of the api, it converts the requests and responses of
+// v1 for robotService
+type robotServiceV1 struct {
+	*robotService
+}
+
+// Dashboard returns the web ui
+func (s *robotServiceV1) Dashboard(ctx echo.Context) error {
+	return ctx.File("index.html")
+}
+
+// RobotsWebsocket is used by clients to read robots status via websocket
+func (s *robotServiceV1) RobotsWebsocket(ctx echo.Context) error {
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		internalRobotStatusChannel := make(chan []robotStatus)
+		id := s.idGeneratorService.Generate()
+
+		s.internalRobotStatusChannelsMutex.Lock()
+		s.internalRobotStatusChannels[id] = internalRobotStatusChannel
+		s.internalRobotStatusChannelsMutex.Unlock()
+
+		defer func() {
+			s.internalRobotStatusChannelsMutex.Lock()
+			delete(s.internalRobotStatusChannels, id)
+			close(internalRobotStatusChannel)
+			s.internalRobotStatusChannelsMutex.Unlock()
+		}()
+
+		robots, err := s.listRobotStatuses()
+		if err != nil {
+			s.logger.Error(err)
+
+			return
+		}
+
+		buf, err := json.Marshal(convertToTransportRobots(robots))
+		if err != nil {
+			s.logger.Error(err)
+
+			return
+		}
+
+		if err = websocket.Message.Send(ws, string(buf)); err != nil {
+			s.logger.Error(err)
+
+			return
+		}
+
+		for robots := range internalRobotStatusChannel {
+			buf, err := json.Marshal(convertToTransportRobots(robots))
+			if err != nil {
+				s.logger.Error(err)
+
+				break
+			}
+
+			if err = websocket.Message.Send(ws, string(buf)); err != nil {
+				s.logger.Error(err)
+
+				break
+			}
+
+		}
+	}).ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
+
+// TasksWebsocket is used by clients to read tasks status via websocket
+func (s *robotServiceV1) TasksWebsocket(ctx echo.Context) error {
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+		for {
+			tasks, err := s.listTasks(taskFilter{})
+			if err != nil {
+				s.logger.Error(err)
+				break
+			}
+			buf, err := json.Marshal(convertToTransportTasks(tasks))
+			if err != nil {
+				s.logger.Error(err)
+				break
+			}
+			if err = websocket.Message.Send(ws, string(buf)); err != nil {
+				s.logger.Error(err)
+				break
+			}
+
+			time.Sleep(time.Second * 1)
+		}
+	}).ServeHTTP(ctx.Response(), ctx.Request())
+	return nil
+}
+
+// GetAllRobots returns a page of the robots which match the filters
+func (s *robotServiceV1) GetAllRobots(ctx echo.Context, params robotapiserver.GetAllRobotsParams) error {
+	allRobots, err := s.listRobotStatuses()
+	if err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	robots := make([]robotStatus, 0)
+	for _, robot := range allRobots {
+		if matchesRobotFilters(robot, params) {
+			robots = append(robots, robot)
+		}
+	}
+
+	by := "id"
+	if params.Sort != nil {
+		by = string(*params.Sort)
+	}
+
+	page, next, err := pageRobotStatuses(
+		robots,
+		by,
+		params.Order != nil && *params.Order == "desc",
+		getV1PageLimit(params.Limit, params.Cursor),
+		params.Cursor)
+	if err != nil {
+		return getInvalidCursorError(ctx)
+	}
+
+	setPageHeaders(ctx, len(robots), next)
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportRobots(page))
+}
+
+// GetRobot returns a robot by its id
+func (s *robotServiceV1) GetRobot(ctx echo.Context, robotId robotapiserver.RobotId) error {
+	robot, err := s.getRobotStatus(int64(robotId))
+	if err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportRobot(robot))
+}
+
+// MoveRobot move a robot on grid, when the task has to be accepted by the simulator the task
+// is only created once the simulator has accepted it
+func (s *robotServiceV1) MoveRobot(
+	ctx echo.Context,
+	robotId robotapiserver.RobotId,
+	params robotapiserver.MoveRobotParams) error {
+	var moveRequest robotapiserver.MoveRobotRequest
+
+	err := ctx.Bind(&moveRequest)
+	if err != nil {
+		return getError(
+			ctx,
+			http.StatusBadRequest,
+			"Invalid format for MoveRobot request")
+	}
+
+	moveSequeneces := make([]eventpublisher.MoveRobotRequestMoveSequence, 0)
+
+	for _, moveSequence := range moveRequest.MoveSequences {
+		moveSequeneces = append(
+			moveSequeneces,
+			eventpublisher.MoveRobotRequestMoveSequence(moveSequence))
+	}
+
+	acceptance := ""
+	if params.Acceptance != nil {
+		acceptance = string(*params.Acceptance)
+	}
+
+	syncAcceptance := s.isSyncAcceptance(acceptance)
+
+	task, err := s.createTask(int64(robotId), moveSequeneces, syncAcceptance)
+	if err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	code := http.StatusAccepted
+	if syncAcceptance {
+		code = http.StatusCreated
+	}
+
+	return ctx.JSON(
+		code,
+		robotapiserver.MoveRobotResponse{
+			Task: convertToTransportTask(task),
+		},
+	)
+}
+
+// GetAllTasks returns a page of the tasks which match the filters
+func (s *robotServiceV1) GetAllTasks(ctx echo.Context, params robotapiserver.GetAllTasksParams) error {
+	filter := taskFilter{
+		createdAfter:  params.CreatedAfter,
+		createdBefore: params.CreatedBefore,
+	}
+
+	if params.Status != nil {
+		status := repository.TaskStatus(*params.Status)
+		filter.status = &status
+	}
+
+	if params.RobotId != nil {
+		robotId := int64(*params.RobotId)
+		filter.robotId = &robotId
+	}
+
+	tasks, err := s.listTasks(filter)
+	if err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	by := "id"
+	if params.Sort != nil {
+		by = string(*params.Sort)
+	}
+
+	page, next, err := pageTasks(
+		tasks,
+		by,
+		params.Order != nil && *params.Order == "desc",
+		getV1PageLimit(params.Limit, params.Cursor),
+		params.Cursor)
+	if err != nil {
+		return getInvalidCursorError(ctx)
+	}
+
+	setPageHeaders(ctx, len(tasks), next)
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportTasks(page))
+}
+
+// MoveRobot returns an specific task by its id
+func (s *robotServiceV1) GetTask(ctx echo.Context, taskId robotapiserver.TaskId) error {
+	task, err := s.getTask(taskId)
+	if err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportTask(task))
+}
+
+// MoveRobot cancels a task by its id
+func (s *robotServiceV1) CancelTask(ctx echo.Context, taskId robotapiserver.TaskId) error {
+	if _, err := s.cancelTask(taskId); err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// CreateTaskBatch creates a task for each robot of the batch, the batch is either accepted or
+// rejected as a whole. The Idempotency-Key header is honoured by the idempotency middleware of
+// the server
+func (s *robotServiceV1) CreateTaskBatch(
+	ctx echo.Context,
+	params robotapiserver.CreateTaskBatchParams) error {
+	var batchRequest robotapiserver.TaskBatchRequest
+
+	err := ctx.Bind(&batchRequest)
+	if err != nil {
+		return getError(
+			ctx,
+			http.StatusBadRequest,
+			"Invalid format for CreateTaskBatch request")
+	}
+
+	requests := make([]taskRequest, 0, len(batchRequest.Tasks))
+	for _, task := range batchRequest.Tasks {
+		moveSequeneces := make([]eventpublisher.MoveRobotRequestMoveSequence, 0, len(task.MoveSequences))
+		for _, moveSequence := range task.MoveSequences {
+			moveSequeneces = append(
+				moveSequeneces,
+				eventpublisher.MoveRobotRequestMoveSequence(moveSequence))
+		}
+
+		requests = append(requests, taskRequest{
+			robotId:       int64(task.RobotId),
+			moveSequences: moveSequeneces,
+		})
+	}
+
+	acceptance := ""
+	if params.Acceptance != nil {
+		acceptance = string(*params.Acceptance)
+	}
+
+	syncAcceptance := s.isSyncAcceptance(acceptance)
+
+	batchId, tasks, err := s.createTaskBatch(requests, syncAcceptance)
+	if err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	code := http.StatusAccepted
+	if syncAcceptance {
+		code = http.StatusCreated
+	}
+
+	return ctx.JSON(code, convertToTransportTaskBatch(batchId, tasks))
+}
+
+// GetTaskBatch returns the tasks of a batch
+func (s *robotServiceV1) GetTaskBatch(ctx echo.Context, batchId robotapiserver.BatchId) error {
+	tasks, err := s.getTaskBatch(batchId)
+	if err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportTaskBatch(batchId, tasks))
+}
+
+// CancelTaskBatch cancels the tasks of a batch which are not finished yet
+func (s *robotServiceV1) CancelTaskBatch(ctx echo.Context, batchId robotapiserver.BatchId) error {
+	if _, err := s.cancelTaskBatch(batchId); err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetWarehouse returns the size and the layout of the warehouse
+func (s *robotServiceV1) GetWarehouse(ctx echo.Context) error {
+	warehouse, err := s.getWarehouse()
+	if err != nil {
+		return getServiceError(ctx, err)
+	}
+
+	transportWarehouse := robotapiserver.Warehouse{
+		Name:         warehouse.Name,
+		Width:        warehouse.Width,
+		Height:       warehouse.Height,
+		Obstacles:    make([]robotapiserver.Cell, 0, len(warehouse.Obstacles)),
+		SpecialCells: make([]robotapiserver.SpecialCell, 0, len(warehouse.SpecialCells)),
+	}
+
+	for _, obstacle := range warehouse.Obstacles {
+		transportWarehouse.Obstacles = append(transportWarehouse.Obstacles, robotapiserver.Cell{
+			XPosition: obstacle.X,
+			YPosition: obstacle.Y,
+		})
+	}
+
+	for _, specialCell := range warehouse.SpecialCells {
+		transportWarehouse.SpecialCells = append(transportWarehouse.SpecialCells, robotapiserver.SpecialCell{
+			XPosition: specialCell.X,
+			YPosition: specialCell.Y,
+			Kind:      robotapiserver.SpecialCellKind(specialCell.Kind),
+		})
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		transportWarehouse)
+}
+
+// GetHealth returns the state of the connection to the broker
+func (s *robotServiceV1) GetHealth(ctx echo.Context) error {
+	stats := s.healthService.ConnectionStats()
+
+	health := robotapiserver.Health{
+		Broker: robotapiserver.BrokerHealth{
+			State:       robotapiserver.BrokerHealthState(stats.State),
+			Since:       stats.Since,
+			Disconnects: int64(stats.Disconnects),
+			Reconnects:  int64(stats.Reconnects),
+			InMsgs:      int64(stats.InMsgs),
+			OutMsgs:     int64(stats.OutMsgs),
+			InBytes:     int64(stats.InBytes),
+			OutBytes:    int64(stats.OutBytes),
+		},
+		PublishCircuit: robotapiserver.HealthPublishCircuit(s.circuitBreakerService.State()),
+	}
+
+	if stats.LastError != "" {
+		health.Broker.LastError = &stats.LastError
+	}
+
+	code := http.StatusOK
+	if stats.State != robotbroker.Connected {
+		code = http.StatusServiceUnavailable
+	}
+
+	return ctx.JSON(code, health)
+}
+
+func getError(ctx echo.Context, code int, message string) error {
+	return ctx.JSON(
+		code,
+		robotapiserver.Error{
+			Code:    code,
+			Message: message,
+		},
+	)
+}
+
+// getServiceError responds with the status code of an error returned by robotService
+func getServiceError(ctx echo.Context, err error) error {
+	serviceErr := asServiceError(ctx, err)
+
+	return getError(
+		ctx,
+		serviceErr.code,
+		serviceErr.message)
+}
+
+func matchesRobotFilters(
+	robot robotStatus,
+	params robotapiserver.GetAllRobotsParams) bool {
+	switch {
+	case params.MinX != nil && robot.robot.X < *params.MinX,
+		params.MaxX != nil && robot.robot.X > *params.MaxX,
+		params.MinY != nil && robot.robot.Y < *params.MinY,
+		params.MaxY != nil && robot.robot.Y > *params.MaxY,
+		params.Busy != nil && robot.activity.isBusy() != *params.Busy:
+		return false
+	}
+
+	return true
+}
+
+func convertToTransportRobots(robots []robotStatus) []robotapiserver.Robot {
+	transportRobots := make([]robotapiserver.Robot, 0, len(robots))
+	for _, robot := range robots {
+		transportRobots = append(transportRobots, convertToTransportRobot(robot))
+	}
+
+	return transportRobots
+}
+
+func convertToTransportRobot(robot robotStatus) robotapiserver.Robot {
+	transportRobot := robotapiserver.Robot{
+		Id:              int(robot.robot.Id),
+		XPosition:       robot.robot.X,
+		YPosition:       robot.robot.Y,
+		State:           robotapiserver.RobotState(robot.state()),
+		QueuedTaskCount: robot.activity.queuedTaskCount,
+		HasCrate:        robot.robot.HasCrate,
+		LastSeenAt:      robot.robot.UpdatedAt,
+	}
+
+	if robot.activity.currentTaskId != 0 {
+		currentTaskId := robot.activity.currentTaskId
+		transportRobot.CurrentTaskId = &currentTaskId
+	}
+
+	if robot.robot.LastError != "" {
+		transportRobot.LastError = &robot.robot.LastError
+	}
+
+	return transportRobot
+}
+
+func convertToTransportTasks(tasks []repository.Task) []robotapiserver.Task {
+	transportTasks := make([]robotapiserver.Task, 0, len(tasks))
+	for _, task := range tasks {
+		transportTasks = append(transportTasks, convertToTransportTask(task))
+	}
+
+	return transportTasks
+}
+
+func convertToTransportTask(task repository.Task) robotapiserver.Task {
+	transportTask := robotapiserver.Task{
+		Id:            task.Id,
+		IdString:      strconv.FormatInt(task.Id, 10),
+		Status:        robotapiserver.TaskStatus(task.Status),
+		RobotId:       int(task.RobotId),
+		MoveSequences: make([]robotapiserver.TaskMoveSequences, 0, len(task.MoveSequences)),
+		CreatedAt:     task.CreatedAt,
+		StartedAt:     task.StartedAt,
+		FinishedAt:    task.FinishedAt,
+		Trajectory:    make([]robotapiserver.Cell, 0, len(task.Trajectory)),
+	}
+
+	for _, moveSequence := range task.MoveSequences {
+		transportTask.MoveSequences = append(
+			transportTask.MoveSequences,
+			robotapiserver.TaskMoveSequences(moveSequence))
+	}
+
+	for _, position := range task.Trajectory {
+		transportTask.Trajectory = append(transportTask.Trajectory, robotapiserver.Cell{
+			XPosition: position.X,
+			YPosition: position.Y,
+		})
+	}
+
+	if task.BatchId != "" {
+		transportTask.BatchId = &task.BatchId
+	}
+
+	if task.FailureReason != "" {
+		transportTask.FailureReason = &task.FailureReason
+	}
+
+	if position := task.Position(); position != nil {
+		transportTask.Position = &robotapiserver.Cell{
+			XPosition: position.X,
+			YPosition: position.Y,
+		}
+	}
+
+	return transportTask
+}
+
+func convertToTransportTaskBatch(batchId string, tasks []repository.Task) robotapiserver.TaskBatch {
+	return robotapiserver.TaskBatch{
+		Id:    batchId,
+		Tasks: convertToTransportTasks(tasks),
+	}
+}
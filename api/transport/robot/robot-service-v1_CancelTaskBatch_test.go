@@ -0,0 +1,100 @@
+package robot_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+)
+
+func Test_CancelTaskBatch_Should_Cancel_The_Unfinished_Tasks_In_A_Single_Event(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	for _, task := range []repository.Task{
+		{Id: 1, RobotId: 0, BatchId: "10", Status: repository.TaskStatusCompleted},
+		{Id: 2, RobotId: 1, BatchId: "10", Status: repository.TaskStatusCreated},
+		{Id: 3, RobotId: 2, BatchId: "10", Status: repository.TaskStatusInProgress},
+	} {
+		g.Expect(repositoryService.SaveTask(task)).Should(Succeed())
+	}
+
+	sut, _, mockEventPublisherService := newRobotService(ctrl, g, repositoryService, nil)
+
+	mockEventPublisherService.
+		EXPECT().
+		PublishTaskEvent(eventpublisher.TaskEvent{
+			EventType: eventpublisher.TaskBatchCancelled,
+			Id:        10,
+			Data: eventpublisher.TaskData{
+				BatchId: "10",
+				Tasks: []eventpublisher.BatchTask{
+					{Id: 2, RobotId: 1},
+					{Id: 3, RobotId: 2},
+				},
+			},
+		}).
+		Return(nil)
+
+	response := serve(g, http.MethodPost, "/api/batches/10/cancel", "", func(ctx echo.Context) error {
+		return sut.CancelTaskBatch(ctx, "10")
+	}, nil)
+	g.Expect(response.Code).Should(Equal(http.StatusNoContent))
+
+	for taskId, status := range map[int64]repository.TaskStatus{
+		1: repository.TaskStatusCompleted,
+		2: repository.TaskStatusCancelled,
+		3: repository.TaskStatusCancelled,
+	} {
+		task, found, err := repositoryService.GetTask(taskId)
+		g.Expect(err).Should(BeNil())
+		g.Expect(found).Should(BeTrue())
+		g.Expect(task.Status).Should(Equal(status))
+	}
+}
+
+func Test_CancelTaskBatch_Should_Cancel_No_Task_When_The_Event_Is_Not_Published(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	for _, task := range []repository.Task{
+		{Id: 1, RobotId: 0, BatchId: "10", Status: repository.TaskStatusCreated},
+		{Id: 2, RobotId: 1, BatchId: "10", Status: repository.TaskStatusCreated},
+	} {
+		g.Expect(repositoryService.SaveTask(task)).Should(Succeed())
+	}
+
+	sut, _, mockEventPublisherService := newRobotService(ctrl, g, repositoryService, nil)
+
+	mockEventPublisherService.
+		EXPECT().
+		PublishTaskEvent(gomock.Any()).
+		Return(errors.New("nats: connection closed"))
+
+	response := serve(g, http.MethodPost, "/api/batches/10/cancel", "", func(ctx echo.Context) error {
+		return sut.CancelTaskBatch(ctx, "10")
+	}, nil)
+	g.Expect(response.Code).ShouldNot(Equal(http.StatusNoContent))
+
+	for _, taskId := range []int64{1, 2} {
+		task, found, err := repositoryService.GetTask(taskId)
+		g.Expect(err).Should(BeNil())
+		g.Expect(found).Should(BeTrue())
+		g.Expect(task.Status).Should(Equal(repository.TaskStatusCreated))
+	}
+}
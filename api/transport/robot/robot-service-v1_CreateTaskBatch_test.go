@@ -0,0 +1,111 @@
+package robot_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/api/processors"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+)
+
+func Test_CreateTaskBatch_Should_Publish_The_Tasks_In_A_Single_Event(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 0, X: 0, Y: 0})).Should(Succeed())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 1, X: 1, Y: 0})).Should(Succeed())
+
+	sut, _, mockEventPublisherService := newRobotService(ctrl, g, repositoryService, &processors.Warehouse{
+		Width:  10,
+		Height: 10,
+	})
+
+	var event eventpublisher.TaskEvent
+
+	mockEventPublisherService.
+		EXPECT().
+		PublishTaskEvent(gomock.Any()).
+		DoAndReturn(func(taskEvent eventpublisher.TaskEvent) error {
+			event = taskEvent
+
+			return nil
+		}).
+		Times(1)
+
+	var batch robotapiserver.TaskBatch
+
+	response := serve(
+		g,
+		http.MethodPost,
+		"/api/tasks:batch",
+		`{"tasks":[{"robotId":0,"moveSequences":["N","N","N"]},{"robotId":1,"moveSequences":["N","E"]}]}`,
+		func(ctx echo.Context) error {
+			return sut.CreateTaskBatch(ctx, robotapiserver.CreateTaskBatchParams{})
+		},
+		&batch)
+	g.Expect(response.Code).Should(Equal(http.StatusAccepted))
+	g.Expect(batch.Id).ShouldNot(BeEmpty())
+	g.Expect(batch.Tasks).Should(HaveLen(2))
+
+	for _, task := range batch.Tasks {
+		g.Expect(task.BatchId).Should(Equal(&batch.Id))
+		g.Expect(task.Status).Should(Equal(robotapiserver.TaskStatusCreated))
+	}
+
+	g.Expect(event.EventType).Should(Equal(eventpublisher.TaskBatchCreated))
+	g.Expect(event.Data.BatchId).Should(Equal(batch.Id))
+	g.Expect(event.Data.Tasks).Should(Equal([]eventpublisher.BatchTask{
+		{
+			Id:             batch.Tasks[0].Id,
+			RobotId:        0,
+			MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.NORTH, eventpublisher.NORTH, eventpublisher.NORTH},
+		},
+		{
+			Id:             batch.Tasks[1].Id,
+			RobotId:        1,
+			MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.NORTH, eventpublisher.EAST},
+		},
+	}))
+}
+
+func Test_CreateTaskBatch_Should_Reject_The_Batch_As_A_Whole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 0, X: 0, Y: 0})).Should(Succeed())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 1, X: 1, Y: 0})).Should(Succeed())
+
+	sut, _, _ := newRobotService(ctrl, g, repositoryService, &processors.Warehouse{
+		Width:  10,
+		Height: 10,
+	})
+
+	// Moving south takes robot 0 off the board, the moves of robot 1 are fine
+	response := serve(
+		g,
+		http.MethodPost,
+		"/api/tasks:batch",
+		`{"tasks":[{"robotId":1,"moveSequences":["N"]},{"robotId":0,"moveSequences":["S"]}]}`,
+		func(ctx echo.Context) error {
+			return sut.CreateTaskBatch(ctx, robotapiserver.CreateTaskBatchParams{})
+		},
+		nil)
+	g.Expect(response.Code).Should(Equal(http.StatusUnprocessableEntity))
+
+	tasks, err := repositoryService.ListTasks()
+	g.Expect(err).Should(BeNil())
+	g.Expect(tasks).Should(BeEmpty())
+}
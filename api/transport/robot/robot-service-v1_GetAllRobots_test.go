@@ -0,0 +1,60 @@
+package robot_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+)
+
+func Test_GetAllRobots_Should_Filter_And_Sort_The_Robots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	// The robots start on the first row, one next to the other
+	for _, robot := range []repository.Robot{
+		{Id: 0, X: 0, Y: 0},
+		{Id: 1, X: 1, Y: 0},
+		{Id: 2, X: 2, Y: 0},
+		{Id: 3, X: 3, Y: 1},
+	} {
+		g.Expect(repositoryService.SaveRobot(robot)).Should(Succeed())
+	}
+
+	sut, _, _ := newRobotService(ctrl, g, repositoryService, nil)
+
+	minX := 1
+	maxY := 0
+	byXPosition := robotapiserver.GetAllRobotsParamsSort("xPosition")
+	descending := robotapiserver.GetAllRobotsParamsOrder("desc")
+
+	robots := make([]robotapiserver.Robot, 0)
+	response := serve(g, http.MethodGet, "/api/robots", "", func(ctx echo.Context) error {
+		return sut.GetAllRobots(ctx, robotapiserver.GetAllRobotsParams{
+			MinX:  &minX,
+			MaxY:  &maxY,
+			Sort:  &byXPosition,
+			Order: &descending,
+		})
+	}, &robots)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+	g.Expect(response.Header().Get("X-Total-Count")).Should(Equal("2"))
+	g.Expect(robots).Should(HaveLen(2))
+	g.Expect(robots[0]).Should(And(
+		HaveField("Id", 2),
+		HaveField("XPosition", 2),
+		HaveField("YPosition", 0)))
+	g.Expect(robots[1]).Should(And(
+		HaveField("Id", 1),
+		HaveField("XPosition", 1),
+		HaveField("YPosition", 0)))
+}
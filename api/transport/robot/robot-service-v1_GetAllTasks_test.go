@@ -0,0 +1,158 @@
+package robot_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+)
+
+func Test_GetAllTasks_Should_Page_Through_The_Tasks_Of_A_Robot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	for _, task := range []repository.Task{
+		{Id: 1, RobotId: 0, Status: repository.TaskStatusCompleted},
+		{Id: 2, RobotId: 1, Status: repository.TaskStatusCompleted},
+		{Id: 3, RobotId: 0, Status: repository.TaskStatusInProgress},
+		{Id: 4, RobotId: 0, Status: repository.TaskStatusCreated},
+	} {
+		g.Expect(repositoryService.SaveTask(task)).Should(Succeed())
+	}
+
+	sut, _, _ := newRobotService(ctrl, g, repositoryService, nil)
+
+	robotId := 0
+	limit := 2
+	descending := robotapiserver.GetAllTasksParamsOrder("desc")
+
+	tasks := make([]robotapiserver.Task, 0)
+	response := serve(g, http.MethodGet, "/api/tasks", "", func(ctx echo.Context) error {
+		return sut.GetAllTasks(ctx, robotapiserver.GetAllTasksParams{
+			RobotId: &robotId,
+			Order:   &descending,
+			Limit:   &limit,
+		})
+	}, &tasks)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+	g.Expect(response.Header().Get("X-Total-Count")).Should(Equal("3"))
+	g.Expect(tasks).Should(HaveLen(2))
+	g.Expect(tasks[0].Id).Should(Equal(int64(4)))
+	g.Expect(tasks[1].Id).Should(Equal(int64(3)))
+
+	cursor := response.Header().Get("X-Next-Cursor")
+	g.Expect(cursor).ShouldNot(BeEmpty())
+
+	nextTasks := make([]robotapiserver.Task, 0)
+	response = serve(g, http.MethodGet, "/api/tasks", "", func(ctx echo.Context) error {
+		return sut.GetAllTasks(ctx, robotapiserver.GetAllTasksParams{
+			RobotId: &robotId,
+			Order:   &descending,
+			Limit:   &limit,
+			Cursor:  &cursor,
+		})
+	}, &nextTasks)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+	g.Expect(response.Header().Get("X-Next-Cursor")).Should(BeEmpty())
+	g.Expect(nextTasks).Should(HaveLen(1))
+	g.Expect(nextTasks[0].Id).Should(Equal(int64(1)))
+}
+
+func Test_GetAllTasks_Should_Refuse_A_Cursor_Of_Another_Listing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	for _, task := range []repository.Task{
+		{Id: 1, Status: repository.TaskStatusCompleted},
+		{Id: 2, Status: repository.TaskStatusCompleted},
+	} {
+		g.Expect(repositoryService.SaveTask(task)).Should(Succeed())
+	}
+
+	sut, _, _ := newRobotService(ctrl, g, repositoryService, nil)
+
+	limit := 1
+	descending := robotapiserver.GetAllTasksParamsOrder("desc")
+
+	response := serve(g, http.MethodGet, "/api/tasks", "", func(ctx echo.Context) error {
+		return sut.GetAllTasks(ctx, robotapiserver.GetAllTasksParams{
+			Order: &descending,
+			Limit: &limit,
+		})
+	}, nil)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+
+	cursor := response.Header().Get("X-Next-Cursor")
+	g.Expect(cursor).ShouldNot(BeEmpty())
+
+	// A cursor only continues the listing it comes from
+	var robotError robotapiserver.Error
+
+	response = serve(g, http.MethodGet, "/api/tasks", "", func(ctx echo.Context) error {
+		return sut.GetAllTasks(ctx, robotapiserver.GetAllTasksParams{
+			Limit:  &limit,
+			Cursor: &cursor,
+		})
+	}, &robotError)
+	g.Expect(response.Code).Should(Equal(http.StatusBadRequest))
+	g.Expect(robotError.Code).Should(Equal(http.StatusBadRequest))
+}
+
+func Test_GetAllTasks_Should_List_Every_Task_Unless_A_Page_Is_Asked_For(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	for id := int64(1); id <= robot.DefaultPageLimit+1; id++ {
+		g.Expect(repositoryService.SaveTask(repository.Task{
+			Id:     id,
+			Status: repository.TaskStatusCompleted,
+		})).Should(Succeed())
+	}
+
+	sut, _, _ := newRobotService(ctrl, g, repositoryService, nil)
+
+	tasks := make([]robotapiserver.Task, 0)
+	response := serve(g, http.MethodGet, "/api/tasks", "", func(ctx echo.Context) error {
+		return sut.GetAllTasks(ctx, robotapiserver.GetAllTasksParams{})
+	}, &tasks)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+	g.Expect(response.Header().Get("X-Next-Cursor")).Should(BeEmpty())
+	g.Expect(tasks).Should(HaveLen(robot.DefaultPageLimit + 1))
+
+	// A cursor pages through the tasks by DefaultPageLimit
+	limit := 1
+
+	response = serve(g, http.MethodGet, "/api/tasks", "", func(ctx echo.Context) error {
+		return sut.GetAllTasks(ctx, robotapiserver.GetAllTasksParams{Limit: &limit})
+	}, nil)
+
+	cursor := response.Header().Get("X-Next-Cursor")
+	g.Expect(cursor).ShouldNot(BeEmpty())
+
+	response = serve(g, http.MethodGet, "/api/tasks", "", func(ctx echo.Context) error {
+		return sut.GetAllTasks(ctx, robotapiserver.GetAllTasksParams{Cursor: &cursor})
+	}, &tasks)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+	g.Expect(response.Header().Get("X-Next-Cursor")).Should(BeEmpty())
+	g.Expect(tasks).Should(HaveLen(robot.DefaultPageLimit))
+}
@@ -0,0 +1,48 @@
+package robot_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+)
+
+func Test_GetTaskBatch_Should_Return_The_Tasks_Of_The_Batch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	for _, task := range []repository.Task{
+		{Id: 1, RobotId: 0, BatchId: "10", Status: repository.TaskStatusCompleted},
+		{Id: 2, RobotId: 0, Status: repository.TaskStatusCompleted},
+		{Id: 3, RobotId: 1, BatchId: "10", Status: repository.TaskStatusCancelled},
+	} {
+		g.Expect(repositoryService.SaveTask(task)).Should(Succeed())
+	}
+
+	sut, _, _ := newRobotService(ctrl, g, repositoryService, nil)
+
+	var batch robotapiserver.TaskBatch
+
+	response := serve(g, http.MethodGet, "/api/batches/10", "", func(ctx echo.Context) error {
+		return sut.GetTaskBatch(ctx, "10")
+	}, &batch)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+	g.Expect(batch.Id).Should(Equal("10"))
+	g.Expect(batch.Tasks).Should(ConsistOf(
+		HaveField("Id", int64(1)),
+		HaveField("Id", int64(3))))
+
+	response = serve(g, http.MethodGet, "/api/batches/unknown", "", func(ctx echo.Context) error {
+		return sut.GetTaskBatch(ctx, "unknown")
+	}, nil)
+	g.Expect(response.Code).Should(Equal(http.StatusNotFound))
+}
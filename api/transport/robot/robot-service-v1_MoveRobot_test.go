@@ -0,0 +1,242 @@
+package robot_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/api/processors"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+	. "github.com/sepisoad/robot-challange/shared/services/circuitbreaker/mock"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	. "github.com/sepisoad/robot-challange/shared/services/eventpublisher/mock"
+	. "github.com/sepisoad/robot-challange/shared/services/idgenerator/mock"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_MoveRobot_Should_Keep_The_Robot_On_The_Board_And_Off_The_Obstacles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 0})).Should(Succeed())
+
+	sut, _, mockEventPublisherService := newRobotService(ctrl, g, repositoryService, &processors.Warehouse{
+		Width:     6,
+		Height:    4,
+		Obstacles: []eventpublisher.Cell{{X: 3, Y: 3}},
+	})
+
+	mockEventPublisherService.
+		EXPECT().
+		PublishTaskEvent(gomock.Any()).
+		Return(nil).
+		Times(2)
+
+	moveRobot := func(body string) int {
+		return serve(g, http.MethodPut, "/api/robots/0", body, func(ctx echo.Context) error {
+			return sut.MoveRobot(ctx, 0, robotapiserver.MoveRobotParams{})
+		}, nil).Code
+	}
+
+	// A robot can never walk 6 cells east on a board 6 cells wide
+	g.Expect(moveRobot(`{"moveSequences":["E","E","E","E","E","E"]}`)).Should(Equal(http.StatusUnprocessableEntity))
+
+	// Nor onto an obstacle
+	g.Expect(moveRobot(`{"moveSequences":["N","N","N","E","E","E"]}`)).Should(Equal(http.StatusUnprocessableEntity))
+
+	// The moves are checked from where the tasks queued before take the robot, whether they are
+	// done or not
+	g.Expect(moveRobot(`{"moveSequences":["E","E","E","E"]}`)).Should(Equal(http.StatusAccepted))
+	g.Expect(moveRobot(`{"moveSequences":["E","E"]}`)).Should(Equal(http.StatusUnprocessableEntity))
+	g.Expect(moveRobot(`{"moveSequences":["E"]}`)).Should(Equal(http.StatusAccepted))
+}
+
+func Test_MoveRobot_Should_Not_Move_An_Unknown_Robot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	sut, _, _ := newRobotService(ctrl, g, repositoryService, nil)
+
+	var robotError robotapiserver.Error
+
+	response := serve(g, http.MethodPut, "/api/robots/404", `{"moveSequences":["E"]}`, func(ctx echo.Context) error {
+		return sut.MoveRobot(ctx, 404, robotapiserver.MoveRobotParams{})
+	}, &robotError)
+	g.Expect(response.Code).Should(Equal(http.StatusNotFound))
+	g.Expect(robotError.Code).Should(Equal(http.StatusNotFound))
+}
+
+func Test_MoveRobot_Should_Not_Wait_For_The_Acceptance_Of_The_Tasks_Of_Other_Robots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 0})).Should(Succeed())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 1, X: 1})).Should(Succeed())
+
+	mockEventPublisherService := NewMockEventPublisherInterface(ctrl)
+	mockTaskRequesterService := NewMockTaskRequesterInterface(ctrl)
+	mockIdGeneratorService := NewMockIdGeneratorInterface(ctrl)
+
+	mockIdGeneratorService.
+		EXPECT().
+		Generate().
+		Return(int64(1))
+
+	mockIdGeneratorService.
+		EXPECT().
+		Generate().
+		Return(int64(2))
+
+	mockEventPublisherService.
+		EXPECT().
+		PublishTaskEvent(gomock.Any()).
+		Return(nil).
+		Times(2)
+
+	requested := make(chan struct{})
+	accept := make(chan struct{})
+
+	// The simulator is slow to accept the task of robot 0
+	mockTaskRequesterService.
+		EXPECT().
+		RequestTask(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(event eventpublisher.TaskEvent, timeout time.Duration) (eventpublisher.TaskAcceptance, error) {
+			close(requested)
+			<-accept
+
+			return eventpublisher.TaskAcceptance{Accepted: true}, nil
+		})
+
+	sut, _, err := robot.NewRobotService(
+		sugarLogger,
+		make(chan repository.Robot),
+		make(chan processors.Warehouse),
+		nil,
+		nil,
+		repositoryService,
+		mockEventPublisherService,
+		mockTaskRequesterService,
+		mockIdGeneratorService,
+		NewMockHealthInterface(ctrl),
+		NewMockCircuitBreakerInterface(ctrl),
+		robot.AcceptanceOptions{Sync: true})
+	g.Expect(err).Should(BeNil())
+
+	accepted := make(chan int)
+
+	go func() {
+		accepted <- serve(g, http.MethodPut, "/api/robots/0", `{"moveSequences":["E"]}`, func(ctx echo.Context) error {
+			return sut.MoveRobot(ctx, 0, robotapiserver.MoveRobotParams{})
+		}, nil).Code
+	}()
+
+	g.Eventually(requested).Should(BeClosed())
+
+	async := robotapiserver.MoveRobotParamsAcceptance("async")
+	created := make(chan int)
+
+	go func() {
+		created <- serve(g, http.MethodPut, "/api/robots/1", `{"moveSequences":["E"]}`, func(ctx echo.Context) error {
+			return sut.MoveRobot(ctx, 1, robotapiserver.MoveRobotParams{Acceptance: &async})
+		}, nil).Code
+	}()
+
+	g.Eventually(created).Should(Receive(Equal(http.StatusAccepted)))
+
+	close(accept)
+	g.Eventually(accepted).Should(Receive(Equal(http.StatusCreated)))
+}
+
+func Test_MoveRobot_Should_Mark_The_Created_Event_Of_An_Accepted_Task(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 0})).Should(Succeed())
+
+	mockEventPublisherService := NewMockEventPublisherInterface(ctrl)
+	mockTaskRequesterService := NewMockTaskRequesterInterface(ctrl)
+	mockIdGeneratorService := NewMockIdGeneratorInterface(ctrl)
+
+	mockIdGeneratorService.
+		EXPECT().
+		Generate().
+		Return(int64(1))
+
+	// The simulator is asked before it accepted the task
+	mockTaskRequesterService.
+		EXPECT().
+		RequestTask(eventpublisher.TaskEvent{
+			EventType: eventpublisher.TaskCreated,
+			Id:        1,
+			Data: eventpublisher.TaskData{
+				RobotId:        0,
+				MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.EAST},
+			},
+		}, gomock.Any()).
+		Return(eventpublisher.TaskAcceptance{Accepted: true}, nil)
+
+	// Every simulator then skips the created event, the one which accepted it enqueued it already
+	mockEventPublisherService.
+		EXPECT().
+		PublishTaskEvent(eventpublisher.TaskEvent{
+			EventType: eventpublisher.TaskCreated,
+			Id:        1,
+			Data: eventpublisher.TaskData{
+				RobotId:        0,
+				MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.EAST},
+				Accepted:       true,
+			},
+		}).
+		Return(nil)
+
+	sut, _, err := robot.NewRobotService(
+		sugarLogger,
+		make(chan repository.Robot),
+		make(chan processors.Warehouse),
+		nil,
+		nil,
+		repositoryService,
+		mockEventPublisherService,
+		mockTaskRequesterService,
+		mockIdGeneratorService,
+		NewMockHealthInterface(ctrl),
+		NewMockCircuitBreakerInterface(ctrl),
+		robot.AcceptanceOptions{Sync: true})
+	g.Expect(err).Should(BeNil())
+
+	response := serve(g, http.MethodPut, "/api/robots/0", `{"moveSequences":["E"]}`, func(ctx echo.Context) error {
+		return sut.MoveRobot(ctx, 0, robotapiserver.MoveRobotParams{})
+	}, nil)
+	g.Expect(response.Code).Should(Equal(http.StatusCreated))
+}
@@ -0,0 +1,540 @@
+package robot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+// MIMEApplicationProblemJSON is the content type of the errors of v2, see RFC 7807
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// GetProblem responds with the problem details of an error, the type is about:blank as the
+// status code tells what the problem is
+func GetProblem(ctx echo.Context, code int, detail string) error {
+	instance := ctx.Request().URL.Path
+
+	buf, err := json.Marshal(robotapiv2server.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Detail:   &detail,
+		Instance: &instance,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.Blob(code, MIMEApplicationProblemJSON, buf)
+}
+
+// robotServiceV2 serves v2 of the api, it converts the requests and responses of v2 for
+// robotService. Ids are strings so clients do not depend on their format
+type robotServiceV2 struct {
+	*robotService
+}
+
+var taskStatusesV2 = map[repository.TaskStatus]robotapiv2server.TaskStatus{
+	repository.TaskStatusQueuedForDispatch: robotapiv2server.TaskStatusQueuedForDispatch,
+	repository.TaskStatusCreated:           robotapiv2server.TaskStatusCreated,
+	repository.TaskStatusInProgress:        robotapiv2server.TaskStatusInProgress,
+	repository.TaskStatusCompleted:         robotapiv2server.TaskStatusCompleted,
+	repository.TaskStatusFailed:            robotapiv2server.TaskStatusFailed,
+	repository.TaskStatusCancelled:         robotapiv2server.TaskStatusCancelled,
+}
+
+// robotSortsV2 are the fields robots are sorted by in robotService, by the sort parameter of v2
+var robotSortsV2 = map[robotapiv2server.ListRobotsParamsSort]string{
+	robotapiv2server.ListRobotsParamsSort(robotapiv2server.RobotSortId): "id",
+	robotapiv2server.ListRobotsParamsSort(robotapiv2server.RobotSortX):  "xPosition",
+	robotapiv2server.ListRobotsParamsSort(robotapiv2server.RobotSortY):  "yPosition",
+}
+
+// ListRobots returns a page of the robots which match the filters
+func (s *robotServiceV2) ListRobots(ctx echo.Context, params robotapiv2server.ListRobotsParams) error {
+	allRobots, err := s.listRobotStatuses()
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	robots := make([]robotStatus, 0)
+	for _, robot := range allRobots {
+		if params.State == nil || robot.state() == string(*params.State) {
+			robots = append(robots, robot)
+		}
+	}
+
+	by := "id"
+	if params.Sort != nil {
+		by = robotSortsV2[*params.Sort]
+	}
+
+	page, next, err := pageRobotStatuses(
+		robots,
+		by,
+		params.Order != nil && *params.Order == "desc",
+		getPageLimit(params.Limit),
+		params.Cursor)
+	if err != nil {
+		return getInvalidCursorProblem(ctx)
+	}
+
+	robotPage := robotapiv2server.RobotPage{
+		Items:      make([]robotapiv2server.Robot, 0, len(page)),
+		TotalCount: len(robots),
+	}
+
+	for _, robot := range page {
+		robotPage.Items = append(robotPage.Items, convertToTransportRobotV2(robot))
+	}
+
+	if next != "" {
+		robotPage.NextCursor = &next
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		robotPage)
+}
+
+// GetRobot returns a robot by its id
+func (s *robotServiceV2) GetRobot(ctx echo.Context, robotId robotapiv2server.RobotId) error {
+	id, ok := parseId(robotId)
+	if !ok {
+		return GetProblem(
+			ctx,
+			http.StatusNotFound,
+			fmt.Sprintf("No robot found with Id: %s", robotId))
+	}
+
+	robot, err := s.getRobotStatus(id)
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportRobotV2(robot))
+}
+
+// ListTasks returns a page of the tasks which match the filters
+func (s *robotServiceV2) ListTasks(ctx echo.Context, params robotapiv2server.ListTasksParams) error {
+	filter := taskFilter{
+		batchId:       params.BatchId,
+		createdAfter:  params.CreatedAfter,
+		createdBefore: params.CreatedBefore,
+	}
+
+	if params.Status != nil {
+		for status, statusV2 := range taskStatusesV2 {
+			if statusV2 == *params.Status {
+				status := status
+				filter.status = &status
+			}
+		}
+	}
+
+	if params.RobotId != nil {
+		robotId, ok := parseId(*params.RobotId)
+		if !ok {
+			// No task belongs to a robot which does not exist
+			robotId = -1
+		}
+
+		filter.robotId = &robotId
+	}
+
+	tasks, err := s.listTasks(filter)
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	by := "createdAt"
+	if params.Sort != nil {
+		by = string(*params.Sort)
+	}
+
+	page, next, err := pageTasks(
+		tasks,
+		by,
+		params.Order != nil && *params.Order == "desc",
+		getPageLimit(params.Limit),
+		params.Cursor)
+	if err != nil {
+		return getInvalidCursorProblem(ctx)
+	}
+
+	taskPage := robotapiv2server.TaskPage{
+		Items:      convertToTransportTasksV2(page),
+		TotalCount: len(tasks),
+	}
+
+	if next != "" {
+		taskPage.NextCursor = &next
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		taskPage)
+}
+
+// CreateTask creates a task which moves a robot, when the task has to be accepted by the
+// simulator the task is only created once the simulator has accepted it. The Idempotency-Key
+// header is honoured by the idempotency middleware of the server
+func (s *robotServiceV2) CreateTask(ctx echo.Context, params robotapiv2server.CreateTaskParams) error {
+	var request robotapiv2server.TaskRequest
+
+	if err := ctx.Bind(&request); err != nil {
+		return GetProblem(
+			ctx,
+			http.StatusBadRequest,
+			"Invalid format for CreateTask request")
+	}
+
+	robotId, ok := parseId(request.RobotId)
+	if !ok {
+		return GetProblem(
+			ctx,
+			http.StatusNotFound,
+			fmt.Sprintf("No robot found with Id: %s", request.RobotId))
+	}
+
+	acceptance := ""
+	if params.Acceptance != nil {
+		acceptance = string(*params.Acceptance)
+	}
+
+	syncAcceptance := s.isSyncAcceptance(acceptance)
+
+	task, err := s.createTask(robotId, convertFromTransportMoves(request.Moves), syncAcceptance)
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	code := http.StatusAccepted
+	if syncAcceptance {
+		code = http.StatusCreated
+	}
+
+	return ctx.JSON(
+		code,
+		convertToTransportTaskV2(task))
+}
+
+// GetTask returns a task by its id
+func (s *robotServiceV2) GetTask(ctx echo.Context, taskId robotapiv2server.TaskId) error {
+	id, ok := parseId(taskId)
+	if !ok {
+		return getTaskNotFoundProblem(ctx, taskId)
+	}
+
+	task, err := s.getTask(id)
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportTaskV2(task))
+}
+
+// CancelTask cancels a task by its id and returns it
+func (s *robotServiceV2) CancelTask(ctx echo.Context, taskId robotapiv2server.TaskId) error {
+	id, ok := parseId(taskId)
+	if !ok {
+		return getTaskNotFoundProblem(ctx, taskId)
+	}
+
+	task, err := s.cancelTask(id)
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportTaskV2(task))
+}
+
+// CreateTaskBatch creates a task for each robot of the batch, the batch is either accepted or
+// rejected as a whole. The Idempotency-Key header is honoured by the idempotency middleware of
+// the server
+func (s *robotServiceV2) CreateTaskBatch(
+	ctx echo.Context,
+	params robotapiv2server.CreateTaskBatchParams) error {
+	var batchRequest robotapiv2server.TaskBatchRequest
+
+	if err := ctx.Bind(&batchRequest); err != nil {
+		return GetProblem(
+			ctx,
+			http.StatusBadRequest,
+			"Invalid format for CreateTaskBatch request")
+	}
+
+	requests := make([]taskRequest, 0, len(batchRequest.Tasks))
+	for _, task := range batchRequest.Tasks {
+		robotId, ok := parseId(task.RobotId)
+		if !ok {
+			return GetProblem(
+				ctx,
+				http.StatusNotFound,
+				fmt.Sprintf("No robot found with Id: %s", task.RobotId))
+		}
+
+		requests = append(requests, taskRequest{
+			robotId:       robotId,
+			moveSequences: convertFromTransportMoves(task.Moves),
+		})
+	}
+
+	acceptance := ""
+	if params.Acceptance != nil {
+		acceptance = string(*params.Acceptance)
+	}
+
+	syncAcceptance := s.isSyncAcceptance(acceptance)
+
+	batchId, tasks, err := s.createTaskBatch(requests, syncAcceptance)
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	code := http.StatusAccepted
+	if syncAcceptance {
+		code = http.StatusCreated
+	}
+
+	return ctx.JSON(code, convertToTransportTaskBatchV2(batchId, tasks))
+}
+
+// GetTaskBatch returns the tasks of a batch
+func (s *robotServiceV2) GetTaskBatch(ctx echo.Context, batchId robotapiv2server.BatchId) error {
+	tasks, err := s.getTaskBatch(batchId)
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportTaskBatchV2(batchId, tasks))
+}
+
+// CancelTaskBatch cancels the tasks of a batch which are not finished yet and returns the batch
+func (s *robotServiceV2) CancelTaskBatch(ctx echo.Context, batchId robotapiv2server.BatchId) error {
+	tasks, err := s.cancelTaskBatch(batchId)
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		convertToTransportTaskBatchV2(batchId, tasks))
+}
+
+// GetWarehouse returns the size and the layout of the warehouse
+func (s *robotServiceV2) GetWarehouse(ctx echo.Context) error {
+	warehouse, err := s.getWarehouse()
+	if err != nil {
+		return getServiceProblem(ctx, err)
+	}
+
+	transportWarehouse := robotapiv2server.Warehouse{
+		Name:         warehouse.Name,
+		Width:        warehouse.Width,
+		Height:       warehouse.Height,
+		Obstacles:    make([]robotapiv2server.Position, 0, len(warehouse.Obstacles)),
+		SpecialCells: make([]robotapiv2server.SpecialCell, 0, len(warehouse.SpecialCells)),
+	}
+
+	for _, obstacle := range warehouse.Obstacles {
+		transportWarehouse.Obstacles = append(transportWarehouse.Obstacles, robotapiv2server.Position{
+			X: obstacle.X,
+			Y: obstacle.Y,
+		})
+	}
+
+	for _, specialCell := range warehouse.SpecialCells {
+		transportWarehouse.SpecialCells = append(transportWarehouse.SpecialCells, robotapiv2server.SpecialCell{
+			Position: robotapiv2server.Position{
+				X: specialCell.X,
+				Y: specialCell.Y,
+			},
+			Kind: robotapiv2server.SpecialCellKind(strings.ToLower(string(specialCell.Kind))),
+		})
+	}
+
+	return ctx.JSON(
+		http.StatusOK,
+		transportWarehouse)
+}
+
+// GetHealth returns the state of the connection to the broker
+func (s *robotServiceV2) GetHealth(ctx echo.Context) error {
+	stats := s.healthService.ConnectionStats()
+
+	health := robotapiv2server.Health{
+		Broker: robotapiv2server.BrokerHealth{
+			State:       robotapiv2server.BrokerHealthState(stats.State),
+			Since:       stats.Since,
+			Disconnects: int64(stats.Disconnects),
+			Reconnects:  int64(stats.Reconnects),
+			InMsgs:      int64(stats.InMsgs),
+			OutMsgs:     int64(stats.OutMsgs),
+			InBytes:     int64(stats.InBytes),
+			OutBytes:    int64(stats.OutBytes),
+		},
+		PublishCircuit: robotapiv2server.HealthPublishCircuit(s.circuitBreakerService.State()),
+	}
+
+	if stats.LastError != "" {
+		health.Broker.LastError = &stats.LastError
+	}
+
+	code := http.StatusOK
+	if stats.State != robotbroker.Connected {
+		code = http.StatusServiceUnavailable
+	}
+
+	return ctx.JSON(code, health)
+}
+
+// parseId returns the id of a robot or a task given as a string, ids which can not be parsed
+// belong to nothing
+func parseId(id string) (int64, bool) {
+	parsedId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return parsedId, true
+}
+
+func formatId(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// getServiceProblem responds with the status code of an error returned by robotService
+func getServiceProblem(ctx echo.Context, err error) error {
+	serviceErr := asServiceError(ctx, err)
+
+	return GetProblem(
+		ctx,
+		serviceErr.code,
+		serviceErr.message)
+}
+
+func getTaskNotFoundProblem(ctx echo.Context, taskId string) error {
+	return GetProblem(
+		ctx,
+		http.StatusNotFound,
+		fmt.Sprintf("No task found with Id: %s", taskId))
+}
+
+// getInvalidCursorProblem responds with 400 when the cursor of a listing is not valid
+func getInvalidCursorProblem(ctx echo.Context) error {
+	return GetProblem(
+		ctx,
+		http.StatusBadRequest,
+		"Invalid cursor, it has to be the nextCursor of a listing sorted the same way")
+}
+
+func convertFromTransportMoves(moves []robotapiv2server.Move) []eventpublisher.MoveRobotRequestMoveSequence {
+	moveSequences := make([]eventpublisher.MoveRobotRequestMoveSequence, 0, len(moves))
+	for _, move := range moves {
+		moveSequences = append(moveSequences, eventpublisher.MoveRobotRequestMoveSequence(move))
+	}
+
+	return moveSequences
+}
+
+func convertToTransportRobotV2(robot robotStatus) robotapiv2server.Robot {
+	transportRobot := robotapiv2server.Robot{
+		Id: formatId(robot.robot.Id),
+		Position: robotapiv2server.Position{
+			X: robot.robot.X,
+			Y: robot.robot.Y,
+		},
+		State:           robotapiv2server.RobotState(robot.state()),
+		QueuedTaskCount: robot.activity.queuedTaskCount,
+		HasCrate:        robot.robot.HasCrate,
+		LastSeenAt:      robot.robot.UpdatedAt,
+	}
+
+	if robot.activity.currentTaskId != 0 {
+		currentTaskId := formatId(robot.activity.currentTaskId)
+		transportRobot.CurrentTaskId = &currentTaskId
+	}
+
+	if robot.robot.LastError != "" {
+		transportRobot.LastError = &robot.robot.LastError
+	}
+
+	return transportRobot
+}
+
+func convertToTransportTasksV2(tasks []repository.Task) []robotapiv2server.Task {
+	transportTasks := make([]robotapiv2server.Task, 0, len(tasks))
+	for _, task := range tasks {
+		transportTasks = append(transportTasks, convertToTransportTaskV2(task))
+	}
+
+	return transportTasks
+}
+
+func convertToTransportTaskV2(task repository.Task) robotapiv2server.Task {
+	transportTask := robotapiv2server.Task{
+		Id:         formatId(task.Id),
+		RobotId:    formatId(task.RobotId),
+		Status:     taskStatusesV2[task.Status],
+		Moves:      make([]robotapiv2server.Move, 0, len(task.MoveSequences)),
+		CreatedAt:  task.CreatedAt,
+		StartedAt:  task.StartedAt,
+		FinishedAt: task.FinishedAt,
+		Trajectory: make([]robotapiv2server.Position, 0, len(task.Trajectory)),
+	}
+
+	for _, moveSequence := range task.MoveSequences {
+		transportTask.Moves = append(transportTask.Moves, robotapiv2server.Move(moveSequence))
+	}
+
+	for _, position := range task.Trajectory {
+		transportTask.Trajectory = append(transportTask.Trajectory, robotapiv2server.Position{
+			X: position.X,
+			Y: position.Y,
+		})
+	}
+
+	if task.BatchId != "" {
+		transportTask.BatchId = &task.BatchId
+	}
+
+	if position := task.Position(); position != nil {
+		transportTask.Position = &robotapiv2server.Position{
+			X: position.X,
+			Y: position.Y,
+		}
+	}
+
+	if task.FailureReason != "" {
+		transportTask.Failure = &robotapiv2server.TaskFailure{
+			Reason:   task.FailureReason,
+			Position: transportTask.Position,
+		}
+	}
+
+	return transportTask
+}
+
+func convertToTransportTaskBatchV2(batchId string, tasks []repository.Task) robotapiv2server.TaskBatch {
+	return robotapiv2server.TaskBatch{
+		Id:    batchId,
+		Tasks: convertToTransportTasksV2(tasks),
+	}
+}
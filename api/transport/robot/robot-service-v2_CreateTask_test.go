@@ -0,0 +1,82 @@
+package robot_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/api/processors"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+)
+
+func Test_CreateTask_Should_Create_A_Task_With_A_String_Id(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 0})).Should(Succeed())
+
+	_, sut, mockEventPublisherService := newRobotService(ctrl, g, repositoryService, nil)
+
+	mockEventPublisherService.
+		EXPECT().
+		PublishTaskEvent(gomock.Any()).
+		Return(nil).
+		Times(1)
+
+	var task robotapiv2server.Task
+
+	response := serve(g, http.MethodPost, "/api/v2/tasks", `{"robotId":"0","moves":["E"]}`, func(ctx echo.Context) error {
+		return sut.CreateTask(ctx, robotapiv2server.CreateTaskParams{})
+	}, &task)
+	g.Expect(response.Code).Should(Equal(http.StatusAccepted))
+	g.Expect(task.Id).Should(Equal("1"))
+	g.Expect(task.RobotId).Should(Equal("0"))
+	g.Expect(task.Moves).Should(Equal([]robotapiv2server.Move{robotapiv2server.E}))
+	g.Expect(task.Status).Should(Equal(robotapiv2server.TaskStatusCreated))
+}
+
+func Test_CreateTask_Should_Respond_With_The_Problem_Details_Of_An_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(repositoryService.SaveRobot(repository.Robot{Id: 0})).Should(Succeed())
+
+	_, sut, _ := newRobotService(ctrl, g, repositoryService, &processors.Warehouse{
+		Width:  10,
+		Height: 10,
+	})
+
+	for body, code := range map[string]int{
+		`{"robotId":"404","moves":["E"]}`:      http.StatusNotFound,
+		`{"robotId":"robot","moves":["E"]}`:    http.StatusNotFound,
+		`{"robotId":"0","moves":["W","W"]}`:    http.StatusUnprocessableEntity,
+		`{"robotId":"0","moves":"unexpected"}`: http.StatusBadRequest,
+	} {
+		var problem robotapiv2server.Problem
+
+		response := serve(g, http.MethodPost, "/api/v2/tasks", body, func(ctx echo.Context) error {
+			return sut.CreateTask(ctx, robotapiv2server.CreateTaskParams{})
+		}, &problem)
+		g.Expect(response.Code).Should(Equal(code))
+		g.Expect(response.Header().Get("Content-Type")).Should(Equal(robot.MIMEApplicationProblemJSON))
+		g.Expect(problem.Status).Should(Equal(code))
+		g.Expect(problem.Title).Should(Equal(http.StatusText(code)))
+		g.Expect(problem.Instance).Should(Equal(stringPointer("/api/v2/tasks")))
+	}
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
@@ -0,0 +1,42 @@
+package robot_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+)
+
+func Test_ListTasks_Should_Page_The_Tasks_By_Default(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	for id := int64(1); id <= robot.DefaultPageLimit+1; id++ {
+		g.Expect(repositoryService.SaveTask(repository.Task{
+			Id:     id,
+			Status: repository.TaskStatusCompleted,
+		})).Should(Succeed())
+	}
+
+	_, sut, _ := newRobotService(ctrl, g, repositoryService, nil)
+
+	var taskPage robotapiv2server.TaskPage
+
+	response := serve(g, http.MethodGet, "/api/v2/tasks", "", func(ctx echo.Context) error {
+		return sut.ListTasks(ctx, robotapiv2server.ListTasksParams{})
+	}, &taskPage)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+	g.Expect(taskPage.TotalCount).Should(Equal(robot.DefaultPageLimit + 1))
+	g.Expect(taskPage.Items).Should(HaveLen(robot.DefaultPageLimit))
+	g.Expect(taskPage.NextCursor).ShouldNot(BeNil())
+}
@@ -1,62 +1,134 @@
 package robot
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"sort"
+	"strconv"
 	"sync"
 	"time"
 
 	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
 	"github.com/sepisoad/robot-challange/api/processors"
+	"github.com/sepisoad/robot-challange/shared/services/circuitbreaker"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	"github.com/sepisoad/robot-challange/shared/services/idgenerator"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
-	"golang.org/x/net/websocket"
 )
 
+// DefaultAcceptanceTimeout is how long MoveRobot waits for the simulator to accept a task unless
+// told otherwise
+const DefaultAcceptanceTimeout = 5 * time.Second
+
+// AcceptanceOptions configures whether MoveRobot waits for the simulator to accept the tasks
+type AcceptanceOptions struct {
+	// Sync waits for the simulator to accept every task unless a request asks otherwise
+	Sync bool
+	// Timeout bounds the wait, defaults to DefaultAcceptanceTimeout
+	Timeout time.Duration
+}
+
+const (
+	robotStateIdle   = "idle"
+	robotStateBusy   = "busy"
+	robotStateFailed = "failed"
+)
+
+// robotService holds what both versions of the api do, the handlers of each version only
+// convert their requests and responses
 type robotService struct {
 	logger                           *zap.SugaredLogger
-	robotsStatus                     map[int64]processors.RobotStatus
-	tasksStatus                      map[int64]processors.TaskStatus
+	repositoryService                repository.RepositoryInterface
+	warehouse                        *processors.Warehouse
+	warehouseMutex                   *sync.Mutex
 	eventPublisherService            eventpublisher.EventPublisherInterface
+	taskRequesterService             eventpublisher.TaskRequesterInterface
+	acceptance                       AcceptanceOptions
 	robotStatusMutex                 *sync.Mutex
-	taskStatusMutex                  *sync.Mutex
-	internalRobotStatusChannels      map[int64]chan map[int64]processors.RobotStatus
+	robotLocks                       map[int64]*sync.Mutex
+	robotLocksMutex                  *sync.Mutex
+	internalRobotStatusChannels      map[int64]chan []robotStatus
 	internalRobotStatusChannelsMutex *sync.Mutex
 	idGeneratorService               idgenerator.IdGeneratorInterface
+	healthService                    robotbroker.HealthInterface
+	circuitBreakerService            circuitbreaker.CircuitBreakerInterface
+	queueForDispatch                 bool
 }
 
+// NewRobotService creates the handlers of the two versions of the api, the deprecated v1 and
+// v2, which read and write the same tasks and robots through repositoryService.
+// robotStatusChannel receives every robot which moved. When tasks go through an outbox,
+// taskDispatchedChannel receives the id of every task the broker has acknowledged and
+// queuedTasks lists the tasks still waiting in the outbox, new tasks are queued for dispatch
+// until they are acknowledged. taskDispatchedChannel is nil otherwise. Tasks go through
+// taskRequesterService first when they have to be accepted by the simulator. warehouseChannel
+// receives the layout of the warehouse every time the simulator publishes it. healthService and
+// circuitBreakerService report the health of the connection to the broker
 func NewRobotService(
 	logger *zap.SugaredLogger,
-	robotStatusChannel chan map[int64]processors.RobotStatus,
-	taskStatusChannel chan map[int64]processors.TaskStatus,
+	robotStatusChannel chan repository.Robot,
+	warehouseChannel chan processors.Warehouse,
+	queuedTasks []repository.Task,
+	taskDispatchedChannel chan int64,
+	repositoryService repository.RepositoryInterface,
 	eventPublisherService eventpublisher.EventPublisherInterface,
-	idGeneratorService idgenerator.IdGeneratorInterface) (
+	taskRequesterService eventpublisher.TaskRequesterInterface,
+	idGeneratorService idgenerator.IdGeneratorInterface,
+	healthService robotbroker.HealthInterface,
+	circuitBreakerService circuitbreaker.CircuitBreakerInterface,
+	acceptance AcceptanceOptions) (
 	robotapiserver.ServerInterface,
+	robotapiv2server.ServerInterface,
 	error) {
+	if acceptance.Timeout == 0 {
+		acceptance.Timeout = DefaultAcceptanceTimeout
+	}
+
 	service := &robotService{
 		logger:                           logger,
-		robotsStatus:                     make(map[int64]processors.RobotStatus),
-		tasksStatus:                      make(map[int64]processors.TaskStatus),
+		repositoryService:                repositoryService,
 		eventPublisherService:            eventPublisherService,
+		taskRequesterService:             taskRequesterService,
+		acceptance:                       acceptance,
 		robotStatusMutex:                 &sync.Mutex{},
-		taskStatusMutex:                  &sync.Mutex{},
-		internalRobotStatusChannels:      make(map[int64]chan map[int64]processors.RobotStatus),
+		robotLocks:                       make(map[int64]*sync.Mutex),
+		robotLocksMutex:                  &sync.Mutex{},
+		warehouseMutex:                   &sync.Mutex{},
+		internalRobotStatusChannels:      make(map[int64]chan []robotStatus),
 		internalRobotStatusChannelsMutex: &sync.Mutex{},
 		idGeneratorService:               idGeneratorService,
+		healthService:                    healthService,
+		circuitBreakerService:            circuitBreakerService,
+		queueForDispatch:                 taskDispatchedChannel != nil,
 	}
 
-	go func(s *robotService, robotStatusChannel chan map[int64]processors.RobotStatus) {
-		for robotStatus := range robotStatusChannel {
+	for _, task := range queuedTasks {
+		task.Status = repository.TaskStatusQueuedForDispatch
+		if _, err := repositoryService.MergeTask(task); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	go func(s *robotService, robotStatusChannel chan repository.Robot) {
+		for range robotStatusChannel {
 			s.robotStatusMutex.Lock()
-			service.robotsStatus = robotStatus
+			robots, err := s.listRobotStatuses()
+			if err != nil {
+				s.logger.Errorf("Failed to list the robots. Error: %v", err)
+				s.robotStatusMutex.Unlock()
+
+				continue
+			}
 
 			s.internalRobotStatusChannelsMutex.Lock()
 			for _, internalRobotStatusChannel := range s.internalRobotStatusChannels {
-				internalRobotStatusChannel <- s.robotsStatus
+				internalRobotStatusChannel <- robots
 			}
 			s.internalRobotStatusChannelsMutex.Unlock()
 
@@ -64,295 +136,600 @@ func NewRobotService(
 		}
 	}(service, robotStatusChannel)
 
-	go func(s *robotService, taskStatusChannel chan map[int64]processors.TaskStatus) {
-		for taskStatus := range taskStatusChannel {
-			s.taskStatusMutex.Lock()
+	go func(s *robotService, warehouseChannel chan processors.Warehouse) {
+		for warehouse := range warehouseChannel {
+			warehouse := warehouse
 
-			for taskId, status := range taskStatus {
-				if service.tasksStatus[taskId] != processors.TaskStatusCancelled {
-					service.tasksStatus[taskId] = status
+			s.warehouseMutex.Lock()
+			s.warehouse = &warehouse
+			s.warehouseMutex.Unlock()
+		}
+	}(service, warehouseChannel)
+
+	// A task is stored before it is published, so it is known once the outbox dispatches it
+	if taskDispatchedChannel != nil {
+		go func(s *robotService, taskDispatchedChannel chan int64) {
+			for taskId := range taskDispatchedChannel {
+				if _, err := s.repositoryService.MergeTask(repository.Task{
+					Id:     taskId,
+					Status: repository.TaskStatusCreated,
+				}); err != nil {
+					s.logger.Errorf("Failed to store task %d as dispatched. Error: %v", taskId, err)
 				}
 			}
+		}(service, taskDispatchedChannel)
+	}
 
-			s.taskStatusMutex.Unlock()
-		}
-	}(service, taskStatusChannel)
-
-	return service, nil
+	return &robotServiceV1{robotService: service}, &robotServiceV2{robotService: service}, nil
 }
 
-// Dashboard returns the web ui
-func (s *robotService) Dashboard(ctx echo.Context) error {
-	return ctx.File("index.html")
+// robotStatus is the last known state of a robot with what its tasks tell about it
+type robotStatus struct {
+	robot    repository.Robot
+	activity robotActivity
 }
 
-// RobotsWebsocket is used by clients to read robots status via websocket
-func (s *robotService) RobotsWebsocket(ctx echo.Context) error {
-	websocket.Handler(func(ws *websocket.Conn) {
-		defer ws.Close()
+// state is failed while the last move of the robot failed, busy while one of its tasks is not
+// finished and idle otherwise
+func (r robotStatus) state() string {
+	switch {
+	case r.robot.Failed:
+		return robotStateFailed
+	case r.activity.isBusy():
+		return robotStateBusy
+	default:
+		return robotStateIdle
+	}
+}
 
-		internalRobotStatusChannel := make(chan map[int64]processors.RobotStatus)
-		id := s.idGeneratorService.Generate()
+// robotActivity is what the tasks of a robot which are not finished yet tell about it
+type robotActivity struct {
+	// currentTaskId is the task the robot started, it is 0 when there is none
+	currentTaskId   int64
+	queuedTaskCount int
+}
 
-		s.internalRobotStatusChannelsMutex.Lock()
-		s.internalRobotStatusChannels[id] = internalRobotStatusChannel
-		s.internalRobotStatusChannelsMutex.Unlock()
+func (a robotActivity) isBusy() bool {
+	return a.currentTaskId != 0 || a.queuedTaskCount > 0
+}
 
-		defer func() {
-			s.internalRobotStatusChannelsMutex.Lock()
-			delete(s.internalRobotStatusChannels, id)
-			close(internalRobotStatusChannel)
-			s.internalRobotStatusChannelsMutex.Unlock()
-		}()
+// getRobotActivities returns the activity of the robots which have a task which is not
+// finished yet
+func getRobotActivities(tasks []repository.Task) map[int64]robotActivity {
+	activities := make(map[int64]robotActivity)
+	for _, task := range tasks {
+		if task.Status.IsFinished() {
+			continue
+		}
 
-		robots := s.getRobotStatuses()
-		buf, err := json.Marshal(robots)
-		if err != nil {
-			s.logger.Error(err)
+		activity := activities[task.RobotId]
+		activity.queuedTaskCount++
 
-			return
+		// A robot makes the moves of its tasks one after the other
+		if task.Status == repository.TaskStatusInProgress &&
+			(activity.currentTaskId == 0 || task.Id < activity.currentTaskId) {
+			activity.currentTaskId = task.Id
 		}
 
-		if err = websocket.Message.Send(ws, string(buf)); err != nil {
-			s.logger.Error(err)
+		activities[task.RobotId] = activity
+	}
 
-			return
+	for robotId, activity := range activities {
+		if activity.currentTaskId != 0 {
+			activity.queuedTaskCount--
+			activities[robotId] = activity
 		}
+	}
 
-		for robotStatus := range internalRobotStatusChannel {
-			robots := convertToTransportRobots(robotStatus)
-
-			buf, err := json.Marshal(robots)
-			if err != nil {
-				s.logger.Error(err)
+	return activities
+}
 
-				break
-			}
+// listRobotStatuses returns every robot ordered by id
+func (s *robotService) listRobotStatuses() ([]robotStatus, error) {
+	robots, err := s.repositoryService.ListRobots()
+	if err != nil {
+		return nil, newRepositoryError(err)
+	}
 
-			if err = websocket.Message.Send(ws, string(buf)); err != nil {
-				s.logger.Error(err)
+	tasks, err := s.repositoryService.ListTasks()
+	if err != nil {
+		return nil, newRepositoryError(err)
+	}
 
-				break
-			}
+	activities := getRobotActivities(tasks)
 
-		}
-	}).ServeHTTP(ctx.Response(), ctx.Request())
-	return nil
-}
+	robotStatuses := make([]robotStatus, 0, len(robots))
+	for _, robot := range robots {
+		robotStatuses = append(robotStatuses, robotStatus{
+			robot:    robot,
+			activity: activities[robot.Id],
+		})
+	}
 
-// TasksWebsocket is used by clients to read tasks status via websocket
-func (s *robotService) TasksWebsocket(ctx echo.Context) error {
-	websocket.Handler(func(ws *websocket.Conn) {
-		defer ws.Close()
-		for {
-			tasksStatus := s.getAllTasks(ctx)
-			buf, err := json.Marshal(tasksStatus)
-			if err != nil {
-				s.logger.Error(err)
-				break
-			}
-			if err = websocket.Message.Send(ws, string(buf)); err != nil {
-				s.logger.Error(err)
-				break
-			}
+	sort.SliceStable(robotStatuses, func(i, j int) bool {
+		return robotStatuses[i].robot.Id < robotStatuses[j].robot.Id
+	})
 
-			time.Sleep(time.Second * 1)
-		}
-	}).ServeHTTP(ctx.Response(), ctx.Request())
-	return nil
+	return robotStatuses, nil
 }
 
-func (s *robotService) GetAllRobots(ctx echo.Context) error {
-	robots := s.getRobotStatuses()
+func (s *robotService) getRobotStatus(robotId int64) (robotStatus, error) {
+	robot, err := s.getRobot(robotId)
+	if err != nil {
+		return robotStatus{}, err
+	}
+
+	tasks, err := s.repositoryService.ListTasks()
+	if err != nil {
+		return robotStatus{}, newRepositoryError(err)
+	}
 
-	return ctx.JSON(
-		http.StatusOK,
-		robots)
+	return robotStatus{
+		robot:    robot,
+		activity: getRobotActivities(tasks)[robot.Id],
+	}, nil
 }
 
-// GetRobot returns a robot by its id
-func (s *robotService) GetRobot(ctx echo.Context, robotId robotapiserver.RobotId) error {
-	s.robotStatusMutex.Lock()
-	defer s.robotStatusMutex.Unlock()
+func (s *robotService) getRobot(robotId int64) (repository.Robot, error) {
+	robot, found, err := s.repositoryService.GetRobot(robotId)
+	if err != nil {
+		return repository.Robot{}, newRepositoryError(err)
+	}
 
-	robot, found := s.robotsStatus[int64(robotId)]
 	if !found {
-		return getError(
-			ctx,
+		return repository.Robot{}, newServiceError(
 			http.StatusNotFound,
 			fmt.Sprintf("No robot found with Id: %d", robotId))
 	}
 
-	return ctx.JSON(
-		http.StatusOK,
-		robot)
+	return robot, nil
 }
 
-// MoveRobot move a robot on grid
-func (s *robotService) MoveRobot(ctx echo.Context, robotId robotapiserver.RobotId) error {
-	var moveRequest robotapiserver.MoveRobotRequest
-
-	err := ctx.Bind(&moveRequest)
-	if err != nil {
-		return getError(
-			ctx,
-			http.StatusBadRequest,
-			"Invalid format for MoveRobot request")
+// isSyncAcceptance tells whether a task waits for the simulator to accept it, acceptance is
+// what the request asked for, empty when it did not ask
+func (s *robotService) isSyncAcceptance(acceptance string) bool {
+	if acceptance == "" {
+		return s.acceptance.Sync
 	}
 
-	s.robotStatusMutex.Lock()
-	defer s.robotStatusMutex.Unlock()
+	return acceptance == string(robotapiserver.Sync)
+}
 
-	_, found := s.robotsStatus[int64(robotId)]
-	if !found {
-		return getError(
-			ctx,
-			http.StatusNotFound,
-			fmt.Sprintf("No robot found with Id: %d", robotId))
+// createTask creates a task which moves a robot, when the task has to be accepted by the
+// simulator the task is only created once the simulator has accepted it
+func (s *robotService) createTask(
+	robotId int64,
+	moveSequences []eventpublisher.MoveRobotRequestMoveSequence,
+	syncAcceptance bool) (repository.Task, error) {
+	robot, err := s.getRobot(robotId)
+	if err != nil {
+		return repository.Task{}, err
 	}
 
-	moveSequeneces := make([]eventpublisher.MoveRobotRequestMoveSequence, 0)
+	// The lock of the robot is held from the check of the moves until the task is published, also
+	// while the simulator is asked to accept it, so the moves are checked against every task
+	// queued before. The tasks of the other robots are created meanwhile
+	defer s.lockRobots(robotId)()
 
-	for _, moveSequence := range moveRequest.MoveSequences {
-		moveSequeneces = append(
-			moveSequeneces,
-			eventpublisher.MoveRobotRequestMoveSequence(moveSequence))
+	message, err := s.checkMoveSequences(robot, moveSequences)
+	if err != nil {
+		return repository.Task{}, err
 	}
 
-	s.taskStatusMutex.Lock()
-	defer s.taskStatusMutex.Unlock()
+	if message != "" {
+		return repository.Task{}, newServiceError(
+			http.StatusUnprocessableEntity,
+			fmt.Sprintf("Robot %d can not move: %s", robotId, message))
+	}
 
-	taskId := len(s.tasksStatus) + 1
-	s.tasksStatus[int64(taskId)] = processors.TaskStatusCreated
+	// Snowflake ids do not collide across restarts nor across the replicas of the api
+	taskId := s.idGeneratorService.Generate()
 
-	if err = s.eventPublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+	event := eventpublisher.TaskEvent{
 		EventType: eventpublisher.TaskCreated,
 		Id:        taskId,
 		Data: eventpublisher.TaskData{
-			RobotId:        int64(robotId),
-			MoveSequeneces: moveSequeneces,
+			RobotId:        robotId,
+			MoveSequeneces: moveSequences,
 		},
+	}
+
+	if syncAcceptance {
+		acceptance, err := s.taskRequesterService.RequestTask(event, s.acceptance.Timeout)
+		if errors.Is(err, robotbroker.ErrNoResponders) || errors.Is(err, robotbroker.ErrRequestTimeout) {
+			return repository.Task{}, newServiceError(
+				http.StatusGatewayTimeout,
+				fmt.Sprintf("The simulator did not accept task %d in time: %v", taskId, err))
+		}
+
+		if err != nil {
+			return repository.Task{}, newPublishError(err)
+		}
+
+		if !acceptance.Accepted {
+			return repository.Task{}, newServiceError(
+				http.StatusUnprocessableEntity,
+				fmt.Sprintf("The simulator rejected task %d: %s", taskId, acceptance.Reason))
+		}
+
+		// The simulator enqueued it already, it does not execute it again when it is created
+		event.Data.Accepted = true
+	}
+
+	task := repository.Task{
+		Id:            taskId,
+		RobotId:       robotId,
+		MoveSequences: moveSequences,
+		Status:        repository.TaskStatusCreated,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	if s.queueForDispatch {
+		task.Status = repository.TaskStatusQueuedForDispatch
+	}
+
+	if err = s.repositoryService.SaveTask(task); err != nil {
+		if syncAcceptance {
+			s.withdrawTask(taskId)
+		}
+
+		return repository.Task{}, newRepositoryError(err)
+	}
+
+	if err = s.eventPublisherService.PublishTaskEvent(event); err != nil {
+		if err := s.repositoryService.DeleteTask(task.Id); err != nil {
+			s.logger.Errorf("Failed to forget task %d. Error: %v", task.Id, err)
+		}
+
+		if syncAcceptance {
+			s.withdrawTask(taskId)
+		}
+
+		return repository.Task{}, newPublishError(err)
+	}
+
+	return task, nil
+}
+
+// withdrawTask cancels a task the simulator accepted, and so enqueued, but which could not be
+// created
+func (s *robotService) withdrawTask(taskId int64) {
+	if err := s.eventPublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCancelled,
+		Id:        taskId,
 	}); err != nil {
-		return getError(
-			ctx,
-			http.StatusInternalServerError,
-			err.Error())
-	}
-
-	return ctx.JSON(
-		http.StatusAccepted,
-		robotapiserver.MoveRobotResponse{
-			Task: robotapiserver.Task{
-				Id: taskId,
-			},
-		},
-	)
+		s.logger.Errorf("Failed to withdraw accepted task %d. Error: %v", taskId, err)
+	}
 }
 
-// MoveRobot returns all tasks with their state
-func (s *robotService) GetAllTasks(ctx echo.Context) error {
-	tasksStatus := s.getAllTasks(ctx)
-	return ctx.JSON(
-		http.StatusOK,
-		tasksStatus)
+// taskFilter selects tasks, the fields which are nil select every task
+type taskFilter struct {
+	status        *repository.TaskStatus
+	robotId       *int64
+	batchId       *string
+	createdAfter  *time.Time
+	createdBefore *time.Time
 }
 
-// MoveRobot returns an specific task by its id
-func (s *robotService) GetTask(ctx echo.Context, taskId robotapiserver.TaskId) error {
-	s.taskStatusMutex.Lock()
-	defer s.taskStatusMutex.Unlock()
+func (f taskFilter) matches(task repository.Task) bool {
+	switch {
+	case f.status != nil && task.Status != *f.status,
+		f.robotId != nil && task.RobotId != *f.robotId,
+		f.batchId != nil && task.BatchId != *f.batchId,
+		f.createdAfter != nil && task.CreatedAt.Before(*f.createdAfter),
+		f.createdBefore != nil && !task.CreatedAt.Before(*f.createdBefore):
+		return false
+	}
 
-	status, found := s.tasksStatus[int64(taskId)]
-	if !found {
-		return getError(
-			ctx,
-			http.StatusNotFound,
-			fmt.Sprintf("No task found with Id: %d", taskId))
+	return true
+}
+
+// listTasks returns the tasks which match the filter ordered by id
+func (s *robotService) listTasks(filter taskFilter) ([]repository.Task, error) {
+	allTasks, err := s.repositoryService.ListTasks()
+	if err != nil {
+		return nil, newRepositoryError(err)
 	}
 
-	return ctx.JSON(
-		http.StatusOK,
-		robotapiserver.Task{
-			Id:     taskId,
-			Status: robotapiserver.TaskStatus(status),
-		})
+	tasks := make([]repository.Task, 0)
+	for _, task := range allTasks {
+		if filter.matches(task) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Id < tasks[j].Id
+	})
+
+	return tasks, nil
 }
 
-// MoveRobot cancels a task by its id
-func (s *robotService) CancelTask(ctx echo.Context, taskId robotapiserver.TaskId) error {
-	s.taskStatusMutex.Lock()
-	defer s.taskStatusMutex.Unlock()
+func (s *robotService) getTask(taskId int64) (repository.Task, error) {
+	task, found, err := s.repositoryService.GetTask(taskId)
+	if err != nil {
+		return repository.Task{}, newRepositoryError(err)
+	}
 
-	_, found := s.tasksStatus[int64(taskId)]
 	if !found {
-		return getError(
-			ctx,
+		return repository.Task{}, newServiceError(
 			http.StatusNotFound,
 			fmt.Sprintf("No task found with Id: %d", taskId))
 	}
 
+	return task, nil
+}
+
+// cancelTask cancels a task and returns it
+func (s *robotService) cancelTask(taskId int64) (repository.Task, error) {
+	task, err := s.getTask(taskId)
+	if err != nil {
+		return repository.Task{}, err
+	}
+
+	// The task is not cancelled while it is being created, see createTask
+	defer s.lockRobots(task.RobotId)()
+
 	if err := s.eventPublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
 		EventType: eventpublisher.TaskCancelled,
 		Id:        taskId,
 	}); err != nil {
-		return getError(
-			ctx,
-			http.StatusInternalServerError,
-			err.Error())
+		return repository.Task{}, newPublishError(err)
+	}
+
+	task, err = s.markTaskCancelled(taskId)
+	if err != nil {
+		return repository.Task{}, newRepositoryError(err)
 	}
 
-	return ctx.NoContent(http.StatusNoContent)
+	return task, nil
 }
 
-func getError(ctx echo.Context, code int, message string) error {
-	return ctx.JSON(
-		code,
-		robotapiserver.Error{
-			Code:    code,
-			Message: message,
-		},
-	)
+// lockRobots locks the creation and the cancellation of the tasks of robots and returns what
+// unlocks them. The robots are locked in the order of their ids so the requests sharing some
+// robots never wait for each other
+func (s *robotService) lockRobots(robotIds ...int64) func() {
+	sortedRobotIds := make([]int64, 0, len(robotIds))
+	seen := make(map[int64]bool, len(robotIds))
+
+	for _, robotId := range robotIds {
+		if !seen[robotId] {
+			seen[robotId] = true
+			sortedRobotIds = append(sortedRobotIds, robotId)
+		}
+	}
+
+	sort.Slice(sortedRobotIds, func(i, j int) bool {
+		return sortedRobotIds[i] < sortedRobotIds[j]
+	})
+
+	locks := make([]*sync.Mutex, 0, len(sortedRobotIds))
+
+	s.robotLocksMutex.Lock()
+	for _, robotId := range sortedRobotIds {
+		lock, found := s.robotLocks[robotId]
+		if !found {
+			lock = &sync.Mutex{}
+			s.robotLocks[robotId] = lock
+		}
+
+		locks = append(locks, lock)
+	}
+	s.robotLocksMutex.Unlock()
+
+	for _, lock := range locks {
+		lock.Lock()
+	}
+
+	return func() {
+		for idx := len(locks) - 1; idx >= 0; idx-- {
+			locks[idx].Unlock()
+		}
+	}
+}
+
+// markTaskCancelled stores that a task was cancelled, a finished task stays as it is and the
+// robot still reports where a cancelled one stopped
+func (s *robotService) markTaskCancelled(taskId int64) (repository.Task, error) {
+	return s.repositoryService.MergeTask(repository.Task{
+		Id:     taskId,
+		Status: repository.TaskStatusCancelled,
+	})
 }
 
-func (s *robotService) getRobotStatuses() []robotapiserver.Robot {
-	s.robotStatusMutex.Lock()
-	defer s.robotStatusMutex.Unlock()
+// getWarehouse returns the layout of the warehouse the simulator published last
+func (s *robotService) getWarehouse() (processors.Warehouse, error) {
+	s.warehouseMutex.Lock()
+	defer s.warehouseMutex.Unlock()
 
-	return convertToTransportRobots(s.robotsStatus)
+	if s.warehouse == nil {
+		return processors.Warehouse{}, newServiceError(
+			http.StatusNotFound,
+			"The simulator has not published its warehouse yet")
+	}
+
+	return *s.warehouse, nil
 }
 
-func convertToTransportRobots(robotStatus map[int64]processors.RobotStatus) []robotapiserver.Robot {
-	robots := make([]robotapiserver.Robot, 0)
-	for id, status := range robotStatus {
-		robots = append(robots, robotapiserver.Robot{
-			Id:        int(id),
-			XPosition: status.X,
-			YPosition: status.Y,
-		})
+// checkMoveSequences follows the moves from where the robot stands once its unfinished tasks
+// are done and tells why they take it off the board or into an obstacle, moves are not checked
+// until the warehouse is known. It must be called with the lock of the robot held
+func (s *robotService) checkMoveSequences(
+	robot repository.Robot,
+	moveSequences []eventpublisher.MoveRobotRequestMoveSequence) (string, error) {
+	s.warehouseMutex.Lock()
+	warehouse := s.warehouse
+	s.warehouseMutex.Unlock()
+
+	if warehouse == nil {
+		return "", nil
 	}
 
-	sort.SliceStable(robots, func(i, j int) bool {
-		return robots[i].Id < robots[j].Id
-	})
+	position, err := s.projectPosition(robot, *warehouse)
+	if err != nil {
+		return "", err
+	}
+
+	obstacles := getObstacles(*warehouse)
+
+	for idx, moveSequence := range moveSequences {
+		position = move(position, moveSequence)
 
-	return robots
+		if !isOnBoard(position, *warehouse) {
+			return fmt.Sprintf(
+				"move %d takes it to %d,%d which is off the %dx%d board",
+				idx+1,
+				position.X,
+				position.Y,
+				warehouse.Width,
+				warehouse.Height), nil
+		}
+
+		if obstacles[position] {
+			return fmt.Sprintf(
+				"move %d takes it to %d,%d which holds an obstacle",
+				idx+1,
+				position.X,
+				position.Y), nil
+		}
+	}
+
+	return "", nil
 }
 
-func (s *robotService) getAllTasks(ctx echo.Context) []robotapiserver.Task {
-	s.taskStatusMutex.Lock()
-	defer s.taskStatusMutex.Unlock()
+// projectPosition returns where the robot stands once it is done with its unfinished tasks. The
+// simulator refuses the moves which take a robot off the board or into an obstacle, they leave
+// the robot where it stands. The moves of the task in progress are followed from where the
+// previous task left the robot, they are skipped when that is not known
+func (s *robotService) projectPosition(
+	robot repository.Robot,
+	warehouse processors.Warehouse) (repository.Position, error) {
+	tasks, err := s.listTasks(taskFilter{robotId: &robot.Id})
+	if err != nil {
+		return repository.Position{}, err
+	}
+
+	position := repository.Position{X: robot.X, Y: robot.Y}
+	obstacles := getObstacles(warehouse)
 
-	tasksStatus := make([]robotapiserver.Task, 0)
-	for id, status := range s.tasksStatus {
-		tasksStatus = append(tasksStatus, robotapiserver.Task{
-			Id:     int(id),
-			Status: robotapiserver.TaskStatus(status),
-		})
+	// The robot executes its tasks in the order they were created, which is the order of their ids
+	var finishedPosition *repository.Position
+
+	for _, task := range tasks {
+		if task.Status.IsFinished() {
+			if taskPosition := task.Position(); taskPosition != nil {
+				finishedPosition = taskPosition
+			}
+
+			continue
+		}
+
+		if task.Status == repository.TaskStatusInProgress {
+			if finishedPosition == nil {
+				continue
+			}
+
+			position = *finishedPosition
+		}
+
+		for _, moveSequence := range task.MoveSequences {
+			if next := move(position, moveSequence); isOnBoard(next, warehouse) && !obstacles[next] {
+				position = next
+			}
+		}
 	}
 
-	sort.SliceStable(tasksStatus, func(i, j int) bool {
-		return tasksStatus[i].Id < tasksStatus[j].Id
-	})
+	return position, nil
+}
+
+// move returns the cell a move takes a robot standing at position to
+func move(position repository.Position, moveSequence eventpublisher.MoveRobotRequestMoveSequence) repository.Position {
+	switch moveSequence {
+	case eventpublisher.NORTH:
+		position.Y++
+	case eventpublisher.SOUTH:
+		position.Y--
+	case eventpublisher.EAST:
+		position.X++
+	case eventpublisher.WEST:
+		position.X--
+	}
+
+	return position
+}
+
+func isOnBoard(position repository.Position, warehouse processors.Warehouse) bool {
+	return position.X >= 0 && position.X < warehouse.Width && position.Y >= 0 && position.Y < warehouse.Height
+}
+
+func getObstacles(warehouse processors.Warehouse) map[repository.Position]bool {
+	obstacles := make(map[repository.Position]bool, len(warehouse.Obstacles))
+	for _, obstacle := range warehouse.Obstacles {
+		obstacles[repository.Position{X: obstacle.X, Y: obstacle.Y}] = true
+	}
+
+	return obstacles
+}
+
+// serviceError is an error the handlers of both versions answer with its status code
+type serviceError struct {
+	code    int
+	message string
+	// retryAfter is how long to wait before retrying a request answered with 503
+	retryAfter time.Duration
+}
+
+func (e *serviceError) Error() string {
+	return e.message
+}
+
+func newServiceError(code int, message string) error {
+	return &serviceError{
+		code:    code,
+		message: message,
+	}
+}
+
+// newRepositoryError is answered with 500 when the repository failed
+func newRepositoryError(err error) error {
+	return newServiceError(
+		http.StatusInternalServerError,
+		fmt.Sprintf("Failed to access the repository: %v", err))
+}
+
+// newPublishError is answered with 503 and a Retry-After header when the circuit breaker failed
+// the call fast, and with 500 otherwise
+func newPublishError(err error) error {
+	var openErr *circuitbreaker.OpenError
+	if errors.As(err, &openErr) {
+		return &serviceError{
+			code:       http.StatusServiceUnavailable,
+			message:    err.Error(),
+			retryAfter: openErr.RetryAfter,
+		}
+	}
+
+	return newServiceError(
+		http.StatusInternalServerError,
+		err.Error())
+}
+
+// asServiceError returns the serviceError err wraps, an unexpected error is answered with 500.
+// The Retry-After header is set on 503
+func asServiceError(ctx echo.Context, err error) *serviceError {
+	var serviceErr *serviceError
+	if !errors.As(err, &serviceErr) {
+		serviceErr = &serviceError{
+			code:    http.StatusInternalServerError,
+			message: err.Error(),
+		}
+	}
+
+	if serviceErr.code == http.StatusServiceUnavailable {
+		ctx.Response().Header().Set(
+			echo.HeaderRetryAfter,
+			strconv.Itoa(int(math.Ceil(serviceErr.retryAfter.Seconds()))))
+	}
 
-	return tasksStatus
+	return serviceErr
 }
@@ -0,0 +1,141 @@
+package robot_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/labstack/echo/v4"
+	. "github.com/onsi/gomega"
+	robotapiserver "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapi-server"
+	robotapiv2server "github.com/sepisoad/robot-challange/api-definitions/openapi/robotapiv2-server"
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/api/processors"
+	"github.com/sepisoad/robot-challange/api/transport/robot"
+	. "github.com/sepisoad/robot-challange/shared/services/circuitbreaker/mock"
+	. "github.com/sepisoad/robot-challange/shared/services/eventpublisher/mock"
+	. "github.com/sepisoad/robot-challange/shared/services/idgenerator/mock"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_NewRobotService_Should_Queue_The_Tasks_Left_In_The_Outbox(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	// The repository is empty when it is kept in memory, the tasks come from the outbox
+	repositoryService, err := repository.NewMemoryRepositoryService(repository.Options{})
+	g.Expect(err).Should(BeNil())
+
+	_, sut, err := robot.NewRobotService(
+		sugarLogger,
+		make(chan repository.Robot),
+		make(chan processors.Warehouse),
+		[]repository.Task{{Id: 1, Status: repository.TaskStatusCreated}},
+		make(chan int64),
+		repositoryService,
+		NewMockEventPublisherInterface(ctrl),
+		NewMockTaskRequesterInterface(ctrl),
+		NewMockIdGeneratorInterface(ctrl),
+		NewMockHealthInterface(ctrl),
+		NewMockCircuitBreakerInterface(ctrl),
+		robot.AcceptanceOptions{})
+	g.Expect(err).Should(BeNil())
+
+	var task robotapiv2server.Task
+
+	response := serve(g, http.MethodGet, "/api/v2/tasks/1", "", func(ctx echo.Context) error {
+		return sut.GetTask(ctx, "1")
+	}, &task)
+	g.Expect(response.Code).Should(Equal(http.StatusOK))
+	g.Expect(task.Status).Should(Equal(robotapiv2server.TaskStatusQueuedForDispatch))
+}
+
+// newRobotService creates the handlers of both versions of the api on top of repositoryService,
+// tasks are published straight away and are not accepted by the simulator first. The layout of
+// the warehouse is known once it returns when warehouse is not nil
+func newRobotService(
+	ctrl *gomock.Controller,
+	g *WithT,
+	repositoryService repository.RepositoryInterface,
+	warehouse *processors.Warehouse) (
+	robotapiserver.ServerInterface,
+	robotapiv2server.ServerInterface,
+	*MockEventPublisherInterface) {
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	mockEventPublisherService := NewMockEventPublisherInterface(ctrl)
+	mockIdGeneratorService := NewMockIdGeneratorInterface(ctrl)
+
+	var id int64
+
+	mockIdGeneratorService.
+		EXPECT().
+		Generate().
+		DoAndReturn(func() int64 {
+			return atomic.AddInt64(&id, 1)
+		}).
+		AnyTimes()
+
+	warehouseChannel := make(chan processors.Warehouse, 1)
+
+	sutV1, sutV2, err := robot.NewRobotService(
+		sugarLogger,
+		make(chan repository.Robot),
+		warehouseChannel,
+		nil,
+		nil,
+		repositoryService,
+		mockEventPublisherService,
+		NewMockTaskRequesterInterface(ctrl),
+		mockIdGeneratorService,
+		NewMockHealthInterface(ctrl),
+		NewMockCircuitBreakerInterface(ctrl),
+		robot.AcceptanceOptions{})
+	g.Expect(err).Should(BeNil())
+
+	if warehouse != nil {
+		warehouseChannel <- *warehouse
+
+		g.Eventually(func() int {
+			return serve(g, http.MethodGet, "/api/warehouse", "", sutV1.GetWarehouse, nil).Code
+		}).Should(Equal(http.StatusOK))
+	}
+
+	return sutV1, sutV2, mockEventPublisherService
+}
+
+// serve sends a request with a JSON body to handler and decodes the JSON body of the response
+// into result unless it is nil
+func serve(
+	g *WithT,
+	method string,
+	target string,
+	body string,
+	handler echo.HandlerFunc,
+	result interface{}) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	response := httptest.NewRecorder()
+
+	g.Expect(handler(echo.New().NewContext(request, response))).Should(Succeed())
+
+	if result != nil {
+		g.Expect(json.Unmarshal(response.Body.Bytes(), result)).Should(Succeed())
+	}
+
+	return response
+}
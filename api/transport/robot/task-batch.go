@@ -0,0 +1,245 @@
+package robot
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sepisoad/robot-challange/api/internals/services/repository"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+// taskRequest is the moves one of the robots of a batch is asked to make
+type taskRequest struct {
+	robotId       int64
+	moveSequences []eventpublisher.MoveRobotRequestMoveSequence
+}
+
+// createTaskBatch creates a task for each robot of the batch, the moves of every robot are
+// checked before any task is created so the batch is either accepted or rejected as a whole.
+// The tasks are published in a single event the simulator accepts as a whole as well, their
+// robots start them together. It returns the id of the batch with its tasks
+func (s *robotService) createTaskBatch(
+	requests []taskRequest,
+	syncAcceptance bool) (string, []repository.Task, error) {
+	batchIdValue := s.idGeneratorService.Generate()
+	batchId := strconv.FormatInt(batchIdValue, 10)
+	tasks := make([]repository.Task, 0, len(requests))
+	robotIds := make(map[int64]bool)
+
+	// The moves are checked under the locks of the robots the tasks are stored with, see
+	// createTask
+	batchRobotIds := make([]int64, 0, len(requests))
+	for _, request := range requests {
+		batchRobotIds = append(batchRobotIds, request.robotId)
+	}
+
+	defer s.lockRobots(batchRobotIds...)()
+
+	for _, request := range requests {
+		if robotIds[request.robotId] {
+			return "", nil, newServiceError(
+				http.StatusUnprocessableEntity,
+				fmt.Sprintf("Robot %d is given more than one move sequence", request.robotId))
+		}
+
+		robotIds[request.robotId] = true
+
+		robot, err := s.getRobot(request.robotId)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// The moves of a robot are checked from where its queued tasks take it
+		message, err := s.checkMoveSequences(robot, request.moveSequences)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if message != "" {
+			return "", nil, newServiceError(
+				http.StatusUnprocessableEntity,
+				fmt.Sprintf("Robot %d can not move: %s", request.robotId, message))
+		}
+
+		tasks = append(tasks, repository.Task{
+			Id:            s.idGeneratorService.Generate(),
+			RobotId:       robot.Id,
+			BatchId:       batchId,
+			MoveSequences: request.moveSequences,
+			Status:        repository.TaskStatusCreated,
+		})
+	}
+
+	event := eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskBatchCreated,
+		Id:        batchIdValue,
+		Data: eventpublisher.TaskData{
+			BatchId: batchId,
+			Tasks:   make([]eventpublisher.BatchTask, 0, len(tasks)),
+		},
+	}
+
+	for _, task := range tasks {
+		event.Data.Tasks = append(event.Data.Tasks, eventpublisher.BatchTask{
+			Id:             task.Id,
+			RobotId:        task.RobotId,
+			MoveSequeneces: task.MoveSequences,
+		})
+	}
+
+	if syncAcceptance {
+		acceptance, err := s.taskRequesterService.RequestTask(event, s.acceptance.Timeout)
+		if errors.Is(err, robotbroker.ErrNoResponders) || errors.Is(err, robotbroker.ErrRequestTimeout) {
+			return "", nil, newServiceError(
+				http.StatusGatewayTimeout,
+				fmt.Sprintf("The simulator did not accept batch %s in time: %v", batchId, err))
+		}
+
+		if err != nil {
+			return "", nil, newPublishError(err)
+		}
+
+		if !acceptance.Accepted {
+			return "", nil, newServiceError(
+				http.StatusUnprocessableEntity,
+				fmt.Sprintf("The simulator rejected batch %s: %s", batchId, acceptance.Reason))
+		}
+
+		// The simulator enqueued it already, it does not execute it again when it is created
+		event.Data.Accepted = true
+	}
+
+	createdAt := time.Now().UTC()
+
+	for idx := range tasks {
+		tasks[idx].CreatedAt = createdAt
+
+		if s.queueForDispatch {
+			tasks[idx].Status = repository.TaskStatusQueuedForDispatch
+		}
+
+		if err := s.repositoryService.SaveTask(tasks[idx]); err != nil {
+			s.forgetTasks(tasks[:idx])
+
+			if syncAcceptance {
+				s.withdrawTaskBatch(tasks)
+			}
+
+			return "", nil, newRepositoryError(err)
+		}
+	}
+
+	// Nothing was published when the event is not, the batch is rejected as a whole
+	if err := s.eventPublisherService.PublishTaskEvent(event); err != nil {
+		s.forgetTasks(tasks)
+
+		if syncAcceptance {
+			s.withdrawTaskBatch(tasks)
+		}
+
+		return "", nil, newPublishError(err)
+	}
+
+	return batchId, tasks, nil
+}
+
+// withdrawTaskBatch cancels the tasks of a batch the simulator accepted, and so enqueued, but
+// which could not be created, see withdrawTask
+func (s *robotService) withdrawTaskBatch(tasks []repository.Task) {
+	for _, task := range tasks {
+		s.withdrawTask(task.Id)
+	}
+}
+
+// getTaskBatch returns the tasks of a batch ordered by id
+func (s *robotService) getTaskBatch(batchId string) ([]repository.Task, error) {
+	tasks, err := s.listTasks(taskFilter{batchId: &batchId})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tasks) == 0 {
+		return nil, newServiceError(
+			http.StatusNotFound,
+			fmt.Sprintf("No batch found with Id: %s", batchId))
+	}
+
+	return tasks, nil
+}
+
+// cancelTaskBatch cancels the tasks of a batch which are not finished yet and returns the tasks
+// of the batch
+func (s *robotService) cancelTaskBatch(batchId string) ([]repository.Task, error) {
+	tasks, err := s.getTaskBatch(batchId)
+	if err != nil {
+		return nil, err
+	}
+
+	robotIds := make([]int64, 0, len(tasks))
+	for _, task := range tasks {
+		robotIds = append(robotIds, task.RobotId)
+	}
+
+	defer s.lockRobots(robotIds...)()
+
+	batchIdValue, err := strconv.ParseInt(batchId, 10, 64)
+	if err != nil {
+		return nil, newServiceError(
+			http.StatusNotFound,
+			fmt.Sprintf("No batch found with Id: %s", batchId))
+	}
+
+	// The unfinished tasks are cancelled in a single event, the simulator cancels all of them
+	// or none when it is not published
+	event := eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskBatchCancelled,
+		Id:        batchIdValue,
+		Data: eventpublisher.TaskData{
+			BatchId: batchId,
+			Tasks:   make([]eventpublisher.BatchTask, 0, len(tasks)),
+		},
+	}
+
+	for _, task := range tasks {
+		if !task.Status.IsFinished() {
+			event.Data.Tasks = append(event.Data.Tasks, eventpublisher.BatchTask{
+				Id:             task.Id,
+				RobotId:        task.RobotId,
+				MoveSequeneces: task.MoveSequences,
+			})
+		}
+	}
+
+	if len(event.Data.Tasks) == 0 {
+		return tasks, nil
+	}
+
+	if err := s.eventPublisherService.PublishTaskEvent(event); err != nil {
+		return nil, newPublishError(err)
+	}
+
+	for idx, task := range tasks {
+		if task.Status.IsFinished() {
+			continue
+		}
+
+		if tasks[idx], err = s.markTaskCancelled(task.Id); err != nil {
+			return nil, newRepositoryError(err)
+		}
+	}
+
+	return tasks, nil
+}
+
+// forgetTasks deletes the tasks of a rejected batch
+func (s *robotService) forgetTasks(tasks []repository.Task) {
+	for _, task := range tasks {
+		if err := s.repositoryService.DeleteTask(task.Id); err != nil {
+			s.logger.Errorf("Failed to forget task %d. Error: %v", task.Id, err)
+		}
+	}
+}
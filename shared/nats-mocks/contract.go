@@ -1,7 +0,0 @@
-package nats
-
-import "github.com/nats-io/nats.go"
-
-type JetStreamContext interface {
-	nats.JetStreamContext
-}
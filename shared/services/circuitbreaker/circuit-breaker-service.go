@@ -0,0 +1,213 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+type circuitBreakerService struct {
+	logger             *zap.SugaredLogger
+	robotBrokerService robotbroker.RobotBrokerInterface
+	options            Options
+	mutex              *sync.Mutex
+	state              State
+	failures           int
+	disconnected       bool
+	trialInFlight      bool
+	retryAt            time.Time
+	unregister         func()
+}
+
+// NewCircuitBreakerService creates a concrete instance of CircuitBreakerInterface which
+// publishes and sends requests to robotBrokerService as long as its circuit is closed
+func NewCircuitBreakerService(
+	logger *zap.SugaredLogger,
+	robotBrokerService robotbroker.RobotBrokerInterface,
+	options Options) (CircuitBreakerInterface, error) {
+	if robotBrokerService == nil {
+		return nil, errors.New("a circuit breaker requires a broker")
+	}
+
+	if options.FailureThreshold == 0 {
+		options.FailureThreshold = DefaultFailureThreshold
+	}
+
+	if options.OpenTimeout == 0 {
+		options.OpenTimeout = DefaultOpenTimeout
+	}
+
+	if options.FailureThreshold < 0 || options.OpenTimeout < 0 {
+		return nil, errors.New("failure threshold and open timeout must be positive")
+	}
+
+	s := &circuitBreakerService{
+		logger:             logger,
+		robotBrokerService: robotBrokerService,
+		options:            options,
+		mutex:              &sync.Mutex{},
+		state:              StateClosed,
+	}
+
+	s.unregister = robotBrokerService.OnConnectionStateChange(s.handleConnectionStateChanged)
+
+	if state := robotBrokerService.ConnectionState(); state != robotbroker.Connected {
+		s.handleConnectionStateChanged(state, nil)
+	}
+
+	return s, nil
+}
+
+// Publish publishes a message unless the circuit is open
+func (s *circuitBreakerService) Publish(msg *robotbroker.Message) error {
+	if err := s.allow(); err != nil {
+		return err
+	}
+
+	// A message which can never be published says nothing about the broker
+	err := s.robotBrokerService.Publish(msg)
+	if robotbroker.IsPermanent(err) {
+		s.record(nil)
+	} else {
+		s.record(err)
+	}
+
+	return err
+}
+
+// Request sends a request unless the circuit is open, requests nobody replies to do not count
+// as failures since the broker did its job
+func (s *circuitBreakerService) Request(msg *robotbroker.Message, timeout time.Duration) (*robotbroker.Message, error) {
+	if err := s.allow(); err != nil {
+		return nil, err
+	}
+
+	reply, err := s.robotBrokerService.Request(msg, timeout)
+	if errors.Is(err, robotbroker.ErrNoResponders) || errors.Is(err, robotbroker.ErrRequestTimeout) {
+		s.record(nil)
+	} else {
+		s.record(err)
+	}
+
+	return reply, err
+}
+
+// State returns the state of the circuit
+func (s *circuitBreakerService) State() State {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.state
+}
+
+// Close stops following the state of the broker connection
+func (s *circuitBreakerService) Close() {
+	s.unregister()
+}
+
+// allow returns an OpenError when the call has to fail fast
+func (s *circuitBreakerService) allow() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+
+	if s.disconnected {
+		// The broker reconnects on its own, tell the caller to come back a bit later
+		if !now.Before(s.retryAt) {
+			s.retryAt = now.Add(s.options.OpenTimeout)
+		}
+
+		return s.openError(now)
+	}
+
+	switch s.state {
+	case StateOpen:
+		if now.Before(s.retryAt) {
+			return s.openError(now)
+		}
+
+		s.transition(StateHalfOpen)
+		s.retryAt = now.Add(s.options.OpenTimeout)
+		s.trialInFlight = true
+
+		return nil
+	case StateHalfOpen:
+		if s.trialInFlight {
+			return s.openError(now)
+		}
+
+		s.trialInFlight = true
+
+		return nil
+	}
+
+	return nil
+}
+
+// record updates the circuit with the outcome of a call it let through
+func (s *circuitBreakerService) record(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.trialInFlight = false
+
+	if err == nil {
+		s.failures = 0
+
+		if !s.disconnected {
+			s.transition(StateClosed)
+		}
+
+		return
+	}
+
+	s.failures++
+
+	if s.state == StateHalfOpen || s.failures >= s.options.FailureThreshold {
+		s.retryAt = time.Now().Add(s.options.OpenTimeout)
+		s.transition(StateOpen)
+	}
+}
+
+func (s *circuitBreakerService) handleConnectionStateChanged(
+	state robotbroker.ConnectionState,
+	err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if state == robotbroker.Connected {
+		s.disconnected = false
+		s.failures = 0
+		s.transition(StateClosed)
+
+		return
+	}
+
+	s.disconnected = true
+	s.retryAt = time.Now().Add(s.options.OpenTimeout)
+	s.transition(StateOpen)
+}
+
+// transition must be called with the lock held
+func (s *circuitBreakerService) transition(state State) {
+	if s.state == state {
+		return
+	}
+
+	s.logger.Warnf("Circuit breaker moved from %s to %s", s.state, state)
+	s.state = state
+}
+
+// openError must be called with the lock held
+func (s *circuitBreakerService) openError(now time.Time) error {
+	retryAfter := s.retryAt.Sub(now)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+
+	return &OpenError{RetryAfter: retryAfter}
+}
@@ -0,0 +1,130 @@
+package circuitbreaker_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/circuitbreaker"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_Publish_Should_Fail_Fast_While_Broker_Is_Disconnected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	var onConnectionStateChange robotbroker.ConnectionStateHandler
+
+	mockRobotBrokerService.
+		EXPECT().
+		OnConnectionStateChange(gomock.Any()).
+		DoAndReturn(func(handler robotbroker.ConnectionStateHandler) func() {
+			onConnectionStateChange = handler
+
+			return func() {}
+		})
+
+	mockRobotBrokerService.
+		EXPECT().
+		ConnectionState().
+		Return(robotbroker.Connected)
+
+	sut, err := circuitbreaker.NewCircuitBreakerService(
+		sugarLogger,
+		mockRobotBrokerService,
+		circuitbreaker.Options{OpenTimeout: 10 * time.Second})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	msg := robotbroker.NewMessage(robotbroker.TaskSubject("1", "created"))
+
+	onConnectionStateChange(robotbroker.Disconnected, errors.New("connection reset"))
+	g.Expect(sut.State()).Should(Equal(circuitbreaker.StateOpen))
+
+	err = sut.Publish(msg)
+	g.Expect(errors.Is(err, circuitbreaker.ErrBrokerUnavailable)).Should(BeTrue())
+
+	var openErr *circuitbreaker.OpenError
+	g.Expect(errors.As(err, &openErr)).Should(BeTrue())
+	g.Expect(openErr.RetryAfter).Should(BeNumerically(">", 9*time.Second))
+
+	onConnectionStateChange(robotbroker.Connected, nil)
+	g.Expect(sut.State()).Should(Equal(circuitbreaker.StateClosed))
+
+	mockRobotBrokerService.
+		EXPECT().
+		Publish(msg).
+		Return(nil)
+
+	g.Expect(sut.Publish(msg)).Should(Succeed())
+}
+
+func Test_Publish_Should_Open_Circuit_After_Consecutive_Failures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	mockRobotBrokerService.
+		EXPECT().
+		OnConnectionStateChange(gomock.Any()).
+		Return(func() {})
+
+	mockRobotBrokerService.
+		EXPECT().
+		ConnectionState().
+		Return(robotbroker.Connected)
+
+	sut, err := circuitbreaker.NewCircuitBreakerService(
+		sugarLogger,
+		mockRobotBrokerService,
+		circuitbreaker.Options{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	msg := robotbroker.NewMessage(robotbroker.TaskSubject("1", "created"))
+	publishErr := errors.New("no response from stream")
+
+	mockRobotBrokerService.
+		EXPECT().
+		Publish(msg).
+		Return(publishErr).
+		Times(2)
+
+	g.Expect(sut.Publish(msg)).Should(Equal(publishErr))
+	g.Expect(sut.State()).Should(Equal(circuitbreaker.StateClosed))
+	g.Expect(sut.Publish(msg)).Should(Equal(publishErr))
+	g.Expect(sut.State()).Should(Equal(circuitbreaker.StateOpen))
+
+	err = sut.Publish(msg)
+	g.Expect(errors.Is(err, circuitbreaker.ErrBrokerUnavailable)).Should(BeTrue())
+
+	time.Sleep(60 * time.Millisecond)
+
+	// A single trial is let through once the circuit has been open long enough
+	mockRobotBrokerService.
+		EXPECT().
+		Publish(msg).
+		Return(nil)
+
+	g.Expect(sut.Publish(msg)).Should(Succeed())
+	g.Expect(sut.State()).Should(Equal(circuitbreaker.StateClosed))
+}
@@ -0,0 +1,54 @@
+package circuitbreaker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/circuitbreaker"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_Request_Should_Not_Open_Circuit_When_Nobody_Replies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	mockRobotBrokerService.
+		EXPECT().
+		OnConnectionStateChange(gomock.Any()).
+		Return(func() {})
+
+	mockRobotBrokerService.
+		EXPECT().
+		ConnectionState().
+		Return(robotbroker.Connected)
+
+	sut, err := circuitbreaker.NewCircuitBreakerService(
+		sugarLogger,
+		mockRobotBrokerService,
+		circuitbreaker.Options{FailureThreshold: 1})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	msg := robotbroker.NewMessage(robotbroker.RequestSubject(robotbroker.TaskSubject("1", "created")))
+
+	mockRobotBrokerService.
+		EXPECT().
+		Request(msg, time.Second).
+		Return(nil, robotbroker.ErrNoResponders)
+
+	_, err = sut.Request(msg, time.Second)
+	g.Expect(err).Should(Equal(robotbroker.ErrNoResponders))
+	g.Expect(sut.State()).Should(Equal(circuitbreaker.StateClosed))
+}
@@ -0,0 +1,63 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+// State is the state of a circuit breaker
+type State string
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = "closed"
+	// StateOpen fails every call fast
+	StateOpen State = "open"
+	// StateHalfOpen lets a single trial call through, its outcome closes or opens the circuit
+	StateHalfOpen State = "halfOpen"
+)
+
+const (
+	// DefaultFailureThreshold is the number of consecutive failures which open the circuit
+	DefaultFailureThreshold = 5
+	// DefaultOpenTimeout is how long the circuit stays open before a trial call is let through
+	DefaultOpenTimeout = 5 * time.Second
+)
+
+// ErrBrokerUnavailable is wrapped by the errors of the calls a circuit breaker fails fast
+var ErrBrokerUnavailable = errors.New("broker is unavailable")
+
+// OpenError is returned by the calls a circuit breaker fails fast
+type OpenError struct {
+	// RetryAfter is when the call is worth trying again
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%v, retry in %v", ErrBrokerUnavailable, e.RetryAfter)
+}
+
+func (e *OpenError) Unwrap() error {
+	return ErrBrokerUnavailable
+}
+
+// Options configures a circuit breaker
+type Options struct {
+	// FailureThreshold defaults to DefaultFailureThreshold
+	FailureThreshold int
+	// OpenTimeout defaults to DefaultOpenTimeout
+	OpenTimeout time.Duration
+}
+
+// CircuitBreakerInterface defines contract for a circuit breaker in front of a broker. The
+// circuit opens while the broker is disconnected or once too many calls failed in a row
+type CircuitBreakerInterface interface {
+	robotbroker.PublisherInterface
+	robotbroker.RequesterInterface
+	State() State
+	// Close stops following the state of the broker connection
+	Close()
+}
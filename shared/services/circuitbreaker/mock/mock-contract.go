@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_circuitbreaker is a generated GoMock package.
+package mock_circuitbreaker
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	circuitbreaker "github.com/sepisoad/robot-challange/shared/services/circuitbreaker"
+	robotbroker "github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+// MockCircuitBreakerInterface is a mock of CircuitBreakerInterface interface.
+type MockCircuitBreakerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCircuitBreakerInterfaceMockRecorder
+}
+
+// MockCircuitBreakerInterfaceMockRecorder is the mock recorder for MockCircuitBreakerInterface.
+type MockCircuitBreakerInterfaceMockRecorder struct {
+	mock *MockCircuitBreakerInterface
+}
+
+// NewMockCircuitBreakerInterface creates a new mock instance.
+func NewMockCircuitBreakerInterface(ctrl *gomock.Controller) *MockCircuitBreakerInterface {
+	mock := &MockCircuitBreakerInterface{ctrl: ctrl}
+	mock.recorder = &MockCircuitBreakerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCircuitBreakerInterface) EXPECT() *MockCircuitBreakerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockCircuitBreakerInterface) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockCircuitBreakerInterfaceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCircuitBreakerInterface)(nil).Close))
+}
+
+// Publish mocks base method.
+func (m *MockCircuitBreakerInterface) Publish(msg *robotbroker.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockCircuitBreakerInterfaceMockRecorder) Publish(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockCircuitBreakerInterface)(nil).Publish), msg)
+}
+
+// Request mocks base method.
+func (m *MockCircuitBreakerInterface) Request(msg *robotbroker.Message, timeout time.Duration) (*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", msg, timeout)
+	ret0, _ := ret[0].(*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockCircuitBreakerInterfaceMockRecorder) Request(msg, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockCircuitBreakerInterface)(nil).Request), msg, timeout)
+}
+
+// State mocks base method.
+func (m *MockCircuitBreakerInterface) State() circuitbreaker.State {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "State")
+	ret0, _ := ret[0].(circuitbreaker.State)
+	return ret0
+}
+
+// State indicates an expected call of State.
+func (mr *MockCircuitBreakerInterfaceMockRecorder) State() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "State", reflect.TypeOf((*MockCircuitBreakerInterface)(nil).State))
+}
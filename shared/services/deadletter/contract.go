@@ -0,0 +1,27 @@
+package deadletter
+
+import (
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+// DeadLetter describes a message which could not be processed by one of the consumers
+type DeadLetter struct {
+	Sequence         uint64
+	OriginalSubject  string
+	OriginalSequence uint64
+	Consumer         string
+	Deliveries       uint64
+	Error            string
+	Time             time.Time
+	Header           robotbroker.Header
+	Data             []byte
+}
+
+// DeadLetterInterface defines contract for inspecting and republishing dead letters
+type DeadLetterInterface interface {
+	List() ([]DeadLetter, error)
+	Get(sequence uint64) (DeadLetter, error)
+	Republish(sequence uint64) error
+}
@@ -0,0 +1,118 @@
+package deadletter
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+var deadLetterHeaders = []string{
+	robotbroker.HEADER_DEAD_LETTER_SUBJECT,
+	robotbroker.HEADER_DEAD_LETTER_STREAM_SEQUENCE,
+	robotbroker.HEADER_DEAD_LETTER_CONSUMER,
+	robotbroker.HEADER_DEAD_LETTER_DELIVERIES,
+	robotbroker.HEADER_DEAD_LETTER_ERROR,
+	robotbroker.HEADER_DEAD_LETTER_TIME,
+	robotbroker.HEADER_MESSAGE_ID,
+}
+
+type deadLetterService struct {
+	logger             *zap.SugaredLogger
+	robotBrokerService robotbroker.RobotBrokerInterface
+}
+
+// NewDeadLetterService creates a concrete instance of DeadLetterInterface
+func NewDeadLetterService(
+	logger *zap.SugaredLogger,
+	robotBrokerService robotbroker.RobotBrokerInterface) (DeadLetterInterface, error) {
+	return &deadLetterService{
+		logger:             logger,
+		robotBrokerService: robotBrokerService,
+	}, nil
+}
+
+// List returns all the dead letters which are still kept by the broker
+func (s *deadLetterService) List() ([]DeadLetter, error) {
+	msgs, err := s.robotBrokerService.DeadLetters()
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]DeadLetter, 0, len(msgs))
+	for _, msg := range msgs {
+		deadLetters = append(deadLetters, convertToDeadLetter(msg))
+	}
+
+	return deadLetters, nil
+}
+
+// Get returns a dead letter by its sequence
+func (s *deadLetterService) Get(sequence uint64) (DeadLetter, error) {
+	msg, err := s.robotBrokerService.GetDeadLetter(sequence)
+	if err != nil {
+		return DeadLetter{}, err
+	}
+
+	return convertToDeadLetter(msg), nil
+}
+
+// Republish publishes a dead letter back to its original subject and removes it from
+// the dead letters
+func (s *deadLetterService) Republish(sequence uint64) error {
+	deadLetter, err := s.Get(sequence)
+	if err != nil {
+		return err
+	}
+
+	if deadLetter.OriginalSubject == "" {
+		return fmt.Errorf("dead letter %d has no original subject", sequence)
+	}
+
+	msg := robotbroker.NewMessage(deadLetter.OriginalSubject)
+	msg.Data = deadLetter.Data
+	msg.Header = deadLetter.Header.Clone()
+
+	for _, key := range deadLetterHeaders {
+		delete(msg.Header, key)
+	}
+
+	if err := s.robotBrokerService.Publish(msg); err != nil {
+		s.logger.Errorf(
+			"Failed to republish dead letter %d to %s. Error: %v",
+			sequence,
+			deadLetter.OriginalSubject,
+			err)
+
+		return err
+	}
+
+	return s.robotBrokerService.DeleteDeadLetter(sequence)
+}
+
+func convertToDeadLetter(msg *robotbroker.Message) DeadLetter {
+	deadLetter := DeadLetter{
+		Sequence:        msg.Sequence,
+		OriginalSubject: msg.Header.Get(robotbroker.HEADER_DEAD_LETTER_SUBJECT),
+		Consumer:        msg.Header.Get(robotbroker.HEADER_DEAD_LETTER_CONSUMER),
+		Error:           msg.Header.Get(robotbroker.HEADER_DEAD_LETTER_ERROR),
+		Time:            msg.Time,
+		Header:          msg.Header,
+		Data:            msg.Data,
+	}
+
+	deadLetter.OriginalSequence, _ = strconv.ParseUint(
+		msg.Header.Get(robotbroker.HEADER_DEAD_LETTER_STREAM_SEQUENCE), 10, 64)
+	deadLetter.Deliveries, _ = strconv.ParseUint(
+		msg.Header.Get(robotbroker.HEADER_DEAD_LETTER_DELIVERIES), 10, 64)
+
+	if deadLetteredAt, err := time.Parse(
+		time.RFC3339Nano,
+		msg.Header.Get(robotbroker.HEADER_DEAD_LETTER_TIME)); err == nil {
+		deadLetter.Time = deadLetteredAt
+	}
+
+	return deadLetter
+}
@@ -0,0 +1,108 @@
+package deadletter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/deadletter"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+// deadLetter publishes msg on a broker whose only consumer gives up on it straight away
+func deadLetter(g *WithT, robotBrokerService robotbroker.RobotBrokerInterface, msg *robotbroker.Message, handlerErr error) {
+	subscription, err := robotBrokerService.QueueSubscribe(
+		msg.Subject,
+		"consumer",
+		func(*robotbroker.Message) error {
+			return robotbroker.Permanent(handlerErr)
+		})
+	g.Expect(err).Should(BeNil())
+	defer subscription.Unsubscribe()
+
+	deadLetters, err := robotBrokerService.DeadLetters()
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(robotBrokerService.Publish(msg)).Should(Succeed())
+	g.Eventually(robotBrokerService.DeadLetters).Should(HaveLen(len(deadLetters) + 1))
+}
+
+func Test_List_Should_Return_All_Dead_Letters_Kept_By_The_Broker(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer robotBrokerService.Close()
+
+	sut, err := deadletter.NewDeadLetterService(sugarLogger, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	deadLetters, err := sut.List()
+	g.Expect(err).Should(BeNil())
+	g.Expect(deadLetters).Should(BeEmpty())
+
+	expectedError := cuid.New()
+	taskSubject := robotbroker.TaskSubject("42", "created")
+	robotSubject := robotbroker.RobotSubject(robotbroker.DEFAULT_WAREHOUSE, "3", "moved")
+
+	for _, subject := range []string{taskSubject, robotSubject, taskSubject} {
+		msg := robotbroker.NewMessage(subject)
+		msg.Data = []byte(subject)
+
+		deadLetter(g, robotBrokerService, msg, errors.New(expectedError))
+	}
+
+	// A republished dead letter leaves a gap
+	deadLetters, err = sut.List()
+	g.Expect(err).Should(BeNil())
+	g.Expect(robotBrokerService.DeleteDeadLetter(deadLetters[1].Sequence)).Should(Succeed())
+
+	deadLetters, err = sut.List()
+	g.Expect(err).Should(BeNil())
+	g.Expect(deadLetters).Should(HaveLen(2))
+
+	g.Expect(deadLetters[0].OriginalSubject).Should(Equal(taskSubject))
+	g.Expect(deadLetters[0].OriginalSequence).ShouldNot(BeZero())
+	g.Expect(deadLetters[0].Consumer).Should(Equal("consumer"))
+	g.Expect(deadLetters[0].Deliveries).Should(Equal(uint64(1)))
+	g.Expect(deadLetters[0].Error).Should(ContainSubstring(expectedError))
+	g.Expect(deadLetters[0].Time).ShouldNot(BeZero())
+	g.Expect(deadLetters[0].Data).Should(Equal([]byte(taskSubject)))
+
+	g.Expect(deadLetters[1].OriginalSubject).Should(Equal(taskSubject))
+	g.Expect(deadLetters[1].Sequence).Should(BeNumerically(">", deadLetters[0].Sequence))
+}
+
+func Test_List_Should_Return_Error_If_The_Broker_Returns_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := deadletter.NewDeadLetterService(sugarLogger, mockRobotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	expectedErr := errors.New(cuid.New())
+
+	mockRobotBrokerService.
+		EXPECT().
+		DeadLetters().
+		Return(nil, expectedErr)
+
+	_, err = sut.List()
+	g.Expect(err).Should(Equal(expectedErr))
+}
@@ -0,0 +1,110 @@
+package deadletter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/deadletter"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_Republish_Should_Publish_To_Original_Subject_And_Delete_Dead_Letter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer robotBrokerService.Close()
+
+	sut, err := deadletter.NewDeadLetterService(sugarLogger, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	subject := robotbroker.TaskSubject("42", "created")
+	expectedData := []byte(cuid.New())
+
+	msg := robotbroker.NewMessage(subject)
+	msg.Data = expectedData
+	msg.Header.Set(robotbroker.HEADER_MESSAGE_ID, cuid.New())
+	msg.Header.Set("Custom-Header", "value")
+
+	deadLetter(g, robotBrokerService, msg, errors.New(cuid.New()))
+
+	deadLetters, err := sut.List()
+	g.Expect(err).Should(BeNil())
+	g.Expect(deadLetters).Should(HaveLen(1))
+
+	republished := make(chan *robotbroker.Message, 1)
+
+	subscription, err := robotBrokerService.QueueSubscribe(subject, "consumer", func(msg *robotbroker.Message) error {
+		republished <- msg
+
+		return nil
+	})
+	g.Expect(err).Should(BeNil())
+	defer subscription.Unsubscribe()
+
+	g.Expect(sut.Republish(deadLetters[0].Sequence)).Should(Succeed())
+
+	var msgRepublished *robotbroker.Message
+	g.Eventually(republished).Should(Receive(&msgRepublished))
+	g.Expect(msgRepublished.Data).Should(Equal(expectedData))
+	g.Expect(msgRepublished.Header.Get("Custom-Header")).Should(Equal("value"))
+	g.Expect(msgRepublished.Header.Get(robotbroker.HEADER_DEAD_LETTER_ERROR)).Should(BeEmpty())
+	g.Expect(msgRepublished.Header.Get(robotbroker.HEADER_DEAD_LETTER_SUBJECT)).Should(BeEmpty())
+	g.Expect(msgRepublished.Header.Get(robotbroker.HEADER_MESSAGE_ID)).Should(BeEmpty())
+
+	_, err = sut.Get(deadLetters[0].Sequence)
+	g.Expect(err).Should(Equal(robotbroker.ErrMessageNotFound))
+}
+
+func Test_Republish_Should_Keep_Dead_Letter_If_Publish_Returns_Error(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+
+	sut, err := deadletter.NewDeadLetterService(sugarLogger, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	deadLetter(g, robotBrokerService, robotbroker.NewMessage(robotbroker.TaskSubject("42", "created")), errors.New(cuid.New()))
+
+	deadLetters, err := sut.List()
+	g.Expect(err).Should(BeNil())
+
+	// A closed broker refuses to publish
+	robotBrokerService.Close()
+
+	err = sut.Republish(deadLetters[0].Sequence)
+	g.Expect(err).Should(Equal(robotbroker.ErrBrokerClosed))
+
+	_, err = sut.Get(deadLetters[0].Sequence)
+	g.Expect(err).Should(BeNil())
+}
+
+func Test_Republish_Should_Return_Error_For_Unknown_Dead_Letter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer robotBrokerService.Close()
+
+	sut, err := deadletter.NewDeadLetterService(sugarLogger, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	err = sut.Republish(7)
+	g.Expect(err).Should(Equal(robotbroker.ErrMessageNotFound))
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_deadletter is a generated GoMock package.
+package mock_deadletter
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	deadletter "github.com/sepisoad/robot-challange/shared/services/deadletter"
+)
+
+// MockDeadLetterInterface is a mock of DeadLetterInterface interface.
+type MockDeadLetterInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterInterfaceMockRecorder
+}
+
+// MockDeadLetterInterfaceMockRecorder is the mock recorder for MockDeadLetterInterface.
+type MockDeadLetterInterfaceMockRecorder struct {
+	mock *MockDeadLetterInterface
+}
+
+// NewMockDeadLetterInterface creates a new mock instance.
+func NewMockDeadLetterInterface(ctrl *gomock.Controller) *MockDeadLetterInterface {
+	mock := &MockDeadLetterInterface{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterInterface) EXPECT() *MockDeadLetterInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockDeadLetterInterface) Get(sequence uint64) (deadletter.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", sequence)
+	ret0, _ := ret[0].(deadletter.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDeadLetterInterfaceMockRecorder) Get(sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeadLetterInterface)(nil).Get), sequence)
+}
+
+// List mocks base method.
+func (m *MockDeadLetterInterface) List() ([]deadletter.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].([]deadletter.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockDeadLetterInterfaceMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockDeadLetterInterface)(nil).List))
+}
+
+// Republish mocks base method.
+func (m *MockDeadLetterInterface) Republish(sequence uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Republish", sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Republish indicates an expected call of Republish.
+func (mr *MockDeadLetterInterfaceMockRecorder) Republish(sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Republish", reflect.TypeOf((*MockDeadLetterInterface)(nil).Republish), sequence)
+}
@@ -0,0 +1,7 @@
+package embeddednats
+
+// EmbeddedNatsInterface defines contracts for a NATS server running in process
+type EmbeddedNatsInterface interface {
+	ClientURL() string
+	Shutdown()
+}
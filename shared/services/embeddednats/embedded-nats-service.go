@@ -0,0 +1,99 @@
+package embeddednats
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"go.uber.org/zap"
+)
+
+// DefaultStartTimeout is how long the server is given to accept connections
+const DefaultStartTimeout = 10 * time.Second
+
+type embeddedNatsService struct {
+	server *server.Server
+}
+
+// NewEmbeddedNatsService starts a JetStream enabled NATS server in process which persists
+// its streams under dataDir. A port of -1 picks a random port
+func NewEmbeddedNatsService(
+	logger *zap.SugaredLogger,
+	dataDir string,
+	host string,
+	port int) (EmbeddedNatsInterface, error) {
+	storeDir, err := filepath.Abs(filepath.Join(dataDir, "jetstream"))
+	if err != nil {
+		return nil, err
+	}
+
+	natsServer, err := server.NewServer(&server.Options{
+		ServerName: "robots-all-in-one",
+		Host:       host,
+		Port:       port,
+		JetStream:  true,
+		StoreDir:   storeDir,
+		NoSigs:     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	natsServer.SetLoggerV2(&natsLogger{logger: logger}, false, false, false)
+
+	go natsServer.Start()
+
+	if !natsServer.ReadyForConnections(DefaultStartTimeout) {
+		natsServer.Shutdown()
+
+		return nil, errors.New("embedded NATS server did not start in time")
+	}
+
+	logger.Infof("Embedded NATS server is listening on %s, data is kept in %s", natsServer.ClientURL(), storeDir)
+
+	return &embeddedNatsService{
+		server: natsServer,
+	}, nil
+}
+
+// ClientURL returns the url clients can connect to the server with
+func (s *embeddedNatsService) ClientURL() string {
+	return s.server.ClientURL()
+}
+
+// Shutdown stops the server and waits for it to flush its streams to disk
+func (s *embeddedNatsService) Shutdown() {
+	s.server.Shutdown()
+	s.server.WaitForShutdown()
+}
+
+// natsLogger forwards the logs of the NATS server to zap
+type natsLogger struct {
+	logger *zap.SugaredLogger
+}
+
+func (l *natsLogger) Noticef(format string, v ...interface{}) {
+	l.logger.Infof(format, v...)
+}
+
+func (l *natsLogger) Warnf(format string, v ...interface{}) {
+	l.logger.Warnf(format, v...)
+}
+
+// Fatalf does not exit, the server shuts itself down on fatal errors
+func (l *natsLogger) Fatalf(format string, v ...interface{}) {
+	l.logger.Errorf(format, v...)
+}
+
+func (l *natsLogger) Errorf(format string, v ...interface{}) {
+	l.logger.Errorf(format, v...)
+}
+
+func (l *natsLogger) Debugf(format string, v ...interface{}) {
+	l.logger.Debugf(format, v...)
+}
+
+func (l *natsLogger) Tracef(format string, v ...interface{}) {
+	l.logger.Debugf(format, v...)
+}
@@ -0,0 +1,60 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_embeddednats is a generated GoMock package.
+package mock_embeddednats
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEmbeddedNatsInterface is a mock of EmbeddedNatsInterface interface.
+type MockEmbeddedNatsInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmbeddedNatsInterfaceMockRecorder
+}
+
+// MockEmbeddedNatsInterfaceMockRecorder is the mock recorder for MockEmbeddedNatsInterface.
+type MockEmbeddedNatsInterfaceMockRecorder struct {
+	mock *MockEmbeddedNatsInterface
+}
+
+// NewMockEmbeddedNatsInterface creates a new mock instance.
+func NewMockEmbeddedNatsInterface(ctrl *gomock.Controller) *MockEmbeddedNatsInterface {
+	mock := &MockEmbeddedNatsInterface{ctrl: ctrl}
+	mock.recorder = &MockEmbeddedNatsInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmbeddedNatsInterface) EXPECT() *MockEmbeddedNatsInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ClientURL mocks base method.
+func (m *MockEmbeddedNatsInterface) ClientURL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClientURL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ClientURL indicates an expected call of ClientURL.
+func (mr *MockEmbeddedNatsInterfaceMockRecorder) ClientURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClientURL", reflect.TypeOf((*MockEmbeddedNatsInterface)(nil).ClientURL))
+}
+
+// Shutdown mocks base method.
+func (m *MockEmbeddedNatsInterface) Shutdown() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Shutdown")
+}
+
+// Shutdown indicates an expected call of Shutdown.
+func (mr *MockEmbeddedNatsInterfaceMockRecorder) Shutdown() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Shutdown", reflect.TypeOf((*MockEmbeddedNatsInterface)(nil).Shutdown))
+}
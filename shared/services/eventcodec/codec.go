@@ -0,0 +1,38 @@
+package eventcodec
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownCodec is returned when a codec name or content type is not supported
+var ErrUnknownCodec = errors.New("unknown codec")
+
+// NewCodec creates the codec with the given name, an empty name selects JSON
+func NewCodec(name string) (CodecInterface, error) {
+	switch name {
+	case "", JSON:
+		return &jsonCodec{}, nil
+	case PROTOBUF:
+		return &protobufCodec{}, nil
+	case MSGPACK:
+		return &msgpackCodec{}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownCodec, name)
+}
+
+// NewCodecForContentType creates the codec announced by a content type header. Messages
+// without a content type predate the codecs and are always JSON
+func NewCodecForContentType(contentType string) (CodecInterface, error) {
+	switch contentType {
+	case "", CONTENT_TYPE_JSON:
+		return &jsonCodec{}, nil
+	case CONTENT_TYPE_PROTOBUF:
+		return &protobufCodec{}, nil
+	case CONTENT_TYPE_MSGPACK:
+		return &msgpackCodec{}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownCodec, contentType)
+}
@@ -0,0 +1,85 @@
+package eventcodec_test
+
+import (
+	"testing"
+
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+)
+
+func Test_NewCodec_Should_Return_Codec_By_Name(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for name, contentType := range map[string]string{
+		eventcodec.JSON:     eventcodec.CONTENT_TYPE_JSON,
+		eventcodec.PROTOBUF: eventcodec.CONTENT_TYPE_PROTOBUF,
+		eventcodec.MSGPACK:  eventcodec.CONTENT_TYPE_MSGPACK,
+	} {
+		sut, err := eventcodec.NewCodec(name)
+		g.Expect(err).Should(BeNil())
+		g.Expect(sut.Name()).Should(Equal(name))
+		g.Expect(sut.ContentType()).Should(Equal(contentType))
+
+		sut, err = eventcodec.NewCodecForContentType(contentType)
+		g.Expect(err).Should(BeNil())
+		g.Expect(sut.Name()).Should(Equal(name))
+	}
+}
+
+func Test_NewCodec_Should_Default_To_JSON(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := eventcodec.NewCodec("")
+	g.Expect(err).Should(BeNil())
+	g.Expect(sut.Name()).Should(Equal(eventcodec.JSON))
+
+	sut, err = eventcodec.NewCodecForContentType("")
+	g.Expect(err).Should(BeNil())
+	g.Expect(sut.Name()).Should(Equal(eventcodec.JSON))
+}
+
+func Test_NewCodec_Should_Return_Error_For_Unknown_Codec(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := eventcodec.NewCodec(cuid.New())
+	g.Expect(err).Should(MatchError(eventcodec.ErrUnknownCodec))
+
+	_, err = eventcodec.NewCodecForContentType(cuid.New())
+	g.Expect(err).Should(MatchError(eventcodec.ErrUnknownCodec))
+}
+
+func Test_Marshal_Should_Round_Trip_With_MessagePack(t *testing.T) {
+	type event struct {
+		Name  string `json:"Name"`
+		Count int    `json:"Count"`
+	}
+
+	g := NewGomegaWithT(t)
+
+	sut, err := eventcodec.NewCodec(eventcodec.MSGPACK)
+	g.Expect(err).Should(BeNil())
+
+	expected := event{Name: cuid.New(), Count: 42}
+
+	buf, err := sut.Marshal(expected)
+	g.Expect(err).Should(BeNil())
+
+	var document map[string]interface{}
+	g.Expect(sut.Unmarshal(buf, &document)).Should(BeNil())
+	g.Expect(document).Should(HaveKey("Name"))
+
+	actual := event{}
+	g.Expect(sut.Unmarshal(buf, &actual)).Should(BeNil())
+	g.Expect(actual).Should(Equal(expected))
+}
+
+func Test_Marshal_Should_Return_Error_For_Types_Without_Protobuf_Mapping(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := eventcodec.NewCodec(eventcodec.PROTOBUF)
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.Marshal(struct{}{})
+	g.Expect(err).Should(Not(BeNil()))
+}
@@ -0,0 +1,40 @@
+package eventcodec
+
+import "google.golang.org/protobuf/proto"
+
+// HEADER_CONTENT_TYPE is the message header which announces the codec of a message
+const HEADER_CONTENT_TYPE = "Content-Type"
+
+const (
+	// JSON is the name of the JSON codec
+	JSON = "json"
+	// PROTOBUF is the name of the Protocol Buffers codec
+	PROTOBUF = "protobuf"
+	// MSGPACK is the name of the MessagePack codec
+	MSGPACK = "msgpack"
+)
+
+const (
+	CONTENT_TYPE_JSON     = "application/json"
+	CONTENT_TYPE_PROTOBUF = "application/x-protobuf"
+	CONTENT_TYPE_MSGPACK  = "application/msgpack"
+)
+
+// CodecInterface defines contract for an event wire encoding
+type CodecInterface interface {
+	Name() string
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// ProtoMarshaler is implemented by the types which can be converted to a protobuf message
+type ProtoMarshaler interface {
+	ToProto() proto.Message
+}
+
+// ProtoUnmarshaler is implemented by the types which can be populated from a protobuf message
+type ProtoUnmarshaler interface {
+	NewProto() proto.Message
+	FromProto(message proto.Message) error
+}
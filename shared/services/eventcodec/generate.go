@@ -0,0 +1,3 @@
+package eventcodec
+
+//go:generate mockgen -source=contract.go -destination=mock/mock-contract.go
@@ -0,0 +1,26 @@
+package eventcodec
+
+import "encoding/json"
+
+type jsonCodec struct {
+}
+
+// Name returns the name of the codec
+func (c *jsonCodec) Name() string {
+	return JSON
+}
+
+// ContentType returns the content type announced in the message header
+func (c *jsonCodec) ContentType() string {
+	return CONTENT_TYPE_JSON
+}
+
+// Marshal serializes v to JSON
+func (c *jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal de-serializes JSON data into v
+func (c *jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
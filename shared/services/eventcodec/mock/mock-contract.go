@@ -0,0 +1,180 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_eventcodec is a generated GoMock package.
+package mock_eventcodec
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	proto "google.golang.org/protobuf/proto"
+)
+
+// MockCodecInterface is a mock of CodecInterface interface.
+type MockCodecInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCodecInterfaceMockRecorder
+}
+
+// MockCodecInterfaceMockRecorder is the mock recorder for MockCodecInterface.
+type MockCodecInterfaceMockRecorder struct {
+	mock *MockCodecInterface
+}
+
+// NewMockCodecInterface creates a new mock instance.
+func NewMockCodecInterface(ctrl *gomock.Controller) *MockCodecInterface {
+	mock := &MockCodecInterface{ctrl: ctrl}
+	mock.recorder = &MockCodecInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCodecInterface) EXPECT() *MockCodecInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ContentType mocks base method.
+func (m *MockCodecInterface) ContentType() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContentType")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// ContentType indicates an expected call of ContentType.
+func (mr *MockCodecInterfaceMockRecorder) ContentType() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContentType", reflect.TypeOf((*MockCodecInterface)(nil).ContentType))
+}
+
+// Marshal mocks base method.
+func (m *MockCodecInterface) Marshal(v interface{}) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Marshal", v)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Marshal indicates an expected call of Marshal.
+func (mr *MockCodecInterfaceMockRecorder) Marshal(v interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Marshal", reflect.TypeOf((*MockCodecInterface)(nil).Marshal), v)
+}
+
+// Name mocks base method.
+func (m *MockCodecInterface) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockCodecInterfaceMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockCodecInterface)(nil).Name))
+}
+
+// Unmarshal mocks base method.
+func (m *MockCodecInterface) Unmarshal(data []byte, v interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unmarshal", data, v)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unmarshal indicates an expected call of Unmarshal.
+func (mr *MockCodecInterfaceMockRecorder) Unmarshal(data, v interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unmarshal", reflect.TypeOf((*MockCodecInterface)(nil).Unmarshal), data, v)
+}
+
+// MockProtoMarshaler is a mock of ProtoMarshaler interface.
+type MockProtoMarshaler struct {
+	ctrl     *gomock.Controller
+	recorder *MockProtoMarshalerMockRecorder
+}
+
+// MockProtoMarshalerMockRecorder is the mock recorder for MockProtoMarshaler.
+type MockProtoMarshalerMockRecorder struct {
+	mock *MockProtoMarshaler
+}
+
+// NewMockProtoMarshaler creates a new mock instance.
+func NewMockProtoMarshaler(ctrl *gomock.Controller) *MockProtoMarshaler {
+	mock := &MockProtoMarshaler{ctrl: ctrl}
+	mock.recorder = &MockProtoMarshalerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProtoMarshaler) EXPECT() *MockProtoMarshalerMockRecorder {
+	return m.recorder
+}
+
+// ToProto mocks base method.
+func (m *MockProtoMarshaler) ToProto() proto.Message {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ToProto")
+	ret0, _ := ret[0].(proto.Message)
+	return ret0
+}
+
+// ToProto indicates an expected call of ToProto.
+func (mr *MockProtoMarshalerMockRecorder) ToProto() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToProto", reflect.TypeOf((*MockProtoMarshaler)(nil).ToProto))
+}
+
+// MockProtoUnmarshaler is a mock of ProtoUnmarshaler interface.
+type MockProtoUnmarshaler struct {
+	ctrl     *gomock.Controller
+	recorder *MockProtoUnmarshalerMockRecorder
+}
+
+// MockProtoUnmarshalerMockRecorder is the mock recorder for MockProtoUnmarshaler.
+type MockProtoUnmarshalerMockRecorder struct {
+	mock *MockProtoUnmarshaler
+}
+
+// NewMockProtoUnmarshaler creates a new mock instance.
+func NewMockProtoUnmarshaler(ctrl *gomock.Controller) *MockProtoUnmarshaler {
+	mock := &MockProtoUnmarshaler{ctrl: ctrl}
+	mock.recorder = &MockProtoUnmarshalerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProtoUnmarshaler) EXPECT() *MockProtoUnmarshalerMockRecorder {
+	return m.recorder
+}
+
+// FromProto mocks base method.
+func (m *MockProtoUnmarshaler) FromProto(message proto.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FromProto", message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FromProto indicates an expected call of FromProto.
+func (mr *MockProtoUnmarshalerMockRecorder) FromProto(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FromProto", reflect.TypeOf((*MockProtoUnmarshaler)(nil).FromProto), message)
+}
+
+// NewProto mocks base method.
+func (m *MockProtoUnmarshaler) NewProto() proto.Message {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewProto")
+	ret0, _ := ret[0].(proto.Message)
+	return ret0
+}
+
+// NewProto indicates an expected call of NewProto.
+func (mr *MockProtoUnmarshalerMockRecorder) NewProto() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewProto", reflect.TypeOf((*MockProtoUnmarshaler)(nil).NewProto))
+}
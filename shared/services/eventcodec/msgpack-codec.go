@@ -0,0 +1,42 @@
+package eventcodec
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type msgpackCodec struct {
+}
+
+// Name returns the name of the codec
+func (c *msgpackCodec) Name() string {
+	return MSGPACK
+}
+
+// ContentType returns the content type announced in the message header
+func (c *msgpackCodec) ContentType() string {
+	return CONTENT_TYPE_MSGPACK
+}
+
+// Marshal serializes v to MessagePack, the json struct tags are used as field names
+func (c *msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal de-serializes MessagePack data into v
+func (c *msgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+
+	return decoder.Decode(v)
+}
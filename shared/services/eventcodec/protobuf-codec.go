@@ -0,0 +1,50 @@
+package eventcodec
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type protobufCodec struct {
+}
+
+// Name returns the name of the codec
+func (c *protobufCodec) Name() string {
+	return PROTOBUF
+}
+
+// ContentType returns the content type announced in the message header
+func (c *protobufCodec) ContentType() string {
+	return CONTENT_TYPE_PROTOBUF
+}
+
+// Marshal serializes v to protobuf, v has to be a protobuf message or implement ProtoMarshaler
+func (c *protobufCodec) Marshal(v interface{}) ([]byte, error) {
+	switch message := v.(type) {
+	case proto.Message:
+		return proto.Marshal(message)
+	case ProtoMarshaler:
+		return proto.Marshal(message.ToProto())
+	}
+
+	return nil, fmt.Errorf("%T can not be encoded as protobuf", v)
+}
+
+// Unmarshal de-serializes protobuf data into v, v has to be a protobuf message or implement
+// ProtoUnmarshaler
+func (c *protobufCodec) Unmarshal(data []byte, v interface{}) error {
+	switch message := v.(type) {
+	case proto.Message:
+		return proto.Unmarshal(data, message)
+	case ProtoUnmarshaler:
+		protoMessage := message.NewProto()
+		if err := proto.Unmarshal(data, protoMessage); err != nil {
+			return err
+		}
+
+		return message.FromProto(protoMessage)
+	}
+
+	return fmt.Errorf("%T can not be decoded from protobuf", v)
+}
@@ -0,0 +1,264 @@
+package eventpublisher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+type asyncEventPublisherService struct {
+	eventPublisherService *eventPublisherService
+	robotBrokerService    robotbroker.RobotBrokerInterface
+	options               AsyncPublishOptions
+	mutex                 *sync.Mutex
+	cond                  *sync.Cond
+	// queue holds the events which have not been handed over to the broker yet
+	queue []*pendingEvent
+	// lastQueued is the latest queued event of every robot
+	lastQueued map[string]*pendingEvent
+	inFlight   int
+	stats      AsyncPublishStats
+	closed     bool
+}
+
+type pendingEvent struct {
+	msg   *robotbroker.Message
+	robot string
+	// coalescable events can be replaced by a later event of the same robot
+	coalescable bool
+}
+
+// NewAsyncEventPublisherService creates a concrete instance of AsyncEventPublisherInterface
+// which buffers the events and hands them over to the broker in order from a single
+// goroutine, without waiting for each of them to be persisted
+func NewAsyncEventPublisherService(
+	logger *zap.SugaredLogger,
+	source string,
+	codec eventcodec.CodecInterface,
+	robotBrokerService robotbroker.RobotBrokerInterface,
+	options AsyncPublishOptions) (AsyncEventPublisherInterface, error) {
+	if codec == nil || robotBrokerService == nil {
+		return nil, errors.New("an event publisher requires a codec and a broker")
+	}
+
+	if options.MaxPending == 0 {
+		options.MaxPending = DefaultMaxPendingPublishes
+	}
+
+	if options.MaxPending < 0 || options.MaxPending > robotbroker.MaxPendingAsyncPublishes {
+		return nil, fmt.Errorf(
+			"max pending publishes must be between 1 and %d",
+			robotbroker.MaxPendingAsyncPublishes)
+	}
+
+	s := &asyncEventPublisherService{
+		eventPublisherService: &eventPublisherService{
+			logger:    logger,
+			source:    source,
+			codec:     codec,
+			publisher: robotBrokerService,
+		},
+		robotBrokerService: robotBrokerService,
+		options:            options,
+		mutex:              &sync.Mutex{},
+		lastQueued:         make(map[string]*pendingEvent),
+	}
+	s.cond = sync.NewCond(s.mutex)
+
+	go s.run()
+
+	return s, nil
+}
+
+// PublishTaskEvent queues a task event, it blocks while too many events are pending
+func (s *asyncEventPublisherService) PublishTaskEvent(event TaskEvent) error {
+	msg, err := s.eventPublisherService.newTaskEventMsg(event)
+	if err != nil {
+		return err
+	}
+
+	return s.enqueue(&pendingEvent{msg: msg})
+}
+
+// PublishRobotEvent queues a robot event, it blocks while too many events are pending
+func (s *asyncEventPublisherService) PublishRobotEvent(event RobotEvent) error {
+	msg, err := s.eventPublisherService.newRobotEventMsg(event)
+	if err != nil {
+		return err
+	}
+
+	return s.enqueue(&pendingEvent{
+		msg:         msg,
+		robot:       msg.Subject[:strings.LastIndex(msg.Subject, ".")],
+		coalescable: s.options.CoalesceRobotMoves && event.EventType == RobotMoved,
+	})
+}
+
+// PublishWarehouseEvent queues a warehouse event, it blocks while too many events are pending
+func (s *asyncEventPublisherService) PublishWarehouseEvent(event WarehouseEvent) error {
+	msg, err := s.eventPublisherService.newWarehouseEventMsg(event)
+	if err != nil {
+		return err
+	}
+
+	return s.enqueue(&pendingEvent{msg: msg})
+}
+
+// Flush waits until every event published so far has been acknowledged or has failed
+func (s *asyncEventPublisherService) Flush(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	// Wake the waiting loop up once the deadline has passed
+	timer := time.AfterFunc(timeout, func() {
+		s.mutex.Lock()
+		s.cond.Broadcast()
+		s.mutex.Unlock()
+	})
+	defer timer.Stop()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for s.pending() > 0 {
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("%w: %d events are still pending", ErrFlushTimeout, s.pending())
+		}
+
+		s.cond.Wait()
+	}
+
+	return nil
+}
+
+// Close stops accepting events and flushes the pending ones
+func (s *asyncEventPublisherService) Close(timeout time.Duration) error {
+	s.mutex.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.mutex.Unlock()
+
+	return s.Flush(timeout)
+}
+
+// Stats returns the counters of the publisher
+func (s *asyncEventPublisherService) Stats() AsyncPublishStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := s.stats
+	stats.Pending = s.pending()
+
+	return stats
+}
+
+func (s *asyncEventPublisherService) enqueue(event *pendingEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrPublisherClosed
+	}
+
+	if event.coalescable {
+		if last, found := s.lastQueued[event.robot]; found && last.coalescable {
+			last.msg = event.msg
+			s.stats.Coalesced++
+
+			return nil
+		}
+	}
+
+	for s.pending() >= s.options.MaxPending {
+		s.cond.Wait()
+
+		if s.closed {
+			return ErrPublisherClosed
+		}
+	}
+
+	s.queue = append(s.queue, event)
+
+	if event.robot != "" {
+		s.lastQueued[event.robot] = event
+	}
+
+	s.cond.Broadcast()
+
+	return nil
+}
+
+// run hands the queued events over to the broker until the publisher is closed and the queue
+// is empty
+func (s *asyncEventPublisherService) run() {
+	for {
+		s.mutex.Lock()
+		for len(s.queue) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+
+		if len(s.queue) == 0 {
+			s.mutex.Unlock()
+
+			return
+		}
+
+		event := s.queue[0]
+		s.queue = s.queue[1:]
+
+		if s.lastQueued[event.robot] == event {
+			delete(s.lastQueued, event.robot)
+		}
+
+		msg := event.msg
+		s.inFlight++
+		s.stats.Published++
+		s.mutex.Unlock()
+
+		if err := s.robotBrokerService.PublishAsync(
+			msg,
+			func(err error) {
+				s.acknowledge(msg, err)
+			}); err != nil {
+			s.acknowledge(msg, err)
+		}
+	}
+}
+
+func (s *asyncEventPublisherService) acknowledge(msg *robotbroker.Message, err error) {
+	s.mutex.Lock()
+	s.inFlight--
+
+	if err == nil {
+		s.stats.Acknowledged++
+	} else {
+		s.stats.Failed++
+	}
+
+	s.cond.Broadcast()
+	s.mutex.Unlock()
+
+	if err == nil {
+		return
+	}
+
+	s.eventPublisherService.logger.Errorf(
+		"Failed to publish message to %s. Error: %v",
+		msg.Subject,
+		err)
+
+	if s.options.OnError != nil {
+		s.options.OnError(msg, err)
+	}
+}
+
+// pending returns the number of events buffered or waiting for an acknowledgement, it must
+// be called with the lock held
+func (s *asyncEventPublisherService) pending() int {
+	return len(s.queue) + s.inFlight
+}
@@ -0,0 +1,96 @@
+package eventpublisher_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_AsyncClose_Should_Flush_Pending_Events(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer robotBrokerService.Close()
+
+	received := make(chan *robotbroker.Message, 100)
+
+	_, err = robotBrokerService.Subscribe(
+		robotbroker.SUBJECT_ROBOT+"."+robotbroker.SUBJECT_FULL_WILDCARD,
+		func(msg *robotbroker.Message) error {
+			received <- msg
+
+			return nil
+		})
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewAsyncEventPublisherService(
+		sugarLogger,
+		"test",
+		codec,
+		robotBrokerService,
+		eventpublisher.AsyncPublishOptions{})
+	g.Expect(err).Should(BeNil())
+
+	for id := int64(0); id < 50; id++ {
+		err = sut.PublishRobotEvent(eventpublisher.RobotEvent{EventType: eventpublisher.RobotMoved, Id: id})
+		g.Expect(err).Should(BeNil())
+	}
+
+	g.Expect(sut.Close(time.Second)).Should(Succeed())
+	g.Expect(sut.Stats().Acknowledged).Should(Equal(uint64(50)))
+	g.Eventually(received).Should(HaveLen(50))
+
+	err = sut.PublishRobotEvent(eventpublisher.RobotEvent{EventType: eventpublisher.RobotMoved, Id: 1})
+	g.Expect(err).Should(Equal(eventpublisher.ErrPublisherClosed))
+}
+
+func Test_AsyncClose_Should_Return_Error_If_Events_Are_Not_Acknowledged_In_Time(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewAsyncEventPublisherService(
+		sugarLogger,
+		"test",
+		codec,
+		mockRobotBrokerService,
+		eventpublisher.AsyncPublishOptions{})
+	g.Expect(err).Should(BeNil())
+
+	// The acknowledgement never arrives
+	mockRobotBrokerService.
+		EXPECT().
+		PublishAsync(gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	err = sut.PublishTaskEvent(eventpublisher.TaskEvent{EventType: eventpublisher.TaskCreated, Id: 1})
+	g.Expect(err).Should(BeNil())
+
+	err = sut.Close(50 * time.Millisecond)
+	g.Expect(errors.Is(err, eventpublisher.ErrFlushTimeout)).Should(BeTrue())
+	g.Expect(sut.Stats().Pending).Should(Equal(1))
+}
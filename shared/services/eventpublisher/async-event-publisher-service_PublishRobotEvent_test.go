@@ -0,0 +1,241 @@
+package eventpublisher_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_AsyncPublishRobotEvent_Should_Coalesce_Buffered_Moves_Of_The_Same_Robot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewAsyncEventPublisherService(
+		sugarLogger,
+		"test",
+		codec,
+		mockRobotBrokerService,
+		eventpublisher.AsyncPublishOptions{CoalesceRobotMoves: true})
+	g.Expect(err).Should(BeNil())
+
+	handedOver := make(chan struct{})
+	release := make(chan struct{})
+	published := make(chan *robotbroker.Message, 10)
+
+	// The first event keeps the broker busy while the moves are buffered
+	first := mockRobotBrokerService.
+		EXPECT().
+		PublishAsync(gomock.Any(), gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message, onAck robotbroker.AckHandler) error {
+				close(handedOver)
+				<-release
+				published <- msg
+				onAck(nil)
+
+				return nil
+			})
+
+	mockRobotBrokerService.
+		EXPECT().
+		PublishAsync(gomock.Any(), gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message, onAck robotbroker.AckHandler) error {
+				published <- msg
+				onAck(nil)
+
+				return nil
+			}).
+		Times(3).
+		After(first)
+
+	err = sut.PublishTaskEvent(eventpublisher.TaskEvent{EventType: eventpublisher.TaskCreated, Id: 1})
+	g.Expect(err).Should(BeNil())
+
+	<-handedOver
+
+	for x := 1; x <= 3; x++ {
+		err = sut.PublishRobotEvent(eventpublisher.RobotEvent{
+			EventType: eventpublisher.RobotMoved,
+			Id:        7,
+			Data:      eventpublisher.RobotData{X: x},
+		})
+		g.Expect(err).Should(BeNil())
+	}
+
+	err = sut.PublishRobotEvent(eventpublisher.RobotEvent{EventType: eventpublisher.RobotFailedToMove, Id: 7})
+	g.Expect(err).Should(BeNil())
+
+	// A move after a failure is not merged into an earlier move
+	err = sut.PublishRobotEvent(eventpublisher.RobotEvent{
+		EventType: eventpublisher.RobotMoved,
+		Id:        7,
+		Data:      eventpublisher.RobotData{X: 4},
+	})
+	g.Expect(err).Should(BeNil())
+
+	close(release)
+
+	g.Expect(sut.Close(time.Second)).Should(Succeed())
+	g.Expect(sut.Stats()).Should(Equal(eventpublisher.AsyncPublishStats{
+		Published:    4,
+		Acknowledged: 4,
+		Coalesced:    2,
+	}))
+
+	close(published)
+
+	positions := make([]string, 0)
+	for msg := range published {
+		if msg.Subject == robotbroker.TaskSubject("1", "created") {
+			continue
+		}
+
+		var envelope eventpublisher.Envelope
+		g.Expect(json.Unmarshal(msg.Data, &envelope)).Should(Succeed())
+
+		var event eventpublisher.RobotEvent
+		g.Expect(json.Unmarshal(envelope.Data, &event)).Should(Succeed())
+
+		positions = append(positions, fmt.Sprintf("%s:%d", event.EventType, event.Data.X))
+	}
+
+	g.Expect(positions).Should(Equal([]string{"Moved:3", "FailedToMove:0", "Moved:4"}))
+}
+
+func Test_AsyncPublishRobotEvent_Should_Block_While_Too_Many_Events_Are_Pending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewAsyncEventPublisherService(
+		sugarLogger,
+		"test",
+		codec,
+		mockRobotBrokerService,
+		eventpublisher.AsyncPublishOptions{MaxPending: 2})
+	g.Expect(err).Should(BeNil())
+
+	acks := make(chan robotbroker.AckHandler, 10)
+
+	mockRobotBrokerService.
+		EXPECT().
+		PublishAsync(gomock.Any(), gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message, onAck robotbroker.AckHandler) error {
+				acks <- onAck
+
+				return nil
+			}).
+		Times(3)
+
+	for id := int64(1); id <= 2; id++ {
+		err = sut.PublishRobotEvent(eventpublisher.RobotEvent{EventType: eventpublisher.RobotMoved, Id: id})
+		g.Expect(err).Should(BeNil())
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sut.PublishRobotEvent(eventpublisher.RobotEvent{EventType: eventpublisher.RobotMoved, Id: 3})
+	}()
+
+	g.Consistently(done, 100*time.Millisecond).ShouldNot(Receive())
+
+	(<-acks)(nil)
+
+	g.Eventually(done).Should(Receive(BeNil()))
+
+	(<-acks)(nil)
+	(<-acks)(nil)
+
+	g.Expect(sut.Close(time.Second)).Should(Succeed())
+}
+
+func Test_AsyncPublishRobotEvent_Should_Report_Failed_Acknowledgements(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	failures := make(chan *robotbroker.Message, 10)
+
+	sut, err := eventpublisher.NewAsyncEventPublisherService(
+		sugarLogger,
+		"test",
+		codec,
+		mockRobotBrokerService,
+		eventpublisher.AsyncPublishOptions{
+			OnError: func(msg *robotbroker.Message, err error) {
+				failures <- msg
+			},
+		})
+	g.Expect(err).Should(BeNil())
+
+	mockRobotBrokerService.
+		EXPECT().
+		PublishAsync(gomock.Any(), gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message, onAck robotbroker.AckHandler) error {
+				onAck(errors.New("stream is full"))
+
+				return nil
+			})
+
+	mockRobotBrokerService.
+		EXPECT().
+		PublishAsync(gomock.Any(), gomock.Any()).
+		Return(errors.New("connection closed"))
+
+	err = sut.PublishRobotEvent(eventpublisher.RobotEvent{EventType: eventpublisher.RobotMoved, Id: 1})
+	g.Expect(err).Should(BeNil())
+
+	err = sut.PublishRobotEvent(eventpublisher.RobotEvent{EventType: eventpublisher.RobotMoved, Id: 2})
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.Flush(time.Second)).Should(Succeed())
+
+	g.Expect(failures).Should(HaveLen(2))
+	g.Expect(sut.Stats()).Should(Equal(eventpublisher.AsyncPublishStats{
+		Published: 2,
+		Failed:    2,
+	}))
+
+	g.Expect(sut.Close(time.Second)).Should(Succeed())
+}
@@ -1,70 +1,142 @@
 package eventpublisher
 
-// TaskEventType describe task state
-type TaskEventType string
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
 
-const (
-	// TaskCreated is used to denote a task event that is Created
-	TaskCreated TaskEventType = "Created"
-	// TaskCompleted is used to denote a task event that is Completed
-	TaskCompleted TaskEventType = "Completed"
-	// TaskCancelled is used to denote a task event that is Cancelled
-	TaskCancelled TaskEventType = "Cancelled"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 )
 
-// TaskEvent describes a task event
-type TaskEvent struct {
-	EventType TaskEventType `json:"EventType"`
-	Id        int           `json:"Id"`
-	Data      TaskData      `json:"Data,omitempty"`
-}
+// The event types are generated from api-definitions/asyncapi/Robot_Events.yaml, see events.gen.go
 
-// MoveRobotRequestMoveSequence describes a movement code
-type MoveRobotRequestMoveSequence string
+// SpecVersion is the version of the envelope specification every event is wrapped in
+const SpecVersion = "1.0"
 
 const (
-	// EAST denotes a movement code towards EAST
-	EAST MoveRobotRequestMoveSequence = "E"
-	// NORTH denotes a movement code towards NORTH
-	NORTH MoveRobotRequestMoveSequence = "N"
-	// SOUTH denotes a movement code towards SOUTH
-	SOUTH MoveRobotRequestMoveSequence = "S"
-	// WEST denotes a movement code towards WEST
-	WEST MoveRobotRequestMoveSequence = "W"
+	// TaskEnvelopeType is the envelope type of a TaskEvent
+	TaskEnvelopeType = "robots.task"
+	// RobotEnvelopeType is the envelope type of a RobotEvent
+	RobotEnvelopeType = "robots.robot"
+	// WarehouseEnvelopeType is the envelope type of a WarehouseEvent
+	WarehouseEnvelopeType = "robots.warehouse"
 )
 
-// TaskData describes task data
-type TaskData struct {
-	RobotId        int64                          `json:"RobotId"`
-	MoveSequeneces []MoveRobotRequestMoveSequence `json:"MoveSequeneces"`
-}
-
-// RobotMovedEventType descries a robot movement event type
-type RobotMovedEventType string
-
 const (
-	// RobotMoved is used when a RobotMoved
-	RobotMoved RobotMovedEventType = "Moved"
-	// RobotFailedToMove is used when a RobotFailedToMove
-	RobotFailedToMove RobotMovedEventType = "FailedToMove"
+	// TaskEventSchemaVersion is the current schema version of TaskEvent
+	TaskEventSchemaVersion = 1
+	// RobotEventSchemaVersion is the current schema version of RobotEvent
+	RobotEventSchemaVersion = 1
+	// WarehouseEventSchemaVersion is the current schema version of WarehouseEvent
+	WarehouseEventSchemaVersion = 1
 )
 
-// RobotEvent describe a RobotEvent
-type RobotEvent struct {
-	EventType    RobotMovedEventType `json:"EventType"`
-	Id           int64               `json:"Id"`
-	Data         RobotData           `json:"Data,omitempty"`
-	ErrorMessage string              `json:"ErrorMessage,omitempty"`
+// Envelope is a CloudEvents style envelope every event is published in
+type Envelope struct {
+	SpecVersion   string    `json:"specversion"`
+	Id            string    `json:"id"`
+	Source        string    `json:"source"`
+	Time          time.Time `json:"time"`
+	Type          string    `json:"type"`
+	SchemaVersion int       `json:"schemaversion"`
+	// Data holds the event encoded with the same codec as the envelope
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// SubjectToken returns the subject token the task event type is published under
+func (t TaskEventType) SubjectToken() string {
+	return strings.ToLower(string(t))
 }
 
-// RobotData show robot's location on grid
-type RobotData struct {
-	X int `json:"X"`
-	Y int `json:"Y"`
+// SubjectToken returns the subject token the robot event type is published under
+func (t RobotMovedEventType) SubjectToken() string {
+	return strings.ToLower(string(t))
+}
+
+// SubjectToken returns the subject token the warehouse event type is published under
+func (t WarehouseEventType) SubjectToken() string {
+	return strings.ToLower(string(t))
 }
 
 // EventPublisherInterface defines contract for event publishers
 type EventPublisherInterface interface {
 	PublishTaskEvent(event TaskEvent) error
 	PublishRobotEvent(event RobotEvent) error
+	PublishWarehouseEvent(event WarehouseEvent) error
+}
+
+// DefaultMaxPendingPublishes is the number of events an asynchronous publisher buffers or
+// waits for the acknowledgement of unless told otherwise
+const DefaultMaxPendingPublishes = 256
+
+var (
+	// ErrPublisherClosed is returned when publishing with a publisher which has been closed
+	ErrPublisherClosed = errors.New("publisher is closed")
+	// ErrFlushTimeout is returned when events are still pending once a flush times out
+	ErrFlushTimeout = errors.New("timed out flushing pending events")
+)
+
+// AsyncPublishOptions configures an asynchronous event publisher
+type AsyncPublishOptions struct {
+	// MaxPending bounds the number of events buffered or waiting for an acknowledgement,
+	// publishing blocks once it is reached. Defaults to DefaultMaxPendingPublishes
+	MaxPending int
+	// CoalesceRobotMoves replaces a buffered Moved event by the next position of the same
+	// robot, so only the latest position is published when the broker falls behind
+	CoalesceRobotMoves bool
+	// OnError is called with every message the broker failed to persist
+	OnError func(msg *robotbroker.Message, err error)
+}
+
+// AsyncPublishStats counts the events of an asynchronous event publisher
+type AsyncPublishStats struct {
+	// Published is the number of events handed over to the broker
+	Published uint64
+	// Acknowledged is the number of events the broker has persisted
+	Acknowledged uint64
+	// Failed is the number of events the broker failed to persist
+	Failed uint64
+	// Coalesced is the number of Moved events replaced by a later position
+	Coalesced uint64
+	// Pending is the number of events buffered or waiting for an acknowledgement
+	Pending int
+}
+
+// AsyncEventPublisherInterface defines contract for event publishers which do not wait for
+// the broker to persist every event, publishing only fails if the event can not be encoded
+// or the publisher is closed
+type AsyncEventPublisherInterface interface {
+	EventPublisherInterface
+	// Flush waits until every event published so far has been acknowledged or has failed
+	Flush(timeout time.Duration) error
+	// Close stops accepting events and flushes the pending ones
+	Close(timeout time.Duration) error
+	Stats() AsyncPublishStats
+}
+
+// TaskAcceptance is the reply of a warehouse to a task it has been asked to accept, it is
+// always encoded as JSON
+type TaskAcceptance struct {
+	Accepted bool `json:"accepted"`
+	// Reason explains why the task has been rejected
+	Reason string `json:"reason,omitempty"`
+}
+
+// TaskRequesterInterface defines contract for sending tasks to a warehouse and waiting for it
+// to accept or reject them
+type TaskRequesterInterface interface {
+	// RequestTask returns robotbroker.ErrNoResponders when no warehouse is listening and
+	// robotbroker.ErrRequestTimeout when none replied in time
+	RequestTask(event TaskEvent, timeout time.Duration) (TaskAcceptance, error)
+}
+
+// Upcaster converts the data of an event from one schema version to the next one
+type Upcaster func(data json.RawMessage) (json.RawMessage, error)
+
+// EventDecoderInterface defines contract for decoding enveloped events
+type EventDecoderInterface interface {
+	Register(envelopeType string, schemaVersion int, event interface{})
+	RegisterUpcaster(envelopeType string, fromSchemaVersion int, upcaster Upcaster)
+	Decode(contentType string, buf []byte, event interface{}) (Envelope, error)
 }
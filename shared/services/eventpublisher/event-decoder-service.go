@@ -0,0 +1,235 @@
+package eventpublisher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+)
+
+var (
+	// ErrUnregisteredEvent is returned when decoding into an event which was never registered
+	ErrUnregisteredEvent = errors.New("event is not registered")
+	// ErrUnexpectedEnvelopeType is returned when an envelope does not carry the requested event
+	ErrUnexpectedEnvelopeType = errors.New("unexpected envelope type")
+	// ErrUnsupportedSchemaVersion is returned when an event can not be upcasted to the current schema
+	ErrUnsupportedSchemaVersion = errors.New("unsupported schema version")
+)
+
+type eventRegistration struct {
+	envelopeType  string
+	schemaVersion int
+}
+
+type eventDecoderService struct {
+	registrations map[reflect.Type]eventRegistration
+	upcasters     map[string]map[int]Upcaster
+	mutex         *sync.RWMutex
+}
+
+// NewEventDecoderService creates a concrete instance of EventDecoderInterface which knows
+// about the events of this package
+func NewEventDecoderService() (EventDecoderInterface, error) {
+	eventDecoderService := &eventDecoderService{
+		registrations: make(map[reflect.Type]eventRegistration),
+		upcasters:     make(map[string]map[int]Upcaster),
+		mutex:         &sync.RWMutex{},
+	}
+
+	eventDecoderService.Register(TaskEnvelopeType, TaskEventSchemaVersion, TaskEvent{})
+	eventDecoderService.Register(RobotEnvelopeType, RobotEventSchemaVersion, RobotEvent{})
+	eventDecoderService.Register(WarehouseEnvelopeType, WarehouseEventSchemaVersion, WarehouseEvent{})
+
+	// Events published before the envelope was introduced have the same shape as version 1
+	eventDecoderService.RegisterUpcaster(TaskEnvelopeType, 0, keepData)
+	eventDecoderService.RegisterUpcaster(RobotEnvelopeType, 0, keepData)
+
+	return eventDecoderService, nil
+}
+
+// Register binds an event type to its envelope type and its current schema version
+func (s *eventDecoderService) Register(
+	envelopeType string,
+	schemaVersion int,
+	event interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.registrations[indirectType(event)] = eventRegistration{
+		envelopeType:  envelopeType,
+		schemaVersion: schemaVersion,
+	}
+}
+
+// RegisterUpcaster registers a function which converts the data of an envelope type from
+// the given schema version to the next one
+func (s *eventDecoderService) RegisterUpcaster(
+	envelopeType string,
+	fromSchemaVersion int,
+	upcaster Upcaster) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, found := s.upcasters[envelopeType]; !found {
+		s.upcasters[envelopeType] = make(map[int]Upcaster)
+	}
+
+	s.upcasters[envelopeType][fromSchemaVersion] = upcaster
+}
+
+// Decode opens the envelope, which is encoded with the codec announced by the content type,
+// upcasts its data to the current schema version of the event and de-serializes it into
+// the given event
+func (s *eventDecoderService) Decode(
+	contentType string,
+	buf []byte,
+	event interface{}) (Envelope, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	eventType := indirectType(event)
+
+	registration, found := s.registrations[eventType]
+	if !found {
+		return Envelope{}, fmt.Errorf("%w: %T", ErrUnregisteredEvent, event)
+	}
+
+	codec, err := eventcodec.NewCodecForContentType(contentType)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	envelope, err := openEnvelope(codec, buf, registration.envelopeType)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	if envelope.Type != registration.envelopeType {
+		return envelope, fmt.Errorf(
+			"%w: expected %s, received %s",
+			ErrUnexpectedEnvelopeType,
+			registration.envelopeType,
+			envelope.Type)
+	}
+
+	if envelope.SchemaVersion > registration.schemaVersion {
+		return envelope, fmt.Errorf(
+			"%w: %s version %d is newer than %d",
+			ErrUnsupportedSchemaVersion,
+			envelope.Type,
+			envelope.SchemaVersion,
+			registration.schemaVersion)
+	}
+
+	if len(envelope.Data) == 0 {
+		return envelope, nil
+	}
+
+	if envelope.SchemaVersion == registration.schemaVersion {
+		return envelope, codec.Unmarshal(envelope.Data, event)
+	}
+
+	// Upcasters work on JSON, whatever the codec of the envelope is
+	data, err := toJSON(codec, envelope.Data, eventType)
+	if err != nil {
+		return envelope, err
+	}
+
+	for version := envelope.SchemaVersion; version < registration.schemaVersion; version++ {
+		upcaster, found := s.upcasters[envelope.Type][version]
+		if !found {
+			return envelope, fmt.Errorf(
+				"%w: no upcaster for %s version %d",
+				ErrUnsupportedSchemaVersion,
+				envelope.Type,
+				version)
+		}
+
+		if data, err = upcaster(data); err != nil {
+			return envelope, err
+		}
+	}
+
+	return envelope, json.Unmarshal(data, event)
+}
+
+func openEnvelope(
+	codec eventcodec.CodecInterface,
+	buf []byte,
+	envelopeType string) (Envelope, error) {
+	envelope := Envelope{}
+
+	if codec.Name() != eventcodec.JSON {
+		return envelope, codec.Unmarshal(buf, &envelope)
+	}
+
+	header := struct {
+		SpecVersion string `json:"specversion"`
+	}{}
+
+	if err := json.Unmarshal(buf, &header); err != nil {
+		return envelope, err
+	}
+
+	// Events which are not enveloped predate the envelope, they are schema version 0
+	if header.SpecVersion == "" {
+		return Envelope{
+			Type:          envelopeType,
+			SchemaVersion: 0,
+			Data:          buf,
+		}, nil
+	}
+
+	if header.SpecVersion != SpecVersion {
+		return envelope, fmt.Errorf("unsupported envelope spec version %s", header.SpecVersion)
+	}
+
+	if err := json.Unmarshal(buf, &envelope); err != nil {
+		return envelope, err
+	}
+
+	return envelope, nil
+}
+
+// toJSON converts the data of an envelope to JSON. Protobuf data has no self describing
+// structure, so it is read with the current definition of the event
+func toJSON(
+	codec eventcodec.CodecInterface,
+	data []byte,
+	eventType reflect.Type) (json.RawMessage, error) {
+	switch codec.Name() {
+	case eventcodec.JSON:
+		return data, nil
+
+	case eventcodec.PROTOBUF:
+		event := reflect.New(eventType).Interface()
+		if err := codec.Unmarshal(data, event); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(event)
+	}
+
+	var document interface{}
+	if err := codec.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(document)
+}
+
+func indirectType(event interface{}) reflect.Type {
+	eventType := reflect.TypeOf(event)
+	for eventType != nil && eventType.Kind() == reflect.Ptr {
+		eventType = eventType.Elem()
+	}
+
+	return eventType
+}
+
+func keepData(data json.RawMessage) (json.RawMessage, error) {
+	return data, nil
+}
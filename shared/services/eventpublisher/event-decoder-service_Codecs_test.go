@@ -0,0 +1,200 @@
+package eventpublisher_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_Decode_Should_Decode_Events_Published_With_Every_Codec(t *testing.T) {
+	for _, codecName := range []string{eventcodec.JSON, eventcodec.PROTOBUF, eventcodec.MSGPACK} {
+		t.Run(codecName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+			g := NewGomegaWithT(t)
+
+			logger, err := zap.NewDevelopment()
+			sugarLogger := logger.Sugar()
+			g.Expect(err).Should(BeNil())
+
+			codec, err := eventcodec.NewCodec(codecName)
+			g.Expect(err).Should(BeNil())
+
+			source := cuid.New()
+
+			publisher, err := eventpublisher.NewEventPublisherService(
+				sugarLogger,
+				source,
+				codec,
+				mockRobotBrokerService)
+			g.Expect(err).Should(BeNil())
+
+			sut, err := eventpublisher.NewEventDecoderService()
+			g.Expect(err).Should(BeNil())
+
+			published := make([]*robotbroker.Message, 0)
+
+			mockRobotBrokerService.
+				EXPECT().
+				Publish(gomock.Any()).
+				DoAndReturn(
+					func(msg *robotbroker.Message) error {
+						published = append(published, msg)
+
+						return nil
+					}).
+				Times(4)
+
+			taskEvent := eventpublisher.TaskEvent{
+				EventType: eventpublisher.TaskCreated,
+				Id:        int64(rand.Intn(10000)),
+				Data: eventpublisher.TaskData{
+					RobotId: int64(rand.Intn(10000)),
+					MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{
+						eventpublisher.WEST,
+						eventpublisher.SOUTH,
+					},
+				},
+			}
+
+			failedTaskEvent := eventpublisher.TaskEvent{
+				EventType: eventpublisher.TaskFailed,
+				Id:        int64(rand.Intn(10000)),
+				Data: eventpublisher.TaskData{
+					RobotId: int64(rand.Intn(10000)),
+					Trajectory: []eventpublisher.RobotData{
+						{X: rand.Intn(10000), Y: rand.Intn(10000)},
+						{X: rand.Intn(10000), Y: rand.Intn(10000), HasCrate: true},
+					},
+					FailureReason: cuid.New(),
+				},
+			}
+
+			batchEvent := eventpublisher.TaskEvent{
+				EventType: eventpublisher.TaskBatchCreated,
+				Id:        int64(rand.Intn(10000)),
+				Data: eventpublisher.TaskData{
+					BatchId: cuid.New(),
+					Tasks: []eventpublisher.BatchTask{
+						{
+							Id:      int64(rand.Intn(10000)),
+							RobotId: int64(rand.Intn(10000)),
+							MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{
+								eventpublisher.NORTH,
+							},
+						},
+						{
+							Id:      int64(rand.Intn(10000)),
+							RobotId: int64(rand.Intn(10000)),
+							MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{
+								eventpublisher.EAST,
+								eventpublisher.EAST,
+							},
+						},
+					},
+					Accepted: true,
+				},
+			}
+
+			robotEvent := eventpublisher.RobotEvent{
+				EventType: eventpublisher.RobotFailedToMove,
+				Id:        int64(rand.Intn(10000)),
+				Data: eventpublisher.RobotData{
+					X:        rand.Intn(10000),
+					Y:        rand.Intn(10000),
+					HasCrate: true,
+				},
+				ErrorMessage: cuid.New(),
+			}
+
+			g.Expect(publisher.PublishTaskEvent(taskEvent)).Should(BeNil())
+			g.Expect(publisher.PublishRobotEvent(robotEvent)).Should(BeNil())
+			g.Expect(publisher.PublishTaskEvent(failedTaskEvent)).Should(BeNil())
+			g.Expect(publisher.PublishTaskEvent(batchEvent)).Should(BeNil())
+
+			g.Expect(published[0].Header.Get(eventcodec.HEADER_CONTENT_TYPE)).
+				Should(Equal(codec.ContentType()))
+
+			decodedTaskEvent := eventpublisher.TaskEvent{}
+			envelope, err := sut.Decode(
+				published[0].Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+				published[0].Data,
+				&decodedTaskEvent)
+			g.Expect(err).Should(BeNil())
+			g.Expect(envelope.Source).Should(Equal(source))
+			g.Expect(envelope.Type).Should(Equal(eventpublisher.TaskEnvelopeType))
+			g.Expect(decodedTaskEvent).Should(Equal(taskEvent))
+
+			decodedRobotEvent := eventpublisher.RobotEvent{}
+			envelope, err = sut.Decode(
+				published[1].Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+				published[1].Data,
+				&decodedRobotEvent)
+			g.Expect(err).Should(BeNil())
+			g.Expect(envelope.Type).Should(Equal(eventpublisher.RobotEnvelopeType))
+			g.Expect(decodedRobotEvent).Should(Equal(robotEvent))
+
+			decodedFailedTaskEvent := eventpublisher.TaskEvent{}
+			_, err = sut.Decode(
+				published[2].Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+				published[2].Data,
+				&decodedFailedTaskEvent)
+			g.Expect(err).Should(BeNil())
+			g.Expect(decodedFailedTaskEvent).Should(Equal(failedTaskEvent))
+
+			decodedBatchEvent := eventpublisher.TaskEvent{}
+			_, err = sut.Decode(
+				published[3].Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+				published[3].Data,
+				&decodedBatchEvent)
+			g.Expect(err).Should(BeNil())
+			g.Expect(decodedBatchEvent).Should(Equal(batchEvent))
+		})
+	}
+}
+
+func Test_Decode_Should_Upcast_Events_Encoded_With_MessagePack(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	codec, err := eventcodec.NewCodec(eventcodec.MSGPACK)
+	g.Expect(err).Should(BeNil())
+
+	event := eventpublisher.RobotEvent{
+		EventType: eventpublisher.RobotMoved,
+		Id:        int64(rand.Intn(10000)),
+		Data: eventpublisher.RobotData{
+			X: rand.Intn(10000),
+			Y: rand.Intn(10000),
+		},
+	}
+
+	data, err := codec.Marshal(event)
+	g.Expect(err).Should(BeNil())
+
+	buf, err := codec.Marshal(eventpublisher.Envelope{
+		SpecVersion:   eventpublisher.SpecVersion,
+		Type:          eventpublisher.RobotEnvelopeType,
+		SchemaVersion: 0,
+		Data:          data,
+	})
+	g.Expect(err).Should(BeNil())
+
+	decodedEvent := eventpublisher.RobotEvent{}
+	_, err = sut.Decode(codec.ContentType(), buf, &decodedEvent)
+	g.Expect(err).Should(BeNil())
+	g.Expect(decodedEvent).Should(Equal(event))
+}
@@ -0,0 +1,155 @@
+package eventpublisher_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+)
+
+func Test_Decode_Should_Open_Envelope_And_Deserialize_Event(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	event := eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCreated,
+		Id:        int64(rand.Intn(10000)),
+		Data: eventpublisher.TaskData{
+			RobotId: int64(rand.Intn(10000)),
+			MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{
+				eventpublisher.NORTH,
+			},
+		},
+	}
+
+	data, err := json.Marshal(event)
+	g.Expect(err).Should(BeNil())
+
+	expectedEnvelope := eventpublisher.Envelope{
+		SpecVersion:   eventpublisher.SpecVersion,
+		Id:            cuid.New(),
+		Source:        cuid.New(),
+		Time:          time.Now().UTC(),
+		Type:          eventpublisher.TaskEnvelopeType,
+		SchemaVersion: eventpublisher.TaskEventSchemaVersion,
+		Data:          data,
+	}
+
+	buf, err := json.Marshal(expectedEnvelope)
+	g.Expect(err).Should(BeNil())
+
+	decodedEvent := eventpublisher.TaskEvent{}
+	envelope, err := sut.Decode(eventcodec.CONTENT_TYPE_JSON, buf, &decodedEvent)
+	g.Expect(err).Should(BeNil())
+	g.Expect(decodedEvent).Should(Equal(event))
+	g.Expect(envelope.Id).Should(Equal(expectedEnvelope.Id))
+	g.Expect(envelope.Source).Should(Equal(expectedEnvelope.Source))
+	g.Expect(envelope.Time.Equal(expectedEnvelope.Time)).Should(BeTrue())
+}
+
+func Test_Decode_Should_Accept_Events_Published_Without_Envelope(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	event := eventpublisher.RobotEvent{
+		EventType: eventpublisher.RobotMoved,
+		Id:        int64(rand.Intn(10000)),
+		Data: eventpublisher.RobotData{
+			X: rand.Intn(10000),
+			Y: rand.Intn(10000),
+		},
+	}
+
+	buf, err := json.Marshal(event)
+	g.Expect(err).Should(BeNil())
+
+	decodedEvent := eventpublisher.RobotEvent{}
+	envelope, err := sut.Decode("", buf, &decodedEvent)
+	g.Expect(err).Should(BeNil())
+	g.Expect(envelope.SchemaVersion).Should(BeZero())
+	g.Expect(decodedEvent).Should(Equal(event))
+}
+
+func Test_Decode_Should_Upcast_Older_Schema_Versions(t *testing.T) {
+	type renamedEvent struct {
+		Name string `json:"Name"`
+	}
+
+	g := NewGomegaWithT(t)
+
+	sut, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut.Register("test.renamed", 2, renamedEvent{})
+	sut.RegisterUpcaster("test.renamed", 1, func(data json.RawMessage) (json.RawMessage, error) {
+		old := struct {
+			Title string `json:"Title"`
+		}{}
+
+		if err := json.Unmarshal(data, &old); err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(renamedEvent{Name: old.Title})
+	})
+
+	expectedName := cuid.New()
+
+	buf, err := json.Marshal(eventpublisher.Envelope{
+		SpecVersion:   eventpublisher.SpecVersion,
+		Type:          "test.renamed",
+		SchemaVersion: 1,
+		Data:          json.RawMessage(`{"Title":"` + expectedName + `"}`),
+	})
+	g.Expect(err).Should(BeNil())
+
+	decodedEvent := renamedEvent{}
+	_, err = sut.Decode(eventcodec.CONTENT_TYPE_JSON, buf, &decodedEvent)
+	g.Expect(err).Should(BeNil())
+	g.Expect(decodedEvent.Name).Should(Equal(expectedName))
+}
+
+func Test_Decode_Should_Return_Error_For_Newer_Schema_Versions(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	buf, err := json.Marshal(eventpublisher.Envelope{
+		SpecVersion:   eventpublisher.SpecVersion,
+		Type:          eventpublisher.TaskEnvelopeType,
+		SchemaVersion: eventpublisher.TaskEventSchemaVersion + 1,
+		Data:          json.RawMessage(`{}`),
+	})
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.Decode(eventcodec.CONTENT_TYPE_JSON, buf, &eventpublisher.TaskEvent{})
+	g.Expect(err).Should(MatchError(eventpublisher.ErrUnsupportedSchemaVersion))
+}
+
+func Test_Decode_Should_Return_Error_For_Unexpected_Envelope_Type(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	buf, err := json.Marshal(eventpublisher.Envelope{
+		SpecVersion:   eventpublisher.SpecVersion,
+		Type:          eventpublisher.RobotEnvelopeType,
+		SchemaVersion: eventpublisher.RobotEventSchemaVersion,
+		Data:          json.RawMessage(`{}`),
+	})
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.Decode(eventcodec.CONTENT_TYPE_JSON, buf, &eventpublisher.TaskEvent{})
+	g.Expect(err).Should(MatchError(eventpublisher.ErrUnexpectedEnvelopeType))
+}
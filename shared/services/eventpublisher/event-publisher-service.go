@@ -1,50 +1,78 @@
 package eventpublisher
 
 import (
-	"encoding/json"
+	"errors"
+	"time"
 
+	"github.com/lucsky/cuid"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
-	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 )
 
 type eventPublisherService struct {
 	logger    *zap.SugaredLogger
-	jetStream nats.JetStreamContext
+	source    string
+	codec     eventcodec.CodecInterface
+	publisher robotbroker.PublisherInterface
 }
 
-// NewEventPublisherService creates a concerete instance of EventPublisherInterface
+// NewEventPublisherService creates a concerete instance of EventPublisherInterface, source
+// names the service the published events originate from and codec is the wire encoding
+// the events are published with. Events are usually published to a broker but any publisher,
+// like an outbox, will do
 func NewEventPublisherService(
 	logger *zap.SugaredLogger,
-	robotBrokerService robotbroker.RobotBrokerInterface) (EventPublisherInterface, error) {
-	eventPublisherService := eventPublisherService{
-		logger: logger,
+	source string,
+	codec eventcodec.CodecInterface,
+	publisher robotbroker.PublisherInterface) (EventPublisherInterface, error) {
+	if codec == nil || publisher == nil {
+		return nil, errors.New("an event publisher requires a codec and a broker")
 	}
 
-	jetStream, err := robotBrokerService.CreateNewJetStream()
+	return &eventPublisherService{
+		logger:    logger,
+		source:    source,
+		codec:     codec,
+		publisher: publisher,
+	}, nil
+}
+
+// PublishTaskEvent publishes task event on event queue
+func (s *eventPublisherService) PublishTaskEvent(event TaskEvent) error {
+	msg, err := s.newTaskEventMsg(event)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	eventPublisherService.jetStream = jetStream
-
-	return &eventPublisherService, nil
+	return s.publish(msg)
 }
 
-// PublishTaskEvent publishes task event on event queue
-func (s *eventPublisherService) PublishTaskEvent(event TaskEvent) error {
-	buf, err := json.Marshal(event)
+// PublishTaskEvent publishes robot event on event queue
+func (s *eventPublisherService) PublishRobotEvent(event RobotEvent) error {
+	msg, err := s.newRobotEventMsg(event)
 	if err != nil {
-		s.logger.Errorf(
-			"Failed to serialize TaskEvent message to json. Error: %v", err)
+		return err
+	}
+
+	return s.publish(msg)
+}
 
+// PublishWarehouseEvent publishes warehouse event on event queue
+func (s *eventPublisherService) PublishWarehouseEvent(event WarehouseEvent) error {
+	msg, err := s.newWarehouseEventMsg(event)
+	if err != nil {
 		return err
 	}
 
-	if _, err := s.jetStream.Publish(robotbroker.SUBJECT_TASK, buf); err != nil {
+	return s.publish(msg)
+}
+
+func (s *eventPublisherService) publish(msg *robotbroker.Message) error {
+	if err := s.publisher.Publish(msg); err != nil {
 		s.logger.Errorf(
 			"Failed to publish message to %s. Error: %v",
-			robotbroker.SUBJECT_TASK,
+			msg.Subject,
 			err)
 
 		return err
@@ -53,24 +81,112 @@ func (s *eventPublisherService) PublishTaskEvent(event TaskEvent) error {
 	return nil
 }
 
-// PublishTaskEvent publishes robot event on event queue
-func (s *eventPublisherService) PublishRobotEvent(event RobotEvent) error {
-	buf, err := json.Marshal(event)
+// newTaskEventMsg seals a task event in a message published on the subject of the task
+func (s *eventPublisherService) newTaskEventMsg(event TaskEvent) (*robotbroker.Message, error) {
+	if event.EventType == "" {
+		return nil, errors.New("can not publish a TaskEvent without an event type")
+	}
+
+	buf, err := s.seal(TaskEnvelopeType, TaskEventSchemaVersion, event)
 	if err != nil {
 		s.logger.Errorf(
-			"Failed to serialize RobotEvent message to json. Error: %v", err)
+			"Failed to serialize TaskEvent message to %s. Error: %v",
+			s.codec.Name(),
+			err)
 
-		return err
+		return nil, err
+	}
+
+	subject := robotbroker.TaskSubject(
+		robotbroker.FormatId(event.Id),
+		event.EventType.SubjectToken())
+
+	return s.newMsg(subject, buf), nil
+}
+
+// newRobotEventMsg seals a robot event in a message published on the subject of the robot
+func (s *eventPublisherService) newRobotEventMsg(event RobotEvent) (*robotbroker.Message, error) {
+	if event.EventType == "" {
+		return nil, errors.New("can not publish a RobotEvent without an event type")
 	}
 
-	if _, err := s.jetStream.Publish(robotbroker.SUBJECT_ROBOT, buf); err != nil {
+	buf, err := s.seal(RobotEnvelopeType, RobotEventSchemaVersion, event)
+	if err != nil {
 		s.logger.Errorf(
-			"Failed to publish message to %s. Error: %v",
-			robotbroker.SUBJECT_ROBOT,
+			"Failed to serialize RobotEvent message to %s. Error: %v",
+			s.codec.Name(),
 			err)
 
-		return err
+		return nil, err
 	}
 
-	return nil
+	warehouse := event.Warehouse
+	if warehouse == "" {
+		warehouse = robotbroker.DEFAULT_WAREHOUSE
+	}
+
+	subject := robotbroker.RobotSubject(
+		warehouse,
+		robotbroker.FormatId(event.Id),
+		event.EventType.SubjectToken())
+
+	return s.newMsg(subject, buf), nil
+}
+
+// newWarehouseEventMsg seals a warehouse event in a message published on the subject of the
+// warehouse
+func (s *eventPublisherService) newWarehouseEventMsg(event WarehouseEvent) (*robotbroker.Message, error) {
+	if event.EventType == "" {
+		return nil, errors.New("can not publish a WarehouseEvent without an event type")
+	}
+
+	if event.Warehouse == "" {
+		event.Warehouse = robotbroker.DEFAULT_WAREHOUSE
+	}
+
+	buf, err := s.seal(WarehouseEnvelopeType, WarehouseEventSchemaVersion, event)
+	if err != nil {
+		s.logger.Errorf(
+			"Failed to serialize WarehouseEvent message to %s. Error: %v",
+			s.codec.Name(),
+			err)
+
+		return nil, err
+	}
+
+	subject := robotbroker.WarehouseSubject(
+		event.Warehouse,
+		event.EventType.SubjectToken())
+
+	return s.newMsg(subject, buf), nil
+}
+
+// seal wraps an event in an envelope and serializes both with the codec
+func (s *eventPublisherService) seal(
+	envelopeType string,
+	schemaVersion int,
+	event interface{}) ([]byte, error) {
+	data, err := s.codec.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.codec.Marshal(Envelope{
+		SpecVersion:   SpecVersion,
+		Id:            cuid.New(),
+		Source:        s.source,
+		Time:          time.Now().UTC(),
+		Type:          envelopeType,
+		SchemaVersion: schemaVersion,
+		Data:          data,
+	})
+}
+
+// newMsg creates a message which announces the codec it is encoded with
+func (s *eventPublisherService) newMsg(subject string, buf []byte) *robotbroker.Message {
+	msg := robotbroker.NewMessage(subject)
+	msg.Header.Set(eventcodec.HEADER_CONTENT_TYPE, s.codec.ContentType())
+	msg.Data = buf
+
+	return msg
 }
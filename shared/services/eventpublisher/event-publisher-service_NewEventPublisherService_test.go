@@ -1,29 +1,22 @@
 package eventpublisher_test
 
 import (
-	"errors"
 	"testing"
 
-	. "github.com/sepisoad/robot-challange/shared/nats-mocks/mock"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/lucsky/cuid"
 	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
 	"go.uber.org/zap"
 )
 
-func Test_NewEventPublisherService_Should_Call_CreateNewJetStream(t *testing.T) {
+func Test_NewEventPublisherService_Should_Not_Publish_On_Creation(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
-	mockJetStreamContext := NewMockJetStreamContext(ctrl)
-
-	mockRobotBrokerService.
-		EXPECT().
-		CreateNewJetStream().
-		Return(mockJetStreamContext, nil)
 
 	g := NewGomegaWithT(t)
 
@@ -31,23 +24,18 @@ func Test_NewEventPublisherService_Should_Call_CreateNewJetStream(t *testing.T)
 	sugarLogger := logger.Sugar()
 	g.Expect(err).Should(BeNil())
 
-	_, err = eventpublisher.NewEventPublisherService(sugarLogger, mockRobotBrokerService)
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	_, err = eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
 	g.Expect(err).Should(BeNil())
 }
 
-func Test_NewEventPublisherService_Should_Return_Error_If_CreateNewJetStream_Returns_Error(t *testing.T) {
+func Test_NewEventPublisherService_Should_Return_Error_If_Codec_Is_Missing(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
-	mockJetStreamContext := NewMockJetStreamContext(ctrl)
-
-	expectedErr := errors.New(cuid.New())
-
-	mockRobotBrokerService.
-		EXPECT().
-		CreateNewJetStream().
-		Return(mockJetStreamContext, expectedErr)
 
 	g := NewGomegaWithT(t)
 
@@ -55,6 +43,8 @@ func Test_NewEventPublisherService_Should_Return_Error_If_CreateNewJetStream_Ret
 	sugarLogger := logger.Sugar()
 	g.Expect(err).Should(BeNil())
 
-	_, err = eventpublisher.NewEventPublisherService(sugarLogger, mockRobotBrokerService)
-	g.Expect(err).Should(Equal(expectedErr))
+	source := cuid.New()
+
+	_, err = eventpublisher.NewEventPublisherService(sugarLogger, source, nil, mockRobotBrokerService)
+	g.Expect(err).Should(Not(BeNil()))
 }
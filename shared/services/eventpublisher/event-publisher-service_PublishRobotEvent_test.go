@@ -6,14 +6,14 @@ import (
 	"math/rand"
 	"testing"
 
-	. "github.com/sepisoad/robot-challange/shared/nats-mocks/mock"
+	robotevents "github.com/sepisoad/robot-challange/api-definitions/asyncapi"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/lucsky/cuid"
-	"github.com/nats-io/nats.go"
 	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
 	"go.uber.org/zap"
 )
 
@@ -22,12 +22,6 @@ func Test_PublishRobotEvent_Should_Serialize_Event(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
-	mockJetStreamContext := NewMockJetStreamContext(ctrl)
-
-	mockRobotBrokerService.
-		EXPECT().
-		CreateNewJetStream().
-		Return(mockJetStreamContext, nil)
 
 	g := NewGomegaWithT(t)
 
@@ -35,7 +29,10 @@ func Test_PublishRobotEvent_Should_Serialize_Event(t *testing.T) {
 	sugarLogger := logger.Sugar()
 	g.Expect(err).Should(BeNil())
 
-	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, mockRobotBrokerService)
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
 	g.Expect(err).Should(BeNil())
 
 	event := eventpublisher.RobotEvent{
@@ -48,19 +45,35 @@ func Test_PublishRobotEvent_Should_Serialize_Event(t *testing.T) {
 		ErrorMessage: cuid.New(),
 	}
 
-	mockJetStreamContext.
+	mockRobotBrokerService.
 		EXPECT().
-		Publish(robotbroker.SUBJECT_ROBOT, gomock.Any()).
+		Publish(gomock.Any()).
 		DoAndReturn(
-			func(_ string, data []byte, _ ...nats.PubOpt) (*nats.PubAck, error) {
+			func(msg *robotbroker.Message) error {
+				g.Expect(msg.Subject).Should(Equal(robotbroker.RobotSubject(
+					robotbroker.DEFAULT_WAREHOUSE,
+					robotbroker.FormatId(event.Id),
+					"moved")))
+				g.Expect(msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE)).Should(Equal(eventcodec.CONTENT_TYPE_JSON))
+
+				var envelope eventpublisher.Envelope
+
+				err := json.Unmarshal(msg.Data, &envelope)
+				g.Expect(err).Should(BeNil())
+				g.Expect(envelope.SpecVersion).Should(Equal(eventpublisher.SpecVersion))
+				g.Expect(envelope.Id).Should(Not(BeEmpty()))
+				g.Expect(envelope.Source).Should(Equal(source))
+				g.Expect(envelope.Time).Should(Not(BeZero()))
+				g.Expect(envelope.Type).Should(Equal(eventpublisher.RobotEnvelopeType))
+				g.Expect(envelope.SchemaVersion).Should(Equal(eventpublisher.RobotEventSchemaVersion))
 
 				var providedEvent eventpublisher.RobotEvent
 
-				err := json.Unmarshal(data, &providedEvent)
+				err = json.Unmarshal(envelope.Data, &providedEvent)
 				g.Expect(err).Should(BeNil())
 				g.Expect(providedEvent).Should(Equal(event))
 
-				return nil, nil
+				return nil
 			})
 
 	err = sut.PublishRobotEvent(event)
@@ -73,12 +86,6 @@ func Test_PublishRobotEvent_Should_Call_Publish_Method(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
-	mockJetStreamContext := NewMockJetStreamContext(ctrl)
-
-	mockRobotBrokerService.
-		EXPECT().
-		CreateNewJetStream().
-		Return(mockJetStreamContext, nil)
 
 	g := NewGomegaWithT(t)
 
@@ -86,15 +93,27 @@ func Test_PublishRobotEvent_Should_Call_Publish_Method(t *testing.T) {
 	sugarLogger := logger.Sugar()
 	g.Expect(err).Should(BeNil())
 
-	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, mockRobotBrokerService)
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
 	g.Expect(err).Should(BeNil())
 
-	mockJetStreamContext.
+	mockRobotBrokerService.
 		EXPECT().
-		Publish(robotbroker.SUBJECT_ROBOT, gomock.Any()).
-		Return(nil, nil)
+		Publish(gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message) error {
+				g.Expect(msg.Subject).Should(Equal("robot.north.3.failedtomove"))
 
-	event := eventpublisher.RobotEvent{}
+				return nil
+			})
+
+	event := eventpublisher.RobotEvent{
+		EventType: eventpublisher.RobotFailedToMove,
+		Id:        3,
+		Warehouse: "north",
+	}
 
 	err = sut.PublishRobotEvent(event)
 	g.Expect(err).Should(BeNil())
@@ -106,12 +125,6 @@ func Test_PublishRobotEvent_Should_Return_Error_If_Publish_Return_Error(t *testi
 	defer ctrl.Finish()
 
 	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
-	mockJetStreamContext := NewMockJetStreamContext(ctrl)
-
-	mockRobotBrokerService.
-		EXPECT().
-		CreateNewJetStream().
-		Return(mockJetStreamContext, nil)
 
 	g := NewGomegaWithT(t)
 
@@ -119,18 +132,99 @@ func Test_PublishRobotEvent_Should_Return_Error_If_Publish_Return_Error(t *testi
 	sugarLogger := logger.Sugar()
 	g.Expect(err).Should(BeNil())
 
-	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, mockRobotBrokerService)
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
 	g.Expect(err).Should(BeNil())
 
 	expectedErr := errors.New(cuid.New())
 
-	mockJetStreamContext.
+	mockRobotBrokerService.
 		EXPECT().
-		Publish(robotbroker.SUBJECT_ROBOT, gomock.Any()).
-		Return(nil, expectedErr)
+		Publish(gomock.Any()).
+		Return(expectedErr)
 
-	event := eventpublisher.RobotEvent{}
+	event := eventpublisher.RobotEvent{
+		EventType: eventpublisher.RobotMoved,
+	}
 
 	err = sut.PublishRobotEvent(event)
 	g.Expect(err).Should(Equal(expectedErr))
 }
+
+func Test_PublishRobotEvent_Should_Return_Error_If_Event_Type_Is_Missing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	err = sut.PublishRobotEvent(eventpublisher.RobotEvent{})
+	g.Expect(err).Should(Not(BeNil()))
+}
+
+func Test_PublishRobotEvent_Should_Publish_Events_Valid_Against_AsyncAPI_Spec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	spec, err := robotevents.GetSpec()
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, cuid.New(), codec, mockRobotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	events := []eventpublisher.RobotEvent{
+		{
+			EventType: eventpublisher.RobotMoved,
+			Id:        int64(rand.Intn(10000)),
+			Warehouse: cuid.New(),
+			Data: eventpublisher.RobotData{
+				X: rand.Intn(10000),
+				Y: rand.Intn(10000),
+			},
+		},
+		{
+			EventType:    eventpublisher.RobotFailedToMove,
+			Id:           int64(rand.Intn(10000)),
+			ErrorMessage: cuid.New(),
+		},
+	}
+
+	mockRobotBrokerService.
+		EXPECT().
+		Publish(gomock.Any()).
+		Times(len(events)).
+		DoAndReturn(
+			func(msg *robotbroker.Message) error {
+				g.Expect(spec.ValidateMessage(msg.Subject, msg.Data)).Should(Succeed())
+
+				return nil
+			})
+
+	for _, event := range events {
+		err = sut.PublishRobotEvent(event)
+		g.Expect(err).Should(BeNil())
+	}
+}
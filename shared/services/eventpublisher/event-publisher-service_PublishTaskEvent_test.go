@@ -4,16 +4,17 @@ import (
 	"encoding/json"
 	"errors"
 	"math/rand"
+	"strconv"
 	"testing"
 
-	. "github.com/sepisoad/robot-challange/shared/nats-mocks/mock"
+	robotevents "github.com/sepisoad/robot-challange/api-definitions/asyncapi"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
 	"github.com/golang/mock/gomock"
 	"github.com/lucsky/cuid"
-	"github.com/nats-io/nats.go"
 	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
 	"go.uber.org/zap"
 )
 
@@ -22,12 +23,6 @@ func Test_PublishTaskEvent_Should_Serialize_Event(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
-	mockJetStreamContext := NewMockJetStreamContext(ctrl)
-
-	mockRobotBrokerService.
-		EXPECT().
-		CreateNewJetStream().
-		Return(mockJetStreamContext, nil)
 
 	g := NewGomegaWithT(t)
 
@@ -35,12 +30,15 @@ func Test_PublishTaskEvent_Should_Serialize_Event(t *testing.T) {
 	sugarLogger := logger.Sugar()
 	g.Expect(err).Should(BeNil())
 
-	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, mockRobotBrokerService)
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
 	g.Expect(err).Should(BeNil())
 
 	event := eventpublisher.TaskEvent{
 		EventType: eventpublisher.TaskCreated,
-		Id:        rand.Intn(10000),
+		Id:        int64(rand.Intn(10000)),
 		Data: eventpublisher.TaskData{
 			RobotId: int64(rand.Intn(10000)),
 			MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{
@@ -51,19 +49,34 @@ func Test_PublishTaskEvent_Should_Serialize_Event(t *testing.T) {
 		},
 	}
 
-	mockJetStreamContext.
+	mockRobotBrokerService.
 		EXPECT().
-		Publish(robotbroker.SUBJECT_TASK, gomock.Any()).
+		Publish(gomock.Any()).
 		DoAndReturn(
-			func(_ string, data []byte, _ ...nats.PubOpt) (*nats.PubAck, error) {
+			func(msg *robotbroker.Message) error {
+				g.Expect(msg.Subject).Should(Equal(robotbroker.TaskSubject(
+					strconv.FormatInt(event.Id, 10),
+					"created")))
+				g.Expect(msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE)).Should(Equal(eventcodec.CONTENT_TYPE_JSON))
+
+				var envelope eventpublisher.Envelope
+
+				err := json.Unmarshal(msg.Data, &envelope)
+				g.Expect(err).Should(BeNil())
+				g.Expect(envelope.SpecVersion).Should(Equal(eventpublisher.SpecVersion))
+				g.Expect(envelope.Id).Should(Not(BeEmpty()))
+				g.Expect(envelope.Source).Should(Equal(source))
+				g.Expect(envelope.Time).Should(Not(BeZero()))
+				g.Expect(envelope.Type).Should(Equal(eventpublisher.TaskEnvelopeType))
+				g.Expect(envelope.SchemaVersion).Should(Equal(eventpublisher.TaskEventSchemaVersion))
 
 				var providedEvent eventpublisher.TaskEvent
 
-				err := json.Unmarshal(data, &providedEvent)
+				err = json.Unmarshal(envelope.Data, &providedEvent)
 				g.Expect(err).Should(BeNil())
 				g.Expect(providedEvent).Should(Equal(event))
 
-				return nil, nil
+				return nil
 			})
 
 	err = sut.PublishTaskEvent(event)
@@ -76,12 +89,6 @@ func Test_PublishTaskEvent_Should_Call_Publish_Method(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
-	mockJetStreamContext := NewMockJetStreamContext(ctrl)
-
-	mockRobotBrokerService.
-		EXPECT().
-		CreateNewJetStream().
-		Return(mockJetStreamContext, nil)
 
 	g := NewGomegaWithT(t)
 
@@ -89,15 +96,26 @@ func Test_PublishTaskEvent_Should_Call_Publish_Method(t *testing.T) {
 	sugarLogger := logger.Sugar()
 	g.Expect(err).Should(BeNil())
 
-	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, mockRobotBrokerService)
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
 	g.Expect(err).Should(BeNil())
 
-	mockJetStreamContext.
+	mockRobotBrokerService.
 		EXPECT().
-		Publish(robotbroker.SUBJECT_TASK, gomock.Any()).
-		Return(nil, nil)
+		Publish(gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message) error {
+				g.Expect(msg.Subject).Should(Equal("task.7.cancelled"))
 
-	event := eventpublisher.TaskEvent{}
+				return nil
+			})
+
+	event := eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCancelled,
+		Id:        7,
+	}
 
 	err = sut.PublishTaskEvent(event)
 	g.Expect(err).Should(BeNil())
@@ -109,12 +127,6 @@ func Test_PublishTaskEvent_Should_Return_Error_If_Publish_Return_Error(t *testin
 	defer ctrl.Finish()
 
 	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
-	mockJetStreamContext := NewMockJetStreamContext(ctrl)
-
-	mockRobotBrokerService.
-		EXPECT().
-		CreateNewJetStream().
-		Return(mockJetStreamContext, nil)
 
 	g := NewGomegaWithT(t)
 
@@ -122,18 +134,123 @@ func Test_PublishTaskEvent_Should_Return_Error_If_Publish_Return_Error(t *testin
 	sugarLogger := logger.Sugar()
 	g.Expect(err).Should(BeNil())
 
-	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, mockRobotBrokerService)
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
 	g.Expect(err).Should(BeNil())
 
 	expectedErr := errors.New(cuid.New())
 
-	mockJetStreamContext.
+	mockRobotBrokerService.
 		EXPECT().
-		Publish(robotbroker.SUBJECT_TASK, gomock.Any()).
-		Return(nil, expectedErr)
+		Publish(gomock.Any()).
+		Return(expectedErr)
 
-	event := eventpublisher.TaskEvent{}
+	event := eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCompleted,
+	}
 
 	err = sut.PublishTaskEvent(event)
 	g.Expect(err).Should(Equal(expectedErr))
 }
+
+func Test_PublishTaskEvent_Should_Return_Error_If_Event_Type_Is_Missing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	source := cuid.New()
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, source, codec, mockRobotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	err = sut.PublishTaskEvent(eventpublisher.TaskEvent{})
+	g.Expect(err).Should(Not(BeNil()))
+}
+
+func Test_PublishTaskEvent_Should_Publish_Events_Valid_Against_AsyncAPI_Spec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	spec, err := robotevents.GetSpec()
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, cuid.New(), codec, mockRobotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	events := []eventpublisher.TaskEvent{
+		{
+			EventType: eventpublisher.TaskCreated,
+			Id:        int64(rand.Intn(10000)),
+			Data: eventpublisher.TaskData{
+				RobotId: int64(rand.Intn(10000)),
+				MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{
+					eventpublisher.NORTH,
+					eventpublisher.WEST,
+				},
+			},
+		},
+		{
+			EventType: eventpublisher.TaskCancelled,
+			Id:        int64(rand.Intn(10000)),
+		},
+		{
+			EventType: eventpublisher.TaskCompleted,
+			Id:        int64(rand.Intn(10000)),
+		},
+		{
+			EventType: eventpublisher.TaskStarted,
+			Id:        int64(rand.Intn(10000)),
+			Data: eventpublisher.TaskData{
+				RobotId: int64(rand.Intn(10000)),
+			},
+		},
+		{
+			EventType: eventpublisher.TaskFailed,
+			Id:        int64(rand.Intn(10000)),
+			Data: eventpublisher.TaskData{
+				RobotId: int64(rand.Intn(10000)),
+				Trajectory: []eventpublisher.RobotData{
+					{X: 0, Y: 1},
+					{X: 0, Y: 2},
+				},
+				FailureReason: "robot hit the wall",
+			},
+		},
+	}
+
+	mockRobotBrokerService.
+		EXPECT().
+		Publish(gomock.Any()).
+		Times(len(events)).
+		DoAndReturn(
+			func(msg *robotbroker.Message) error {
+				g.Expect(spec.ValidateMessage(msg.Subject, msg.Data)).Should(Succeed())
+
+				return nil
+			})
+
+	for _, event := range events {
+		err = sut.PublishTaskEvent(event)
+		g.Expect(err).Should(BeNil())
+	}
+}
@@ -0,0 +1,135 @@
+package eventpublisher_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	robotevents "github.com/sepisoad/robot-challange/api-definitions/asyncapi"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func newWarehouseEvent() eventpublisher.WarehouseEvent {
+	return eventpublisher.WarehouseEvent{
+		EventType: eventpublisher.WarehouseConfigured,
+		Warehouse: "north",
+		Data: eventpublisher.WarehouseData{
+			Width:     12,
+			Height:    8,
+			Obstacles: []eventpublisher.Cell{{X: 3, Y: 4}, {X: 3, Y: 5}},
+			SpecialCells: []eventpublisher.SpecialCell{
+				{X: 0, Y: 7, Kind: eventpublisher.PickupCell},
+				{X: 11, Y: 0, Kind: eventpublisher.ChargerCell},
+			},
+		},
+	}
+}
+
+func Test_PublishWarehouseEvent_Should_Publish_Events_Valid_Against_AsyncAPI_Spec(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	spec, err := robotevents.GetSpec()
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, cuid.New(), codec, mockRobotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	mockRobotBrokerService.
+		EXPECT().
+		Publish(gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message) error {
+				g.Expect(msg.Subject).Should(Equal("warehouse.north.configured"))
+				g.Expect(spec.ValidateMessage(msg.Subject, msg.Data)).Should(Succeed())
+
+				return nil
+			})
+
+	err = sut.PublishWarehouseEvent(newWarehouseEvent())
+	g.Expect(err).Should(BeNil())
+}
+
+func Test_PublishWarehouseEvent_Should_Be_Decoded_With_Every_Codec(t *testing.T) {
+	for _, codecName := range []string{eventcodec.JSON, eventcodec.PROTOBUF, eventcodec.MSGPACK} {
+		t.Run(codecName, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+			g := NewGomegaWithT(t)
+
+			logger, err := zap.NewDevelopment()
+			sugarLogger := logger.Sugar()
+			g.Expect(err).Should(BeNil())
+
+			codec, err := eventcodec.NewCodec(codecName)
+			g.Expect(err).Should(BeNil())
+
+			sut, err := eventpublisher.NewEventPublisherService(sugarLogger, cuid.New(), codec, mockRobotBrokerService)
+			g.Expect(err).Should(BeNil())
+
+			eventDecoderService, err := eventpublisher.NewEventDecoderService()
+			g.Expect(err).Should(BeNil())
+
+			event := newWarehouseEvent()
+
+			mockRobotBrokerService.
+				EXPECT().
+				Publish(gomock.Any()).
+				DoAndReturn(
+					func(msg *robotbroker.Message) error {
+						decodedEvent := eventpublisher.WarehouseEvent{}
+
+						envelope, err := eventDecoderService.Decode(
+							msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+							msg.Data,
+							&decodedEvent)
+						g.Expect(err).Should(BeNil())
+						g.Expect(envelope.Type).Should(Equal(eventpublisher.WarehouseEnvelopeType))
+						g.Expect(decodedEvent).Should(Equal(event))
+
+						return nil
+					})
+
+			g.Expect(sut.PublishWarehouseEvent(event)).Should(Succeed())
+		})
+	}
+}
+
+func Test_PublishWarehouseEvent_Should_Return_Error_If_Event_Type_Is_Missing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRobotBrokerService := NewMockRobotBrokerInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	codec, _ := eventcodec.NewCodec(eventcodec.JSON)
+
+	sut, err := eventpublisher.NewEventPublisherService(sugarLogger, cuid.New(), codec, mockRobotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	err = sut.PublishWarehouseEvent(eventpublisher.WarehouseEvent{})
+	g.Expect(err).Should(Not(BeNil()))
+}
@@ -0,0 +1,143 @@
+// Code generated by asyncapi-codegen from Robot_Events.yaml. DO NOT EDIT.
+
+package eventpublisher
+
+// TaskEventType describe task state
+type TaskEventType string
+
+const (
+	// TaskCreated is used to denote a task event that is Created
+	TaskCreated TaskEventType = "Created"
+	// TaskStarted is used to denote a task event that is Started
+	TaskStarted TaskEventType = "Started"
+	// TaskCompleted is used to denote a task event that is Completed
+	TaskCompleted TaskEventType = "Completed"
+	// TaskFailed is used to denote a task event that is Failed
+	TaskFailed TaskEventType = "Failed"
+	// TaskCancelled is used to denote a task event that is Cancelled
+	TaskCancelled TaskEventType = "Cancelled"
+	// TaskBatchCreated is used to denote a batch of tasks that is Created, the event carries the id of the batch
+	TaskBatchCreated TaskEventType = "BatchCreated"
+	// TaskBatchCancelled is used to denote the unfinished tasks of a batch that are Cancelled, the event carries the id of the batch
+	TaskBatchCancelled TaskEventType = "BatchCancelled"
+)
+
+// TaskEvent describes a task event
+type TaskEvent struct {
+	EventType TaskEventType `json:"EventType"`
+	// is the 64-bit snowflake id of the task, or of the batch of a BatchCreated or a BatchCancelled event
+	Id   int64    `json:"Id"`
+	Data TaskData `json:"Data,omitempty"`
+}
+
+// MoveRobotRequestMoveSequence describes a movement code
+type MoveRobotRequestMoveSequence string
+
+const (
+	// EAST denotes a movement code towards EAST
+	EAST MoveRobotRequestMoveSequence = "E"
+	// NORTH denotes a movement code towards NORTH
+	NORTH MoveRobotRequestMoveSequence = "N"
+	// SOUTH denotes a movement code towards SOUTH
+	SOUTH MoveRobotRequestMoveSequence = "S"
+	// WEST denotes a movement code towards WEST
+	WEST MoveRobotRequestMoveSequence = "W"
+)
+
+// TaskData describes task data
+type TaskData struct {
+	RobotId        int64                          `json:"RobotId"`
+	MoveSequeneces []MoveRobotRequestMoveSequence `json:"MoveSequeneces"`
+	// lists the positions of the robot while it executed the task, the last one is where it stopped
+	Trajectory []RobotData `json:"Trajectory,omitempty"`
+	// tells why the robot could not make some of the moves
+	FailureReason string `json:"FailureReason,omitempty"`
+	// is the batch the task was created with, the tasks of a batch share it
+	BatchId string `json:"BatchId,omitempty"`
+	// lists the tasks of a batch which is created, or the tasks of a batch which are cancelled
+	Tasks []BatchTask `json:"Tasks,omitempty"`
+	// tells the simulator accepted the task or the batch when it was requested, and so enqueued it already, it is not executed again when it is created
+	Accepted bool `json:"Accepted,omitempty"`
+}
+
+// BatchTask describes a task of a batch
+type BatchTask struct {
+	Id             int64                          `json:"Id"`
+	RobotId        int64                          `json:"RobotId"`
+	MoveSequeneces []MoveRobotRequestMoveSequence `json:"MoveSequeneces"`
+}
+
+// RobotMovedEventType descries a robot movement event type
+type RobotMovedEventType string
+
+const (
+	// RobotMoved is used when a RobotMoved
+	RobotMoved RobotMovedEventType = "Moved"
+	// RobotFailedToMove is used when a RobotFailedToMove
+	RobotFailedToMove RobotMovedEventType = "FailedToMove"
+)
+
+// RobotEvent describe a RobotEvent
+type RobotEvent struct {
+	EventType    RobotMovedEventType `json:"EventType"`
+	Id           int64               `json:"Id"`
+	Warehouse    string              `json:"Warehouse,omitempty"`
+	Data         RobotData           `json:"Data,omitempty"`
+	ErrorMessage string              `json:"ErrorMessage,omitempty"`
+}
+
+// RobotData show robot's location on grid
+type RobotData struct {
+	X int `json:"X"`
+	Y int `json:"Y"`
+	// tells whether the robot carries a crate
+	HasCrate bool `json:"HasCrate,omitempty"`
+}
+
+// WarehouseEventType describes a warehouse event type
+type WarehouseEventType string
+
+const (
+	// WarehouseConfigured is used when a warehouse is started or its layout changes
+	WarehouseConfigured WarehouseEventType = "Configured"
+)
+
+// WarehouseEvent describes a warehouse event
+type WarehouseEvent struct {
+	EventType WarehouseEventType `json:"EventType"`
+	Warehouse string             `json:"Warehouse"`
+	Data      WarehouseData      `json:"Data"`
+}
+
+// WarehouseData describes the layout of a warehouse, cells are addressed from 0 to Width - 1 and Height - 1
+type WarehouseData struct {
+	Width        int           `json:"Width"`
+	Height       int           `json:"Height"`
+	Obstacles    []Cell        `json:"Obstacles,omitempty"`
+	SpecialCells []SpecialCell `json:"SpecialCells,omitempty"`
+}
+
+// Cell is a cell of the warehouse grid
+type Cell struct {
+	X int `json:"X"`
+	Y int `json:"Y"`
+}
+
+// SpecialCellKind describes what a special cell is used for
+type SpecialCellKind string
+
+const (
+	// PickupCell is a cell crates are picked up from
+	PickupCell SpecialCellKind = "Pickup"
+	// DropoffCell is a cell crates are dropped off at
+	DropoffCell SpecialCellKind = "Dropoff"
+	// ChargerCell is a cell robots charge at
+	ChargerCell SpecialCellKind = "Charger"
+)
+
+// SpecialCell is a cell of the warehouse grid with a purpose
+type SpecialCell struct {
+	X    int             `json:"X"`
+	Y    int             `json:"Y"`
+	Kind SpecialCellKind `json:"Kind"`
+}
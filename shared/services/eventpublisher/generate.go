@@ -1,3 +1,4 @@
 package eventpublisher
 
 //go:generate mockgen -source=contract.go -destination=mock/mock-contract.go
+//go:generate protoc --proto_path=../../../api-definitions/protobuf --go_out=eventspb --go_opt=paths=source_relative events.proto
@@ -6,6 +6,7 @@ package mock_eventpublisher
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	eventpublisher "github.com/sepisoad/robot-challange/shared/services/eventpublisher"
@@ -61,3 +62,224 @@ func (mr *MockEventPublisherInterfaceMockRecorder) PublishTaskEvent(event interf
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishTaskEvent", reflect.TypeOf((*MockEventPublisherInterface)(nil).PublishTaskEvent), event)
 }
+
+// PublishWarehouseEvent mocks base method.
+func (m *MockEventPublisherInterface) PublishWarehouseEvent(event eventpublisher.WarehouseEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishWarehouseEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishWarehouseEvent indicates an expected call of PublishWarehouseEvent.
+func (mr *MockEventPublisherInterfaceMockRecorder) PublishWarehouseEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishWarehouseEvent", reflect.TypeOf((*MockEventPublisherInterface)(nil).PublishWarehouseEvent), event)
+}
+
+// MockAsyncEventPublisherInterface is a mock of AsyncEventPublisherInterface interface.
+type MockAsyncEventPublisherInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAsyncEventPublisherInterfaceMockRecorder
+}
+
+// MockAsyncEventPublisherInterfaceMockRecorder is the mock recorder for MockAsyncEventPublisherInterface.
+type MockAsyncEventPublisherInterfaceMockRecorder struct {
+	mock *MockAsyncEventPublisherInterface
+}
+
+// NewMockAsyncEventPublisherInterface creates a new mock instance.
+func NewMockAsyncEventPublisherInterface(ctrl *gomock.Controller) *MockAsyncEventPublisherInterface {
+	mock := &MockAsyncEventPublisherInterface{ctrl: ctrl}
+	mock.recorder = &MockAsyncEventPublisherInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAsyncEventPublisherInterface) EXPECT() *MockAsyncEventPublisherInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockAsyncEventPublisherInterface) Close(timeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockAsyncEventPublisherInterfaceMockRecorder) Close(timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAsyncEventPublisherInterface)(nil).Close), timeout)
+}
+
+// Flush mocks base method.
+func (m *MockAsyncEventPublisherInterface) Flush(timeout time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Flush", timeout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Flush indicates an expected call of Flush.
+func (mr *MockAsyncEventPublisherInterfaceMockRecorder) Flush(timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Flush", reflect.TypeOf((*MockAsyncEventPublisherInterface)(nil).Flush), timeout)
+}
+
+// PublishRobotEvent mocks base method.
+func (m *MockAsyncEventPublisherInterface) PublishRobotEvent(event eventpublisher.RobotEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishRobotEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishRobotEvent indicates an expected call of PublishRobotEvent.
+func (mr *MockAsyncEventPublisherInterfaceMockRecorder) PublishRobotEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishRobotEvent", reflect.TypeOf((*MockAsyncEventPublisherInterface)(nil).PublishRobotEvent), event)
+}
+
+// PublishTaskEvent mocks base method.
+func (m *MockAsyncEventPublisherInterface) PublishTaskEvent(event eventpublisher.TaskEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishTaskEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishTaskEvent indicates an expected call of PublishTaskEvent.
+func (mr *MockAsyncEventPublisherInterfaceMockRecorder) PublishTaskEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishTaskEvent", reflect.TypeOf((*MockAsyncEventPublisherInterface)(nil).PublishTaskEvent), event)
+}
+
+// PublishWarehouseEvent mocks base method.
+func (m *MockAsyncEventPublisherInterface) PublishWarehouseEvent(event eventpublisher.WarehouseEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishWarehouseEvent", event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishWarehouseEvent indicates an expected call of PublishWarehouseEvent.
+func (mr *MockAsyncEventPublisherInterfaceMockRecorder) PublishWarehouseEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishWarehouseEvent", reflect.TypeOf((*MockAsyncEventPublisherInterface)(nil).PublishWarehouseEvent), event)
+}
+
+// Stats mocks base method.
+func (m *MockAsyncEventPublisherInterface) Stats() eventpublisher.AsyncPublishStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(eventpublisher.AsyncPublishStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockAsyncEventPublisherInterfaceMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockAsyncEventPublisherInterface)(nil).Stats))
+}
+
+// MockTaskRequesterInterface is a mock of TaskRequesterInterface interface.
+type MockTaskRequesterInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTaskRequesterInterfaceMockRecorder
+}
+
+// MockTaskRequesterInterfaceMockRecorder is the mock recorder for MockTaskRequesterInterface.
+type MockTaskRequesterInterfaceMockRecorder struct {
+	mock *MockTaskRequesterInterface
+}
+
+// NewMockTaskRequesterInterface creates a new mock instance.
+func NewMockTaskRequesterInterface(ctrl *gomock.Controller) *MockTaskRequesterInterface {
+	mock := &MockTaskRequesterInterface{ctrl: ctrl}
+	mock.recorder = &MockTaskRequesterInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTaskRequesterInterface) EXPECT() *MockTaskRequesterInterfaceMockRecorder {
+	return m.recorder
+}
+
+// RequestTask mocks base method.
+func (m *MockTaskRequesterInterface) RequestTask(event eventpublisher.TaskEvent, timeout time.Duration) (eventpublisher.TaskAcceptance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestTask", event, timeout)
+	ret0, _ := ret[0].(eventpublisher.TaskAcceptance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestTask indicates an expected call of RequestTask.
+func (mr *MockTaskRequesterInterfaceMockRecorder) RequestTask(event, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestTask", reflect.TypeOf((*MockTaskRequesterInterface)(nil).RequestTask), event, timeout)
+}
+
+// MockEventDecoderInterface is a mock of EventDecoderInterface interface.
+type MockEventDecoderInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventDecoderInterfaceMockRecorder
+}
+
+// MockEventDecoderInterfaceMockRecorder is the mock recorder for MockEventDecoderInterface.
+type MockEventDecoderInterfaceMockRecorder struct {
+	mock *MockEventDecoderInterface
+}
+
+// NewMockEventDecoderInterface creates a new mock instance.
+func NewMockEventDecoderInterface(ctrl *gomock.Controller) *MockEventDecoderInterface {
+	mock := &MockEventDecoderInterface{ctrl: ctrl}
+	mock.recorder = &MockEventDecoderInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventDecoderInterface) EXPECT() *MockEventDecoderInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Decode mocks base method.
+func (m *MockEventDecoderInterface) Decode(contentType string, buf []byte, event interface{}) (eventpublisher.Envelope, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decode", contentType, buf, event)
+	ret0, _ := ret[0].(eventpublisher.Envelope)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decode indicates an expected call of Decode.
+func (mr *MockEventDecoderInterfaceMockRecorder) Decode(contentType, buf, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decode", reflect.TypeOf((*MockEventDecoderInterface)(nil).Decode), contentType, buf, event)
+}
+
+// Register mocks base method.
+func (m *MockEventDecoderInterface) Register(envelopeType string, schemaVersion int, event interface{}) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Register", envelopeType, schemaVersion, event)
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockEventDecoderInterfaceMockRecorder) Register(envelopeType, schemaVersion, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockEventDecoderInterface)(nil).Register), envelopeType, schemaVersion, event)
+}
+
+// RegisterUpcaster mocks base method.
+func (m *MockEventDecoderInterface) RegisterUpcaster(envelopeType string, fromSchemaVersion int, upcaster eventpublisher.Upcaster) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterUpcaster", envelopeType, fromSchemaVersion, upcaster)
+}
+
+// RegisterUpcaster indicates an expected call of RegisterUpcaster.
+func (mr *MockEventDecoderInterfaceMockRecorder) RegisterUpcaster(envelopeType, fromSchemaVersion, upcaster interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUpcaster", reflect.TypeOf((*MockEventDecoderInterface)(nil).RegisterUpcaster), envelopeType, fromSchemaVersion, upcaster)
+}
@@ -0,0 +1,265 @@
+package eventpublisher
+
+import (
+	"fmt"
+
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher/eventspb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ToProto converts the envelope to its protobuf message
+func (e Envelope) ToProto() proto.Message {
+	return &eventspb.Envelope{
+		SpecVersion:   e.SpecVersion,
+		Id:            e.Id,
+		Source:        e.Source,
+		Time:          timestamppb.New(e.Time),
+		Type:          e.Type,
+		SchemaVersion: int32(e.SchemaVersion),
+		Data:          e.Data,
+	}
+}
+
+// NewProto returns an empty protobuf message of the envelope
+func (e *Envelope) NewProto() proto.Message {
+	return &eventspb.Envelope{}
+}
+
+// FromProto populates the envelope from its protobuf message
+func (e *Envelope) FromProto(message proto.Message) error {
+	envelope, ok := message.(*eventspb.Envelope)
+	if !ok {
+		return fmt.Errorf("unexpected protobuf message %T", message)
+	}
+
+	*e = Envelope{
+		SpecVersion:   envelope.SpecVersion,
+		Id:            envelope.Id,
+		Source:        envelope.Source,
+		Time:          envelope.Time.AsTime(),
+		Type:          envelope.Type,
+		SchemaVersion: int(envelope.SchemaVersion),
+		Data:          envelope.Data,
+	}
+
+	return nil
+}
+
+// ToProto converts the task event to its protobuf message
+func (e TaskEvent) ToProto() proto.Message {
+	moveSequences := make([]string, 0, len(e.Data.MoveSequeneces))
+	for _, moveSequence := range e.Data.MoveSequeneces {
+		moveSequences = append(moveSequences, string(moveSequence))
+	}
+
+	trajectory := make([]*eventspb.RobotData, 0, len(e.Data.Trajectory))
+	for _, position := range e.Data.Trajectory {
+		trajectory = append(trajectory, &eventspb.RobotData{
+			X:        int64(position.X),
+			Y:        int64(position.Y),
+			HasCrate: position.HasCrate,
+		})
+	}
+
+	tasks := make([]*eventspb.BatchTask, 0, len(e.Data.Tasks))
+	for _, task := range e.Data.Tasks {
+		taskMoveSequences := make([]string, 0, len(task.MoveSequeneces))
+		for _, moveSequence := range task.MoveSequeneces {
+			taskMoveSequences = append(taskMoveSequences, string(moveSequence))
+		}
+
+		tasks = append(tasks, &eventspb.BatchTask{
+			Id:            task.Id,
+			RobotId:       task.RobotId,
+			MoveSequences: taskMoveSequences,
+		})
+	}
+
+	return &eventspb.TaskEvent{
+		EventType: string(e.EventType),
+		Id:        e.Id,
+		Data: &eventspb.TaskData{
+			RobotId:       e.Data.RobotId,
+			MoveSequences: moveSequences,
+			Trajectory:    trajectory,
+			FailureReason: e.Data.FailureReason,
+			BatchId:       e.Data.BatchId,
+			Tasks:         tasks,
+			Accepted:      e.Data.Accepted,
+		},
+	}
+}
+
+// NewProto returns an empty protobuf message of the task event
+func (e *TaskEvent) NewProto() proto.Message {
+	return &eventspb.TaskEvent{}
+}
+
+// FromProto populates the task event from its protobuf message
+func (e *TaskEvent) FromProto(message proto.Message) error {
+	event, ok := message.(*eventspb.TaskEvent)
+	if !ok {
+		return fmt.Errorf("unexpected protobuf message %T", message)
+	}
+
+	*e = TaskEvent{
+		EventType: TaskEventType(event.EventType),
+		Id:        event.Id,
+	}
+
+	if event.Data != nil {
+		e.Data.RobotId = event.Data.RobotId
+
+		for _, moveSequence := range event.Data.MoveSequences {
+			e.Data.MoveSequeneces = append(
+				e.Data.MoveSequeneces,
+				MoveRobotRequestMoveSequence(moveSequence))
+		}
+
+		for _, position := range event.Data.Trajectory {
+			e.Data.Trajectory = append(e.Data.Trajectory, RobotData{
+				X:        int(position.X),
+				Y:        int(position.Y),
+				HasCrate: position.HasCrate,
+			})
+		}
+
+		e.Data.FailureReason = event.Data.FailureReason
+		e.Data.BatchId = event.Data.BatchId
+
+		for _, task := range event.Data.Tasks {
+			batchTask := BatchTask{
+				Id:             task.Id,
+				RobotId:        task.RobotId,
+				MoveSequeneces: make([]MoveRobotRequestMoveSequence, 0, len(task.MoveSequences)),
+			}
+
+			for _, moveSequence := range task.MoveSequences {
+				batchTask.MoveSequeneces = append(
+					batchTask.MoveSequeneces,
+					MoveRobotRequestMoveSequence(moveSequence))
+			}
+
+			e.Data.Tasks = append(e.Data.Tasks, batchTask)
+		}
+
+		e.Data.Accepted = event.Data.Accepted
+	}
+
+	return nil
+}
+
+// ToProto converts the robot event to its protobuf message
+func (e RobotEvent) ToProto() proto.Message {
+	return &eventspb.RobotEvent{
+		EventType: string(e.EventType),
+		Id:        e.Id,
+		Warehouse: e.Warehouse,
+		Data: &eventspb.RobotData{
+			X:        int64(e.Data.X),
+			Y:        int64(e.Data.Y),
+			HasCrate: e.Data.HasCrate,
+		},
+		ErrorMessage: e.ErrorMessage,
+	}
+}
+
+// NewProto returns an empty protobuf message of the robot event
+func (e *RobotEvent) NewProto() proto.Message {
+	return &eventspb.RobotEvent{}
+}
+
+// FromProto populates the robot event from its protobuf message
+func (e *RobotEvent) FromProto(message proto.Message) error {
+	event, ok := message.(*eventspb.RobotEvent)
+	if !ok {
+		return fmt.Errorf("unexpected protobuf message %T", message)
+	}
+
+	*e = RobotEvent{
+		EventType:    RobotMovedEventType(event.EventType),
+		Id:           event.Id,
+		Warehouse:    event.Warehouse,
+		ErrorMessage: event.ErrorMessage,
+	}
+
+	if event.Data != nil {
+		e.Data = RobotData{
+			X:        int(event.Data.X),
+			Y:        int(event.Data.Y),
+			HasCrate: event.Data.HasCrate,
+		}
+	}
+
+	return nil
+}
+
+// ToProto converts the warehouse event to its protobuf message
+func (e WarehouseEvent) ToProto() proto.Message {
+	data := &eventspb.WarehouseData{
+		Width:  int64(e.Data.Width),
+		Height: int64(e.Data.Height),
+	}
+
+	for _, obstacle := range e.Data.Obstacles {
+		data.Obstacles = append(data.Obstacles, &eventspb.Cell{
+			X: int64(obstacle.X),
+			Y: int64(obstacle.Y),
+		})
+	}
+
+	for _, specialCell := range e.Data.SpecialCells {
+		data.SpecialCells = append(data.SpecialCells, &eventspb.SpecialCell{
+			X:    int64(specialCell.X),
+			Y:    int64(specialCell.Y),
+			Kind: string(specialCell.Kind),
+		})
+	}
+
+	return &eventspb.WarehouseEvent{
+		EventType: string(e.EventType),
+		Warehouse: e.Warehouse,
+		Data:      data,
+	}
+}
+
+// NewProto returns an empty protobuf message of the warehouse event
+func (e *WarehouseEvent) NewProto() proto.Message {
+	return &eventspb.WarehouseEvent{}
+}
+
+// FromProto populates the warehouse event from its protobuf message
+func (e *WarehouseEvent) FromProto(message proto.Message) error {
+	event, ok := message.(*eventspb.WarehouseEvent)
+	if !ok {
+		return fmt.Errorf("unexpected protobuf message %T", message)
+	}
+
+	*e = WarehouseEvent{
+		EventType: WarehouseEventType(event.EventType),
+		Warehouse: event.Warehouse,
+	}
+
+	if event.Data != nil {
+		e.Data.Width = int(event.Data.Width)
+		e.Data.Height = int(event.Data.Height)
+
+		for _, obstacle := range event.Data.Obstacles {
+			e.Data.Obstacles = append(e.Data.Obstacles, Cell{
+				X: int(obstacle.X),
+				Y: int(obstacle.Y),
+			})
+		}
+
+		for _, specialCell := range event.Data.SpecialCells {
+			e.Data.SpecialCells = append(e.Data.SpecialCells, SpecialCell{
+				X:    int(specialCell.X),
+				Y:    int(specialCell.Y),
+				Kind: SpecialCellKind(specialCell.Kind),
+			})
+		}
+	}
+
+	return nil
+}
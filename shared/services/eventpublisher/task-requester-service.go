@@ -0,0 +1,82 @@
+package eventpublisher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+type taskRequesterService struct {
+	eventPublisherService *eventPublisherService
+	requester             robotbroker.RequesterInterface
+}
+
+// NewTaskRequesterService creates a concrete instance of TaskRequesterInterface which sends the
+// tasks, encoded like the published events, as requests on robotbroker.RequestSubject of their
+// event subject. Requests usually go to a broker but any requester, like a circuit breaker,
+// will do
+func NewTaskRequesterService(
+	logger *zap.SugaredLogger,
+	source string,
+	codec eventcodec.CodecInterface,
+	requester robotbroker.RequesterInterface) (TaskRequesterInterface, error) {
+	if codec == nil || requester == nil {
+		return nil, errors.New("a task requester requires a codec and a broker")
+	}
+
+	return &taskRequesterService{
+		eventPublisherService: &eventPublisherService{
+			logger: logger,
+			source: source,
+			codec:  codec,
+		},
+		requester: requester,
+	}, nil
+}
+
+// RequestTask sends a task to the warehouse and waits for it to accept or reject the task
+func (s *taskRequesterService) RequestTask(event TaskEvent, timeout time.Duration) (TaskAcceptance, error) {
+	msg, err := s.eventPublisherService.newTaskEventMsg(event)
+	if err != nil {
+		return TaskAcceptance{}, err
+	}
+
+	msg.Subject = robotbroker.RequestSubject(msg.Subject)
+
+	reply, err := s.requester.Request(msg, timeout)
+	if err != nil {
+		return TaskAcceptance{}, err
+	}
+
+	return DecodeTaskAcceptance(reply)
+}
+
+// NewTaskAcceptanceReply creates the reply of a warehouse to a task request
+func NewTaskAcceptanceReply(subject string, acceptance TaskAcceptance) (*robotbroker.Message, error) {
+	buf, err := json.Marshal(acceptance)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := robotbroker.NewMessage(subject)
+	reply.Header.Set(eventcodec.HEADER_CONTENT_TYPE, eventcodec.CONTENT_TYPE_JSON)
+	reply.Data = buf
+
+	return reply, nil
+}
+
+// DecodeTaskAcceptance reads the reply of a warehouse to a task request
+func DecodeTaskAcceptance(reply *robotbroker.Message) (TaskAcceptance, error) {
+	var acceptance TaskAcceptance
+
+	if err := json.Unmarshal(reply.Data, &acceptance); err != nil {
+		return TaskAcceptance{}, fmt.Errorf("invalid reply to task request: %w", err)
+	}
+
+	return acceptance, nil
+}
@@ -0,0 +1,106 @@
+package eventpublisher_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_RequestTask_Should_Return_Acceptance_Of_Warehouse(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer robotBrokerService.Close()
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	_, err = robotBrokerService.QueueRespond(
+		robotbroker.RequestSubject(robotbroker.TaskSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			eventpublisher.TaskCreated.SubjectToken())),
+		"warehouse",
+		func(msg *robotbroker.Message) *robotbroker.Message {
+			event := eventpublisher.TaskEvent{}
+			_, err := eventDecoderService.Decode(
+				msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+				msg.Data,
+				&event)
+			g.Expect(err).Should(BeNil())
+
+			acceptance := eventpublisher.TaskAcceptance{Accepted: event.Data.RobotId == 1}
+			if !acceptance.Accepted {
+				acceptance.Reason = "robot not found"
+			}
+
+			reply, err := eventpublisher.NewTaskAcceptanceReply(msg.Subject, acceptance)
+			g.Expect(err).Should(BeNil())
+
+			return reply
+		})
+	g.Expect(err).Should(BeNil())
+
+	for _, codecName := range []string{eventcodec.JSON, eventcodec.PROTOBUF, eventcodec.MSGPACK} {
+		codec, err := eventcodec.NewCodec(codecName)
+		g.Expect(err).Should(BeNil())
+
+		sut, err := eventpublisher.NewTaskRequesterService(sugarLogger, "test", codec, robotBrokerService)
+		g.Expect(err).Should(BeNil())
+
+		acceptance, err := sut.RequestTask(eventpublisher.TaskEvent{
+			EventType: eventpublisher.TaskCreated,
+			Id:        1,
+			Data: eventpublisher.TaskData{
+				RobotId:        1,
+				MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.NORTH},
+			},
+		}, time.Second)
+		g.Expect(err).Should(BeNil())
+		g.Expect(acceptance).Should(Equal(eventpublisher.TaskAcceptance{Accepted: true}))
+
+		acceptance, err = sut.RequestTask(eventpublisher.TaskEvent{
+			EventType: eventpublisher.TaskCreated,
+			Id:        2,
+			Data: eventpublisher.TaskData{
+				RobotId:        2,
+				MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.NORTH},
+			},
+		}, time.Second)
+		g.Expect(err).Should(BeNil())
+		g.Expect(acceptance).Should(Equal(eventpublisher.TaskAcceptance{Reason: "robot not found"}))
+	}
+}
+
+func Test_RequestTask_Should_Return_Error_If_No_Warehouse_Is_Listening(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	robotBrokerService, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer robotBrokerService.Close()
+
+	codec, err := eventcodec.NewCodec(eventcodec.JSON)
+	g.Expect(err).Should(BeNil())
+
+	sut, err := eventpublisher.NewTaskRequesterService(sugarLogger, "test", codec, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.RequestTask(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCreated,
+		Id:        1,
+	}, time.Second)
+	g.Expect(err).Should(Equal(robotbroker.ErrNoResponders))
+}
@@ -0,0 +1,14 @@
+package journal
+
+// JournalInterface defines contract for an append-only file of JSON records, one per line. A
+// journal is replayed with Read and compacted with Rewrite
+type JournalInterface interface {
+	// Append writes a record at the end of the journal and, when sync is set, waits for it to
+	// reach the disk with the records appended before. A record which fails to reach the disk
+	// is cut off so the journal does not end with a torn line
+	Append(record interface{}, sync bool) error
+	// Clear empties the journal and waits for it to reach the disk
+	Clear() error
+	// Close waits for the records appended without sync to reach the disk and closes the journal
+	Close() error
+}
@@ -0,0 +1,221 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+type journalService struct {
+	logger *zap.SugaredLogger
+	path   string
+	file   *os.File
+	// offset is where the next record is appended, a record which failed to reach the disk is
+	// cut off there
+	offset int64
+	// unsynced tells whether records were appended since the journal was last synced
+	unsynced bool
+}
+
+// NewJournalService creates a concrete instance of JournalInterface which appends to the
+// journal at path, the journal is created when it does not exist
+func NewJournalService(logger *zap.SugaredLogger, path string) (JournalInterface, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return &journalService{
+		logger: logger,
+		path:   path,
+		file:   file,
+		offset: info.Size(),
+	}, nil
+}
+
+// Append writes a record at the end of the journal, the journal is closed when a torn record
+// can not be cut off so no record is appended after it
+func (s *journalService) Append(record interface{}, sync bool) error {
+	if s.file == nil {
+		return os.ErrClosed
+	}
+
+	buf, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	written, err := s.file.Write(append(buf, '\n'))
+	if err == nil && sync {
+		err = s.file.Sync()
+	}
+
+	if err != nil {
+		s.truncate()
+
+		return err
+	}
+
+	s.offset += int64(written)
+	s.unsynced = !sync
+
+	return nil
+}
+
+// Clear empties the journal
+func (s *journalService) Clear() error {
+	if s.file == nil {
+		return os.ErrClosed
+	}
+
+	if err := s.file.Truncate(0); err != nil {
+		return err
+	}
+
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+
+	s.offset = 0
+	s.unsynced = false
+
+	return nil
+}
+
+// Close closes the journal, closing it again does nothing
+func (s *journalService) Close() error {
+	if s.file == nil {
+		return nil
+	}
+
+	var err error
+	if s.unsynced {
+		err = s.file.Sync()
+	}
+
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	s.file = nil
+
+	return err
+}
+
+// truncate cuts the journal back to the end of the last record which was appended in full
+func (s *journalService) truncate() {
+	err := s.file.Truncate(s.offset)
+	if err == nil {
+		err = s.file.Sync()
+	}
+
+	if err == nil {
+		return
+	}
+
+	s.logger.Errorf(
+		"Failed to truncate the journal %s to %d bytes, it is closed. Error: %v",
+		s.path,
+		s.offset,
+		err)
+
+	_ = s.file.Close()
+	s.file = nil
+}
+
+// Read replays the journal at path, decode is called with every record in order. A journal
+// which does not exist is empty. Only the last line can be cut short by a crash, it is ignored
+// when it is not a whole record
+func Read(path string, decode func(record []byte) error) error {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var corrupted error
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		if corrupted != nil {
+			return corrupted
+		}
+
+		if !json.Valid(scanner.Bytes()) {
+			corrupted = fmt.Errorf("journal %s is corrupted at line %d: invalid record", path, line)
+
+			continue
+		}
+
+		if err := decode(scanner.Bytes()); err != nil {
+			return fmt.Errorf("journal %s is corrupted at line %d: %w", path, line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// Rewrite replaces the journal at path with the given records, the journal is left as it was
+// unless every record reached the disk. The journals open on path must be opened again
+func Rewrite(path string, records []interface{}) error {
+	var buf bytes.Buffer
+
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(append(line, '\n'))
+	}
+
+	tmpPath := path + ".tmp"
+	if err := writeFileSync(tmpPath, buf.Bytes()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
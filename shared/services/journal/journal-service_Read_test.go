@@ -0,0 +1,89 @@
+package journal_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/journal"
+	"go.uber.org/zap"
+)
+
+type record struct {
+	Id int `json:"id"`
+}
+
+func Test_Read_Should_Replay_What_Was_Appended(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+
+	// A journal which does not exist is empty
+	g.Expect(read(path)).Should(BeEmpty())
+
+	sut, err := journal.NewJournalService(sugarLogger, path)
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.Append(record{Id: 1}, true)).Should(Succeed())
+	g.Expect(sut.Append(record{Id: 2}, false)).Should(Succeed())
+	g.Expect(sut.Close()).Should(Succeed())
+
+	g.Expect(read(path)).Should(Equal([]int{1, 2}))
+
+	g.Expect(journal.Rewrite(path, []interface{}{record{Id: 3}})).Should(Succeed())
+	g.Expect(read(path)).Should(Equal([]int{3}))
+
+	sut, err = journal.NewJournalService(sugarLogger, path)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	g.Expect(sut.Append(record{Id: 4}, true)).Should(Succeed())
+	g.Expect(read(path)).Should(Equal([]int{3, 4}))
+
+	g.Expect(sut.Clear()).Should(Succeed())
+	g.Expect(read(path)).Should(BeEmpty())
+}
+
+func Test_Read_Should_Skip_A_Truncated_Last_Line(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	g.Expect(os.WriteFile(path, []byte("{\"id\":1}\n{\"id\":2}\n{\"id\":3\n"), 0600)).Should(Succeed())
+
+	g.Expect(read(path)).Should(Equal([]int{1, 2}))
+}
+
+func Test_Read_Should_Return_Error_If_Journal_Is_Corrupted(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	g.Expect(os.WriteFile(path, []byte("{\"id\":1}\n{\"id\":2\n{\"id\":3}\n"), 0600)).Should(Succeed())
+
+	err := journal.Read(path, func(buf []byte) error {
+		return nil
+	})
+	g.Expect(err).Should(MatchError(ContainSubstring("corrupted at line 2")))
+}
+
+func read(path string) ([]int, error) {
+	ids := make([]int, 0)
+
+	err := journal.Read(path, func(buf []byte) error {
+		var r record
+		if err := json.Unmarshal(buf, &r); err != nil {
+			return err
+		}
+
+		ids = append(ids, r.Id)
+
+		return nil
+	})
+
+	return ids, err
+}
@@ -0,0 +1,76 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_journal is a generated GoMock package.
+package mock_journal
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockJournalInterface is a mock of JournalInterface interface.
+type MockJournalInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockJournalInterfaceMockRecorder
+}
+
+// MockJournalInterfaceMockRecorder is the mock recorder for MockJournalInterface.
+type MockJournalInterfaceMockRecorder struct {
+	mock *MockJournalInterface
+}
+
+// NewMockJournalInterface creates a new mock instance.
+func NewMockJournalInterface(ctrl *gomock.Controller) *MockJournalInterface {
+	mock := &MockJournalInterface{ctrl: ctrl}
+	mock.recorder = &MockJournalInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJournalInterface) EXPECT() *MockJournalInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockJournalInterface) Append(record interface{}, sync bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", record, sync)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockJournalInterfaceMockRecorder) Append(record, sync interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockJournalInterface)(nil).Append), record, sync)
+}
+
+// Clear mocks base method.
+func (m *MockJournalInterface) Clear() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockJournalInterfaceMockRecorder) Clear() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockJournalInterface)(nil).Clear))
+}
+
+// Close mocks base method.
+func (m *MockJournalInterface) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockJournalInterfaceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockJournalInterface)(nil).Close))
+}
@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+// ErrOutboxClosed is returned when publishing to an outbox which has been closed
+var ErrOutboxClosed = errors.New("outbox is closed")
+
+// DefaultRetryBackOff is the delay applied between successive attempts to publish a message
+// of the outbox, the last delay is repeated until the message is published
+var DefaultRetryBackOff = []time.Duration{
+	100 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	15 * time.Second,
+}
+
+// DefaultMaxAttempts is the number of failed attempts to publish a message of the outbox after
+// which it is dead lettered, it takes about an hour with DefaultRetryBackOff
+const DefaultMaxAttempts = 240
+
+// Entry is a message waiting in the outbox
+type Entry struct {
+	Id        string             `json:"id"`
+	Subject   string             `json:"subject"`
+	Header    robotbroker.Header `json:"header,omitempty"`
+	Data      []byte             `json:"data,omitempty"`
+	CreatedAt time.Time          `json:"createdAt"`
+	// Attempts is the number of failed attempts to publish the message since the outbox started
+	Attempts int `json:"-"`
+}
+
+// DeadLetter is a message of the outbox which was given up on, it is kept in the journal
+type DeadLetter struct {
+	Entry
+	// Error is the last error the message was not published with
+	Error string `json:"error"`
+}
+
+// DispatchHandler is called once a message of the outbox has been published
+type DispatchHandler func(msg *robotbroker.Message)
+
+// DeadLetterHandler is called once a message of the outbox has been given up on, err is why it
+// was not published
+type DeadLetterHandler func(msg *robotbroker.Message, err error)
+
+// Options configures an outbox
+type Options struct {
+	// RetryBackOff defaults to DefaultRetryBackOff
+	RetryBackOff []time.Duration
+	// MaxAttempts defaults to DefaultMaxAttempts, a message is dead lettered straight away when
+	// the publisher fails with a robotbroker.Permanent error
+	MaxAttempts    int
+	OnDispatched   DispatchHandler
+	OnDeadLettered DeadLetterHandler
+}
+
+// OutboxInterface defines contract for an outbox. Publish returns once a message has been
+// stored, a relay publishes the stored messages in order in the background
+type OutboxInterface interface {
+	robotbroker.PublisherInterface
+	// Pending returns the messages which have not been published yet
+	Pending() []Entry
+	// DeadLetters returns the messages which were given up on
+	DeadLetters() []DeadLetter
+	// Close stops the relay, pending messages are published once the outbox is opened again
+	Close() error
+}
@@ -0,0 +1,3 @@
+package outbox
+
+//go:generate mockgen -source=contract.go -destination=mock/mock-contract.go
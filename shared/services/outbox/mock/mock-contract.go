@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_outbox is a generated GoMock package.
+package mock_outbox
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	outbox "github.com/sepisoad/robot-challange/shared/services/outbox"
+	robotbroker "github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+// MockOutboxInterface is a mock of OutboxInterface interface.
+type MockOutboxInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxInterfaceMockRecorder
+}
+
+// MockOutboxInterfaceMockRecorder is the mock recorder for MockOutboxInterface.
+type MockOutboxInterfaceMockRecorder struct {
+	mock *MockOutboxInterface
+}
+
+// NewMockOutboxInterface creates a new mock instance.
+func NewMockOutboxInterface(ctrl *gomock.Controller) *MockOutboxInterface {
+	mock := &MockOutboxInterface{ctrl: ctrl}
+	mock.recorder = &MockOutboxInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxInterface) EXPECT() *MockOutboxInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockOutboxInterface) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockOutboxInterfaceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockOutboxInterface)(nil).Close))
+}
+
+// DeadLetters mocks base method.
+func (m *MockOutboxInterface) DeadLetters() []outbox.DeadLetter {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetters")
+	ret0, _ := ret[0].([]outbox.DeadLetter)
+	return ret0
+}
+
+// DeadLetters indicates an expected call of DeadLetters.
+func (mr *MockOutboxInterfaceMockRecorder) DeadLetters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetters", reflect.TypeOf((*MockOutboxInterface)(nil).DeadLetters))
+}
+
+// Pending mocks base method.
+func (m *MockOutboxInterface) Pending() []outbox.Entry {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pending")
+	ret0, _ := ret[0].([]outbox.Entry)
+	return ret0
+}
+
+// Pending indicates an expected call of Pending.
+func (mr *MockOutboxInterfaceMockRecorder) Pending() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockOutboxInterface)(nil).Pending))
+}
+
+// Publish mocks base method.
+func (m *MockOutboxInterface) Publish(msg *robotbroker.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockOutboxInterfaceMockRecorder) Publish(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockOutboxInterface)(nil).Publish), msg)
+}
@@ -0,0 +1,393 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lucsky/cuid"
+	"github.com/sepisoad/robot-challange/shared/services/journal"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+const (
+	recordAdded        = "added"
+	recordDispatched   = "dispatched"
+	recordDeadLettered = "deadLettered"
+)
+
+// record is a line of the journal the outbox is persisted in
+type record struct {
+	Op    string `json:"op"`
+	Entry *Entry `json:"entry,omitempty"`
+	Id    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type outboxService struct {
+	logger      *zap.SugaredLogger
+	path        string
+	publisher   robotbroker.PublisherInterface
+	options     Options
+	mutex       *sync.Mutex
+	cond        *sync.Cond
+	journal     journal.JournalInterface
+	entries     []*Entry
+	deadLetters []*DeadLetter
+	closed      bool
+	closing     chan struct{}
+	done        chan struct{}
+}
+
+// NewOutboxService creates a concrete instance of OutboxInterface which keeps the messages in
+// a journal file at path until publisher has persisted them. Messages left in the journal by
+// a previous run are published first
+func NewOutboxService(
+	logger *zap.SugaredLogger,
+	path string,
+	publisher robotbroker.PublisherInterface,
+	options Options) (OutboxInterface, error) {
+	if publisher == nil {
+		return nil, fmt.Errorf("an outbox requires a publisher")
+	}
+
+	if len(options.RetryBackOff) == 0 {
+		options.RetryBackOff = DefaultRetryBackOff
+	}
+
+	if options.MaxAttempts == 0 {
+		options.MaxAttempts = DefaultMaxAttempts
+	}
+
+	s := &outboxService{
+		logger:    logger,
+		path:      path,
+		publisher: publisher,
+		options:   options,
+		mutex:     &sync.Mutex{},
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	s.cond = sync.NewCond(s.mutex)
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	if len(s.entries) > 0 {
+		logger.Infof("Found %d messages waiting in the outbox %s", len(s.entries), path)
+	}
+
+	if len(s.deadLetters) > 0 {
+		logger.Warnf("Found %d dead letters in the outbox %s", len(s.deadLetters), path)
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+// Publish stores a message in the outbox, it returns once the message is on disk
+func (s *outboxService) Publish(msg *robotbroker.Message) error {
+	entry := &Entry{
+		Id:        cuid.New(),
+		Subject:   msg.Subject,
+		Header:    msg.Header.Clone(),
+		Data:      msg.Data,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// Lets the broker drop the copies published when an acknowledgement gets lost. JetStream only
+	// remembers the ids of the last 2 minutes, the duplicate window of the stream, so a message
+	// published again after a longer outage, or a restart, is delivered twice
+	entry.Header.Set(robotbroker.HEADER_MESSAGE_ID, entry.Id)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrOutboxClosed
+	}
+
+	if err := s.write(record{Op: recordAdded, Entry: entry}); err != nil {
+		return err
+	}
+
+	s.entries = append(s.entries, entry)
+	s.cond.Broadcast()
+
+	return nil
+}
+
+// Pending returns the messages which have not been published yet, in order
+func (s *outboxService) Pending() []Entry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, *entry)
+	}
+
+	return entries
+}
+
+// DeadLetters returns the messages which were given up on, in the order they were
+func (s *outboxService) DeadLetters() []DeadLetter {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deadLetters := make([]DeadLetter, 0, len(s.deadLetters))
+	for _, deadLetter := range s.deadLetters {
+		deadLetters = append(deadLetters, *deadLetter)
+	}
+
+	return deadLetters
+}
+
+// Close stops the relay once the message being published, if any, is done with
+func (s *outboxService) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+
+		return nil
+	}
+
+	s.closed = true
+	close(s.closing)
+	s.cond.Broadcast()
+	s.mutex.Unlock()
+
+	<-s.done
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.journal.Close()
+}
+
+// run publishes the stored messages in order until the outbox is closed, a message which keeps
+// failing is dead lettered so it does not hold back the ones behind it forever
+func (s *outboxService) run() {
+	defer close(s.done)
+
+	for {
+		s.mutex.Lock()
+		for len(s.entries) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+
+		if s.closed {
+			s.mutex.Unlock()
+
+			return
+		}
+
+		entry := s.entries[0]
+		s.mutex.Unlock()
+
+		msg := entry.message()
+
+		if err := s.publisher.Publish(msg); err != nil {
+			if robotbroker.IsPermanent(err) || s.failed(entry) >= s.options.MaxAttempts {
+				s.deadLetter(entry, msg, err)
+
+				continue
+			}
+
+			delay := s.retryDelay(entry)
+
+			s.logger.Warnf(
+				"Failed to publish message %s to %s, attempt %d, retrying in %v. Error: %v",
+				entry.Id,
+				entry.Subject,
+				entry.Attempts,
+				delay,
+				err)
+
+			select {
+			case <-time.After(delay):
+			case <-s.closing:
+			}
+
+			continue
+		}
+
+		s.mutex.Lock()
+		s.entries = s.entries[1:]
+
+		if err := s.dispatched(entry); err != nil {
+			// The message is published again on the next start, the broker drops the copy
+			s.logger.Errorf(
+				"Failed to record message %s as published in the outbox. Error: %v",
+				entry.Id,
+				err)
+		}
+		s.mutex.Unlock()
+
+		if s.options.OnDispatched != nil {
+			s.options.OnDispatched(msg)
+		}
+	}
+}
+
+// failed counts a failed attempt to publish an entry and returns the number of attempts
+func (s *outboxService) failed(entry *Entry) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry.Attempts++
+
+	return entry.Attempts
+}
+
+func (s *outboxService) retryDelay(entry *Entry) time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if entry.Attempts > len(s.options.RetryBackOff) {
+		return s.options.RetryBackOff[len(s.options.RetryBackOff)-1]
+	}
+
+	return s.options.RetryBackOff[entry.Attempts-1]
+}
+
+// deadLetter gives up on the entry at the head of the outbox, it is kept in the journal as a
+// dead letter
+func (s *outboxService) deadLetter(entry *Entry, msg *robotbroker.Message, err error) {
+	s.logger.Errorf(
+		"Gave up publishing message %s to %s after %d attempts. Error: %v",
+		entry.Id,
+		entry.Subject,
+		entry.Attempts,
+		err)
+
+	s.mutex.Lock()
+	s.entries = s.entries[1:]
+	s.deadLetters = append(s.deadLetters, &DeadLetter{Entry: *entry, Error: err.Error()})
+
+	if err := s.write(record{Op: recordDeadLettered, Id: entry.Id, Error: err.Error()}); err != nil {
+		// The message is tried again on the next start
+		s.logger.Errorf(
+			"Failed to record message %s as dead lettered in the outbox. Error: %v",
+			entry.Id,
+			err)
+	}
+	s.mutex.Unlock()
+
+	if s.options.OnDeadLettered != nil {
+		s.options.OnDeadLettered(msg, err)
+	}
+}
+
+// dispatched records that an entry has been published, the journal is emptied once nothing is
+// left in it. It must be called with the lock held
+func (s *outboxService) dispatched(entry *Entry) error {
+	if len(s.entries) == 0 && len(s.deadLetters) == 0 {
+		return s.journal.Clear()
+	}
+
+	return s.write(record{Op: recordDispatched, Id: entry.Id})
+}
+
+// write appends a record to the journal and waits for it to reach the disk, it must be called
+// with the lock held
+func (s *outboxService) write(r record) error {
+	return s.journal.Append(r, true)
+}
+
+// open replays the journal and rewrites it with the pending entries and the dead letters only
+func (s *outboxService) open() error {
+	entries, deadLetters, err := readJournal(s.path)
+	if err != nil {
+		return err
+	}
+
+	records := make([]interface{}, 0, len(entries)+2*len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		entry := deadLetter.Entry
+
+		records = append(
+			records,
+			record{Op: recordAdded, Entry: &entry},
+			record{Op: recordDeadLettered, Id: entry.Id, Error: deadLetter.Error})
+	}
+
+	for _, entry := range entries {
+		records = append(records, record{Op: recordAdded, Entry: entry})
+	}
+
+	if err := journal.Rewrite(s.path, records); err != nil {
+		return err
+	}
+
+	if s.journal, err = journal.NewJournalService(s.logger, s.path); err != nil {
+		return err
+	}
+
+	s.entries = entries
+	s.deadLetters = deadLetters
+
+	return nil
+}
+
+func readJournal(path string) ([]*Entry, []*DeadLetter, error) {
+	entries := make([]*Entry, 0)
+	deadLetters := make([]*DeadLetter, 0)
+
+	err := journal.Read(path, func(buf []byte) error {
+		var r record
+		if err := json.Unmarshal(buf, &r); err != nil {
+			return err
+		}
+
+		switch r.Op {
+		case recordAdded:
+			if r.Entry == nil {
+				return errors.New("missing entry")
+			}
+
+			entries = append(entries, r.Entry)
+		case recordDispatched:
+			entries, _ = removeEntry(entries, r.Id)
+		case recordDeadLettered:
+			var entry *Entry
+			if entries, entry = removeEntry(entries, r.Id); entry != nil {
+				deadLetters = append(deadLetters, &DeadLetter{Entry: *entry, Error: r.Error})
+			}
+		default:
+			return fmt.Errorf("unknown operation %q", r.Op)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return entries, deadLetters, nil
+}
+
+// removeEntry removes the entry with the given id, it returns the entry which is nil when it
+// is not found
+func removeEntry(entries []*Entry, id string) ([]*Entry, *Entry) {
+	for idx, entry := range entries {
+		if entry.Id == id {
+			return append(entries[:idx], entries[idx+1:]...), entry
+		}
+	}
+
+	return entries, nil
+}
+
+func (e *Entry) message() *robotbroker.Message {
+	msg := robotbroker.NewMessage(e.Subject)
+	msg.Header = e.Header.Clone()
+	msg.Data = e.Data
+
+	return msg
+}
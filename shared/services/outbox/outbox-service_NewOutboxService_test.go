@@ -0,0 +1,154 @@
+package outbox_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/outbox"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_NewOutboxService_Should_Publish_Messages_Left_By_A_Previous_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher := NewMockPublisherInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	// The broker is down during the first run
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Return(errors.New("no responders available")).
+		AnyTimes()
+
+	sut, err := outbox.NewOutboxService(
+		sugarLogger,
+		path,
+		mockPublisher,
+		outbox.Options{RetryBackOff: []time.Duration{time.Hour}})
+	g.Expect(err).Should(BeNil())
+
+	for _, taskId := range []string{"1", "2"} {
+		msg := robotbroker.NewMessage(robotbroker.TaskSubject(taskId, "created"))
+		msg.Header.Set("Content-Type", "application/json")
+		msg.Data = []byte(taskId)
+
+		g.Expect(sut.Publish(msg)).Should(Succeed())
+	}
+
+	pending := sut.Pending()
+	g.Expect(pending).Should(HaveLen(2))
+	g.Expect(sut.Close()).Should(Succeed())
+
+	ctrl.Finish()
+
+	ctrl = gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher = NewMockPublisherInterface(ctrl)
+
+	published := make(chan *robotbroker.Message, 10)
+
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message) error {
+				published <- msg
+
+				return nil
+			}).
+		Times(2)
+
+	sut, err = outbox.NewOutboxService(sugarLogger, path, mockPublisher, outbox.Options{})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	for idx, taskId := range []string{"1", "2"} {
+		var msg *robotbroker.Message
+		g.Eventually(published).Should(Receive(&msg))
+		g.Expect(msg.Subject).Should(Equal(robotbroker.TaskSubject(taskId, "created")))
+		g.Expect(msg.Data).Should(Equal([]byte(taskId)))
+		g.Expect(msg.Header.Get("Content-Type")).Should(Equal("application/json"))
+		g.Expect(msg.Header.Get(robotbroker.HEADER_MESSAGE_ID)).Should(Equal(pending[idx].Id))
+	}
+
+	g.Eventually(sut.Pending).Should(BeEmpty())
+}
+
+func Test_NewOutboxService_Should_Ignore_A_Truncated_Last_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher := NewMockPublisherInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	journal := `{"op":"added","entry":{"id":"a","subject":"task.1.created","createdAt":"2022-01-01T00:00:00Z"}}
+{"op":"added","entry":{"id":"b","subject":"task.2.created","createdAt":"2022-01-01T00:00:00Z"}}
+{"op":"dispatched","id":"a"}
+{"op":"added","entry":{"id":"c","sub`
+	g.Expect(os.WriteFile(path, []byte(journal), 0600)).Should(Succeed())
+
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Return(errors.New("no responders available")).
+		AnyTimes()
+
+	sut, err := outbox.NewOutboxService(
+		sugarLogger,
+		path,
+		mockPublisher,
+		outbox.Options{RetryBackOff: []time.Duration{time.Hour}})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	pending := sut.Pending()
+	g.Expect(pending).Should(HaveLen(1))
+	g.Expect(pending[0].Id).Should(Equal("b"))
+}
+
+func Test_NewOutboxService_Should_Return_Error_If_Journal_Is_Corrupted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher := NewMockPublisherInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	journal := `{"op":"added","entry":{"id":"a","sub
+{"op":"dispatched","id":"a"}
+`
+	g.Expect(os.WriteFile(path, []byte(journal), 0600)).Should(Succeed())
+
+	_, err = outbox.NewOutboxService(sugarLogger, path, mockPublisher, outbox.Options{})
+	g.Expect(err).ShouldNot(BeNil())
+}
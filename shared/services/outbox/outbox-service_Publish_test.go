@@ -0,0 +1,255 @@
+package outbox_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/outbox"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	. "github.com/sepisoad/robot-challange/shared/services/robotbroker/mock"
+	"go.uber.org/zap"
+)
+
+func Test_Publish_Should_Relay_Messages_In_Order(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher := NewMockPublisherInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	dispatched := make(chan *robotbroker.Message, 10)
+
+	sut, err := outbox.NewOutboxService(
+		sugarLogger,
+		filepath.Join(t.TempDir(), "outbox.jsonl"),
+		mockPublisher,
+		outbox.Options{
+			OnDispatched: func(msg *robotbroker.Message) {
+				dispatched <- msg
+			},
+		})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	subjects := make([]string, 0)
+
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		DoAndReturn(
+			func(msg *robotbroker.Message) error {
+				g.Expect(msg.Header.Get(robotbroker.HEADER_MESSAGE_ID)).ShouldNot(BeEmpty())
+				subjects = append(subjects, msg.Subject)
+
+				return nil
+			}).
+		Times(3)
+
+	for _, taskId := range []string{"1", "2", "3"} {
+		msg := robotbroker.NewMessage(robotbroker.TaskSubject(taskId, "created"))
+		msg.Data = []byte(taskId)
+
+		g.Expect(sut.Publish(msg)).Should(Succeed())
+	}
+
+	for _, taskId := range []string{"1", "2", "3"} {
+		var msg *robotbroker.Message
+		g.Eventually(dispatched).Should(Receive(&msg))
+		g.Expect(msg.Data).Should(Equal([]byte(taskId)))
+	}
+
+	g.Expect(subjects).Should(Equal([]string{"task.1.created", "task.2.created", "task.3.created"}))
+	g.Expect(sut.Pending()).Should(BeEmpty())
+}
+
+func Test_Publish_Should_Retry_Until_The_Message_Is_Published(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher := NewMockPublisherInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	dispatched := make(chan *robotbroker.Message, 10)
+
+	sut, err := outbox.NewOutboxService(
+		sugarLogger,
+		filepath.Join(t.TempDir(), "outbox.jsonl"),
+		mockPublisher,
+		outbox.Options{
+			RetryBackOff: []time.Duration{time.Millisecond},
+			OnDispatched: func(msg *robotbroker.Message) {
+				dispatched <- msg
+			},
+		})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	failure := mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Return(errors.New("no responders available")).
+		Times(3)
+
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Return(nil).
+		After(failure)
+
+	g.Expect(sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")))).Should(Succeed())
+
+	g.Eventually(dispatched).Should(Receive())
+	g.Expect(sut.Pending()).Should(BeEmpty())
+}
+
+func Test_Publish_Should_Return_Error_If_Outbox_Is_Closed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher := NewMockPublisherInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := outbox.NewOutboxService(
+		sugarLogger,
+		filepath.Join(t.TempDir(), "outbox.jsonl"),
+		mockPublisher,
+		outbox.Options{})
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.Close()).Should(Succeed())
+
+	err = sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")))
+	g.Expect(err).Should(Equal(outbox.ErrOutboxClosed))
+}
+
+func Test_Publish_Should_Dead_Letter_A_Message_Which_Keeps_Failing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher := NewMockPublisherInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	dispatched := make(chan *robotbroker.Message, 10)
+	deadLettered := make(chan *robotbroker.Message, 10)
+
+	sut, err := outbox.NewOutboxService(
+		sugarLogger,
+		path,
+		mockPublisher,
+		outbox.Options{
+			RetryBackOff: []time.Duration{time.Millisecond},
+			MaxAttempts:  3,
+			OnDispatched: func(msg *robotbroker.Message) {
+				dispatched <- msg
+			},
+			OnDeadLettered: func(msg *robotbroker.Message, err error) {
+				deadLettered <- msg
+			},
+		})
+	g.Expect(err).Should(BeNil())
+
+	failure := mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Return(errors.New("no responders available")).
+		Times(3)
+
+	// The message behind is not held back forever
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Return(nil).
+		After(failure)
+
+	g.Expect(sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")))).Should(Succeed())
+	g.Expect(sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject("2", "created")))).Should(Succeed())
+
+	var msg *robotbroker.Message
+	g.Eventually(deadLettered).Should(Receive(&msg))
+	g.Expect(msg.Subject).Should(Equal("task.1.created"))
+
+	g.Eventually(dispatched).Should(Receive(&msg))
+	g.Expect(msg.Subject).Should(Equal("task.2.created"))
+
+	g.Expect(sut.Pending()).Should(BeEmpty())
+	g.Expect(sut.DeadLetters()).Should(HaveLen(1))
+	g.Expect(sut.DeadLetters()[0].Subject).Should(Equal("task.1.created"))
+	g.Expect(sut.DeadLetters()[0].Error).Should(Equal("no responders available"))
+	g.Expect(sut.Close()).Should(Succeed())
+
+	// The dead letters are kept in the journal, they are not published again
+	sut, err = outbox.NewOutboxService(sugarLogger, path, NewMockPublisherInterface(ctrl), outbox.Options{})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	g.Expect(sut.Pending()).Should(BeEmpty())
+	g.Expect(sut.DeadLetters()).Should(HaveLen(1))
+	g.Expect(sut.DeadLetters()[0].Subject).Should(Equal("task.1.created"))
+}
+
+func Test_Publish_Should_Dead_Letter_A_Message_Which_Can_Never_Be_Published(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPublisher := NewMockPublisherInterface(ctrl)
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	deadLettered := make(chan error, 10)
+
+	sut, err := outbox.NewOutboxService(
+		sugarLogger,
+		filepath.Join(t.TempDir(), "outbox.jsonl"),
+		mockPublisher,
+		outbox.Options{
+			RetryBackOff: []time.Duration{time.Millisecond},
+			OnDeadLettered: func(msg *robotbroker.Message, err error) {
+				deadLettered <- err
+			},
+		})
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	tooLarge := errors.New("maximum payload exceeded")
+
+	mockPublisher.
+		EXPECT().
+		Publish(gomock.Any()).
+		Return(robotbroker.Permanent(tooLarge))
+
+	g.Expect(sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")))).Should(Succeed())
+
+	var deadLetterErr error
+	g.Eventually(deadLettered).Should(Receive(&deadLetterErr))
+	g.Expect(deadLetterErr).Should(MatchError(tooLarge))
+	g.Expect(sut.Pending()).Should(BeEmpty())
+}
@@ -0,0 +1,82 @@
+package robotbroker
+
+import (
+	"errors"
+
+	"github.com/nats-io/nats.go"
+)
+
+// ConnectionConfig holds the settings needed to connect to a secured NATS server, every
+// setting is optional. At most one of user and password, token, NKey seed file or credentials
+// file can be used to authenticate
+type ConnectionConfig struct {
+	// TLSCAFile is the certificate authority the server certificate is verified with, the
+	// system roots are used when it is empty
+	TLSCAFile string
+	// TLSCertFile and TLSKeyFile are the client certificate presented to the server
+	TLSCertFile string
+	TLSKeyFile  string
+
+	User     string
+	Password string
+	Token    string
+	// NKeySeedFile is the file holding the seed of the NKey the client signs in with
+	NKeySeedFile string
+	// CredsFile is the .creds file holding the user JWT and its NKey seed
+	CredsFile string
+}
+
+// natsOptions returns the NATS connection options of the configuration
+func (c ConnectionConfig) natsOptions() ([]nats.Option, error) {
+	opts := make([]nats.Option, 0)
+
+	if c.TLSCAFile != "" {
+		opts = append(opts, nats.RootCAs(c.TLSCAFile))
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return nil, errors.New("both the client certificate and its key are required")
+	}
+
+	if c.TLSCertFile != "" {
+		opts = append(opts, nats.ClientCert(c.TLSCertFile, c.TLSKeyFile))
+	}
+
+	authMethods := 0
+
+	if c.User != "" || c.Password != "" {
+		if c.User == "" {
+			return nil, errors.New("a password requires a user")
+		}
+
+		authMethods++
+		opts = append(opts, nats.UserInfo(c.User, c.Password))
+	}
+
+	if c.Token != "" {
+		authMethods++
+		opts = append(opts, nats.Token(c.Token))
+	}
+
+	if c.NKeySeedFile != "" {
+		authMethods++
+
+		nkeyOption, err := nats.NkeyOptionFromSeed(c.NKeySeedFile)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, nkeyOption)
+	}
+
+	if c.CredsFile != "" {
+		authMethods++
+		opts = append(opts, nats.UserCredentials(c.CredsFile))
+	}
+
+	if authMethods > 1 {
+		return nil, errors.New("only one of user and password, token, nkey seed or credentials can be used")
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,93 @@
+package robotbroker
+
+import (
+	"sync"
+	"time"
+)
+
+// connectionMonitor tracks the state of the connection to a broker and tells the registered
+// handlers about every transition
+type connectionMonitor struct {
+	mutex    *sync.Mutex
+	stats    ConnectionStats
+	handlers map[int]ConnectionStateHandler
+	nextId   int
+}
+
+func newConnectionMonitor() *connectionMonitor {
+	return &connectionMonitor{
+		mutex: &sync.Mutex{},
+		stats: ConnectionStats{
+			State: Connected,
+			Since: time.Now().UTC(),
+		},
+		handlers: make(map[int]ConnectionStateHandler),
+	}
+}
+
+func (m *connectionMonitor) state() ConnectionState {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.stats.State
+}
+
+func (m *connectionMonitor) snapshot() ConnectionStats {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.stats
+}
+
+func (m *connectionMonitor) register(handler ConnectionStateHandler) func() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	id := m.nextId
+	m.nextId++
+	m.handlers[id] = handler
+
+	return func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		delete(m.handlers, id)
+	}
+}
+
+// transition moves the connection to state, the handlers are only called when the state changes.
+// A closed connection never changes state again
+func (m *connectionMonitor) transition(state ConnectionState, err error) {
+	m.mutex.Lock()
+
+	if m.stats.State == state || m.stats.State == Closed {
+		m.mutex.Unlock()
+
+		return
+	}
+
+	switch state {
+	case Disconnected:
+		m.stats.Disconnects++
+	case Connected:
+		m.stats.Reconnects++
+	}
+
+	if err != nil {
+		m.stats.LastError = err.Error()
+	}
+
+	m.stats.State = state
+	m.stats.Since = time.Now().UTC()
+
+	handlers := make([]ConnectionStateHandler, 0, len(m.handlers))
+	for _, handler := range m.handlers {
+		handlers = append(handlers, handler)
+	}
+
+	m.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(state, err)
+	}
+}
@@ -1,16 +1,204 @@
 package robotbroker
 
-import "github.com/nats-io/nats.go"
+import (
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
 
 const (
 	CONSUMER_GROUP = "robot-"
 
-	SUBJECT_TASK  = "task"
-	SUBJECT_ROBOT = "robot"
+	// SUBJECT_WILDCARD matches exactly one subject token
+	SUBJECT_WILDCARD = "*"
+	// SUBJECT_FULL_WILDCARD matches one or more trailing subject tokens
+	SUBJECT_FULL_WILDCARD = ">"
+
+	// DEFAULT_WAREHOUSE is the warehouse robots belong to unless told otherwise
+	DEFAULT_WAREHOUSE = "default"
+
+	// SUBJECT_DEAD_LETTER prefixes the subject of every message that ran out of redeliveries
+	SUBJECT_DEAD_LETTER = "deadletter"
+	// SUBJECT_REQUEST prefixes the subject of every request, requests are not kept in a stream
+	SUBJECT_REQUEST = "request"
+
+	// SUBJECT_ADVISORY_MAX_DELIVERIES is followed by the stream and the consumer of a message
+	// JetStream gave up on without it being acknowledged
+	SUBJECT_ADVISORY_MAX_DELIVERIES = "$JS.EVENT.ADVISORY.CONSUMER.MAX_DELIVERIES"
+
+	STREAM_ROBOT       = "RobotStream"
+	STREAM_DEAD_LETTER = "DeadLetterStream"
+
+	// HEADER_MESSAGE_ID identifies a message, brokers which support it drop a message whose id
+	// has been published recently
+	HEADER_MESSAGE_ID = "Nats-Msg-Id"
+
+	// HEADER_DEAD_LETTER_SUBJECT holds the subject a dead letter was originally published on
+	HEADER_DEAD_LETTER_SUBJECT = "Dead-Letter-Subject"
+	// HEADER_DEAD_LETTER_STREAM_SEQUENCE holds the original stream sequence of a dead letter
+	HEADER_DEAD_LETTER_STREAM_SEQUENCE = "Dead-Letter-Stream-Sequence"
+	// HEADER_DEAD_LETTER_CONSUMER holds the durable consumer which gave up on a dead letter
+	HEADER_DEAD_LETTER_CONSUMER = "Dead-Letter-Consumer"
+	// HEADER_DEAD_LETTER_DELIVERIES holds the number of times a dead letter was delivered
+	HEADER_DEAD_LETTER_DELIVERIES = "Dead-Letter-Deliveries"
+	// HEADER_DEAD_LETTER_ERROR holds the last processing error of a dead letter
+	HEADER_DEAD_LETTER_ERROR = "Dead-Letter-Error"
+	// HEADER_DEAD_LETTER_TIME holds the time a message was dead lettered
+	HEADER_DEAD_LETTER_TIME = "Dead-Letter-Time"
+
+	// HEADER_REPLAY_SUBJECT holds the subject a replayed message was originally published on
+	HEADER_REPLAY_SUBJECT = "Replay-Subject"
+	// HEADER_REPLAY_STREAM_SEQUENCE holds the original stream sequence of a replayed message
+	HEADER_REPLAY_STREAM_SEQUENCE = "Replay-Stream-Sequence"
+
+	// HEADER_IMPORT_TIME holds the time an imported message was originally stored
+	HEADER_IMPORT_TIME = "Import-Time"
+	// HEADER_IMPORT_SUBJECT holds the subject an imported message was exported from, it is
+	// only set when the subject was rewritten
+	HEADER_IMPORT_SUBJECT = "Import-Subject"
+	// HEADER_IMPORT_STREAM_SEQUENCE holds the stream sequence an imported message was exported from
+	HEADER_IMPORT_STREAM_SEQUENCE = "Import-Stream-Sequence"
 )
 
+// MaxPendingAsyncPublishes is the number of asynchronously published messages the broker
+// can wait for the acknowledgement of
+const MaxPendingAsyncPublishes = 4096
+
+// DefaultMaxDeliver is the number of deliveries attempted before a message is dead lettered
+const DefaultMaxDeliver = 5
+
+// DefaultBackOff is the delay applied between successive redeliveries of a message
+var DefaultBackOff = []time.Duration{
+	time.Second,
+	5 * time.Second,
+	15 * time.Second,
+	30 * time.Second,
+}
+
+// Header holds the headers of a message
+type Header map[string][]string
+
+// Message is a message exchanged through a broker
+type Message struct {
+	Subject string
+	Header  Header
+	Data    []byte
+	// Sequence is the position of a received message in the stream it is kept in
+	Sequence uint64
+	// Deliveries is the number of times a received message has been delivered
+	Deliveries uint64
+	// Time is when a message read back from the broker was stored
+	Time time.Time
+}
+
+// MessageHandler processes a message delivered to a subscription. Returning nil
+// acknowledges the message, returning an error schedules a redelivery
+type MessageHandler func(msg *Message) error
+
+// RequestHandler processes a request and returns the reply sent back to the requester, the
+// request is left unanswered when it returns nil
+type RequestHandler func(msg *Message) *Message
+
+// AckHandler is called once the broker has persisted a message published asynchronously,
+// or has failed to
+type AckHandler func(err error)
+
+// ConnectionState is the state of the connection to a broker
+type ConnectionState string
+
+const (
+	// Connected brokers accept messages
+	Connected ConnectionState = "connected"
+	// Disconnected brokers are trying to reconnect, publishing fails until they do
+	Disconnected ConnectionState = "disconnected"
+	// Closed brokers are gone for good
+	Closed ConnectionState = "closed"
+)
+
+// ConnectionStats holds the state of the connection to a broker and its counters
+type ConnectionStats struct {
+	State ConnectionState
+	// Since is when the connection entered its state
+	Since time.Time
+	// LastError is the error the connection was last lost with
+	LastError   string
+	Disconnects uint64
+	Reconnects  uint64
+	InMsgs      uint64
+	OutMsgs     uint64
+	InBytes     uint64
+	OutBytes    uint64
+}
+
+// ConnectionStateHandler is called with every state the connection to a broker enters, err
+// holds the reason of a disconnection
+type ConnectionStateHandler func(state ConnectionState, err error)
+
+// HealthInterface defines contract for reporting the health of the connection to a broker
+type HealthInterface interface {
+	ConnectionState() ConnectionState
+	ConnectionStats() ConnectionStats
+	// OnConnectionStateChange calls handler with every state transition until the returned
+	// function is called
+	OnConnectionStateChange(handler ConnectionStateHandler) (unregister func())
+}
+
+// SubscriptionInterface defines contract for a subscription
+type SubscriptionInterface interface {
+	Unsubscribe() error
+}
+
+// PublisherInterface defines contract for anything messages can be published to
+type PublisherInterface interface {
+	// Publish returns once the message has been persisted, the error is a Permanent one when
+	// publishing the message again can not succeed
+	Publish(msg *Message) error
+}
+
+// RequesterInterface defines contract for anything requests can be sent to
+type RequesterInterface interface {
+	// Request sends a message to a single responder and waits up to timeout for its reply
+	Request(msg *Message, timeout time.Duration) (*Message, error)
+}
+
+// DeadLetterStoreInterface defines contract for the dead letters a broker keeps, they are
+// published on SUBJECT_DEAD_LETTER subjects and identified by their sequence
+type DeadLetterStoreInterface interface {
+	// DeadLetters returns the dead letters which are still kept ordered by sequence
+	DeadLetters() ([]*Message, error)
+	// GetDeadLetter returns the dead letter kept at sequence, ErrMessageNotFound when there is none
+	GetDeadLetter(sequence uint64) (*Message, error)
+	// DeleteDeadLetter forgets the dead letter kept at sequence
+	DeleteDeadLetter(sequence uint64) error
+}
+
 // RobotBrokerInterface defines contracts for a message broker
 type RobotBrokerInterface interface {
+	PublisherInterface
+	RequesterInterface
+	HealthInterface
+	DeadLetterStoreInterface
 	Close()
+	// PublishAsync hands a message over to the broker without waiting for it to be persisted,
+	// onAck is called once it has been unless an error is returned
+	PublishAsync(msg *Message, onAck AckHandler) error
+	// Subscribe delivers every message published on subject to handler
+	Subscribe(subject string, handler MessageHandler) (SubscriptionInterface, error)
+	// SubscribeLast delivers the last message kept of every subject matching subject to handler,
+	// then every message published from now on. Each subscriber receives them, which suits the
+	// state a late subscriber has to catch up with
+	SubscribeLast(subject string, handler MessageHandler) (SubscriptionInterface, error)
+	// QueueSubscribe delivers every message published on subject to a single member of
+	// the queue group, the queue group survives restarts when the broker persists messages
+	QueueSubscribe(subject string, queue string, handler MessageHandler) (SubscriptionInterface, error)
+	// QueueRespond replies to the requests sent on subject, a single member of the queue group
+	// receives each request. Requests are not persisted, only the members listening get them
+	QueueRespond(subject string, queue string, handler RequestHandler) (SubscriptionInterface, error)
+}
+
+// JetStreamBrokerInterface defines contracts for a message broker backed by NATS JetStream,
+// it gives access to the streams which have no broker neutral counterpart
+type JetStreamBrokerInterface interface {
+	RobotBrokerInterface
 	CreateNewJetStream() (nats.JetStreamContext, error)
 }
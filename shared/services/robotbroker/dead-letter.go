@@ -0,0 +1,27 @@
+package robotbroker
+
+import (
+	"strconv"
+	"time"
+)
+
+// newDeadLetter creates the dead letter of a message which could not be processed by
+// consumer, the original headers are kept
+func newDeadLetter(msg *Message, consumer string, handlerErr error) *Message {
+	deadLetter := NewMessage(SUBJECT_DEAD_LETTER + "." + msg.Subject)
+	deadLetter.Header = msg.Header.Clone()
+	deadLetter.Data = msg.Data
+
+	deadLetter.Header.Set(HEADER_DEAD_LETTER_SUBJECT, msg.Subject)
+	deadLetter.Header.Set(
+		HEADER_DEAD_LETTER_STREAM_SEQUENCE,
+		strconv.FormatUint(msg.Sequence, 10))
+	deadLetter.Header.Set(HEADER_DEAD_LETTER_CONSUMER, consumer)
+	deadLetter.Header.Set(
+		HEADER_DEAD_LETTER_DELIVERIES,
+		strconv.FormatUint(msg.Deliveries, 10))
+	deadLetter.Header.Set(HEADER_DEAD_LETTER_ERROR, handlerErr.Error())
+	deadLetter.Header.Set(HEADER_DEAD_LETTER_TIME, time.Now().UTC().Format(time.RFC3339Nano))
+
+	return deadLetter
+}
@@ -0,0 +1,44 @@
+package robotbroker
+
+import "errors"
+
+var (
+	// ErrNoResponders is returned when nobody listens to the subject of a request
+	ErrNoResponders = errors.New("no responders available for request")
+	// ErrRequestTimeout is returned when a request is not replied to in time
+	ErrRequestTimeout = errors.New("timed out waiting for a reply")
+	// ErrMessageNotFound is returned when reading a message the broker does not keep
+	ErrMessageNotFound = errors.New("message not found")
+
+	// errMaxDeliveries is the error of the dead letters which were never acknowledged in time
+	errMaxDeliveries = errors.New("the message was not acknowledged within its ack wait")
+)
+
+type permanentError struct {
+	err error
+}
+
+// Permanent wraps an error returned by a MessageHandler to denote that redelivering the
+// message cannot succeed, so that it is dead lettered straight away
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether an error has been marked as permanent
+func IsPermanent(err error) bool {
+	var target *permanentError
+
+	return errors.As(err, &target)
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,550 @@
+package robotbroker
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrBrokerClosed is returned when using a broker which has been closed
+var ErrBrokerClosed = errors.New("broker is closed")
+
+type inMemoryRobotBrokerService struct {
+	logger        *zap.SugaredLogger
+	mutex         *sync.Mutex
+	sequence      uint64
+	subscriptions []*inMemorySubscription
+	responders    []*inMemoryResponder
+	queueCursors  map[string]int
+	deadLetters   []*Message
+	// lastMessages holds the last message published on every subject for SubscribeLast
+	lastMessages map[string]*Message
+	closed       bool
+	monitor      *connectionMonitor
+	outMsgs      uint64
+	outBytes     uint64
+}
+
+type inMemorySubscription struct {
+	broker  *inMemoryRobotBrokerService
+	subject string
+	queue   string
+	handler MessageHandler
+	mutex   *sync.Mutex
+	cond    *sync.Cond
+	pending []*Message
+	closed  bool
+}
+
+type inMemoryResponder struct {
+	broker  *inMemoryRobotBrokerService
+	subject string
+	queue   string
+	handler RequestHandler
+}
+
+// NewInMemoryRobotBrokerService creates a concrete instance of RobotBrokerInterface which
+// keeps the messages in memory. Members of a queue group take turns to receive the messages
+// and failed messages are redelivered straight away until they are dead lettered, only the dead
+// letters and the last message of every subject are kept. This makes it suitable for unit tests
+// and single process runs
+func NewInMemoryRobotBrokerService(logger *zap.SugaredLogger) (RobotBrokerInterface, error) {
+	return &inMemoryRobotBrokerService{
+		logger:       logger,
+		mutex:        &sync.Mutex{},
+		queueCursors: make(map[string]int),
+		lastMessages: make(map[string]*Message),
+		monitor:      newConnectionMonitor(),
+	}, nil
+}
+
+// Close unsubscribes every subscription, messages which have not been processed yet are dropped
+func (s *inMemoryRobotBrokerService) Close() {
+	s.mutex.Lock()
+	subscriptions := s.subscriptions
+	s.subscriptions = nil
+	s.responders = nil
+	s.closed = true
+	s.mutex.Unlock()
+
+	for _, subscription := range subscriptions {
+		subscription.stop()
+	}
+
+	s.monitor.transition(Closed, nil)
+}
+
+// ConnectionState returns connected until the broker is closed
+func (s *inMemoryRobotBrokerService) ConnectionState() ConnectionState {
+	return s.monitor.state()
+}
+
+// ConnectionStats returns the state of the broker and the number of messages published to it
+func (s *inMemoryRobotBrokerService) ConnectionStats() ConnectionStats {
+	stats := s.monitor.snapshot()
+
+	s.mutex.Lock()
+	stats.OutMsgs = s.outMsgs
+	stats.OutBytes = s.outBytes
+	s.mutex.Unlock()
+
+	return stats
+}
+
+// OnConnectionStateChange calls handler once the broker is closed
+func (s *inMemoryRobotBrokerService) OnConnectionStateChange(handler ConnectionStateHandler) func() {
+	return s.monitor.register(handler)
+}
+
+// Publish delivers a message to every matching subscription and to one member of every
+// matching queue group
+func (s *inMemoryRobotBrokerService) Publish(msg *Message) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return ErrBrokerClosed
+	}
+
+	s.sequence++
+	sequence := s.sequence
+
+	s.outMsgs++
+	s.outBytes += uint64(len(msg.Data))
+
+	if strings.HasPrefix(msg.Subject, SUBJECT_DEAD_LETTER+".") {
+		deadLetter := copyMessage(msg, sequence, 0)
+		deadLetter.Time = time.Now().UTC()
+
+		s.deadLetters = append(s.deadLetters, deadLetter)
+	}
+
+	s.lastMessages[msg.Subject] = copyMessage(msg, sequence, 0)
+
+	queues := make(map[string][]*inMemorySubscription)
+
+	for _, subscription := range s.subscriptions {
+		if !MatchSubject(subscription.subject, msg.Subject) {
+			continue
+		}
+
+		if subscription.queue == "" {
+			subscription.deliver(copyMessage(msg, sequence, 1))
+
+			continue
+		}
+
+		queues[subscription.queue] = append(queues[subscription.queue], subscription)
+	}
+
+	for queue, members := range queues {
+		s.nextMember(queue, members).deliver(copyMessage(msg, sequence, 1))
+	}
+
+	return nil
+}
+
+// PublishAsync delivers a message like Publish does, messages are never lost once delivered
+// so onAck is called straight away
+func (s *inMemoryRobotBrokerService) PublishAsync(msg *Message, onAck AckHandler) error {
+	if err := s.Publish(msg); err != nil {
+		return err
+	}
+
+	onAck(nil)
+
+	return nil
+}
+
+// Subscribe delivers every message published on subject from now on to handler
+func (s *inMemoryRobotBrokerService) Subscribe(
+	subject string,
+	handler MessageHandler) (SubscriptionInterface, error) {
+	return s.subscribe(subject, "", handler)
+}
+
+// SubscribeLast delivers the last message published on every subject matching subject to
+// handler in the order they were published, then every message published from now on
+func (s *inMemoryRobotBrokerService) SubscribeLast(
+	subject string,
+	handler MessageHandler) (SubscriptionInterface, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	lastMessages := make([]*Message, 0)
+	for _, msg := range s.lastMessages {
+		if MatchSubject(subject, msg.Subject) {
+			lastMessages = append(lastMessages, msg)
+		}
+	}
+
+	sort.Slice(lastMessages, func(i, j int) bool {
+		return lastMessages[i].Sequence < lastMessages[j].Sequence
+	})
+
+	// The lock is held until they are delivered so no message published meanwhile overtakes them
+	subscription, err := s.subscribeLocked(subject, "", handler)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, msg := range lastMessages {
+		subscription.deliver(copyMessage(msg, msg.Sequence, 1))
+	}
+
+	return subscription, nil
+}
+
+// QueueSubscribe delivers every message published on subject from now on to a single member
+// of the queue group
+func (s *inMemoryRobotBrokerService) QueueSubscribe(
+	subject string,
+	queue string,
+	handler MessageHandler) (SubscriptionInterface, error) {
+	if queue == "" {
+		return nil, errors.New("queue name is required")
+	}
+
+	return s.subscribe(subject, queue, handler)
+}
+
+// DeadLetters returns the dead letters published so far which were not deleted
+func (s *inMemoryRobotBrokerService) DeadLetters() ([]*Message, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deadLetters := make([]*Message, 0, len(s.deadLetters))
+	for _, deadLetter := range s.deadLetters {
+		deadLetters = append(deadLetters, copyStoredMessage(deadLetter))
+	}
+
+	return deadLetters, nil
+}
+
+// GetDeadLetter returns the dead letter published with sequence
+func (s *inMemoryRobotBrokerService) GetDeadLetter(sequence uint64) (*Message, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, deadLetter := range s.deadLetters {
+		if deadLetter.Sequence == sequence {
+			return copyStoredMessage(deadLetter), nil
+		}
+	}
+
+	return nil, ErrMessageNotFound
+}
+
+// DeleteDeadLetter forgets the dead letter published with sequence
+func (s *inMemoryRobotBrokerService) DeleteDeadLetter(sequence uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for idx, deadLetter := range s.deadLetters {
+		if deadLetter.Sequence == sequence {
+			s.deadLetters = append(s.deadLetters[:idx], s.deadLetters[idx+1:]...)
+
+			return nil
+		}
+	}
+
+	return ErrMessageNotFound
+}
+
+// Request hands a message over to one member of every matching queue group of responders and
+// returns the first reply
+func (s *inMemoryRobotBrokerService) Request(msg *Message, timeout time.Duration) (*Message, error) {
+	s.mutex.Lock()
+
+	if s.closed {
+		s.mutex.Unlock()
+
+		return nil, ErrBrokerClosed
+	}
+
+	queues := make(map[string][]*inMemoryResponder)
+	for _, responder := range s.responders {
+		if MatchSubject(responder.subject, msg.Subject) {
+			queues[responder.queue] = append(queues[responder.queue], responder)
+		}
+	}
+
+	handlers := make([]RequestHandler, 0, len(queues))
+	for queue, members := range queues {
+		cursor := s.queueCursors[queue] % len(members)
+		s.queueCursors[queue] = cursor + 1
+
+		handlers = append(handlers, members[cursor].handler)
+	}
+
+	s.mutex.Unlock()
+
+	if len(handlers) == 0 {
+		return nil, ErrNoResponders
+	}
+
+	replies := make(chan *Message, len(handlers))
+	for _, handler := range handlers {
+		go func(handler RequestHandler) {
+			if reply := handler(copyMessage(msg, 0, 1)); reply != nil {
+				replies <- reply
+			}
+		}(handler)
+	}
+
+	select {
+	case reply := <-replies:
+		return reply, nil
+	case <-time.After(timeout):
+		return nil, ErrRequestTimeout
+	}
+}
+
+// QueueRespond replies to the requests sent on subject, members of the queue group take turns
+// to receive them
+func (s *inMemoryRobotBrokerService) QueueRespond(
+	subject string,
+	queue string,
+	handler RequestHandler) (SubscriptionInterface, error) {
+	if queue == "" {
+		return nil, errors.New("queue name is required")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil, ErrBrokerClosed
+	}
+
+	responder := &inMemoryResponder{
+		broker:  s,
+		subject: subject,
+		queue:   queue,
+		handler: handler,
+	}
+
+	s.responders = append(s.responders, responder)
+
+	return responder, nil
+}
+
+func (s *inMemoryRobotBrokerService) subscribe(
+	subject string,
+	queue string,
+	handler MessageHandler) (SubscriptionInterface, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	subscription, err := s.subscribeLocked(subject, queue, handler)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// subscribeLocked adds a subscription, it must be called with the lock held
+func (s *inMemoryRobotBrokerService) subscribeLocked(
+	subject string,
+	queue string,
+	handler MessageHandler) (*inMemorySubscription, error) {
+	if s.closed {
+		return nil, ErrBrokerClosed
+	}
+
+	subscription := &inMemorySubscription{
+		broker:  s,
+		subject: subject,
+		queue:   queue,
+		handler: handler,
+		mutex:   &sync.Mutex{},
+	}
+	subscription.cond = sync.NewCond(subscription.mutex)
+
+	s.subscriptions = append(s.subscriptions, subscription)
+
+	go subscription.run()
+
+	return subscription, nil
+}
+
+// nextMember picks the member of a queue group whose turn it is, it must be called with
+// the lock held
+func (s *inMemoryRobotBrokerService) nextMember(
+	queue string,
+	members []*inMemorySubscription) *inMemorySubscription {
+	cursor := s.queueCursors[queue] % len(members)
+	s.queueCursors[queue] = cursor + 1
+
+	return members[cursor]
+}
+
+// redeliver hands a failed message over to the next member of the queue group, or back to
+// the subscription it failed on if it does not belong to a queue group
+func (s *inMemoryRobotBrokerService) redeliver(subscription *inMemorySubscription, msg *Message) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	redelivered := copyMessage(msg, msg.Sequence, msg.Deliveries+1)
+
+	if subscription.queue == "" {
+		subscription.deliver(redelivered)
+
+		return
+	}
+
+	members := make([]*inMemorySubscription, 0)
+	for _, member := range s.subscriptions {
+		if member.queue == subscription.queue && member.subject == subscription.subject {
+			members = append(members, member)
+		}
+	}
+
+	if len(members) == 0 {
+		// The queue group is gone, like a durable consumer the message waits for it
+		return
+	}
+
+	s.nextMember(subscription.queue, members).deliver(redelivered)
+}
+
+func (s *inMemoryRobotBrokerService) remove(subscription *inMemorySubscription) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for idx, candidate := range s.subscriptions {
+		if candidate == subscription {
+			s.subscriptions = append(s.subscriptions[:idx], s.subscriptions[idx+1:]...)
+
+			return
+		}
+	}
+}
+
+func (s *inMemoryRobotBrokerService) handleFailure(
+	subscription *inMemorySubscription,
+	msg *Message,
+	handlerErr error) {
+	if !IsPermanent(handlerErr) && msg.Deliveries < DefaultMaxDeliver {
+		s.logger.Warnf(
+			"Failed to process message %d from %s, attempt %d of %d. Error: %v",
+			msg.Sequence,
+			msg.Subject,
+			msg.Deliveries,
+			DefaultMaxDeliver,
+			handlerErr)
+
+		s.redeliver(subscription, msg)
+
+		return
+	}
+
+	if err := s.Publish(newDeadLetter(msg, subscription.queue, handlerErr)); err != nil {
+		s.logger.Errorf(
+			"Failed to dead letter message %d from %s. Error: %v",
+			msg.Sequence,
+			msg.Subject,
+			err)
+
+		return
+	}
+
+	s.logger.Errorf(
+		"Message %d from %s has been dead lettered after %d attempts. Error: %v",
+		msg.Sequence,
+		msg.Subject,
+		msg.Deliveries,
+		handlerErr)
+}
+
+// Unsubscribe stops the delivery of messages to the subscription, a message which is being
+// processed is not interrupted
+func (s *inMemorySubscription) Unsubscribe() error {
+	s.broker.remove(s)
+	s.stop()
+
+	return nil
+}
+
+// Unsubscribe stops replying to requests, a request which is being processed is not interrupted
+func (s *inMemoryResponder) Unsubscribe() error {
+	s.broker.mutex.Lock()
+	defer s.broker.mutex.Unlock()
+
+	for idx, candidate := range s.broker.responders {
+		if candidate == s {
+			s.broker.responders = append(s.broker.responders[:idx], s.broker.responders[idx+1:]...)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *inMemorySubscription) deliver(msg *Message) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.pending = append(s.pending, msg)
+	s.cond.Signal()
+}
+
+func (s *inMemorySubscription) stop() {
+	s.mutex.Lock()
+	s.closed = true
+	s.cond.Signal()
+	s.mutex.Unlock()
+}
+
+func (s *inMemorySubscription) run() {
+	for {
+		s.mutex.Lock()
+		for len(s.pending) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+
+		if s.closed {
+			s.mutex.Unlock()
+
+			return
+		}
+
+		msg := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mutex.Unlock()
+
+		if err := s.handler(msg); err != nil {
+			s.broker.handleFailure(s, msg, err)
+		}
+	}
+}
+
+func copyStoredMessage(msg *Message) *Message {
+	stored := copyMessage(msg, msg.Sequence, msg.Deliveries)
+	stored.Time = msg.Time
+
+	return stored
+}
+
+func copyMessage(msg *Message, sequence uint64, deliveries uint64) *Message {
+	data := make([]byte, len(msg.Data))
+	copy(data, msg.Data)
+
+	return &Message{
+		Subject:    msg.Subject,
+		Header:     msg.Header.Clone(),
+		Data:       data,
+		Sequence:   sequence,
+		Deliveries: deliveries,
+	}
+}
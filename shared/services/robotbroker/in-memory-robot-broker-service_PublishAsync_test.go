@@ -0,0 +1,64 @@
+package robotbroker_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_PublishAsync_Should_Deliver_Message_And_Acknowledge_It(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	messages := make(chan *robotbroker.Message, 10)
+
+	_, err = sut.Subscribe(
+		robotbroker.TaskSubject(robotbroker.SUBJECT_WILDCARD, "created"),
+		func(msg *robotbroker.Message) error {
+			messages <- msg
+
+			return nil
+		})
+	g.Expect(err).Should(BeNil())
+
+	acks := make(chan error, 1)
+
+	err = sut.PublishAsync(
+		robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")),
+		func(err error) {
+			acks <- err
+		})
+	g.Expect(err).Should(BeNil())
+
+	g.Eventually(acks).Should(Receive(BeNil()))
+	g.Eventually(messages).Should(Receive())
+}
+
+func Test_PublishAsync_Should_Return_Error_If_Broker_Is_Closed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+
+	sut.Close()
+
+	err = sut.PublishAsync(
+		robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")),
+		func(err error) {
+			t.Error("the message must not be acknowledged")
+		})
+	g.Expect(err).Should(Equal(robotbroker.ErrBrokerClosed))
+}
@@ -0,0 +1,84 @@
+package robotbroker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_Publish_Should_Deliver_Message_To_Every_Matching_Subscription(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	robotMessages := make(chan *robotbroker.Message, 10)
+	singleRobotMessages := make(chan *robotbroker.Message, 10)
+
+	_, err = sut.Subscribe(
+		robotbroker.SUBJECT_ROBOT+"."+robotbroker.SUBJECT_FULL_WILDCARD,
+		func(msg *robotbroker.Message) error {
+			robotMessages <- msg
+
+			return nil
+		})
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.Subscribe(
+		robotbroker.RobotSubject("north", "3", robotbroker.SUBJECT_WILDCARD),
+		func(msg *robotbroker.Message) error {
+			singleRobotMessages <- msg
+
+			return nil
+		})
+	g.Expect(err).Should(BeNil())
+
+	expectedValue := cuid.New()
+
+	msg := robotbroker.NewMessage(robotbroker.RobotSubject("north", "3", "moved"))
+	msg.Header.Set("Test", expectedValue)
+	msg.Data = []byte(expectedValue)
+
+	err = sut.Publish(msg)
+	g.Expect(err).Should(BeNil())
+
+	err = sut.Publish(robotbroker.NewMessage(robotbroker.RobotSubject("north", "4", "moved")))
+	g.Expect(err).Should(BeNil())
+
+	var received *robotbroker.Message
+	g.Eventually(singleRobotMessages).Should(Receive(&received))
+	g.Expect(received.Subject).Should(Equal(msg.Subject))
+	g.Expect(received.Header.Get("Test")).Should(Equal(expectedValue))
+	g.Expect(string(received.Data)).Should(Equal(expectedValue))
+	g.Expect(received.Sequence).Should(Equal(uint64(1)))
+	g.Expect(received.Deliveries).Should(Equal(uint64(1)))
+
+	g.Eventually(robotMessages).Should(Receive())
+	g.Eventually(robotMessages).Should(Receive())
+	g.Consistently(singleRobotMessages, 100*time.Millisecond).ShouldNot(Receive())
+}
+
+func Test_Publish_Should_Return_Error_If_Broker_Is_Closed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+
+	sut.Close()
+
+	err = sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")))
+	g.Expect(err).Should(MatchError(robotbroker.ErrBrokerClosed))
+}
@@ -0,0 +1,132 @@
+package robotbroker_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lucsky/cuid"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_QueueSubscribe_Should_Deliver_Each_Message_To_A_Single_Member(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	subject := robotbroker.TaskSubject(robotbroker.SUBJECT_WILDCARD, "created")
+	queue := cuid.New()
+
+	var firstMemberCount, secondMemberCount int32
+
+	_, err = sut.QueueSubscribe(subject, queue, func(msg *robotbroker.Message) error {
+		atomic.AddInt32(&firstMemberCount, 1)
+
+		return nil
+	})
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.QueueSubscribe(subject, queue, func(msg *robotbroker.Message) error {
+		atomic.AddInt32(&secondMemberCount, 1)
+
+		return nil
+	})
+	g.Expect(err).Should(BeNil())
+
+	for idx := 0; idx < 10; idx++ {
+		err = sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject(cuid.New(), "created")))
+		g.Expect(err).Should(BeNil())
+	}
+
+	g.Eventually(func() int32 {
+		return atomic.LoadInt32(&firstMemberCount) + atomic.LoadInt32(&secondMemberCount)
+	}).Should(Equal(int32(10)))
+	g.Consistently(func() int32 {
+		return atomic.LoadInt32(&firstMemberCount) + atomic.LoadInt32(&secondMemberCount)
+	}, 100*time.Millisecond).Should(Equal(int32(10)))
+	g.Expect(atomic.LoadInt32(&firstMemberCount)).Should(Equal(int32(5)))
+}
+
+func Test_QueueSubscribe_Should_Redeliver_Failed_Messages(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	deliveries := make(chan uint64, robotbroker.DefaultMaxDeliver)
+
+	_, err = sut.QueueSubscribe(robotbroker.TaskSubject("1", "created"), cuid.New(), func(msg *robotbroker.Message) error {
+		deliveries <- msg.Deliveries
+
+		if msg.Deliveries < 2 {
+			return errors.New(cuid.New())
+		}
+
+		return nil
+	})
+	g.Expect(err).Should(BeNil())
+
+	err = sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")))
+	g.Expect(err).Should(BeNil())
+
+	g.Eventually(deliveries).Should(Receive(Equal(uint64(1))))
+	g.Eventually(deliveries).Should(Receive(Equal(uint64(2))))
+	g.Consistently(deliveries, 100*time.Millisecond).ShouldNot(Receive())
+}
+
+func Test_QueueSubscribe_Should_Dead_Letter_Messages_Which_Failed_Permanently(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	subject := robotbroker.TaskSubject("1", "created")
+	queue := cuid.New()
+	expectedErr := errors.New(cuid.New())
+
+	deadLetters := make(chan *robotbroker.Message, 1)
+
+	_, err = sut.Subscribe(
+		robotbroker.SUBJECT_DEAD_LETTER+"."+robotbroker.SUBJECT_FULL_WILDCARD,
+		func(msg *robotbroker.Message) error {
+			deadLetters <- msg
+
+			return nil
+		})
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.QueueSubscribe(subject, queue, func(msg *robotbroker.Message) error {
+		return robotbroker.Permanent(expectedErr)
+	})
+	g.Expect(err).Should(BeNil())
+
+	err = sut.Publish(robotbroker.NewMessage(subject))
+	g.Expect(err).Should(BeNil())
+
+	var deadLetter *robotbroker.Message
+	g.Eventually(deadLetters).Should(Receive(&deadLetter))
+	g.Expect(deadLetter.Subject).Should(Equal(robotbroker.SUBJECT_DEAD_LETTER + "." + subject))
+	g.Expect(deadLetter.Header.Get(robotbroker.HEADER_DEAD_LETTER_SUBJECT)).Should(Equal(subject))
+	g.Expect(deadLetter.Header.Get(robotbroker.HEADER_DEAD_LETTER_CONSUMER)).Should(Equal(queue))
+	g.Expect(deadLetter.Header.Get(robotbroker.HEADER_DEAD_LETTER_DELIVERIES)).Should(Equal("1"))
+	g.Expect(deadLetter.Header.Get(robotbroker.HEADER_DEAD_LETTER_ERROR)).Should(Equal(expectedErr.Error()))
+}
@@ -0,0 +1,72 @@
+package robotbroker_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_Request_Should_Return_Reply_Of_Responder(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	_, err = sut.QueueRespond(
+		robotbroker.RequestSubject(robotbroker.TaskSubject(robotbroker.SUBJECT_WILDCARD, "created")),
+		"responders",
+		func(msg *robotbroker.Message) *robotbroker.Message {
+			reply := robotbroker.NewMessage(msg.Subject)
+			reply.Data = append([]byte("re: "), msg.Data...)
+
+			return reply
+		})
+	g.Expect(err).Should(BeNil())
+
+	msg := robotbroker.NewMessage(robotbroker.RequestSubject(robotbroker.TaskSubject("1", "created")))
+	msg.Data = []byte("hello")
+
+	reply, err := sut.Request(msg, time.Second)
+	g.Expect(err).Should(BeNil())
+	g.Expect(string(reply.Data)).Should(Equal("re: hello"))
+}
+
+func Test_Request_Should_Return_Error_If_Nobody_Responds(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	subscription, err := sut.QueueRespond(
+		robotbroker.RequestSubject(robotbroker.TaskSubject(robotbroker.SUBJECT_WILDCARD, "created")),
+		"responders",
+		func(msg *robotbroker.Message) *robotbroker.Message {
+			time.Sleep(time.Second)
+
+			return robotbroker.NewMessage(msg.Subject)
+		})
+	g.Expect(err).Should(BeNil())
+
+	msg := robotbroker.NewMessage(robotbroker.RequestSubject(robotbroker.TaskSubject("1", "created")))
+
+	_, err = sut.Request(msg, 10*time.Millisecond)
+	g.Expect(err).Should(Equal(robotbroker.ErrRequestTimeout))
+
+	g.Expect(subscription.Unsubscribe()).Should(Succeed())
+
+	_, err = sut.Request(msg, time.Second)
+	g.Expect(err).Should(Equal(robotbroker.ErrNoResponders))
+}
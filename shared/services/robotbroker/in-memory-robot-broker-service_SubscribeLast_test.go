@@ -0,0 +1,60 @@
+package robotbroker_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_SubscribeLast_Should_Deliver_The_Last_Message_Of_Every_Subject_To_Every_Subscriber(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	for _, data := range []string{"north-1", "south-1", "north-2"} {
+		msg := robotbroker.NewMessage(robotbroker.WarehouseSubject(data[:len(data)-2], "configured"))
+		msg.Data = []byte(data)
+
+		g.Expect(sut.Publish(msg)).Should(Succeed())
+	}
+
+	g.Expect(sut.Publish(robotbroker.NewMessage(robotbroker.RobotSubject("north", "1", "moved")))).Should(Succeed())
+
+	subject := robotbroker.WarehouseSubject(robotbroker.SUBJECT_WILDCARD, "configured")
+
+	subscribers := make([]chan string, 0)
+
+	for subscriber := 0; subscriber < 2; subscriber++ {
+		received := make(chan string, 10)
+		subscribers = append(subscribers, received)
+
+		subscription, err := sut.SubscribeLast(subject, func(msg *robotbroker.Message) error {
+			received <- string(msg.Data)
+
+			return nil
+		})
+		g.Expect(err).Should(BeNil())
+		defer subscription.Unsubscribe()
+
+		g.Eventually(received).Should(Receive(Equal("south-1")))
+		g.Eventually(received).Should(Receive(Equal("north-2")))
+		g.Consistently(received, 100*time.Millisecond).ShouldNot(Receive())
+	}
+
+	msg := robotbroker.NewMessage(robotbroker.WarehouseSubject("south", "configured"))
+	msg.Data = []byte("south-2")
+	g.Expect(sut.Publish(msg)).Should(Succeed())
+
+	for _, received := range subscribers {
+		g.Eventually(received).Should(Receive(Equal("south-2")))
+	}
+}
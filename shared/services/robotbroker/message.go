@@ -0,0 +1,33 @@
+package robotbroker
+
+// NewMessage creates a message to be published on subject
+func NewMessage(subject string) *Message {
+	return &Message{
+		Subject: subject,
+		Header:  make(Header),
+	}
+}
+
+// Get returns the first value of a header
+func (h Header) Get(key string) string {
+	if values := h[key]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Set replaces the values of a header
+func (h Header) Set(key string, value string) {
+	h[key] = []string{value}
+}
+
+// Clone returns a copy of the headers
+func (h Header) Clone() Header {
+	clone := make(Header, len(h))
+	for key, values := range h {
+		clone[key] = append([]string(nil), values...)
+	}
+
+	return clone
+}
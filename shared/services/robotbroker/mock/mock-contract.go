@@ -6,11 +6,257 @@ package mock_robotbroker
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	nats "github.com/nats-io/nats.go"
+	robotbroker "github.com/sepisoad/robot-challange/shared/services/robotbroker"
 )
 
+// MockHealthInterface is a mock of HealthInterface interface.
+type MockHealthInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthInterfaceMockRecorder
+}
+
+// MockHealthInterfaceMockRecorder is the mock recorder for MockHealthInterface.
+type MockHealthInterfaceMockRecorder struct {
+	mock *MockHealthInterface
+}
+
+// NewMockHealthInterface creates a new mock instance.
+func NewMockHealthInterface(ctrl *gomock.Controller) *MockHealthInterface {
+	mock := &MockHealthInterface{ctrl: ctrl}
+	mock.recorder = &MockHealthInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthInterface) EXPECT() *MockHealthInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ConnectionState mocks base method.
+func (m *MockHealthInterface) ConnectionState() robotbroker.ConnectionState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionState")
+	ret0, _ := ret[0].(robotbroker.ConnectionState)
+	return ret0
+}
+
+// ConnectionState indicates an expected call of ConnectionState.
+func (mr *MockHealthInterfaceMockRecorder) ConnectionState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionState", reflect.TypeOf((*MockHealthInterface)(nil).ConnectionState))
+}
+
+// ConnectionStats mocks base method.
+func (m *MockHealthInterface) ConnectionStats() robotbroker.ConnectionStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionStats")
+	ret0, _ := ret[0].(robotbroker.ConnectionStats)
+	return ret0
+}
+
+// ConnectionStats indicates an expected call of ConnectionStats.
+func (mr *MockHealthInterfaceMockRecorder) ConnectionStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionStats", reflect.TypeOf((*MockHealthInterface)(nil).ConnectionStats))
+}
+
+// OnConnectionStateChange mocks base method.
+func (m *MockHealthInterface) OnConnectionStateChange(handler robotbroker.ConnectionStateHandler) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnConnectionStateChange", handler)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// OnConnectionStateChange indicates an expected call of OnConnectionStateChange.
+func (mr *MockHealthInterfaceMockRecorder) OnConnectionStateChange(handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnConnectionStateChange", reflect.TypeOf((*MockHealthInterface)(nil).OnConnectionStateChange), handler)
+}
+
+// MockSubscriptionInterface is a mock of SubscriptionInterface interface.
+type MockSubscriptionInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionInterfaceMockRecorder
+}
+
+// MockSubscriptionInterfaceMockRecorder is the mock recorder for MockSubscriptionInterface.
+type MockSubscriptionInterfaceMockRecorder struct {
+	mock *MockSubscriptionInterface
+}
+
+// NewMockSubscriptionInterface creates a new mock instance.
+func NewMockSubscriptionInterface(ctrl *gomock.Controller) *MockSubscriptionInterface {
+	mock := &MockSubscriptionInterface{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionInterface) EXPECT() *MockSubscriptionInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Unsubscribe mocks base method.
+func (m *MockSubscriptionInterface) Unsubscribe() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockSubscriptionInterfaceMockRecorder) Unsubscribe() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockSubscriptionInterface)(nil).Unsubscribe))
+}
+
+// MockPublisherInterface is a mock of PublisherInterface interface.
+type MockPublisherInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPublisherInterfaceMockRecorder
+}
+
+// MockPublisherInterfaceMockRecorder is the mock recorder for MockPublisherInterface.
+type MockPublisherInterfaceMockRecorder struct {
+	mock *MockPublisherInterface
+}
+
+// NewMockPublisherInterface creates a new mock instance.
+func NewMockPublisherInterface(ctrl *gomock.Controller) *MockPublisherInterface {
+	mock := &MockPublisherInterface{ctrl: ctrl}
+	mock.recorder = &MockPublisherInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPublisherInterface) EXPECT() *MockPublisherInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockPublisherInterface) Publish(msg *robotbroker.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockPublisherInterfaceMockRecorder) Publish(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPublisherInterface)(nil).Publish), msg)
+}
+
+// MockRequesterInterface is a mock of RequesterInterface interface.
+type MockRequesterInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRequesterInterfaceMockRecorder
+}
+
+// MockRequesterInterfaceMockRecorder is the mock recorder for MockRequesterInterface.
+type MockRequesterInterfaceMockRecorder struct {
+	mock *MockRequesterInterface
+}
+
+// NewMockRequesterInterface creates a new mock instance.
+func NewMockRequesterInterface(ctrl *gomock.Controller) *MockRequesterInterface {
+	mock := &MockRequesterInterface{ctrl: ctrl}
+	mock.recorder = &MockRequesterInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRequesterInterface) EXPECT() *MockRequesterInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Request mocks base method.
+func (m *MockRequesterInterface) Request(msg *robotbroker.Message, timeout time.Duration) (*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", msg, timeout)
+	ret0, _ := ret[0].(*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockRequesterInterfaceMockRecorder) Request(msg, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockRequesterInterface)(nil).Request), msg, timeout)
+}
+
+// MockDeadLetterStoreInterface is a mock of DeadLetterStoreInterface interface.
+type MockDeadLetterStoreInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterStoreInterfaceMockRecorder
+}
+
+// MockDeadLetterStoreInterfaceMockRecorder is the mock recorder for MockDeadLetterStoreInterface.
+type MockDeadLetterStoreInterfaceMockRecorder struct {
+	mock *MockDeadLetterStoreInterface
+}
+
+// NewMockDeadLetterStoreInterface creates a new mock instance.
+func NewMockDeadLetterStoreInterface(ctrl *gomock.Controller) *MockDeadLetterStoreInterface {
+	mock := &MockDeadLetterStoreInterface{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterStoreInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterStoreInterface) EXPECT() *MockDeadLetterStoreInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeadLetters mocks base method.
+func (m *MockDeadLetterStoreInterface) DeadLetters() ([]*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetters")
+	ret0, _ := ret[0].([]*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeadLetters indicates an expected call of DeadLetters.
+func (mr *MockDeadLetterStoreInterfaceMockRecorder) DeadLetters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetters", reflect.TypeOf((*MockDeadLetterStoreInterface)(nil).DeadLetters))
+}
+
+// DeleteDeadLetter mocks base method.
+func (m *MockDeadLetterStoreInterface) DeleteDeadLetter(sequence uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter.
+func (mr *MockDeadLetterStoreInterfaceMockRecorder) DeleteDeadLetter(sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockDeadLetterStoreInterface)(nil).DeleteDeadLetter), sequence)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockDeadLetterStoreInterface) GetDeadLetter(sequence uint64) (*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", sequence)
+	ret0, _ := ret[0].(*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockDeadLetterStoreInterfaceMockRecorder) GetDeadLetter(sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockDeadLetterStoreInterface)(nil).GetDeadLetter), sequence)
+}
+
 // MockRobotBrokerInterface is a mock of RobotBrokerInterface interface.
 type MockRobotBrokerInterface struct {
 	ctrl     *gomock.Controller
@@ -46,8 +292,260 @@ func (mr *MockRobotBrokerInterfaceMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRobotBrokerInterface)(nil).Close))
 }
 
+// ConnectionState mocks base method.
+func (m *MockRobotBrokerInterface) ConnectionState() robotbroker.ConnectionState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionState")
+	ret0, _ := ret[0].(robotbroker.ConnectionState)
+	return ret0
+}
+
+// ConnectionState indicates an expected call of ConnectionState.
+func (mr *MockRobotBrokerInterfaceMockRecorder) ConnectionState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionState", reflect.TypeOf((*MockRobotBrokerInterface)(nil).ConnectionState))
+}
+
+// ConnectionStats mocks base method.
+func (m *MockRobotBrokerInterface) ConnectionStats() robotbroker.ConnectionStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionStats")
+	ret0, _ := ret[0].(robotbroker.ConnectionStats)
+	return ret0
+}
+
+// ConnectionStats indicates an expected call of ConnectionStats.
+func (mr *MockRobotBrokerInterfaceMockRecorder) ConnectionStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionStats", reflect.TypeOf((*MockRobotBrokerInterface)(nil).ConnectionStats))
+}
+
+// DeadLetters mocks base method.
+func (m *MockRobotBrokerInterface) DeadLetters() ([]*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetters")
+	ret0, _ := ret[0].([]*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeadLetters indicates an expected call of DeadLetters.
+func (mr *MockRobotBrokerInterfaceMockRecorder) DeadLetters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetters", reflect.TypeOf((*MockRobotBrokerInterface)(nil).DeadLetters))
+}
+
+// DeleteDeadLetter mocks base method.
+func (m *MockRobotBrokerInterface) DeleteDeadLetter(sequence uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter.
+func (mr *MockRobotBrokerInterfaceMockRecorder) DeleteDeadLetter(sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockRobotBrokerInterface)(nil).DeleteDeadLetter), sequence)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockRobotBrokerInterface) GetDeadLetter(sequence uint64) (*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", sequence)
+	ret0, _ := ret[0].(*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockRobotBrokerInterfaceMockRecorder) GetDeadLetter(sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockRobotBrokerInterface)(nil).GetDeadLetter), sequence)
+}
+
+// OnConnectionStateChange mocks base method.
+func (m *MockRobotBrokerInterface) OnConnectionStateChange(handler robotbroker.ConnectionStateHandler) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnConnectionStateChange", handler)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// OnConnectionStateChange indicates an expected call of OnConnectionStateChange.
+func (mr *MockRobotBrokerInterfaceMockRecorder) OnConnectionStateChange(handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnConnectionStateChange", reflect.TypeOf((*MockRobotBrokerInterface)(nil).OnConnectionStateChange), handler)
+}
+
+// Publish mocks base method.
+func (m *MockRobotBrokerInterface) Publish(msg *robotbroker.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockRobotBrokerInterfaceMockRecorder) Publish(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRobotBrokerInterface)(nil).Publish), msg)
+}
+
+// PublishAsync mocks base method.
+func (m *MockRobotBrokerInterface) PublishAsync(msg *robotbroker.Message, onAck robotbroker.AckHandler) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishAsync", msg, onAck)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishAsync indicates an expected call of PublishAsync.
+func (mr *MockRobotBrokerInterfaceMockRecorder) PublishAsync(msg, onAck interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAsync", reflect.TypeOf((*MockRobotBrokerInterface)(nil).PublishAsync), msg, onAck)
+}
+
+// QueueRespond mocks base method.
+func (m *MockRobotBrokerInterface) QueueRespond(subject, queue string, handler robotbroker.RequestHandler) (robotbroker.SubscriptionInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueRespond", subject, queue, handler)
+	ret0, _ := ret[0].(robotbroker.SubscriptionInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueRespond indicates an expected call of QueueRespond.
+func (mr *MockRobotBrokerInterfaceMockRecorder) QueueRespond(subject, queue, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueRespond", reflect.TypeOf((*MockRobotBrokerInterface)(nil).QueueRespond), subject, queue, handler)
+}
+
+// QueueSubscribe mocks base method.
+func (m *MockRobotBrokerInterface) QueueSubscribe(subject, queue string, handler robotbroker.MessageHandler) (robotbroker.SubscriptionInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueSubscribe", subject, queue, handler)
+	ret0, _ := ret[0].(robotbroker.SubscriptionInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueSubscribe indicates an expected call of QueueSubscribe.
+func (mr *MockRobotBrokerInterfaceMockRecorder) QueueSubscribe(subject, queue, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueSubscribe", reflect.TypeOf((*MockRobotBrokerInterface)(nil).QueueSubscribe), subject, queue, handler)
+}
+
+// Request mocks base method.
+func (m *MockRobotBrokerInterface) Request(msg *robotbroker.Message, timeout time.Duration) (*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", msg, timeout)
+	ret0, _ := ret[0].(*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockRobotBrokerInterfaceMockRecorder) Request(msg, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockRobotBrokerInterface)(nil).Request), msg, timeout)
+}
+
+// Subscribe mocks base method.
+func (m *MockRobotBrokerInterface) Subscribe(subject string, handler robotbroker.MessageHandler) (robotbroker.SubscriptionInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", subject, handler)
+	ret0, _ := ret[0].(robotbroker.SubscriptionInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockRobotBrokerInterfaceMockRecorder) Subscribe(subject, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockRobotBrokerInterface)(nil).Subscribe), subject, handler)
+}
+
+// SubscribeLast mocks base method.
+func (m *MockRobotBrokerInterface) SubscribeLast(subject string, handler robotbroker.MessageHandler) (robotbroker.SubscriptionInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeLast", subject, handler)
+	ret0, _ := ret[0].(robotbroker.SubscriptionInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeLast indicates an expected call of SubscribeLast.
+func (mr *MockRobotBrokerInterfaceMockRecorder) SubscribeLast(subject, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeLast", reflect.TypeOf((*MockRobotBrokerInterface)(nil).SubscribeLast), subject, handler)
+}
+
+// MockJetStreamBrokerInterface is a mock of JetStreamBrokerInterface interface.
+type MockJetStreamBrokerInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockJetStreamBrokerInterfaceMockRecorder
+}
+
+// MockJetStreamBrokerInterfaceMockRecorder is the mock recorder for MockJetStreamBrokerInterface.
+type MockJetStreamBrokerInterfaceMockRecorder struct {
+	mock *MockJetStreamBrokerInterface
+}
+
+// NewMockJetStreamBrokerInterface creates a new mock instance.
+func NewMockJetStreamBrokerInterface(ctrl *gomock.Controller) *MockJetStreamBrokerInterface {
+	mock := &MockJetStreamBrokerInterface{ctrl: ctrl}
+	mock.recorder = &MockJetStreamBrokerInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJetStreamBrokerInterface) EXPECT() *MockJetStreamBrokerInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockJetStreamBrokerInterface) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).Close))
+}
+
+// ConnectionState mocks base method.
+func (m *MockJetStreamBrokerInterface) ConnectionState() robotbroker.ConnectionState {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionState")
+	ret0, _ := ret[0].(robotbroker.ConnectionState)
+	return ret0
+}
+
+// ConnectionState indicates an expected call of ConnectionState.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) ConnectionState() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionState", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).ConnectionState))
+}
+
+// ConnectionStats mocks base method.
+func (m *MockJetStreamBrokerInterface) ConnectionStats() robotbroker.ConnectionStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConnectionStats")
+	ret0, _ := ret[0].(robotbroker.ConnectionStats)
+	return ret0
+}
+
+// ConnectionStats indicates an expected call of ConnectionStats.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) ConnectionStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConnectionStats", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).ConnectionStats))
+}
+
 // CreateNewJetStream mocks base method.
-func (m *MockRobotBrokerInterface) CreateNewJetStream() (nats.JetStreamContext, error) {
+func (m *MockJetStreamBrokerInterface) CreateNewJetStream() (nats.JetStreamContext, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateNewJetStream")
 	ret0, _ := ret[0].(nats.JetStreamContext)
@@ -56,7 +554,168 @@ func (m *MockRobotBrokerInterface) CreateNewJetStream() (nats.JetStreamContext,
 }
 
 // CreateNewJetStream indicates an expected call of CreateNewJetStream.
-func (mr *MockRobotBrokerInterfaceMockRecorder) CreateNewJetStream() *gomock.Call {
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) CreateNewJetStream() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewJetStream", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).CreateNewJetStream))
+}
+
+// DeadLetters mocks base method.
+func (m *MockJetStreamBrokerInterface) DeadLetters() ([]*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetters")
+	ret0, _ := ret[0].([]*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeadLetters indicates an expected call of DeadLetters.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) DeadLetters() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetters", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).DeadLetters))
+}
+
+// DeleteDeadLetter mocks base method.
+func (m *MockJetStreamBrokerInterface) DeleteDeadLetter(sequence uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeadLetter", sequence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeadLetter indicates an expected call of DeleteDeadLetter.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) DeleteDeadLetter(sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeadLetter", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).DeleteDeadLetter), sequence)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockJetStreamBrokerInterface) GetDeadLetter(sequence uint64) (*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", sequence)
+	ret0, _ := ret[0].(*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) GetDeadLetter(sequence interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).GetDeadLetter), sequence)
+}
+
+// OnConnectionStateChange mocks base method.
+func (m *MockJetStreamBrokerInterface) OnConnectionStateChange(handler robotbroker.ConnectionStateHandler) func() {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OnConnectionStateChange", handler)
+	ret0, _ := ret[0].(func())
+	return ret0
+}
+
+// OnConnectionStateChange indicates an expected call of OnConnectionStateChange.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) OnConnectionStateChange(handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnConnectionStateChange", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).OnConnectionStateChange), handler)
+}
+
+// Publish mocks base method.
+func (m *MockJetStreamBrokerInterface) Publish(msg *robotbroker.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) Publish(msg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).Publish), msg)
+}
+
+// PublishAsync mocks base method.
+func (m *MockJetStreamBrokerInterface) PublishAsync(msg *robotbroker.Message, onAck robotbroker.AckHandler) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishAsync", msg, onAck)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishAsync indicates an expected call of PublishAsync.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) PublishAsync(msg, onAck interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishAsync", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).PublishAsync), msg, onAck)
+}
+
+// QueueRespond mocks base method.
+func (m *MockJetStreamBrokerInterface) QueueRespond(subject, queue string, handler robotbroker.RequestHandler) (robotbroker.SubscriptionInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueRespond", subject, queue, handler)
+	ret0, _ := ret[0].(robotbroker.SubscriptionInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueRespond indicates an expected call of QueueRespond.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) QueueRespond(subject, queue, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueRespond", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).QueueRespond), subject, queue, handler)
+}
+
+// QueueSubscribe mocks base method.
+func (m *MockJetStreamBrokerInterface) QueueSubscribe(subject, queue string, handler robotbroker.MessageHandler) (robotbroker.SubscriptionInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueSubscribe", subject, queue, handler)
+	ret0, _ := ret[0].(robotbroker.SubscriptionInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueSubscribe indicates an expected call of QueueSubscribe.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) QueueSubscribe(subject, queue, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueSubscribe", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).QueueSubscribe), subject, queue, handler)
+}
+
+// Request mocks base method.
+func (m *MockJetStreamBrokerInterface) Request(msg *robotbroker.Message, timeout time.Duration) (*robotbroker.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Request", msg, timeout)
+	ret0, _ := ret[0].(*robotbroker.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Request indicates an expected call of Request.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) Request(msg, timeout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Request", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).Request), msg, timeout)
+}
+
+// Subscribe mocks base method.
+func (m *MockJetStreamBrokerInterface) Subscribe(subject string, handler robotbroker.MessageHandler) (robotbroker.SubscriptionInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", subject, handler)
+	ret0, _ := ret[0].(robotbroker.SubscriptionInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) Subscribe(subject, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).Subscribe), subject, handler)
+}
+
+// SubscribeLast mocks base method.
+func (m *MockJetStreamBrokerInterface) SubscribeLast(subject string, handler robotbroker.MessageHandler) (robotbroker.SubscriptionInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeLast", subject, handler)
+	ret0, _ := ret[0].(robotbroker.SubscriptionInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeLast indicates an expected call of SubscribeLast.
+func (mr *MockJetStreamBrokerInterfaceMockRecorder) SubscribeLast(subject, handler interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNewJetStream", reflect.TypeOf((*MockRobotBrokerInterface)(nil).CreateNewJetStream))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeLast", reflect.TypeOf((*MockJetStreamBrokerInterface)(nil).SubscribeLast), subject, handler)
 }
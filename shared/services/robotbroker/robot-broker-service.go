@@ -1,6 +1,9 @@
 package robotbroker
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -10,28 +13,46 @@ import (
 type robotBrokerService struct {
 	logger         *zap.SugaredLogger
 	natsConnection *nats.Conn
+	asyncJetStream nats.JetStreamContext
+	monitor        *connectionMonitor
 }
 
-// NewRobotBrokerService creates an concrete instance of RobotBrokerInterface
+// NewRobotBrokerService creates an concrete instance of RobotBrokerInterface which is
+// backed by NATS JetStream
 func NewRobotBrokerService(
 	logger *zap.SugaredLogger,
 	clientName string,
-	natsUrl string) (RobotBrokerInterface, error) {
+	natsUrl string,
+	connectionConfig ConnectionConfig,
+	streamConfig StreamConfig) (JetStreamBrokerInterface, error) {
 	robotBrokerService := robotBrokerService{
-		logger: logger,
+		logger:  logger,
+		monitor: newConnectionMonitor(),
 	}
 
 	natsConnection, err := robotBrokerService.createNatsConnection(
 		clientName,
-		natsUrl)
+		natsUrl,
+		connectionConfig)
 	if err != nil {
 		return nil, err
 	}
 
 	robotBrokerService.natsConnection = natsConnection
 
-	err = robotBrokerService.configureStreams()
+	// Asynchronous publishes share a context so that their acknowledgements are tracked together
+	robotBrokerService.asyncJetStream, err = natsConnection.JetStream(
+		nats.PublishAsyncMaxPending(MaxPendingAsyncPublishes))
 	if err != nil {
+		robotBrokerService.Close()
+
+		return nil, err
+	}
+
+	err = robotBrokerService.configureStreams(streamConfig)
+	if err != nil {
+		robotBrokerService.Close()
+
 		return nil, err
 	}
 
@@ -44,6 +65,35 @@ func (s *robotBrokerService) Close() {
 		s.natsConnection.Close()
 		s.natsConnection = nil
 	}
+
+	s.monitor.transition(Closed, nil)
+}
+
+// ConnectionState returns the state of the connection to NATS
+func (s *robotBrokerService) ConnectionState() ConnectionState {
+	return s.monitor.state()
+}
+
+// ConnectionStats returns the state of the connection to NATS and its counters
+func (s *robotBrokerService) ConnectionStats() ConnectionStats {
+	stats := s.monitor.snapshot()
+
+	if natsConnection := s.natsConnection; natsConnection != nil {
+		natsStats := natsConnection.Stats()
+
+		stats.InMsgs = natsStats.InMsgs
+		stats.OutMsgs = natsStats.OutMsgs
+		stats.InBytes = natsStats.InBytes
+		stats.OutBytes = natsStats.OutBytes
+	}
+
+	return stats
+}
+
+// OnConnectionStateChange calls handler whenever the connection to NATS is lost, comes back or
+// is closed
+func (s *robotBrokerService) OnConnectionStateChange(handler ConnectionStateHandler) func() {
+	return s.monitor.register(handler)
 }
 
 // CreateNewJetStream creates a message stream which is persisted on disk
@@ -51,10 +101,466 @@ func (s *robotBrokerService) CreateNewJetStream() (nats.JetStreamContext, error)
 	return s.natsConnection.JetStream(nats.PublishAsyncMaxPending(256))
 }
 
+// Publish publishes a message and waits for JetStream to persist it
+func (s *robotBrokerService) Publish(msg *Message) error {
+	jetStream, err := s.CreateNewJetStream()
+	if err != nil {
+		return err
+	}
+
+	_, err = jetStream.PublishMsg(toNatsMsg(msg))
+
+	// Publishing the message again can not succeed
+	if errors.Is(err, nats.ErrMaxPayload) || errors.Is(err, nats.ErrBadSubject) {
+		return Permanent(err)
+	}
+
+	return err
+}
+
+// PublishAsync publishes a message without waiting for JetStream to persist it, messages
+// published asynchronously keep their order
+func (s *robotBrokerService) PublishAsync(msg *Message, onAck AckHandler) error {
+	future, err := s.asyncJetStream.PublishMsgAsync(toNatsMsg(msg))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		select {
+		case <-future.Ok():
+			onAck(nil)
+		case err := <-future.Err():
+			onAck(err)
+		}
+	}()
+
+	return nil
+}
+
+// Subscribe creates an ephemeral, explicitly acknowledged subscription which receives the
+// messages published from now on
+func (s *robotBrokerService) Subscribe(
+	subject string,
+	handler MessageHandler) (SubscriptionInterface, error) {
+	return s.subscribe(subject, handler, nats.DeliverNew())
+}
+
+// SubscribeLast creates an ephemeral, explicitly acknowledged subscription which receives the
+// last message the stream keeps of every matching subject, then the messages published from now
+// on
+func (s *robotBrokerService) SubscribeLast(
+	subject string,
+	handler MessageHandler) (SubscriptionInterface, error) {
+	return s.subscribe(subject, handler, nats.DeliverLastPerSubject())
+}
+
+func (s *robotBrokerService) subscribe(
+	subject string,
+	handler MessageHandler,
+	deliverPolicy nats.SubOpt) (SubscriptionInterface, error) {
+	jetStream, err := s.CreateNewJetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := jetStream.Subscribe(
+		subject,
+		func(msg *nats.Msg) {
+			s.handleMessage(jetStream, msg, handler)
+		},
+		s.subscriptionOptions(deliverPolicy)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deadLetterMaxDeliveries(jetStream, subscription)
+}
+
+// QueueSubscribe creates a durable, explicitly acknowledged queue subscription, the queue
+// name is used as the durable name. Failed messages are redelivered with a back off and
+// dead lettered once they run out of deliveries, whether the handler failed them or they were
+// not acknowledged in time
+func (s *robotBrokerService) QueueSubscribe(
+	subject string,
+	queue string,
+	handler MessageHandler) (SubscriptionInterface, error) {
+	jetStream, err := s.CreateNewJetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := jetStream.QueueSubscribe(
+		subject,
+		queue,
+		func(msg *nats.Msg) {
+			s.handleMessage(jetStream, msg, handler)
+		},
+		s.subscriptionOptions(nats.Durable(queue))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deadLetterMaxDeliveries(jetStream, subscription)
+}
+
+// DeadLetters returns the dead letters which are still kept in the dead letter stream
+func (s *robotBrokerService) DeadLetters() ([]*Message, error) {
+	jetStream, err := s.CreateNewJetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	streamInfo, err := jetStream.StreamInfo(STREAM_DEAD_LETTER)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]*Message, 0)
+
+	if streamInfo.State.Msgs == 0 {
+		return deadLetters, nil
+	}
+
+	// Dead letters which were republished leave gaps in the sequences
+	for sequence := streamInfo.State.FirstSeq; sequence <= streamInfo.State.LastSeq; sequence++ {
+		deadLetter, err := s.GetDeadLetter(sequence)
+		if errors.Is(err, ErrMessageNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return deadLetters, nil
+}
+
+// GetDeadLetter returns the dead letter kept at sequence in the dead letter stream
+func (s *robotBrokerService) GetDeadLetter(sequence uint64) (*Message, error) {
+	jetStream, err := s.CreateNewJetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	rawMsg, err := jetStream.GetMsg(STREAM_DEAD_LETTER, sequence)
+	if errors.Is(err, nats.ErrMsgNotFound) {
+		return nil, ErrMessageNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return fromRawStreamMsg(rawMsg), nil
+}
+
+// DeleteDeadLetter removes the dead letter kept at sequence from the dead letter stream
+func (s *robotBrokerService) DeleteDeadLetter(sequence uint64) error {
+	jetStream, err := s.CreateNewJetStream()
+	if err != nil {
+		return err
+	}
+
+	err = jetStream.DeleteMsg(STREAM_DEAD_LETTER, sequence)
+	if errors.Is(err, nats.ErrMsgNotFound) {
+		return ErrMessageNotFound
+	}
+
+	return err
+}
+
+// Request sends a core NATS request, requests are not captured by the streams as long as their
+// subject starts with SUBJECT_REQUEST
+func (s *robotBrokerService) Request(msg *Message, timeout time.Duration) (*Message, error) {
+	reply, err := s.natsConnection.RequestMsg(toNatsMsg(msg), timeout)
+	if errors.Is(err, nats.ErrNoResponders) {
+		return nil, ErrNoResponders
+	}
+
+	if errors.Is(err, nats.ErrTimeout) {
+		return nil, ErrRequestTimeout
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return fromNatsMsg(reply), nil
+}
+
+// QueueRespond creates a core NATS queue subscription which replies to the requests it receives
+func (s *robotBrokerService) QueueRespond(
+	subject string,
+	queue string,
+	handler RequestHandler) (SubscriptionInterface, error) {
+	return s.natsConnection.QueueSubscribe(
+		subject,
+		queue,
+		func(natsMsg *nats.Msg) {
+			if natsMsg.Reply == "" {
+				return
+			}
+
+			msg := handler(fromNatsMsg(natsMsg))
+			if msg == nil {
+				return
+			}
+
+			reply := toNatsMsg(msg)
+			reply.Subject = natsMsg.Reply
+
+			if err := s.natsConnection.PublishMsg(reply); err != nil {
+				s.logger.Errorf(
+					"Failed to reply to request from %s. Error: %v",
+					natsMsg.Subject,
+					err)
+			}
+		})
+}
+
+func (s *robotBrokerService) subscriptionOptions(opts ...nats.SubOpt) []nats.SubOpt {
+	return append(opts,
+		nats.ManualAck(),
+		nats.AckExplicit(),
+		nats.MaxDeliver(DefaultMaxDeliver),
+		nats.BackOff(DefaultBackOff))
+}
+
+func (s *robotBrokerService) handleMessage(
+	jetStream nats.JetStreamContext,
+	natsMsg *nats.Msg,
+	handler MessageHandler) {
+	msg := fromNatsMsg(natsMsg)
+
+	metadata, err := natsMsg.Metadata()
+	if err != nil {
+		s.logger.Errorf(
+			"Failed to read metadata of message from %s. Error: %v",
+			natsMsg.Subject,
+			err)
+
+		_ = natsMsg.Nak()
+
+		return
+	}
+
+	msg.Sequence = metadata.Sequence.Stream
+	msg.Deliveries = metadata.NumDelivered
+
+	handlerErr := handler(msg)
+	if handlerErr == nil {
+		if err := natsMsg.Ack(); err != nil {
+			s.logger.Errorf("Failed to ack message from %s. Error: %v", natsMsg.Subject, err)
+		}
+
+		return
+	}
+
+	if !IsPermanent(handlerErr) && metadata.NumDelivered < DefaultMaxDeliver {
+		s.logger.Warnf(
+			"Failed to process message %d from %s, attempt %d of %d. Error: %v",
+			metadata.Sequence.Stream,
+			natsMsg.Subject,
+			metadata.NumDelivered,
+			DefaultMaxDeliver,
+			handlerErr)
+
+		_ = natsMsg.NakWithDelay(backOffDelay(metadata.NumDelivered))
+
+		return
+	}
+
+	deadLetter := newDeadLetter(msg, metadata.Consumer, handlerErr)
+
+	deadLetter.Header.Set(nats.MsgIdHdr, deadLetterMsgId(metadata.Stream, metadata.Consumer, metadata.Sequence.Stream))
+
+	if _, err := jetStream.PublishMsg(toNatsMsg(deadLetter)); err != nil {
+		s.logger.Errorf(
+			"Failed to dead letter message %d from %s. Error: %v",
+			metadata.Sequence.Stream,
+			natsMsg.Subject,
+			err)
+
+		_ = natsMsg.NakWithDelay(backOffDelay(metadata.NumDelivered))
+
+		return
+	}
+
+	s.logger.Errorf(
+		"Message %d from %s has been dead lettered after %d attempts. Error: %v",
+		metadata.Sequence.Stream,
+		natsMsg.Subject,
+		metadata.NumDelivered,
+		handlerErr)
+
+	_ = natsMsg.Term()
+}
+
+// maxDeliveriesAdvisory is published by JetStream when a message runs out of deliveries without
+// being acknowledged, see https://docs.nats.io/running-a-nats-service/nats_admin/monitoring/monitoring_jetstream
+type maxDeliveriesAdvisory struct {
+	Stream     string `json:"stream"`
+	Consumer   string `json:"consumer"`
+	StreamSeq  uint64 `json:"stream_seq"`
+	Deliveries uint64 `json:"deliveries"`
+}
+
+// deadLetterMaxDeliveries dead letters the messages of a subscription which run out of
+// deliveries because their ack wait expired, handleMessage only sees those its handler failed.
+// It returns a subscription which stops both
+func (s *robotBrokerService) deadLetterMaxDeliveries(
+	jetStream nats.JetStreamContext,
+	subscription *nats.Subscription) (SubscriptionInterface, error) {
+	consumerInfo, err := subscription.ConsumerInfo()
+	if err != nil {
+		_ = subscription.Unsubscribe()
+
+		return nil, err
+	}
+
+	// A single member of the queue group handles each advisory
+	advisorySubscription, err := s.natsConnection.QueueSubscribe(
+		fmt.Sprintf("%s.%s.%s", SUBJECT_ADVISORY_MAX_DELIVERIES, consumerInfo.Stream, consumerInfo.Name),
+		consumerInfo.Name,
+		func(natsMsg *nats.Msg) {
+			s.handleMaxDeliveriesAdvisory(jetStream, natsMsg)
+		})
+	if err != nil {
+		_ = subscription.Unsubscribe()
+
+		return nil, err
+	}
+
+	return subscriptions{subscription, advisorySubscription}, nil
+}
+
+func (s *robotBrokerService) handleMaxDeliveriesAdvisory(jetStream nats.JetStreamContext, natsMsg *nats.Msg) {
+	advisory := maxDeliveriesAdvisory{}
+	if err := json.Unmarshal(natsMsg.Data, &advisory); err != nil {
+		s.logger.Errorf("Failed to de-serialize max deliveries advisory. Error: %v", err)
+
+		return
+	}
+
+	rawMsg, err := jetStream.GetMsg(advisory.Stream, advisory.StreamSeq)
+	if err != nil {
+		s.logger.Errorf(
+			"Failed to read message %d which ran out of deliveries from %s. Error: %v",
+			advisory.StreamSeq,
+			advisory.Stream,
+			err)
+
+		return
+	}
+
+	msg := fromRawStreamMsg(rawMsg)
+	msg.Deliveries = advisory.Deliveries
+
+	deadLetter := newDeadLetter(msg, advisory.Consumer, errMaxDeliveries)
+	deadLetter.Header.Set(nats.MsgIdHdr, deadLetterMsgId(advisory.Stream, advisory.Consumer, advisory.StreamSeq))
+
+	if _, err := jetStream.PublishMsg(toNatsMsg(deadLetter)); err != nil {
+		s.logger.Errorf(
+			"Failed to dead letter message %d from %s. Error: %v",
+			advisory.StreamSeq,
+			rawMsg.Subject,
+			err)
+
+		return
+	}
+
+	s.logger.Errorf(
+		"Message %d from %s has been dead lettered after %d attempts. Error: %v",
+		advisory.StreamSeq,
+		rawMsg.Subject,
+		advisory.Deliveries,
+		errMaxDeliveries)
+}
+
+// deadLetterMsgId de-duplicates the dead letters of a message given up by a consumer, e.g. when
+// a consumer dead letters it again on a retry or both from its handler and from the advisory.
+// The dead letters of distinct consumers are kept apart
+func deadLetterMsgId(stream string, consumer string, sequence uint64) string {
+	return fmt.Sprintf("%s-%s-%d", stream, consumer, sequence)
+}
+
+// subscriptions unsubscribes several subscriptions at once
+type subscriptions []*nats.Subscription
+
+func (s subscriptions) Unsubscribe() error {
+	var result error
+
+	for _, subscription := range s {
+		if err := subscription.Unsubscribe(); err != nil && result == nil {
+			result = err
+		}
+	}
+
+	return result
+}
+
+func toNatsMsg(msg *Message) *nats.Msg {
+	natsMsg := nats.NewMsg(msg.Subject)
+	natsMsg.Data = msg.Data
+
+	for key, values := range msg.Header {
+		natsMsg.Header[key] = values
+	}
+
+	return natsMsg
+}
+
+func fromNatsMsg(natsMsg *nats.Msg) *Message {
+	msg := NewMessage(natsMsg.Subject)
+	msg.Data = natsMsg.Data
+
+	for key, values := range natsMsg.Header {
+		msg.Header[key] = values
+	}
+
+	return msg
+}
+
+func fromRawStreamMsg(rawMsg *nats.RawStreamMsg) *Message {
+	msg := NewMessage(rawMsg.Subject)
+	msg.Data = rawMsg.Data
+	msg.Sequence = rawMsg.Sequence
+	msg.Time = rawMsg.Time
+
+	for key, values := range rawMsg.Header {
+		msg.Header[key] = values
+	}
+
+	return msg
+}
+
+func backOffDelay(numDelivered uint64) time.Duration {
+	if numDelivered == 0 {
+		return DefaultBackOff[0]
+	}
+
+	if int(numDelivered) > len(DefaultBackOff) {
+		return DefaultBackOff[len(DefaultBackOff)-1]
+	}
+
+	return DefaultBackOff[numDelivered-1]
+}
+
 func (s *robotBrokerService) createNatsConnection(
 	clientName string,
-	natsUrl string) (*nats.Conn, error) {
+	natsUrl string,
+	connectionConfig ConnectionConfig) (*nats.Conn, error) {
+	securityOptions, err := connectionConfig.natsOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	opts := []nats.Option{nats.Name(clientName)}
+	opts = append(opts, securityOptions...)
 	opts = s.getNatsConnectionOptions(opts)
 
 	return nats.Connect(natsUrl, opts...)
@@ -71,33 +577,100 @@ func (s *robotBrokerService) getNatsConnectionOptions(opts []nats.Option) []nats
 	opts = append(opts, nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
 		if !nc.IsClosed() {
 			s.logger.Warnf("Disconnected due to: %s", err)
+			s.monitor.transition(Disconnected, err)
 		}
 	}))
 
 	opts = append(opts, nats.ReconnectHandler(func(nc *nats.Conn) {
 		s.logger.Warnf("Reconnected [%s]", nc.ConnectedUrl())
+		s.monitor.transition(Connected, nil)
+	}))
+
+	opts = append(opts, nats.ClosedHandler(func(nc *nats.Conn) {
+		s.monitor.transition(Closed, nc.LastError())
 	}))
 
 	return opts
 }
 
-func (s *robotBrokerService) configureStreams() (err error) {
+// configureStreams creates the robot and dead letter streams, or brings existing ones in line
+// with their configuration
+func (s *robotBrokerService) configureStreams(streamConfig StreamConfig) (err error) {
 	var jetStream nats.JetStreamContext
 	if jetStream, err = s.CreateNewJetStream(); err != nil {
 		return err
 	}
 
-	if _, err := jetStream.AddStream(&nats.StreamConfig{
-		Name: "RobotStream",
+	if err = s.reconcileStream(jetStream, streamConfig.natsStreamConfig()); err != nil {
+		return err
+	}
+
+	return s.reconcileStream(jetStream, &nats.StreamConfig{
+		Name: STREAM_DEAD_LETTER,
 		Subjects: []string{
-			SUBJECT_TASK,
-			SUBJECT_ROBOT,
+			SUBJECT_DEAD_LETTER + "." + SUBJECT_FULL_WILDCARD,
 		},
-		MaxAge:  time.Hour * 24,
-		Storage: nats.FileStorage,
-	}); err != nil {
+		Retention: nats.LimitsPolicy,
+		MaxAge:    DefaultDeadLetterStreamMaxAge,
+		MaxBytes:  -1,
+		MaxMsgs:   -1,
+		Replicas:  1,
+		Storage:   nats.FileStorage,
+	})
+}
+
+// reconcileStream creates a stream which does not exist yet and updates an existing one whose
+// configuration differs from the desired one. Changes JetStream can not apply in place, e.g.
+// the storage type, are reported as an error listing every difference
+func (s *robotBrokerService) reconcileStream(
+	jetStream nats.JetStreamManager,
+	desired *nats.StreamConfig) error {
+	streamInfo, err := jetStream.StreamInfo(desired.Name)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		if _, err := jetStream.AddStream(desired); err != nil {
+			return fmt.Errorf("failed to create stream %s: %w", desired.Name, err)
+		}
+
+		s.logger.Infof("Created stream %s", desired.Name)
+
+		return nil
+	}
+
+	if err != nil {
 		return err
 	}
 
+	changes := DiffStreamConfig(&streamInfo.Config, desired)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	for _, change := range changes {
+		if change.Immutable {
+			return fmt.Errorf(
+				"stream %s can not be updated in place, delete it or align the configuration: %s",
+				desired.Name,
+				formatStreamConfigChanges(changes))
+		}
+	}
+
+	// Settings the broker does not manage, e.g. the duplicate window, are left untouched
+	updated := streamInfo.Config
+	updated.Subjects = desired.Subjects
+	updated.MaxAge = desired.MaxAge
+	updated.MaxBytes = desired.MaxBytes
+	updated.MaxMsgs = desired.MaxMsgs
+	updated.Replicas = desired.Replicas
+
+	if _, err := jetStream.UpdateStream(&updated); err != nil {
+		return fmt.Errorf(
+			"failed to update stream %s (%s): %w",
+			desired.Name,
+			formatStreamConfigChanges(changes),
+			err)
+	}
+
+	s.logger.Infof("Updated stream %s: %s", desired.Name, formatStreamConfigChanges(changes))
+
 	return nil
 }
@@ -0,0 +1,437 @@
+package robotbroker_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_NewRobotBrokerService_Should_Reconcile_The_Robot_Stream(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	defer embeddedNatsService.Shutdown()
+
+	streamConfig := robotbroker.DefaultStreamConfig()
+	streamConfig.Name = "ReconciledStream"
+
+	sut, err := robotbroker.NewRobotBrokerService(sugarLogger, "test", embeddedNatsService.ClientURL(), robotbroker.ConnectionConfig{}, streamConfig)
+	g.Expect(err).Should(BeNil())
+	sut.Close()
+
+	streamConfig.MaxAge = time.Hour
+	streamConfig.MaxMsgs = 1000
+
+	sut, err = robotbroker.NewRobotBrokerService(sugarLogger, "test", embeddedNatsService.ClientURL(), robotbroker.ConnectionConfig{}, streamConfig)
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	jetStream, err := sut.CreateNewJetStream()
+	g.Expect(err).Should(BeNil())
+
+	streamInfo, err := jetStream.StreamInfo("ReconciledStream")
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.Config.MaxAge).Should(Equal(time.Hour))
+	g.Expect(streamInfo.Config.MaxMsgs).Should(Equal(int64(1000)))
+}
+
+func Test_NewRobotBrokerService_Should_Return_Diff_If_Stream_Can_Not_Be_Updated(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	defer embeddedNatsService.Shutdown()
+
+	streamConfig := robotbroker.DefaultStreamConfig()
+
+	sut, err := robotbroker.NewRobotBrokerService(sugarLogger, "test", embeddedNatsService.ClientURL(), robotbroker.ConnectionConfig{}, streamConfig)
+	g.Expect(err).Should(BeNil())
+	sut.Close()
+
+	streamConfig.Storage = nats.MemoryStorage
+	streamConfig.MaxAge = time.Hour
+
+	_, err = robotbroker.NewRobotBrokerService(sugarLogger, "test", embeddedNatsService.ClientURL(), robotbroker.ConnectionConfig{}, streamConfig)
+	g.Expect(err).ShouldNot(BeNil())
+	g.Expect(err.Error()).Should(ContainSubstring("storage: File -> Memory"))
+	g.Expect(err.Error()).Should(ContainSubstring("max age: 24h0m0s -> 1h0m0s"))
+}
+
+func Test_NewRobotBrokerService_Should_Connect_Over_Mutual_TLS(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	certificates := writeCertificates(t)
+
+	tlsConfig, err := server.GenTLSConfig(&server.TLSConfigOpts{
+		CertFile: certificates.serverCertFile,
+		KeyFile:  certificates.serverKeyFile,
+		CaFile:   certificates.caFile,
+		Verify:   true,
+	})
+	g.Expect(err).Should(BeNil())
+
+	natsUrl := startSecuredNatsServer(t, &server.Options{
+		TLS:       true,
+		TLSVerify: true,
+		TLSConfig: tlsConfig,
+	})
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		natsUrl,
+		robotbroker.ConnectionConfig{
+			TLSCAFile:   certificates.caFile,
+			TLSCertFile: certificates.clientCertFile,
+			TLSKeyFile:  certificates.clientKeyFile,
+		},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	sut.Close()
+
+	_, err = robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		natsUrl,
+		robotbroker.ConnectionConfig{
+			TLSCAFile: certificates.caFile,
+		},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).ShouldNot(BeNil())
+}
+
+func Test_NewRobotBrokerService_Should_Authenticate_With_User_And_Password(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	natsUrl := startSecuredNatsServer(t, &server.Options{
+		Username: "robot",
+		Password: "s3cret",
+	})
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		natsUrl,
+		robotbroker.ConnectionConfig{User: "robot", Password: "s3cret"},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	sut.Close()
+
+	_, err = robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		natsUrl,
+		robotbroker.ConnectionConfig{User: "robot", Password: "wrong"},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).ShouldNot(BeNil())
+}
+
+func Test_NewRobotBrokerService_Should_Authenticate_With_Token(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	natsUrl := startSecuredNatsServer(t, &server.Options{
+		Authorization: "t0ken",
+	})
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		natsUrl,
+		robotbroker.ConnectionConfig{Token: "t0ken"},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	sut.Close()
+
+	_, err = robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		natsUrl,
+		robotbroker.ConnectionConfig{},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).ShouldNot(BeNil())
+}
+
+func Test_NewRobotBrokerService_Should_Authenticate_With_NKey_Seed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	user, err := nkeys.CreateUser()
+	g.Expect(err).Should(BeNil())
+
+	publicKey, err := user.PublicKey()
+	g.Expect(err).Should(BeNil())
+
+	seed, err := user.Seed()
+	g.Expect(err).Should(BeNil())
+
+	seedFile := filepath.Join(t.TempDir(), "user.nk")
+	g.Expect(os.WriteFile(seedFile, seed, 0600)).Should(Succeed())
+
+	natsUrl := startSecuredNatsServer(t, &server.Options{
+		Nkeys: []*server.NkeyUser{{Nkey: publicKey}},
+	})
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		natsUrl,
+		robotbroker.ConnectionConfig{NKeySeedFile: seedFile},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	sut.Close()
+}
+
+func Test_NewRobotBrokerService_Should_Authenticate_With_Creds_File(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	operator, err := nkeys.CreateOperator()
+	g.Expect(err).Should(BeNil())
+
+	operatorPublicKey, err := operator.PublicKey()
+	g.Expect(err).Should(BeNil())
+
+	operatorJwt, err := jwt.NewOperatorClaims(operatorPublicKey).Encode(operator)
+	g.Expect(err).Should(BeNil())
+
+	operatorClaims, err := jwt.DecodeOperatorClaims(operatorJwt)
+	g.Expect(err).Should(BeNil())
+
+	account, err := nkeys.CreateAccount()
+	g.Expect(err).Should(BeNil())
+
+	accountPublicKey, err := account.PublicKey()
+	g.Expect(err).Should(BeNil())
+
+	accountClaims := jwt.NewAccountClaims(accountPublicKey)
+	accountClaims.Limits.JetStreamLimits.MemoryStorage = -1
+	accountClaims.Limits.JetStreamLimits.DiskStorage = -1
+
+	accountJwt, err := accountClaims.Encode(operator)
+	g.Expect(err).Should(BeNil())
+
+	user, err := nkeys.CreateUser()
+	g.Expect(err).Should(BeNil())
+
+	userPublicKey, err := user.PublicKey()
+	g.Expect(err).Should(BeNil())
+
+	userSeed, err := user.Seed()
+	g.Expect(err).Should(BeNil())
+
+	userJwt, err := jwt.NewUserClaims(userPublicKey).Encode(account)
+	g.Expect(err).Should(BeNil())
+
+	creds, err := jwt.FormatUserConfig(userJwt, userSeed)
+	g.Expect(err).Should(BeNil())
+
+	credsFile := filepath.Join(t.TempDir(), "user.creds")
+	g.Expect(os.WriteFile(credsFile, creds, 0600)).Should(Succeed())
+
+	// JetStream needs a system account in operator mode
+	systemAccount, err := nkeys.CreateAccount()
+	g.Expect(err).Should(BeNil())
+
+	systemAccountPublicKey, err := systemAccount.PublicKey()
+	g.Expect(err).Should(BeNil())
+
+	systemAccountJwt, err := jwt.NewAccountClaims(systemAccountPublicKey).Encode(operator)
+	g.Expect(err).Should(BeNil())
+
+	accountResolver := &server.MemAccResolver{}
+	g.Expect(accountResolver.Store(accountPublicKey, accountJwt)).Should(Succeed())
+	g.Expect(accountResolver.Store(systemAccountPublicKey, systemAccountJwt)).Should(Succeed())
+
+	natsUrl := startSecuredNatsServer(t, &server.Options{
+		TrustedOperators: []*jwt.OperatorClaims{operatorClaims},
+		AccountResolver:  accountResolver,
+		SystemAccount:    systemAccountPublicKey,
+	})
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		natsUrl,
+		robotbroker.ConnectionConfig{CredsFile: credsFile},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	sut.Close()
+}
+
+func Test_NewRobotBrokerService_Should_Return_Error_If_Connection_Config_Is_Ambiguous(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	_, err = robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		nats.DefaultURL,
+		robotbroker.ConnectionConfig{User: "robot", Password: "s3cret", Token: "t0ken"},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		nats.DefaultURL,
+		robotbroker.ConnectionConfig{TLSCertFile: "client.pem"},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).ShouldNot(BeNil())
+}
+
+// startSecuredNatsServer runs a JetStream enabled NATS server on a random port with the given
+// security options for the duration of a test
+func startSecuredNatsServer(t *testing.T, opts *server.Options) string {
+	opts.Host = "127.0.0.1"
+	opts.Port = -1
+	opts.JetStream = true
+	opts.StoreDir = t.TempDir()
+	opts.NoLog = true
+	opts.NoSigs = true
+
+	natsServer, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go natsServer.Start()
+
+	if !natsServer.ReadyForConnections(10 * time.Second) {
+		t.Fatal("nats server did not start")
+	}
+
+	t.Cleanup(natsServer.Shutdown)
+
+	return natsServer.ClientURL()
+}
+
+type certificateFiles struct {
+	caFile         string
+	serverCertFile string
+	serverKeyFile  string
+	clientCertFile string
+	clientKeyFile  string
+}
+
+// writeCertificates creates a certificate authority which signs a server certificate for
+// 127.0.0.1 and a client certificate
+func writeCertificates(t *testing.T) certificateFiles {
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "robot test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	caDer, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := certificateFiles{caFile: filepath.Join(dir, "ca.pem")}
+	writePem(t, files.caFile, "CERTIFICATE", caDer)
+
+	issue := func(name string, serial int64, extKeyUsage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{extKeyUsage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		keyDer, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		certFile := filepath.Join(dir, name+".pem")
+		keyFile := filepath.Join(dir, name+"-key.pem")
+
+		writePem(t, certFile, "CERTIFICATE", der)
+		writePem(t, keyFile, "EC PRIVATE KEY", keyDer)
+
+		return certFile, keyFile
+	}
+
+	files.serverCertFile, files.serverKeyFile = issue("server", 2, x509.ExtKeyUsageServerAuth)
+	files.clientCertFile, files.clientKeyFile = issue("client", 3, x509.ExtKeyUsageClientAuth)
+
+	return files
+}
+
+func writePem(t *testing.T, path string, blockType string, der []byte) {
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
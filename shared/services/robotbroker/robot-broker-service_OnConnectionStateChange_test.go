@@ -0,0 +1,58 @@
+package robotbroker_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_OnConnectionStateChange_Should_Report_Lost_Connection(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	defer embeddedNatsService.Shutdown()
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		embeddedNatsService.ClientURL(),
+		robotbroker.ConnectionConfig{},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	g.Expect(sut.ConnectionState()).Should(Equal(robotbroker.Connected))
+
+	states := make(chan robotbroker.ConnectionState, 10)
+	unregister := sut.OnConnectionStateChange(func(state robotbroker.ConnectionState, err error) {
+		states <- state
+	})
+
+	err = sut.Publish(robotbroker.NewMessage(robotbroker.TaskSubject("1", "created")))
+	g.Expect(err).Should(BeNil())
+	g.Expect(sut.ConnectionStats().OutMsgs).Should(BeNumerically(">", 0))
+
+	embeddedNatsService.Shutdown()
+
+	g.Eventually(states, "5s").Should(Receive(Equal(robotbroker.Disconnected)))
+	g.Expect(sut.ConnectionState()).Should(Equal(robotbroker.Disconnected))
+
+	stats := sut.ConnectionStats()
+	g.Expect(stats.State).Should(Equal(robotbroker.Disconnected))
+	g.Expect(stats.Disconnects).Should(Equal(uint64(1)))
+	g.Expect(stats.LastError).ShouldNot(BeEmpty())
+
+	unregister()
+	sut.Close()
+
+	g.Expect(sut.ConnectionState()).Should(Equal(robotbroker.Closed))
+	g.Consistently(states).ShouldNot(Receive())
+}
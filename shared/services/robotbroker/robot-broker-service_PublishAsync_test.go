@@ -0,0 +1,62 @@
+package robotbroker_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_PublishAsync_Should_Acknowledge_Messages_Persisted_By_JetStream(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	defer embeddedNatsService.Shutdown()
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		embeddedNatsService.ClientURL(),
+		robotbroker.ConnectionConfig{},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	acks := make(chan error, 10)
+
+	for id := 0; id < 10; id++ {
+		err = sut.PublishAsync(
+			robotbroker.NewMessage(robotbroker.RobotSubject(robotbroker.DEFAULT_WAREHOUSE, robotbroker.FormatId(int64(id)), "moved")),
+			func(err error) {
+				acks <- err
+			})
+		g.Expect(err).Should(BeNil())
+	}
+
+	for id := 0; id < 10; id++ {
+		g.Eventually(acks).Should(Receive(BeNil()))
+	}
+
+	// Messages outside of the streams are never persisted
+	err = sut.PublishAsync(
+		robotbroker.NewMessage("unknown.subject"),
+		func(err error) {
+			acks <- err
+		})
+	g.Expect(err).Should(BeNil())
+	g.Eventually(acks, "5s").Should(Receive(Not(BeNil())))
+
+	jetStream, err := sut.CreateNewJetStream()
+	g.Expect(err).Should(BeNil())
+
+	streamInfo, err := jetStream.StreamInfo(robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.State.Msgs).Should(Equal(uint64(10)))
+}
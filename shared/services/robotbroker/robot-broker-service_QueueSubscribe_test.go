@@ -0,0 +1,96 @@
+package robotbroker_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_QueueSubscribe_Should_Dead_Letter_Messages_Which_Ran_Out_Of_Deliveries_Unacknowledged(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	defer embeddedNatsService.Shutdown()
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		embeddedNatsService.ClientURL(),
+		robotbroker.ConnectionConfig{},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	subject := robotbroker.RobotSubject(robotbroker.DEFAULT_WAREHOUSE, robotbroker.FormatId(1), "moved")
+
+	subscription, err := sut.QueueSubscribe(subject, "test-consumer", func(msg *robotbroker.Message) error {
+		return nil
+	})
+	g.Expect(err).Should(BeNil())
+	defer subscription.Unsubscribe()
+
+	msg := robotbroker.NewMessage(subject)
+	msg.Data = []byte("data")
+	g.Expect(sut.Publish(msg)).Should(Succeed())
+
+	jetStream, err := sut.CreateNewJetStream()
+	g.Expect(err).Should(BeNil())
+
+	streamInfo, err := jetStream.StreamInfo(robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	// JetStream advises a message ran out of deliveries when its ack wait expired every time,
+	// the advisory is sent twice to check the dead letter is de-duplicated
+	natsConnection, err := nats.Connect(embeddedNatsService.ClientURL())
+	g.Expect(err).Should(BeNil())
+	defer natsConnection.Close()
+
+	advisory, err := json.Marshal(map[string]interface{}{
+		"stream":     robotbroker.STREAM_ROBOT,
+		"consumer":   "test-consumer",
+		"stream_seq": streamInfo.State.LastSeq,
+		"deliveries": robotbroker.DefaultMaxDeliver,
+	})
+	g.Expect(err).Should(BeNil())
+
+	for idx := 0; idx < 2; idx++ {
+		g.Expect(natsConnection.Publish(
+			robotbroker.SUBJECT_ADVISORY_MAX_DELIVERIES+"."+robotbroker.STREAM_ROBOT+".test-consumer",
+			advisory)).Should(Succeed())
+	}
+
+	g.Expect(natsConnection.Flush()).Should(Succeed())
+
+	g.Eventually(func() uint64 {
+		streamInfo, err := jetStream.StreamInfo(robotbroker.STREAM_DEAD_LETTER)
+		if err != nil {
+			return 0
+		}
+
+		return streamInfo.State.Msgs
+	}, "5s").Should(Equal(uint64(1)))
+
+	g.Consistently(func() uint64 {
+		streamInfo, err = jetStream.StreamInfo(robotbroker.STREAM_DEAD_LETTER)
+		g.Expect(err).Should(BeNil())
+
+		return streamInfo.State.Msgs
+	}, "500ms").Should(Equal(uint64(1)))
+
+	deadLetter, err := jetStream.GetMsg(robotbroker.STREAM_DEAD_LETTER, streamInfo.State.LastSeq)
+	g.Expect(err).Should(BeNil())
+	g.Expect(deadLetter.Data).Should(Equal([]byte("data")))
+	g.Expect(deadLetter.Header.Get(robotbroker.HEADER_DEAD_LETTER_CONSUMER)).Should(Equal("test-consumer"))
+	g.Expect(deadLetter.Header.Get(robotbroker.HEADER_DEAD_LETTER_DELIVERIES)).Should(Equal("5"))
+	g.Expect(deadLetter.Header.Get(robotbroker.HEADER_DEAD_LETTER_ERROR)).ShouldNot(BeEmpty())
+}
@@ -0,0 +1,63 @@
+package robotbroker_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_Request_Should_Not_Be_Captured_By_Streams(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	defer embeddedNatsService.Shutdown()
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		embeddedNatsService.ClientURL(),
+		robotbroker.ConnectionConfig{},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	msg := robotbroker.NewMessage(robotbroker.RequestSubject(robotbroker.TaskSubject("1", "created")))
+	msg.Header.Set("Content-Type", "text/plain")
+	msg.Data = []byte("hello")
+
+	_, err = sut.Request(msg, time.Second)
+	g.Expect(err).Should(Equal(robotbroker.ErrNoResponders))
+
+	_, err = sut.QueueRespond(
+		robotbroker.RequestSubject(robotbroker.TaskSubject(robotbroker.SUBJECT_WILDCARD, "created")),
+		"responders",
+		func(msg *robotbroker.Message) *robotbroker.Message {
+			reply := robotbroker.NewMessage(msg.Subject)
+			reply.Header.Set("Content-Type", msg.Header.Get("Content-Type"))
+			reply.Data = append([]byte("re: "), msg.Data...)
+
+			return reply
+		})
+	g.Expect(err).Should(BeNil())
+
+	reply, err := sut.Request(msg, time.Second)
+	g.Expect(err).Should(BeNil())
+	g.Expect(string(reply.Data)).Should(Equal("re: hello"))
+	g.Expect(reply.Header.Get("Content-Type")).Should(Equal("text/plain"))
+
+	jetStream, err := sut.CreateNewJetStream()
+	g.Expect(err).Should(BeNil())
+
+	streamInfo, err := jetStream.StreamInfo(robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.State.Msgs).Should(BeZero())
+}
@@ -0,0 +1,58 @@
+package robotbroker_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+func Test_SubscribeLast_Should_Deliver_The_Last_Message_Of_Every_Subject_Kept_By_JetStream(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	defer embeddedNatsService.Shutdown()
+
+	sut, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		embeddedNatsService.ClientURL(),
+		robotbroker.ConnectionConfig{},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	defer sut.Close()
+
+	for _, data := range []string{"north-1", "south-1", "north-2"} {
+		msg := robotbroker.NewMessage(robotbroker.WarehouseSubject(data[:len(data)-2], "configured"))
+		msg.Data = []byte(data)
+
+		g.Expect(sut.Publish(msg)).Should(Succeed())
+	}
+
+	subject := robotbroker.WarehouseSubject(robotbroker.SUBJECT_WILDCARD, "configured")
+
+	// Each subscriber, like each replica of a service, receives the last messages
+	for subscriber := 0; subscriber < 2; subscriber++ {
+		received := make(chan string, 10)
+
+		subscription, err := sut.SubscribeLast(subject, func(msg *robotbroker.Message) error {
+			received <- string(msg.Data)
+
+			return nil
+		})
+		g.Expect(err).Should(BeNil())
+		defer subscription.Unsubscribe()
+
+		g.Eventually(received).Should(Receive(Equal("south-1")))
+		g.Eventually(received).Should(Receive(Equal("north-2")))
+		g.Consistently(received, 100*time.Millisecond).ShouldNot(Receive())
+	}
+}
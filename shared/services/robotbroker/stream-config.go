@@ -0,0 +1,192 @@
+package robotbroker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// DefaultStreamMaxAge is how long the robot stream keeps its messages unless told otherwise
+	DefaultStreamMaxAge = 24 * time.Hour
+	// DefaultDeadLetterStreamMaxAge is how long the dead letter stream keeps its messages
+	DefaultDeadLetterStreamMaxAge = 7 * 24 * time.Hour
+)
+
+// StreamConfig holds the settings of the stream task and robot events are kept in. A
+// negative MaxBytes or MaxMsgs means unlimited and a zero MaxAge keeps messages forever
+type StreamConfig struct {
+	Name      string
+	Retention nats.RetentionPolicy
+	MaxAge    time.Duration
+	MaxBytes  int64
+	MaxMsgs   int64
+	Replicas  int
+	Storage   nats.StorageType
+}
+
+// StreamConfigChange is a setting of an existing stream which differs from the desired one
+type StreamConfigChange struct {
+	Field   string
+	Current string
+	Desired string
+	// Immutable changes can not be applied to an existing stream, it has to be recreated
+	Immutable bool
+}
+
+// String formats the change as "field: current -> desired"
+func (c StreamConfigChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.Current, c.Desired)
+}
+
+// DefaultStreamConfig returns the settings the robot stream is created with unless told
+// otherwise
+func DefaultStreamConfig() StreamConfig {
+	return StreamConfig{
+		Name:      STREAM_ROBOT,
+		Retention: nats.LimitsPolicy,
+		MaxAge:    DefaultStreamMaxAge,
+		MaxBytes:  -1,
+		MaxMsgs:   -1,
+		Replicas:  1,
+		Storage:   nats.FileStorage,
+	}
+}
+
+// NewStreamConfig parses the textual stream settings found in a configuration, empty values
+// fall back to DefaultStreamConfig. Retention is one of limits, interest or workqueue, max age
+// is a Go duration and storage is either file or memory
+func NewStreamConfig(
+	name string,
+	retention string,
+	maxAge string,
+	maxBytes string,
+	maxMsgs string,
+	replicas string,
+	storage string) (StreamConfig, error) {
+	config := DefaultStreamConfig()
+
+	var err error
+
+	if name != "" {
+		if !IsValidToken(name) {
+			return config, fmt.Errorf("invalid stream name %q", name)
+		}
+
+		config.Name = name
+	}
+
+	if retention != "" {
+		if err = json.Unmarshal([]byte(strconv.Quote(strings.ToLower(retention))), &config.Retention); err != nil {
+			return config, fmt.Errorf("invalid stream retention %q, expected limits, interest or workqueue", retention)
+		}
+	}
+
+	if maxAge != "" {
+		if config.MaxAge, err = time.ParseDuration(maxAge); err != nil || config.MaxAge < 0 {
+			return config, fmt.Errorf("invalid stream max age %q", maxAge)
+		}
+	}
+
+	if maxBytes != "" {
+		if config.MaxBytes, err = strconv.ParseInt(maxBytes, 10, 64); err != nil {
+			return config, fmt.Errorf("invalid stream max bytes %q", maxBytes)
+		}
+	}
+
+	if maxMsgs != "" {
+		if config.MaxMsgs, err = strconv.ParseInt(maxMsgs, 10, 64); err != nil {
+			return config, fmt.Errorf("invalid stream max messages %q", maxMsgs)
+		}
+	}
+
+	if replicas != "" {
+		if config.Replicas, err = strconv.Atoi(replicas); err != nil || config.Replicas < 1 {
+			return config, fmt.Errorf("invalid stream replicas %q", replicas)
+		}
+	}
+
+	if storage != "" {
+		if err = json.Unmarshal([]byte(strconv.Quote(strings.ToLower(storage))), &config.Storage); err != nil {
+			return config, fmt.Errorf("invalid stream storage %q, expected file or memory", storage)
+		}
+	}
+
+	return config, nil
+}
+
+// natsStreamConfig returns the JetStream configuration of the robot stream
+func (c StreamConfig) natsStreamConfig() *nats.StreamConfig {
+	return &nats.StreamConfig{
+		Name: c.Name,
+		Subjects: []string{
+			SUBJECT_TASK + "." + SUBJECT_FULL_WILDCARD,
+			SUBJECT_ROBOT + "." + SUBJECT_FULL_WILDCARD,
+			SUBJECT_WAREHOUSE + "." + SUBJECT_FULL_WILDCARD,
+		},
+		Retention: c.Retention,
+		MaxAge:    c.MaxAge,
+		MaxBytes:  c.MaxBytes,
+		MaxMsgs:   c.MaxMsgs,
+		Replicas:  c.Replicas,
+		Storage:   c.Storage,
+	}
+}
+
+// DiffStreamConfig lists the settings managed by the broker which differ between the
+// configuration of an existing stream and the desired one
+func DiffStreamConfig(current *nats.StreamConfig, desired *nats.StreamConfig) []StreamConfigChange {
+	changes := make([]StreamConfigChange, 0)
+
+	add := func(field string, currentValue string, desiredValue string, immutable bool) {
+		if currentValue != desiredValue {
+			changes = append(changes, StreamConfigChange{
+				Field:     field,
+				Current:   currentValue,
+				Desired:   desiredValue,
+				Immutable: immutable,
+			})
+		}
+	}
+
+	add("subjects", strings.Join(current.Subjects, ","), strings.Join(desired.Subjects, ","), false)
+	add("retention", current.Retention.String(), desired.Retention.String(), true)
+	add("max age", current.MaxAge.String(), desired.MaxAge.String(), false)
+	add("max bytes", formatLimit(current.MaxBytes), formatLimit(desired.MaxBytes), false)
+	add("max messages", formatLimit(current.MaxMsgs), formatLimit(desired.MaxMsgs), false)
+	add("replicas", strconv.Itoa(normaliseReplicas(current.Replicas)), strconv.Itoa(normaliseReplicas(desired.Replicas)), false)
+	add("storage", current.Storage.String(), desired.Storage.String(), true)
+
+	return changes
+}
+
+// formatLimit treats every negative limit as unlimited, like JetStream does
+func formatLimit(limit int64) string {
+	if limit < 0 {
+		return "unlimited"
+	}
+
+	return strconv.FormatInt(limit, 10)
+}
+
+// normaliseReplicas treats zero replicas as one, like JetStream does
+func normaliseReplicas(replicas int) int {
+	if replicas < 1 {
+		return 1
+	}
+
+	return replicas
+}
+
+func formatStreamConfigChanges(changes []StreamConfigChange) string {
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+
+	return strings.Join(lines, "; ")
+}
@@ -0,0 +1,68 @@
+package robotbroker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+func Test_DiffStreamConfig_Should_Return_Nothing_If_Configs_Match(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	current := &nats.StreamConfig{
+		Name:       "RobotStream",
+		Subjects:   []string{"task.>", "robot.>"},
+		MaxAge:     time.Hour,
+		MaxBytes:   -1,
+		MaxMsgs:    -5,
+		Duplicates: 2 * time.Minute,
+		Storage:    nats.FileStorage,
+	}
+
+	desired := &nats.StreamConfig{
+		Name:     "RobotStream",
+		Subjects: []string{"task.>", "robot.>"},
+		MaxAge:   time.Hour,
+		MaxBytes: -1,
+		MaxMsgs:  -1,
+		Replicas: 1,
+		Storage:  nats.FileStorage,
+	}
+
+	g.Expect(robotbroker.DiffStreamConfig(current, desired)).Should(BeEmpty())
+}
+
+func Test_DiffStreamConfig_Should_Flag_Immutable_Changes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	current := &nats.StreamConfig{
+		Name:      "RobotStream",
+		Subjects:  []string{"task.>", "robot.>"},
+		Retention: nats.LimitsPolicy,
+		MaxAge:    time.Hour,
+		MaxBytes:  -1,
+		MaxMsgs:   -1,
+		Replicas:  1,
+		Storage:   nats.FileStorage,
+	}
+
+	desired := &nats.StreamConfig{
+		Name:      "RobotStream",
+		Subjects:  []string{"task.>", "robot.>"},
+		Retention: nats.LimitsPolicy,
+		MaxAge:    2 * time.Hour,
+		MaxBytes:  -1,
+		MaxMsgs:   100,
+		Replicas:  1,
+		Storage:   nats.MemoryStorage,
+	}
+
+	g.Expect(robotbroker.DiffStreamConfig(current, desired)).Should(Equal([]robotbroker.StreamConfigChange{
+		{Field: "max age", Current: "1h0m0s", Desired: "2h0m0s"},
+		{Field: "max messages", Current: "unlimited", Desired: "100"},
+		{Field: "storage", Current: "File", Desired: "Memory", Immutable: true},
+	}))
+}
@@ -0,0 +1,59 @@
+package robotbroker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+)
+
+func Test_NewStreamConfig_Should_Fall_Back_To_Defaults(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	config, err := robotbroker.NewStreamConfig("", "", "", "", "", "", "")
+	g.Expect(err).Should(BeNil())
+	g.Expect(config).Should(Equal(robotbroker.DefaultStreamConfig()))
+}
+
+func Test_NewStreamConfig_Should_Parse_Every_Setting(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	config, err := robotbroker.NewStreamConfig("Robots", "WorkQueue", "90m", "1048576", "1000", "3", "memory")
+	g.Expect(err).Should(BeNil())
+	g.Expect(config).Should(Equal(robotbroker.StreamConfig{
+		Name:      "Robots",
+		Retention: nats.WorkQueuePolicy,
+		MaxAge:    90 * time.Minute,
+		MaxBytes:  1048576,
+		MaxMsgs:   1000,
+		Replicas:  3,
+		Storage:   nats.MemoryStorage,
+	}))
+}
+
+func Test_NewStreamConfig_Should_Return_Error_If_A_Setting_Is_Invalid(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := robotbroker.NewStreamConfig("robot.stream", "", "", "", "", "", "")
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = robotbroker.NewStreamConfig("", "forever", "", "", "", "", "")
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = robotbroker.NewStreamConfig("", "", "a day", "", "", "", "")
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = robotbroker.NewStreamConfig("", "", "", "1MB", "", "", "")
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = robotbroker.NewStreamConfig("", "", "", "", "many", "", "")
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = robotbroker.NewStreamConfig("", "", "", "", "", "0", "")
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = robotbroker.NewStreamConfig("", "", "", "", "", "", "disk")
+	g.Expect(err).ShouldNot(BeNil())
+}
@@ -0,0 +1,34 @@
+// Code generated by asyncapi-codegen from Robot_Events.yaml. DO NOT EDIT.
+
+package robotbroker
+
+// Defines the first token of the subjects.
+const (
+	SUBJECT_ROBOT     = "robot"
+	SUBJECT_TASK      = "task"
+	SUBJECT_WAREHOUSE = "warehouse"
+)
+
+// Defines the channels events are published on, parameters are enclosed in braces.
+const (
+	// CHANNEL_ROBOT_FAILED_TO_MOVE: A robot could not move
+	CHANNEL_ROBOT_FAILED_TO_MOVE = "robot.{warehouse}.{robotId}.failedtomove"
+	// CHANNEL_ROBOT_MOVED: A robot moved to a new position
+	CHANNEL_ROBOT_MOVED = "robot.{warehouse}.{robotId}.moved"
+	// CHANNEL_TASK_BATCH_CANCELLED: The tasks of a batch which were not finished were cancelled
+	CHANNEL_TASK_BATCH_CANCELLED = "task.{batchId}.batchcancelled"
+	// CHANNEL_TASK_BATCH_CREATED: A batch of tasks was created, the robots of the batch start its tasks together
+	CHANNEL_TASK_BATCH_CREATED = "task.{batchId}.batchcreated"
+	// CHANNEL_TASK_CANCELLED: A task was cancelled before it was completed
+	CHANNEL_TASK_CANCELLED = "task.{taskId}.cancelled"
+	// CHANNEL_TASK_COMPLETED: A robot finished executing a task
+	CHANNEL_TASK_COMPLETED = "task.{taskId}.completed"
+	// CHANNEL_TASK_CREATED: A task was created and is waiting to be executed by a robot
+	CHANNEL_TASK_CREATED = "task.{taskId}.created"
+	// CHANNEL_TASK_FAILED: A robot finished executing a task but could not make some of its moves
+	CHANNEL_TASK_FAILED = "task.{taskId}.failed"
+	// CHANNEL_TASK_STARTED: A robot started executing a task
+	CHANNEL_TASK_STARTED = "task.{taskId}.started"
+	// CHANNEL_WAREHOUSE_CONFIGURED: A warehouse was started or its layout changed
+	CHANNEL_WAREHOUSE_CONFIGURED = "warehouse.{warehouse}.configured"
+)
@@ -0,0 +1,93 @@
+package robotbroker
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TaskSubject returns the subject of a task event, e.g. task.42.created. Use SUBJECT_WILDCARD
+// as task id to match the event of every task
+func TaskSubject(taskId string, event string) string {
+	return strings.Join([]string{SUBJECT_TASK, taskId, event}, ".")
+}
+
+// RobotSubject returns the subject of a robot event, e.g. robot.default.3.moved. Use
+// SUBJECT_WILDCARD as warehouse, robot id or event to match more robots or events
+func RobotSubject(warehouse string, robotId string, event string) string {
+	return strings.Join([]string{SUBJECT_ROBOT, warehouse, robotId, event}, ".")
+}
+
+// WarehouseSubject returns the subject of a warehouse event, e.g. warehouse.default.configured.
+// Use SUBJECT_WILDCARD as warehouse to match the event of every warehouse
+func WarehouseSubject(warehouse string, event string) string {
+	return strings.Join([]string{SUBJECT_WAREHOUSE, warehouse, event}, ".")
+}
+
+// RequestSubject returns the subject a request about the subject of an event is sent on, e.g.
+// request.task.42.created
+func RequestSubject(subject string) string {
+	return SUBJECT_REQUEST + "." + subject
+}
+
+// ParseTaskSubject returns the task id and the event of a task event subject
+func ParseTaskSubject(subject string) (taskId string, event string, ok bool) {
+	tokens := strings.Split(subject, ".")
+	if len(tokens) != 3 || tokens[0] != SUBJECT_TASK {
+		return "", "", false
+	}
+
+	return tokens[1], tokens[2], true
+}
+
+// ParseRobotSubject returns the warehouse, the robot id and the event of a robot event subject
+func ParseRobotSubject(subject string) (warehouse string, robotId string, event string, ok bool) {
+	tokens := strings.Split(subject, ".")
+	if len(tokens) != 4 || tokens[0] != SUBJECT_ROBOT {
+		return "", "", "", false
+	}
+
+	return tokens[1], tokens[2], tokens[3], true
+}
+
+// ParseWarehouseSubject returns the warehouse and the event of a warehouse event subject
+func ParseWarehouseSubject(subject string) (warehouse string, event string, ok bool) {
+	tokens := strings.Split(subject, ".")
+	if len(tokens) != 3 || tokens[0] != SUBJECT_WAREHOUSE {
+		return "", "", false
+	}
+
+	return tokens[1], tokens[2], true
+}
+
+// FormatId formats an id as a subject token
+func FormatId(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// IsValidToken reports whether a string can be used as a single subject token
+func IsValidToken(token string) bool {
+	return token != "" && !strings.ContainsAny(token, ".*> \t\r\n")
+}
+
+// MatchSubject reports whether a subject matches a pattern, SUBJECT_WILDCARD matches exactly
+// one token and SUBJECT_FULL_WILDCARD matches one or more trailing tokens
+func MatchSubject(pattern string, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for idx, patternToken := range patternTokens {
+		if patternToken == SUBJECT_FULL_WILDCARD {
+			return idx < len(subjectTokens)
+		}
+
+		if idx >= len(subjectTokens) {
+			return false
+		}
+
+		if patternToken != SUBJECT_WILDCARD && patternToken != subjectTokens[idx] {
+			return false
+		}
+	}
+
+	return len(patternTokens) == len(subjectTokens)
+}
@@ -0,0 +1,107 @@
+package streaminspector
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+)
+
+const (
+	// DefaultReplaySubjectPrefix prefixes the subject of every replayed message
+	DefaultReplaySubjectPrefix = "replay"
+	// DefaultReplayStream is the stream replayed messages are kept in
+	DefaultReplayStream = "ReplayStream"
+	// DefaultReplayStreamMaxAge is how long replayed messages are kept
+	DefaultReplayStreamMaxAge = time.Hour
+)
+
+// Filter selects the messages of a stream, the zero value selects every message
+type Filter struct {
+	// Subject is a subject pattern, wildcards are allowed
+	Subject string
+	// FirstSequence and LastSequence bound the stream sequences, zero means unbounded
+	FirstSequence uint64
+	LastSequence  uint64
+	// Since and Until bound the time the messages were stored, zero means unbounded
+	Since time.Time
+	Until time.Time
+	// RobotId selects the events of a single robot
+	RobotId *int64
+	// TaskId selects the events of a single task, those of its batch included
+	TaskId *int64
+	// Limit bounds the number of messages selected, zero means unbounded
+	Limit int
+}
+
+// Event is a message of a stream with the event it holds
+type Event struct {
+	Sequence uint64
+	Subject  string
+	Time     time.Time
+	Header   nats.Header
+	Data     []byte
+	// Envelope and one of TaskEvent, RobotEvent and WarehouseEvent are set once the message
+	// has been decoded
+	Envelope       *eventpublisher.Envelope
+	TaskEvent      *eventpublisher.TaskEvent
+	RobotEvent     *eventpublisher.RobotEvent
+	WarehouseEvent *eventpublisher.WarehouseEvent
+	// DecodeError explains why the message could not be decoded
+	DecodeError string
+}
+
+// ReplayTarget is where selected messages are republished to
+type ReplayTarget struct {
+	// SubjectPrefix prefixes the original subject, defaults to DefaultReplaySubjectPrefix
+	SubjectPrefix string
+	// Stream is created to keep the replayed messages when it does not exist, defaults to
+	// DefaultReplayStream
+	Stream string
+}
+
+// Record is a message of a stream as it is exported, a NDJSON export holds one record per line
+type Record struct {
+	Sequence uint64      `json:"sequence"`
+	Subject  string      `json:"subject"`
+	Time     time.Time   `json:"time"`
+	Header   nats.Header `json:"header,omitempty"`
+	Data     []byte      `json:"data"`
+	// Event is the decoded event, it is there for humans and ignored on import
+	Event json.RawMessage `json:"event,omitempty"`
+}
+
+// SubjectRewrite replaces the leading tokens From of a subject with To, e.g. robot.default
+// with robot.staging
+type SubjectRewrite struct {
+	From string
+	To   string
+}
+
+// ImportOptions configures an import
+type ImportOptions struct {
+	// Stream is the empty stream the messages are imported into, defaults to the inspected stream
+	Stream string
+	// Rewrites are applied to the subject of every message, the first matching one wins
+	Rewrites []SubjectRewrite
+	// Force imports into a stream which has consumers, they process the imported messages as
+	// if they had just been published
+	Force bool
+}
+
+// StreamInspectorInterface defines contract for inspecting the events of a stream and
+// replaying them for debugging
+type StreamInspectorInterface interface {
+	Info() (*nats.StreamInfo, error)
+	// Events returns the selected messages in stream order
+	Events(filter Filter) ([]Event, error)
+	// Replay republishes the selected messages to the target and returns how many it republished
+	Replay(filter Filter, target ReplayTarget) (int, error)
+	// Export writes the selected messages as NDJSON records and returns how many it wrote
+	Export(filter Filter, writer io.Writer) (int, error)
+	// Import publishes the NDJSON records of an export to an empty stream and returns how many
+	// it published
+	Import(reader io.Reader, options ImportOptions) (int, error)
+}
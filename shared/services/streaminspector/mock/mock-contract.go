@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: contract.go
+
+// Package mock_streaminspector is a generated GoMock package.
+package mock_streaminspector
+
+import (
+	io "io"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	nats "github.com/nats-io/nats.go"
+	streaminspector "github.com/sepisoad/robot-challange/shared/services/streaminspector"
+)
+
+// MockStreamInspectorInterface is a mock of StreamInspectorInterface interface.
+type MockStreamInspectorInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStreamInspectorInterfaceMockRecorder
+}
+
+// MockStreamInspectorInterfaceMockRecorder is the mock recorder for MockStreamInspectorInterface.
+type MockStreamInspectorInterfaceMockRecorder struct {
+	mock *MockStreamInspectorInterface
+}
+
+// NewMockStreamInspectorInterface creates a new mock instance.
+func NewMockStreamInspectorInterface(ctrl *gomock.Controller) *MockStreamInspectorInterface {
+	mock := &MockStreamInspectorInterface{ctrl: ctrl}
+	mock.recorder = &MockStreamInspectorInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStreamInspectorInterface) EXPECT() *MockStreamInspectorInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Events mocks base method.
+func (m *MockStreamInspectorInterface) Events(filter streaminspector.Filter) ([]streaminspector.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events", filter)
+	ret0, _ := ret[0].([]streaminspector.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockStreamInspectorInterfaceMockRecorder) Events(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockStreamInspectorInterface)(nil).Events), filter)
+}
+
+// Export mocks base method.
+func (m *MockStreamInspectorInterface) Export(filter streaminspector.Filter, writer io.Writer) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", filter, writer)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockStreamInspectorInterfaceMockRecorder) Export(filter, writer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockStreamInspectorInterface)(nil).Export), filter, writer)
+}
+
+// Import mocks base method.
+func (m *MockStreamInspectorInterface) Import(reader io.Reader, options streaminspector.ImportOptions) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", reader, options)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockStreamInspectorInterfaceMockRecorder) Import(reader, options interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockStreamInspectorInterface)(nil).Import), reader, options)
+}
+
+// Info mocks base method.
+func (m *MockStreamInspectorInterface) Info() (*nats.StreamInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info")
+	ret0, _ := ret[0].(*nats.StreamInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockStreamInspectorInterfaceMockRecorder) Info() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockStreamInspectorInterface)(nil).Info))
+}
+
+// Replay mocks base method.
+func (m *MockStreamInspectorInterface) Replay(filter streaminspector.Filter, target streaminspector.ReplayTarget) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", filter, target)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockStreamInspectorInterfaceMockRecorder) Replay(filter, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockStreamInspectorInterface)(nil).Replay), filter, target)
+}
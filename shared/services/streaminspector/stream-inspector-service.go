@@ -0,0 +1,591 @@
+package streaminspector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"go.uber.org/zap"
+)
+
+// walkTimeout is how long a walk waits for the next message, the messages are stored already
+const walkTimeout = 5 * time.Second
+
+// reservedSubjectPrefixes are captured by the streams of the project, replaying to them would
+// hand the messages over to the consumers again
+var reservedSubjectPrefixes = []string{
+	robotbroker.SUBJECT_TASK,
+	robotbroker.SUBJECT_ROBOT,
+	robotbroker.SUBJECT_WAREHOUSE,
+	robotbroker.SUBJECT_DEAD_LETTER,
+	robotbroker.SUBJECT_REQUEST,
+}
+
+type streamInspectorService struct {
+	logger              *zap.SugaredLogger
+	jetStream           nats.JetStreamContext
+	eventDecoderService eventpublisher.EventDecoderInterface
+	stream              string
+}
+
+// NewStreamInspectorService creates a concrete instance of StreamInspectorInterface which
+// inspects the stream of the given name
+func NewStreamInspectorService(
+	logger *zap.SugaredLogger,
+	robotBrokerService robotbroker.JetStreamBrokerInterface,
+	eventDecoderService eventpublisher.EventDecoderInterface,
+	stream string) (StreamInspectorInterface, error) {
+	if eventDecoderService == nil {
+		return nil, errors.New("a stream inspector requires an event decoder")
+	}
+
+	jetStream, err := robotBrokerService.CreateNewJetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	return &streamInspectorService{
+		logger:              logger,
+		jetStream:           jetStream,
+		eventDecoderService: eventDecoderService,
+		stream:              stream,
+	}, nil
+}
+
+// Info returns the configuration and the state of the stream
+func (s *streamInspectorService) Info() (*nats.StreamInfo, error) {
+	return s.jetStream.StreamInfo(s.stream)
+}
+
+// Events returns the selected messages in stream order, decoded when they hold an event
+func (s *streamInspectorService) Events(filter Filter) ([]Event, error) {
+	events := make([]Event, 0)
+
+	err := s.walk(filter, func(msg *nats.RawStreamMsg) error {
+		events = append(events, s.decode(msg))
+
+		return nil
+	})
+
+	return events, err
+}
+
+// Replay republishes the selected messages on the subject prefix of the target, the stream of
+// the target is created when it does not exist
+func (s *streamInspectorService) Replay(filter Filter, target ReplayTarget) (int, error) {
+	if target.SubjectPrefix == "" {
+		target.SubjectPrefix = DefaultReplaySubjectPrefix
+	}
+
+	if target.Stream == "" {
+		target.Stream = DefaultReplayStream
+	}
+
+	if err := validateSubjectPrefix(target.SubjectPrefix); err != nil {
+		return 0, err
+	}
+
+	if target.Stream == s.stream {
+		return 0, fmt.Errorf("can not replay stream %s into itself", s.stream)
+	}
+
+	if err := s.ensureReplayStream(target); err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+
+	err := s.walk(filter, func(msg *nats.RawStreamMsg) error {
+		replay := nats.NewMsg(target.SubjectPrefix + "." + msg.Subject)
+		replay.Data = msg.Data
+
+		for key, values := range msg.Header {
+			replay.Header[key] = values
+		}
+
+		// The replayed message must not be dropped as a duplicate of the original one
+		replay.Header.Del(nats.MsgIdHdr)
+		replay.Header.Set(robotbroker.HEADER_REPLAY_SUBJECT, msg.Subject)
+		replay.Header.Set(robotbroker.HEADER_REPLAY_STREAM_SEQUENCE, strconv.FormatUint(msg.Sequence, 10))
+
+		if _, err := s.jetStream.PublishMsg(replay); err != nil {
+			s.logger.Errorf(
+				"Failed to replay message %d from %s to %s. Error: %v",
+				msg.Sequence,
+				msg.Subject,
+				replay.Subject,
+				err)
+
+			return err
+		}
+
+		replayed++
+
+		return nil
+	})
+
+	return replayed, err
+}
+
+// Export writes the selected messages in stream order, one NDJSON record per message
+func (s *streamInspectorService) Export(filter Filter, writer io.Writer) (int, error) {
+	encoder := json.NewEncoder(writer)
+	exported := 0
+
+	err := s.walk(filter, func(msg *nats.RawStreamMsg) error {
+		record := Record{
+			Sequence: msg.Sequence,
+			Subject:  msg.Subject,
+			Time:     msg.Time.UTC(),
+			Header:   msg.Header,
+			Data:     msg.Data,
+		}
+
+		var err error
+		event := s.decode(msg)
+
+		switch {
+		case event.TaskEvent != nil:
+			record.Event, err = json.Marshal(event.TaskEvent)
+		case event.RobotEvent != nil:
+			record.Event, err = json.Marshal(event.RobotEvent)
+		case event.WarehouseEvent != nil:
+			record.Event, err = json.Marshal(event.WarehouseEvent)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+
+		exported++
+
+		return nil
+	})
+
+	return exported, err
+}
+
+// Import publishes the records of an export to an empty stream in the order of the export, the
+// original time, sequence and subject of every message are kept in headers since JetStream
+// assigns new ones
+func (s *streamInspectorService) Import(reader io.Reader, options ImportOptions) (int, error) {
+	if options.Stream == "" {
+		options.Stream = s.stream
+	}
+
+	for _, rewrite := range options.Rewrites {
+		if err := validateRewrite(rewrite); err != nil {
+			return 0, err
+		}
+	}
+
+	records, err := readRecords(reader)
+	if err != nil {
+		return 0, err
+	}
+
+	streamInfo, err := s.jetStream.StreamInfo(options.Stream)
+	if err != nil {
+		return 0, err
+	}
+
+	if streamInfo.State.Msgs != 0 {
+		return 0, fmt.Errorf(
+			"stream %s holds %d messages, messages can only be imported into an empty stream",
+			options.Stream,
+			streamInfo.State.Msgs)
+	}
+
+	// The consumers of the live stream would execute the imported tasks again
+	if streamInfo.State.Consumers != 0 && !options.Force {
+		return 0, fmt.Errorf(
+			"stream %s has %d consumers which would process the imported messages, force the import to go ahead",
+			options.Stream,
+			streamInfo.State.Consumers)
+	}
+
+	// Every record is checked before the first one is published, so a bad file imports nothing
+	msgs := make([]*nats.Msg, 0, len(records))
+
+	for _, record := range records {
+		msg := importMsg(record, options.Rewrites)
+
+		if !captures(streamInfo.Config.Subjects, msg.Subject) {
+			return 0, fmt.Errorf("stream %s does not capture %s", options.Stream, msg.Subject)
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	imported := 0
+
+	for _, msg := range msgs {
+		if _, err := s.jetStream.PublishMsg(msg, nats.ExpectStream(options.Stream)); err != nil {
+			s.logger.Errorf(
+				"Failed to import message %s of %s. Error: %v",
+				msg.Header.Get(robotbroker.HEADER_IMPORT_STREAM_SEQUENCE),
+				msg.Subject,
+				err)
+
+			return imported, err
+		}
+
+		imported++
+	}
+
+	return imported, nil
+}
+
+// walk calls handler with every selected message of the stream in order, an ordered consumer
+// delivers them from where the filter starts, only those on its subject
+func (s *streamInspectorService) walk(filter Filter, handler func(msg *nats.RawStreamMsg) error) error {
+	streamInfo, err := s.jetStream.StreamInfo(s.stream)
+	if err != nil {
+		return err
+	}
+
+	if streamInfo.State.Msgs == 0 {
+		return nil
+	}
+
+	last := streamInfo.State.LastSeq
+	if filter.LastSequence != 0 && filter.LastSequence < last {
+		last = filter.LastSequence
+	}
+
+	options := []nats.SubOpt{nats.BindStream(s.stream), nats.OrderedConsumer()}
+
+	switch {
+	case filter.FirstSequence > 0:
+		options = append(options, nats.StartSequence(filter.FirstSequence))
+	case !filter.Since.IsZero():
+		options = append(options, nats.StartTime(filter.Since))
+	default:
+		options = append(options, nats.DeliverAll())
+	}
+
+	subscription, err := s.jetStream.SubscribeSync(filter.subject(), options...)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = subscription.Unsubscribe()
+	}()
+
+	consumerInfo, err := subscription.ConsumerInfo()
+	if err != nil {
+		return err
+	}
+
+	// Nothing is delivered when no message is left on the subject
+	if consumerInfo.NumPending == 0 && consumerInfo.Delivered.Consumer == 0 {
+		return nil
+	}
+
+	selected := 0
+
+	for {
+		delivered, err := subscription.NextMsg(walkTimeout)
+		if err != nil {
+			return err
+		}
+
+		metadata, err := delivered.Metadata()
+		if err != nil {
+			return err
+		}
+
+		msg := &nats.RawStreamMsg{
+			Subject:  delivered.Subject,
+			Sequence: metadata.Sequence.Stream,
+			Header:   delivered.Header,
+			Data:     delivered.Data,
+			Time:     metadata.Timestamp,
+		}
+
+		if msg.Sequence > last || (!filter.Until.IsZero() && msg.Time.After(filter.Until)) {
+			return nil
+		}
+
+		if s.matches(filter, msg) {
+			if err := handler(msg); err != nil {
+				return err
+			}
+
+			if selected++; filter.Limit > 0 && selected >= filter.Limit {
+				return nil
+			}
+		}
+
+		// The message is the last one on the subject
+		if metadata.NumPending == 0 || msg.Sequence == last {
+			return nil
+		}
+	}
+}
+
+func (s *streamInspectorService) decode(msg *nats.RawStreamMsg) Event {
+	event := Event{
+		Sequence: msg.Sequence,
+		Subject:  msg.Subject,
+		Time:     msg.Time,
+		Header:   msg.Header,
+		Data:     msg.Data,
+	}
+
+	contentType := msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE)
+
+	var err error
+	var envelope eventpublisher.Envelope
+
+	if _, _, ok := robotbroker.ParseTaskSubject(msg.Subject); ok {
+		taskEvent := eventpublisher.TaskEvent{}
+		if envelope, err = s.eventDecoderService.Decode(contentType, msg.Data, &taskEvent); err == nil {
+			event.TaskEvent = &taskEvent
+		}
+	} else if _, _, _, ok := robotbroker.ParseRobotSubject(msg.Subject); ok {
+		robotEvent := eventpublisher.RobotEvent{}
+		if envelope, err = s.eventDecoderService.Decode(contentType, msg.Data, &robotEvent); err == nil {
+			event.RobotEvent = &robotEvent
+		}
+	} else if _, _, ok := robotbroker.ParseWarehouseSubject(msg.Subject); ok {
+		warehouseEvent := eventpublisher.WarehouseEvent{}
+		if envelope, err = s.eventDecoderService.Decode(contentType, msg.Data, &warehouseEvent); err == nil {
+			event.WarehouseEvent = &warehouseEvent
+		}
+	} else {
+		err = fmt.Errorf("no event is published on %s", msg.Subject)
+	}
+
+	if err != nil {
+		event.DecodeError = err.Error()
+
+		return event
+	}
+
+	event.Envelope = &envelope
+
+	return event
+}
+
+func (s *streamInspectorService) ensureReplayStream(target ReplayTarget) error {
+	subjects := target.SubjectPrefix + "." + robotbroker.SUBJECT_FULL_WILDCARD
+
+	streamInfo, err := s.jetStream.StreamInfo(target.Stream)
+	if errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = s.jetStream.AddStream(&nats.StreamConfig{
+			Name:      target.Stream,
+			Subjects:  []string{subjects},
+			Retention: nats.LimitsPolicy,
+			MaxAge:    DefaultReplayStreamMaxAge,
+			Storage:   nats.FileStorage,
+		})
+
+		return err
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, subject := range streamInfo.Config.Subjects {
+		if subject == subjects {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"stream %s does not capture %s, pick another stream or subject prefix",
+		target.Stream,
+		subjects)
+}
+
+func validateSubjectPrefix(prefix string) error {
+	tokens := strings.Split(prefix, ".")
+
+	for _, token := range tokens {
+		if !robotbroker.IsValidToken(token) {
+			return fmt.Errorf("invalid replay subject prefix %q", prefix)
+		}
+	}
+
+	for _, reserved := range reservedSubjectPrefixes {
+		if tokens[0] == reserved {
+			return fmt.Errorf("replay subject prefix %q is used by the project", prefix)
+		}
+	}
+
+	return nil
+}
+
+func validateRewrite(rewrite SubjectRewrite) error {
+	for _, subject := range []string{rewrite.From, rewrite.To} {
+		for _, token := range strings.Split(subject, ".") {
+			if !robotbroker.IsValidToken(token) {
+				return fmt.Errorf("invalid subject rewrite %s=%s", rewrite.From, rewrite.To)
+			}
+		}
+	}
+
+	return nil
+}
+
+// rewriteSubject applies the first rewrite whose tokens lead the subject
+func rewriteSubject(subject string, rewrites []SubjectRewrite) string {
+	for _, rewrite := range rewrites {
+		if subject == rewrite.From || strings.HasPrefix(subject, rewrite.From+".") {
+			return rewrite.To + subject[len(rewrite.From):]
+		}
+	}
+
+	return subject
+}
+
+func readRecords(reader io.Reader) ([]Record, error) {
+	records := make([]Record, 0)
+	decoder := json.NewDecoder(reader)
+
+	for {
+		record := Record{}
+
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid record %d: %w", len(records)+1, err)
+		}
+
+		if record.Subject == "" {
+			return nil, fmt.Errorf("invalid record %d: missing subject", len(records)+1)
+		}
+
+		records = append(records, record)
+	}
+}
+
+func importMsg(record Record, rewrites []SubjectRewrite) *nats.Msg {
+	msg := nats.NewMsg(rewriteSubject(record.Subject, rewrites))
+	msg.Data = record.Data
+
+	for key, values := range record.Header {
+		msg.Header[key] = values
+	}
+
+	// The stream is empty, but a message id still in the duplicate window would be dropped
+	msg.Header.Del(nats.MsgIdHdr)
+
+	// A message imported before keeps pointing at where it was first exported from
+	if msg.Header.Get(robotbroker.HEADER_IMPORT_STREAM_SEQUENCE) == "" {
+		msg.Header.Set(robotbroker.HEADER_IMPORT_TIME, record.Time.Format(time.RFC3339Nano))
+		msg.Header.Set(robotbroker.HEADER_IMPORT_STREAM_SEQUENCE, strconv.FormatUint(record.Sequence, 10))
+
+		if msg.Subject != record.Subject {
+			msg.Header.Set(robotbroker.HEADER_IMPORT_SUBJECT, record.Subject)
+		}
+	}
+
+	return msg
+}
+
+func captures(streamSubjects []string, subject string) bool {
+	for _, streamSubject := range streamSubjects {
+		if robotbroker.MatchSubject(streamSubject, subject) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// subject narrows down the messages delivered to a walk, the events of a task can be published
+// on the subject of its batch
+func (f Filter) subject() string {
+	switch {
+	case f.Subject != "":
+		return f.Subject
+	case f.RobotId != nil:
+		return robotbroker.RobotSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			robotbroker.FormatId(*f.RobotId),
+			robotbroker.SUBJECT_WILDCARD)
+	case f.TaskId != nil:
+		return robotbroker.TaskSubject(robotbroker.SUBJECT_WILDCARD, robotbroker.SUBJECT_WILDCARD)
+	}
+
+	return ""
+}
+
+// matches reports whether a message is selected by the filter, the sequences are checked
+// by the caller
+func (s *streamInspectorService) matches(f Filter, msg *nats.RawStreamMsg) bool {
+	if !f.Since.IsZero() && msg.Time.Before(f.Since) {
+		return false
+	}
+
+	if !f.Until.IsZero() && msg.Time.After(f.Until) {
+		return false
+	}
+
+	if f.Subject != "" && !robotbroker.MatchSubject(f.Subject, msg.Subject) {
+		return false
+	}
+
+	if f.TaskId != nil && !s.concernsTask(msg, *f.TaskId) {
+		return false
+	}
+
+	if f.RobotId != nil {
+		_, robotId, _, ok := robotbroker.ParseRobotSubject(msg.Subject)
+		if !ok || robotId != robotbroker.FormatId(*f.RobotId) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// concernsTask reports whether a message is an event of a task, or creates or cancels the batch
+// of the task
+func (s *streamInspectorService) concernsTask(msg *nats.RawStreamMsg, taskId int64) bool {
+	id, eventType, ok := robotbroker.ParseTaskSubject(msg.Subject)
+	if !ok {
+		return false
+	}
+
+	if id == robotbroker.FormatId(taskId) {
+		return true
+	}
+
+	if eventType != eventpublisher.TaskBatchCreated.SubjectToken() &&
+		eventType != eventpublisher.TaskBatchCancelled.SubjectToken() {
+		return false
+	}
+
+	event := s.decode(msg)
+	if event.TaskEvent == nil {
+		return false
+	}
+
+	for _, task := range event.TaskEvent.Data.Tasks {
+		if task.Id == taskId {
+			return true
+		}
+	}
+
+	return false
+}
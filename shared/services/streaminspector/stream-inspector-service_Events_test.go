@@ -0,0 +1,194 @@
+package streaminspector_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/embeddednats"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/shared/services/streaminspector"
+	"go.uber.org/zap"
+)
+
+// startBroker connects to an empty stream served by an embedded NATS server
+func startBroker(t *testing.T, g *WithT) (robotbroker.JetStreamBrokerInterface, *zap.SugaredLogger) {
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	embeddedNatsService, err := embeddednats.NewEmbeddedNatsService(sugarLogger, t.TempDir(), "127.0.0.1", -1)
+	g.Expect(err).Should(BeNil())
+	t.Cleanup(embeddedNatsService.Shutdown)
+
+	robotBrokerService, err := robotbroker.NewRobotBrokerService(
+		sugarLogger,
+		"test",
+		embeddedNatsService.ClientURL(),
+		robotbroker.ConnectionConfig{},
+		robotbroker.DefaultStreamConfig())
+	g.Expect(err).Should(BeNil())
+	t.Cleanup(robotBrokerService.Close)
+
+	return robotBrokerService, sugarLogger
+}
+
+// startStream publishes a few task and robot events to a stream served by an embedded NATS server
+func startStream(t *testing.T, g *WithT) (robotbroker.JetStreamBrokerInterface, *zap.SugaredLogger) {
+	robotBrokerService, sugarLogger := startBroker(t, g)
+
+	codec, err := eventcodec.NewCodec(eventcodec.MSGPACK)
+	g.Expect(err).Should(BeNil())
+
+	eventPublisherService, err := eventpublisher.NewEventPublisherService(sugarLogger, "test", codec, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(eventPublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCreated,
+		Id:        1,
+		Data: eventpublisher.TaskData{
+			RobotId:        2,
+			MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.NORTH},
+		},
+	})).Should(Succeed())
+
+	for robotId := int64(1); robotId <= 3; robotId++ {
+		g.Expect(eventPublisherService.PublishRobotEvent(eventpublisher.RobotEvent{
+			EventType: eventpublisher.RobotMoved,
+			Id:        robotId,
+			Data:      eventpublisher.RobotData{X: 1, Y: int(robotId)},
+		})).Should(Succeed())
+	}
+
+	g.Expect(eventPublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCompleted,
+		Id:        1,
+	})).Should(Succeed())
+
+	return robotBrokerService, sugarLogger
+}
+
+func Test_Events_Should_Return_Decoded_Events(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startStream(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	streamInfo, err := sut.Info()
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.State.Msgs).Should(Equal(uint64(5)))
+
+	events, err := sut.Events(streaminspector.Filter{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(events).Should(HaveLen(5))
+
+	g.Expect(events[0].Sequence).Should(Equal(uint64(1)))
+	g.Expect(events[0].DecodeError).Should(BeEmpty())
+	g.Expect(events[0].Envelope.Type).Should(Equal(eventpublisher.TaskEnvelopeType))
+	g.Expect(events[0].TaskEvent.Data.RobotId).Should(Equal(int64(2)))
+
+	g.Expect(events[2].RobotEvent.Id).Should(Equal(int64(2)))
+	g.Expect(events[2].RobotEvent.Data.Y).Should(Equal(2))
+}
+
+func Test_Events_Should_Filter_Events(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startStream(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	sequences := func(filter streaminspector.Filter) []uint64 {
+		events, err := sut.Events(filter)
+		g.Expect(err).Should(BeNil())
+
+		sequences := make([]uint64, 0)
+		for _, event := range events {
+			sequences = append(sequences, event.Sequence)
+		}
+
+		return sequences
+	}
+
+	robotId := int64(3)
+	taskId := int64(1)
+
+	g.Expect(sequences(streaminspector.Filter{FirstSequence: 2, LastSequence: 3})).Should(Equal([]uint64{2, 3}))
+	g.Expect(sequences(streaminspector.Filter{Subject: "task.*.completed"})).Should(Equal([]uint64{5}))
+	g.Expect(sequences(streaminspector.Filter{RobotId: &robotId})).Should(Equal([]uint64{4}))
+	g.Expect(sequences(streaminspector.Filter{TaskId: &taskId})).Should(Equal([]uint64{1, 5}))
+	g.Expect(sequences(streaminspector.Filter{Subject: "robot.>", Limit: 2})).Should(Equal([]uint64{2, 3}))
+	g.Expect(sequences(streaminspector.Filter{Since: time.Now().Add(time.Hour)})).Should(BeEmpty())
+	g.Expect(sequences(streaminspector.Filter{Until: time.Now().Add(-time.Hour)})).Should(BeEmpty())
+}
+
+func Test_Events_Should_Select_The_Batch_Of_A_Task(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startStream(t, g)
+
+	codec, err := eventcodec.NewCodec(eventcodec.MSGPACK)
+	g.Expect(err).Should(BeNil())
+
+	eventPublisherService, err := eventpublisher.NewEventPublisherService(sugarLogger, "test", codec, robotBrokerService)
+	g.Expect(err).Should(BeNil())
+
+	// The created event of a task of a batch is published on the subject of the batch
+	g.Expect(eventPublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskBatchCreated,
+		Id:        10,
+		Data: eventpublisher.TaskData{
+			Tasks: []eventpublisher.BatchTask{
+				{Id: 11, RobotId: 1, MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.EAST}},
+				{Id: 12, RobotId: 2, MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.WEST}},
+			},
+		},
+	})).Should(Succeed())
+
+	g.Expect(eventPublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCompleted,
+		Id:        12,
+	})).Should(Succeed())
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	taskId := int64(12)
+
+	events, err := sut.Events(streaminspector.Filter{TaskId: &taskId})
+	g.Expect(err).Should(BeNil())
+	g.Expect(events).Should(HaveLen(2))
+	g.Expect(events[0].Sequence).Should(Equal(uint64(6)))
+	g.Expect(events[1].Sequence).Should(Equal(uint64(7)))
+
+	// The consumer of the walk is gone once it is done
+	streamInfo, err := sut.Info()
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.State.Consumers).Should(BeZero())
+}
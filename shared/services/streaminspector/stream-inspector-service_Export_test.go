@@ -0,0 +1,54 @@
+package streaminspector_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/shared/services/streaminspector"
+)
+
+func Test_Export_Should_Write_Selected_Events_As_NDJSON(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startStream(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	events, err := sut.Events(streaminspector.Filter{})
+	g.Expect(err).Should(BeNil())
+
+	buf := &bytes.Buffer{}
+
+	exported, err := sut.Export(streaminspector.Filter{Subject: "robot.>"}, buf)
+	g.Expect(err).Should(BeNil())
+	g.Expect(exported).Should(Equal(3))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	g.Expect(lines).Should(HaveLen(3))
+
+	record := streaminspector.Record{}
+	g.Expect(json.Unmarshal([]byte(lines[0]), &record)).Should(Succeed())
+	g.Expect(record.Sequence).Should(Equal(uint64(2)))
+	g.Expect(record.Subject).Should(Equal("robot.default.1.moved"))
+	g.Expect(record.Time.Equal(events[1].Time)).Should(BeTrue())
+	g.Expect(record.Data).Should(Equal(events[1].Data))
+	g.Expect(record.Header.Get("Content-Type")).Should(Equal("application/msgpack"))
+
+	robotEvent := eventpublisher.RobotEvent{}
+	g.Expect(json.Unmarshal(record.Event, &robotEvent)).Should(Succeed())
+	g.Expect(robotEvent.Id).Should(Equal(int64(1)))
+	g.Expect(robotEvent.EventType).Should(Equal(eventpublisher.RobotMoved))
+}
@@ -0,0 +1,147 @@
+package streaminspector_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/shared/services/streaminspector"
+)
+
+func Test_Import_Should_Load_An_Export_Into_An_Empty_Stream(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startBroker(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	file, err := os.Open("testdata/events.ndjson")
+	g.Expect(err).Should(BeNil())
+	defer file.Close()
+
+	imported, err := sut.Import(file, streaminspector.ImportOptions{
+		Rewrites: []streaminspector.SubjectRewrite{{From: "robot.default", To: "robot.staging"}},
+	})
+	g.Expect(err).Should(BeNil())
+	g.Expect(imported).Should(Equal(5))
+
+	events, err := sut.Events(streaminspector.Filter{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(events).Should(HaveLen(5))
+
+	g.Expect(events[0].Subject).Should(Equal("task.1.created"))
+	g.Expect(events[0].TaskEvent.Data.RobotId).Should(Equal(int64(2)))
+	g.Expect(events[0].Header.Get(robotbroker.HEADER_IMPORT_SUBJECT)).Should(BeEmpty())
+
+	g.Expect(events[2].Subject).Should(Equal("robot.staging.2.moved"))
+	g.Expect(events[2].RobotEvent.Data.Y).Should(Equal(2))
+	g.Expect(events[2].Header.Get(robotbroker.HEADER_IMPORT_SUBJECT)).Should(Equal("robot.default.2.moved"))
+	g.Expect(events[2].Header.Get(robotbroker.HEADER_IMPORT_STREAM_SEQUENCE)).Should(Equal("3"))
+	g.Expect(events[2].Header.Get(robotbroker.HEADER_IMPORT_TIME)).Should(Equal("2026-10-19T10:42:07.275184575Z"))
+}
+
+func Test_Import_Should_Refuse_A_Stream_Which_Is_Not_Empty(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startStream(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	file, err := os.Open("testdata/events.ndjson")
+	g.Expect(err).Should(BeNil())
+	defer file.Close()
+
+	_, err = sut.Import(file, streaminspector.ImportOptions{})
+	g.Expect(err).ShouldNot(BeNil())
+}
+
+func Test_Import_Should_Import_Nothing_From_An_Invalid_File(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startBroker(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	// The second record lands on a subject the stream does not capture
+	ndjson := `{"sequence":1,"subject":"task.1.created","time":"2022-07-01T10:00:00Z","data":"e30="}
+{"sequence":2,"subject":"robot.default.1.moved","time":"2022-07-01T10:00:01Z","data":"e30="}
+`
+
+	_, err = sut.Import(strings.NewReader(ndjson), streaminspector.ImportOptions{
+		Rewrites: []streaminspector.SubjectRewrite{{From: "robot", To: "robots"}},
+	})
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = sut.Import(strings.NewReader("{"), streaminspector.ImportOptions{})
+	g.Expect(err).ShouldNot(BeNil())
+
+	streamInfo, err := sut.Info()
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.State.Msgs).Should(BeZero())
+}
+
+func Test_Import_Should_Refuse_A_Stream_Which_Has_Consumers_Unless_Forced(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startBroker(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	subscription, err := robotBrokerService.QueueSubscribe(
+		robotbroker.TaskSubject(robotbroker.SUBJECT_WILDCARD, "created"),
+		"consumer",
+		func(*robotbroker.Message) error {
+			return nil
+		})
+	g.Expect(err).Should(BeNil())
+	defer subscription.Unsubscribe()
+
+	ndjson := `{"sequence":1,"subject":"task.1.created","time":"2022-07-01T10:00:00Z","data":"e30="}
+`
+
+	_, err = sut.Import(strings.NewReader(ndjson), streaminspector.ImportOptions{})
+	g.Expect(err).Should(MatchError(ContainSubstring("consumers")))
+
+	streamInfo, err := sut.Info()
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.State.Msgs).Should(BeZero())
+
+	imported, err := sut.Import(strings.NewReader(ndjson), streaminspector.ImportOptions{Force: true})
+	g.Expect(err).Should(BeNil())
+	g.Expect(imported).Should(Equal(1))
+}
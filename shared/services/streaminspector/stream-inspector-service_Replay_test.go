@@ -0,0 +1,75 @@
+package streaminspector_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/shared/services/streaminspector"
+)
+
+func Test_Replay_Should_Republish_Selected_Events_To_Scratch_Stream(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startStream(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	replayed, err := sut.Replay(
+		streaminspector.Filter{Subject: "robot.>"},
+		streaminspector.ReplayTarget{})
+	g.Expect(err).Should(BeNil())
+	g.Expect(replayed).Should(Equal(3))
+
+	jetStream, err := robotBrokerService.CreateNewJetStream()
+	g.Expect(err).Should(BeNil())
+
+	streamInfo, err := jetStream.StreamInfo(streaminspector.DefaultReplayStream)
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.State.Msgs).Should(Equal(uint64(3)))
+
+	msg, err := jetStream.GetMsg(streaminspector.DefaultReplayStream, 1)
+	g.Expect(err).Should(BeNil())
+	g.Expect(msg.Subject).Should(Equal("replay.robot.default.1.moved"))
+	g.Expect(msg.Header.Get(robotbroker.HEADER_REPLAY_SUBJECT)).Should(Equal("robot.default.1.moved"))
+	g.Expect(msg.Header.Get(robotbroker.HEADER_REPLAY_STREAM_SEQUENCE)).Should(Equal("2"))
+
+	// The original stream is left untouched
+	streamInfo, err = jetStream.StreamInfo(robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+	g.Expect(streamInfo.State.Msgs).Should(Equal(uint64(5)))
+}
+
+func Test_Replay_Should_Refuse_Subjects_Of_The_Project(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	robotBrokerService, sugarLogger := startStream(t, g)
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := streaminspector.NewStreamInspectorService(
+		sugarLogger,
+		robotBrokerService,
+		eventDecoderService,
+		robotbroker.STREAM_ROBOT)
+	g.Expect(err).Should(BeNil())
+
+	_, err = sut.Replay(streaminspector.Filter{}, streaminspector.ReplayTarget{SubjectPrefix: "task"})
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = sut.Replay(streaminspector.Filter{}, streaminspector.ReplayTarget{SubjectPrefix: "debug.*"})
+	g.Expect(err).ShouldNot(BeNil())
+
+	_, err = sut.Replay(streaminspector.Filter{}, streaminspector.ReplayTarget{Stream: robotbroker.STREAM_ROBOT})
+	g.Expect(err).ShouldNot(BeNil())
+}
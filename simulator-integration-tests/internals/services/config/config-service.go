@@ -4,10 +4,30 @@ import (
 	"os"
 
 	"github.com/nats-io/nats.go"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 )
 
 const (
-	NATS_URL = "NATS_URL"
+	NATS_URL    = "NATS_URL"
+	EVENT_CODEC = "EVENT_CODEC"
+
+	NATS_TLS_CA    = "NATS_TLS_CA"
+	NATS_TLS_CERT  = "NATS_TLS_CERT"
+	NATS_TLS_KEY   = "NATS_TLS_KEY"
+	NATS_USER      = "NATS_USER"
+	NATS_PASSWORD  = "NATS_PASSWORD"
+	NATS_TOKEN     = "NATS_TOKEN"
+	NATS_NKEY_SEED = "NATS_NKEY_SEED"
+	NATS_CREDS     = "NATS_CREDS"
+
+	STREAM_NAME      = "STREAM_NAME"
+	STREAM_RETENTION = "STREAM_RETENTION"
+	STREAM_MAX_AGE   = "STREAM_MAX_AGE"
+	STREAM_MAX_BYTES = "STREAM_MAX_BYTES"
+	STREAM_MAX_MSGS  = "STREAM_MAX_MSGS"
+	STREAM_REPLICAS  = "STREAM_REPLICAS"
+	STREAM_STORAGE   = "STREAM_STORAGE"
 )
 
 type configService struct {
@@ -25,3 +45,38 @@ func (p *configService) GetNatsUrl() string {
 
 	return val
 }
+
+func (p *configService) GetEventCodec() string {
+	val := os.Getenv(EVENT_CODEC)
+	if val == "" {
+		return eventcodec.JSON
+	}
+
+	return val
+}
+
+// GetConnectionConfig returns the settings needed to connect to a secured NATS server
+func (p *configService) GetConnectionConfig() robotbroker.ConnectionConfig {
+	return robotbroker.ConnectionConfig{
+		TLSCAFile:    os.Getenv(NATS_TLS_CA),
+		TLSCertFile:  os.Getenv(NATS_TLS_CERT),
+		TLSKeyFile:   os.Getenv(NATS_TLS_KEY),
+		User:         os.Getenv(NATS_USER),
+		Password:     os.Getenv(NATS_PASSWORD),
+		Token:        os.Getenv(NATS_TOKEN),
+		NKeySeedFile: os.Getenv(NATS_NKEY_SEED),
+		CredsFile:    os.Getenv(NATS_CREDS),
+	}
+}
+
+// GetStreamConfig returns the settings of the stream events are kept in
+func (p *configService) GetStreamConfig() (robotbroker.StreamConfig, error) {
+	return robotbroker.NewStreamConfig(
+		os.Getenv(STREAM_NAME),
+		os.Getenv(STREAM_RETENTION),
+		os.Getenv(STREAM_MAX_AGE),
+		os.Getenv(STREAM_MAX_BYTES),
+		os.Getenv(STREAM_MAX_MSGS),
+		os.Getenv(STREAM_REPLICAS),
+		os.Getenv(STREAM_STORAGE))
+}
@@ -1,5 +1,10 @@
 package config
 
+import "github.com/sepisoad/robot-challange/shared/services/robotbroker"
+
 type ConfigInterface interface {
 	GetNatsUrl() string
+	GetEventCodec() string
+	GetConnectionConfig() robotbroker.ConnectionConfig
+	GetStreamConfig() (robotbroker.StreamConfig, error)
 }
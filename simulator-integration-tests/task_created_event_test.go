@@ -1,20 +1,20 @@
 package simulator_integration_tests
 
 import (
-	"encoding/json"
 	"testing"
 
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	"github.com/sepisoad/robot-challange/simulator-integration-tests/internals/services/config"
-	"github.com/nats-io/nats.go"
 	. "github.com/onsi/gomega"
 	"go.uber.org/zap"
 )
 
 var internalChannel = make(chan eventpublisher.RobotEvent)
-var taskId = 1
+var taskId = int64(1)
 var robotId = int64(0)
+var eventDecoderService eventpublisher.EventDecoderInterface
 
 func Test_Should_Process_Task_Created_Event(t *testing.T) {
 	g := NewGomegaWithT(t)
@@ -27,26 +27,37 @@ func Test_Should_Process_Task_Created_Event(t *testing.T) {
 	configService, err := config.NewConfigService()
 	g.Expect(err).Should(BeNil())
 
+	streamConfig, err := configService.GetStreamConfig()
+	g.Expect(err).Should(BeNil())
+
 	robotBrokerService, err := robotbroker.NewRobotBrokerService(
 		sugarLogger,
 		"simulator-integration-tests",
-		configService.GetNatsUrl())
+		configService.GetNatsUrl(),
+		configService.GetConnectionConfig(),
+		streamConfig)
 	g.Expect(err).Should(BeNil())
 
 	defer robotBrokerService.Close()
 
+	eventCodec, err := eventcodec.NewCodec(configService.GetEventCodec())
+	g.Expect(err).Should(BeNil())
+
 	eventpublisherService, err := eventpublisher.NewEventPublisherService(
 		sugarLogger,
+		"simulator-integration-tests",
+		eventCodec,
 		robotBrokerService)
 	g.Expect(err).Should(BeNil())
 
-	var jetStream nats.JetStreamContext
-
-	jetStream, err = robotBrokerService.CreateNewJetStream()
+	eventDecoderService, err = eventpublisher.NewEventDecoderService()
 	g.Expect(err).Should(BeNil())
 
-	subscriber, err := jetStream.QueueSubscribe(
-		robotbroker.SUBJECT_ROBOT,
+	subscriber, err := robotBrokerService.QueueSubscribe(
+		robotbroker.RobotSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			robotbroker.SUBJECT_WILDCARD,
+			robotbroker.SUBJECT_WILDCARD),
 		"simulator-integration-tests-"+robotbroker.SUBJECT_ROBOT,
 		handleRobotEventRasied)
 	g.Expect(err).Should(BeNil())
@@ -82,19 +93,24 @@ func Test_Should_Process_Task_Created_Event(t *testing.T) {
 	g.Expect(event.Data.Y).Should(Equal(1))
 }
 
-func handleRobotEventRasied(msg *nats.Msg) {
+func handleRobotEventRasied(msg *robotbroker.Message) error {
 	event := eventpublisher.RobotEvent{}
-	if err := json.Unmarshal(msg.Data, &event); err != nil {
-		return
+	if _, err := eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
+		return robotbroker.Permanent(err)
 	}
 
 	if event.EventType != eventpublisher.RobotMoved {
-		return
+		return nil
 	}
 
 	if event.Id != robotId {
-		return
+		return nil
 	}
 
 	internalChannel <- event
+
+	return nil
 }
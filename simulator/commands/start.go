@@ -2,22 +2,28 @@ package commands
 
 import (
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/bwmarrin/snowflake"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
-	"github.com/sepisoad/robot-challange/shared/services/idgenerator"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	"github.com/sepisoad/robot-challange/simulator/internals/services/config"
-	"github.com/sepisoad/robot-challange/simulator/processors"
+	"github.com/sepisoad/robot-challange/simulator/simulation"
 	"github.com/sepisoad/robot-challange/simulator/warehouse"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
 type startOptions struct {
-	totalRobotNumber int
-	boardHeight      int
-	boardWidth       int
+	warehouse           string
+	totalRobotNumber    int
+	boardHeight         int
+	boardWidth          int
+	layoutPath          string
+	asyncPublish        bool
+	maxPendingPublishes int
+	coalesceRobotMoves  bool
 }
 
 func startCommand() *cobra.Command {
@@ -35,95 +41,103 @@ func startCommand() *cobra.Command {
 
 			sugarLogger := logger.Sugar()
 
+			if !robotbroker.IsValidToken(opt.warehouse) {
+				sugarLogger.Fatalf("Invalid warehouse name %q", opt.warehouse)
+			}
+
+			layout := warehouse.Layout{
+				Width:  opt.boardWidth,
+				Height: opt.boardHeight,
+			}
+
+			if opt.layoutPath != "" {
+				if layout, err = warehouse.LoadLayout(opt.layoutPath); err != nil {
+					sugarLogger.Fatal(err)
+				}
+			}
+
 			configService, err := config.NewConfigService()
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
 
+			streamConfig, err := configService.GetStreamConfig()
+			if err != nil {
+				sugarLogger.Fatal(err)
+			}
+
 			robotBrokerService, err := robotbroker.NewRobotBrokerService(
 				sugarLogger,
 				"api",
-				configService.GetNatsUrl())
+				configService.GetNatsUrl(),
+				configService.GetConnectionConfig(),
+				streamConfig)
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
 
 			defer robotBrokerService.Close()
 
-			eventpublisherService, err := eventpublisher.NewEventPublisherService(
+			robotSimulation, err := simulation.Start(
 				sugarLogger,
+				simulation.Options{
+					Warehouse:           opt.warehouse,
+					TotalRobotNumber:    opt.totalRobotNumber,
+					BoardHeight:         layout.Height,
+					BoardWidth:          layout.Width,
+					Obstacles:           layout.Obstacles,
+					SpecialCells:        layout.SpecialCells,
+					EventCodec:          configService.GetEventCodec(),
+					AsyncPublish:        opt.asyncPublish,
+					MaxPendingPublishes: opt.maxPendingPublishes,
+					CoalesceRobotMoves:  opt.coalesceRobotMoves,
+				},
 				robotBrokerService)
 			if err != nil {
 				sugarLogger.Fatal(err)
 			}
 
-			snowflakeNode, err := snowflake.NewNode(1)
-			if err != nil {
-				sugarLogger.Fatal(err)
-			}
+			defer robotSimulation.Stop()
 
-			idGeneratorService, err := idgenerator.NewIdGeneratorService(snowflakeNode)
-			if err != nil {
-				sugarLogger.Fatal(err)
-			}
+			// Stop gracefully so that the pending events are flushed, SIGHUP reloads the layout
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
-			coordinates := getRandomPositions(opt.totalRobotNumber)
-
-			robots := make(map[int64]warehouse.RobotInterface)
-			// for idx := 0; idx < opt.totalRobotNumber; idx++ {
-			for idx, coord := range coordinates {
-				robot, err := warehouse.NewRobot(
-					sugarLogger,
-					int64(idx),
-					coord.x,
-					coord.y,
-					opt.boardHeight,
-					opt.boardWidth,
-					eventpublisherService,
-					idGeneratorService)
-				if err != nil {
-					sugarLogger.Fatal(err)
+			for sig := range signals {
+				if sig != syscall.SIGHUP {
+					break
 				}
 
-				robots[int64(idx)] = robot
-			}
+				if opt.layoutPath == "" {
+					sugarLogger.Warn("Ignoring SIGHUP, the simulator was not started with --layout")
 
-			robotProcessor, err := processors.StartTaskProcessor(
-				sugarLogger,
-				robotBrokerService,
-				robots,
-				eventpublisherService)
-			if err != nil {
-				sugarLogger.Fatal(err)
-			}
+					continue
+				}
 
-			defer robotProcessor.Stop()
+				layout, err := warehouse.LoadLayout(opt.layoutPath)
+				if err == nil {
+					err = robotSimulation.Reconfigure(layout)
+				}
 
-			ch := make(chan struct{})
+				if err != nil {
+					sugarLogger.Errorf("Failed to reload layout %s. Error: %v", opt.layoutPath, err)
 
-			<-ch
+					continue
+				}
+
+				sugarLogger.Infof("Reloaded layout %s", opt.layoutPath)
+			}
 		},
 	}
 
+	cmd.Flags().StringVar(&opt.warehouse, "warehouse", robotbroker.DEFAULT_WAREHOUSE, "Specify the warehouse the simulated robots belong to")
 	cmd.Flags().IntVar(&opt.totalRobotNumber, "total-robot-number", 5, "Specify the total number of robots to start simualtion with")
 	cmd.Flags().IntVar(&opt.boardHeight, "board-height", 10, "Specify the board height")
 	cmd.Flags().IntVar(&opt.boardWidth, "board-width", 10, "Specify the board width")
+	cmd.Flags().StringVar(&opt.layoutPath, "layout", "", "Specify a JSON file with the size, obstacles and special cells of the board, it overrides --board-height and --board-width and is reloaded on SIGHUP")
+	cmd.Flags().BoolVar(&opt.asyncPublish, "async-publish", false, "Publish the events without waiting for each of them to be persisted")
+	cmd.Flags().IntVar(&opt.maxPendingPublishes, "max-pending-publishes", eventpublisher.DefaultMaxPendingPublishes, "Specify the number of events waiting to be persisted before publishing blocks")
+	cmd.Flags().BoolVar(&opt.coalesceRobotMoves, "coalesce-robot-moves", false, "Only publish the latest position of a robot when NATS falls behind, requires --async-publish")
 
 	return cmd
 }
-
-type coordinate struct {
-	x int
-	y int
-}
-
-func getRandomPositions(max int) []coordinate {
-	list := make([]coordinate, max)
-
-	for i := 0; i < max; i++ {
-		list[i].x = i
-		list[i].y = 0
-	}
-
-	return list
-}
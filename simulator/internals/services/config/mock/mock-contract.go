@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	robotbroker "github.com/sepisoad/robot-challange/shared/services/robotbroker"
 )
 
 // MockConfigInterface is a mock of ConfigInterface interface.
@@ -33,6 +34,34 @@ func (m *MockConfigInterface) EXPECT() *MockConfigInterfaceMockRecorder {
 	return m.recorder
 }
 
+// GetConnectionConfig mocks base method.
+func (m *MockConfigInterface) GetConnectionConfig() robotbroker.ConnectionConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConnectionConfig")
+	ret0, _ := ret[0].(robotbroker.ConnectionConfig)
+	return ret0
+}
+
+// GetConnectionConfig indicates an expected call of GetConnectionConfig.
+func (mr *MockConfigInterfaceMockRecorder) GetConnectionConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConnectionConfig", reflect.TypeOf((*MockConfigInterface)(nil).GetConnectionConfig))
+}
+
+// GetEventCodec mocks base method.
+func (m *MockConfigInterface) GetEventCodec() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventCodec")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetEventCodec indicates an expected call of GetEventCodec.
+func (mr *MockConfigInterfaceMockRecorder) GetEventCodec() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventCodec", reflect.TypeOf((*MockConfigInterface)(nil).GetEventCodec))
+}
+
 // GetNatsUrl mocks base method.
 func (m *MockConfigInterface) GetNatsUrl() string {
 	m.ctrl.T.Helper()
@@ -46,3 +75,18 @@ func (mr *MockConfigInterfaceMockRecorder) GetNatsUrl() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNatsUrl", reflect.TypeOf((*MockConfigInterface)(nil).GetNatsUrl))
 }
+
+// GetStreamConfig mocks base method.
+func (m *MockConfigInterface) GetStreamConfig() (robotbroker.StreamConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStreamConfig")
+	ret0, _ := ret[0].(robotbroker.StreamConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStreamConfig indicates an expected call of GetStreamConfig.
+func (mr *MockConfigInterfaceMockRecorder) GetStreamConfig() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStreamConfig", reflect.TypeOf((*MockConfigInterface)(nil).GetStreamConfig))
+}
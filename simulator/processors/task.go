@@ -1,44 +1,49 @@
 package processors
 
 import (
-	"encoding/json"
+	"fmt"
 	"sync"
 
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	"github.com/sepisoad/robot-challange/simulator/warehouse"
-	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 )
 
 type taskMapping struct {
-	receivedTaskId int
+	receivedTaskId int64
 	robotTaskId    int64
 }
 
 type taskProcessor struct {
-	logger                  *zap.SugaredLogger
-	taskCreatedSubscriber   *nats.Subscription
-	taskCancelledSubscriber *nats.Subscription
-	robots                  map[int64]warehouse.RobotInterface
-	eventpublisherService   eventpublisher.EventPublisherInterface
-	taskIdMappings          map[int64][]taskMapping
-	taskIdMappingsMutex     *sync.Mutex
+	logger                   *zap.SugaredLogger
+	warehouse                string
+	taskCreatedSubscriber    robotbroker.SubscriptionInterface
+	taskRequestSubscriber    robotbroker.SubscriptionInterface
+	batchCreatedSubscriber   robotbroker.SubscriptionInterface
+	batchRequestSubscriber   robotbroker.SubscriptionInterface
+	taskCancelledSubscriber  robotbroker.SubscriptionInterface
+	batchCancelledSubscriber robotbroker.SubscriptionInterface
+	robots                   map[int64]warehouse.RobotInterface
+	eventDecoderService      eventpublisher.EventDecoderInterface
+	eventpublisherService    eventpublisher.EventPublisherInterface
+	taskIdMappings           map[int64][]taskMapping
+	taskIdMappingsMutex      *sync.Mutex
+	// batchMutex is held while the tasks of a batch are enqueued, every robot then sees the
+	// batches in the same order and none of them waits for a batch queued behind another one
+	batchMutex *sync.Mutex
 }
 
 func StartTaskProcessor(
 	logger *zap.SugaredLogger,
+	warehouse string,
 	robotBrokerService robotbroker.RobotBrokerInterface,
+	eventDecoderService eventpublisher.EventDecoderInterface,
 	robots map[int64]warehouse.RobotInterface,
 	eventpublisherService eventpublisher.EventPublisherInterface) (
 	processor *taskProcessor,
 	err error) {
-	var jetStream nats.JetStreamContext
-
-	if jetStream, err = robotBrokerService.CreateNewJetStream(); err != nil {
-		return
-	}
-
 	taskIdMappings := make(map[int64][]taskMapping)
 	// for id := range robots {
 	// 	taskIdMappings[id] = make([]taskMapping, 0)
@@ -46,30 +51,81 @@ func StartTaskProcessor(
 
 	processor = &taskProcessor{
 		logger:                logger,
+		warehouse:             warehouse,
 		robots:                robots,
+		eventDecoderService:   eventDecoderService,
 		eventpublisherService: eventpublisherService,
 		taskIdMappings:        taskIdMappings,
 		taskIdMappingsMutex:   &sync.Mutex{},
+		batchMutex:            &sync.Mutex{},
 	}
 
-	if processor.taskCreatedSubscriber, err = jetStream.QueueSubscribe(
-		robotbroker.SUBJECT_TASK,
-		"simulator-creation-"+robotbroker.SUBJECT_TASK,
+	if processor.taskCreatedSubscriber, err = robotBrokerService.QueueSubscribe(
+		robotbroker.TaskSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			eventpublisher.TaskCreated.SubjectToken()),
+		"simulator-task-"+eventpublisher.TaskCreated.SubjectToken(),
 		processor.handleTaskCreatedEventRasied); err != nil {
 		processor.Stop()
 
 		return
 	}
 
-	if processor.taskCreatedSubscriber, err = jetStream.QueueSubscribe(
-		robotbroker.SUBJECT_TASK,
-		"simulator-cancellation-"+robotbroker.SUBJECT_TASK,
+	if processor.taskRequestSubscriber, err = robotBrokerService.QueueRespond(
+		robotbroker.RequestSubject(robotbroker.TaskSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			eventpublisher.TaskCreated.SubjectToken())),
+		"simulator-task-request",
+		processor.handleTaskRequested); err != nil {
+		processor.Stop()
+
+		return
+	}
+
+	if processor.batchCreatedSubscriber, err = robotBrokerService.QueueSubscribe(
+		robotbroker.TaskSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			eventpublisher.TaskBatchCreated.SubjectToken()),
+		"simulator-task-"+eventpublisher.TaskBatchCreated.SubjectToken(),
+		processor.handleTaskBatchCreatedEventRasied); err != nil {
+		processor.Stop()
+
+		return
+	}
+
+	if processor.batchRequestSubscriber, err = robotBrokerService.QueueRespond(
+		robotbroker.RequestSubject(robotbroker.TaskSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			eventpublisher.TaskBatchCreated.SubjectToken())),
+		"simulator-task-batch-request",
+		processor.handleTaskBatchRequested); err != nil {
+		processor.Stop()
+
+		return
+	}
+
+	if processor.taskCancelledSubscriber, err = robotBrokerService.QueueSubscribe(
+		robotbroker.TaskSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			eventpublisher.TaskCancelled.SubjectToken()),
+		"simulator-task-"+eventpublisher.TaskCancelled.SubjectToken(),
 		processor.handleTaskCancelledEventRasied); err != nil {
 		processor.Stop()
 
 		return
 	}
 
+	if processor.batchCancelledSubscriber, err = robotBrokerService.QueueSubscribe(
+		robotbroker.TaskSubject(
+			robotbroker.SUBJECT_WILDCARD,
+			eventpublisher.TaskBatchCancelled.SubjectToken()),
+		"simulator-task-"+eventpublisher.TaskBatchCancelled.SubjectToken(),
+		processor.handleTaskBatchCancelledEventRasied); err != nil {
+		processor.Stop()
+
+		return
+	}
+
 	return processor, nil
 }
 
@@ -79,26 +135,54 @@ func (s *taskProcessor) Stop() {
 		s.taskCreatedSubscriber = nil
 	}
 
+	if s.taskRequestSubscriber != nil {
+		_ = s.taskRequestSubscriber.Unsubscribe()
+		s.taskRequestSubscriber = nil
+	}
+
+	if s.batchCreatedSubscriber != nil {
+		_ = s.batchCreatedSubscriber.Unsubscribe()
+		s.batchCreatedSubscriber = nil
+	}
+
+	if s.batchRequestSubscriber != nil {
+		_ = s.batchRequestSubscriber.Unsubscribe()
+		s.batchRequestSubscriber = nil
+	}
+
 	if s.taskCancelledSubscriber != nil {
 		_ = s.taskCancelledSubscriber.Unsubscribe()
 		s.taskCancelledSubscriber = nil
 	}
+
+	if s.batchCancelledSubscriber != nil {
+		_ = s.batchCancelledSubscriber.Unsubscribe()
+		s.batchCancelledSubscriber = nil
+	}
 }
 
-func (s *taskProcessor) handleTaskCreatedEventRasied(msg *nats.Msg) {
+func (s *taskProcessor) handleTaskCreatedEventRasied(msg *robotbroker.Message) error {
 	s.logEnter(msg)
 
 	event := eventpublisher.TaskEvent{}
-	if err := json.Unmarshal(msg.Data, &event); err != nil {
+	if _, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
 		s.logger.Errorf(
 			"Failed to de-serialize TaskEvent message. Error: %v",
 			err)
 
-		return
+		return robotbroker.Permanent(err)
 	}
 
 	if event.EventType != eventpublisher.TaskCreated {
-		return
+		return nil
+	}
+
+	// The task was enqueued already by the simulator which accepted it
+	if event.Data.Accepted {
+		return nil
 	}
 
 	robot, found := s.robots[event.Data.RobotId]
@@ -107,30 +191,80 @@ func (s *taskProcessor) handleTaskCreatedEventRasied(msg *nats.Msg) {
 			"Robot with Id %d not found",
 			event.Data.RobotId)
 
-		return
+		return robotbroker.Permanent(
+			fmt.Errorf("robot with Id %d not found", event.Data.RobotId))
 	}
 
+	s.execute(event, robot)
+
+	return nil
+}
+
+// execute enqueues a task on its robot and publishes the events of the task as it goes
+func (s *taskProcessor) execute(event eventpublisher.TaskEvent, robot warehouse.RobotInterface) {
+	taskId, positionChannel, errorChannel := robot.EnqueueTask(commands(event.Data.MoveSequeneces))
+
+	s.track(event.Id, event.Data.RobotId, robot, taskId, positionChannel, errorChannel)
+}
+
+// executeBatch enqueues the tasks of a batch on their robots, the robots start them together
+// once each of them is done with the tasks queued before
+func (s *taskProcessor) executeBatch(event eventpublisher.TaskEvent) {
+	batch := &sync.WaitGroup{}
+	batch.Add(len(event.Data.Tasks))
+
+	s.batchMutex.Lock()
+	defer s.batchMutex.Unlock()
+
+	for _, task := range event.Data.Tasks {
+		robot := s.robots[task.RobotId]
+
+		taskId, positionChannel, errorChannel := robot.EnqueueBatchTask(
+			commands(task.MoveSequeneces),
+			batch)
+
+		s.track(task.Id, task.RobotId, robot, taskId, positionChannel, errorChannel)
+	}
+}
+
+// commands joins the moves of a task the way a robot reads them
+func commands(moveSequences []eventpublisher.MoveRobotRequestMoveSequence) string {
 	commands := ""
-	for _, moveSequenece := range event.Data.MoveSequeneces {
+	for _, moveSequenece := range moveSequences {
 		commands = commands + " " + string(moveSequenece)
 	}
 
-	taskId, positionChannel, errorChannel := robot.EnqueueTask(commands)
+	return commands
+}
 
+// track maps a received task to the task enqueued on its robot and publishes the events of the
+// task as the robot executes it
+func (s *taskProcessor) track(
+	receivedTaskId int64,
+	robotId int64,
+	robot warehouse.RobotInterface,
+	taskId int64,
+	positionChannel chan warehouse.RobotState,
+	errorChannel chan error) {
 	s.taskIdMappingsMutex.Lock()
-	s.taskIdMappings[taskId] = append(
-		s.taskIdMappings[taskId],
+	s.taskIdMappings[robotId] = append(
+		s.taskIdMappings[robotId],
 		taskMapping{
-			receivedTaskId: event.Id,
+			receivedTaskId: receivedTaskId,
 			robotTaskId:    taskId,
 		})
 	s.taskIdMappingsMutex.Unlock()
 
 	go func(
-		taskId int64,
+		receivedTaskId int64,
 		robotId int64,
+		robot warehouse.RobotInterface,
 		positionChannel chan warehouse.RobotState,
 		errorChannel chan error) {
+		started := false
+		trajectory := make([]eventpublisher.RobotData, 0)
+		failureReason := ""
+
 		for {
 			positionChannelClosed := false
 			errorChannelClosed := false
@@ -143,12 +277,21 @@ func (s *taskProcessor) handleTaskCreatedEventRasied(msg *nats.Msg) {
 					break
 				}
 
+				started = s.publishTaskStarted(started, receivedTaskId, robotId)
+				trajectory = append(trajectory, eventpublisher.RobotData{
+					X:        robotState.X,
+					Y:        robotState.Y,
+					HasCrate: robotState.HasCrate,
+				})
+
 				_ = s.eventpublisherService.PublishRobotEvent(eventpublisher.RobotEvent{
 					EventType: eventpublisher.RobotMoved,
 					Id:        robotId,
+					Warehouse: s.warehouse,
 					Data: eventpublisher.RobotData{
-						X: robotState.X,
-						Y: robotState.Y,
+						X:        robotState.X,
+						Y:        robotState.Y,
+						HasCrate: robotState.HasCrate,
 					},
 				})
 
@@ -159,70 +302,329 @@ func (s *taskProcessor) handleTaskCreatedEventRasied(msg *nats.Msg) {
 					break
 				}
 
+				started = s.publishTaskStarted(started, receivedTaskId, robotId)
+				if failureReason == "" {
+					failureReason = err.Error()
+				}
+
 				_ = s.eventpublisherService.PublishRobotEvent(eventpublisher.RobotEvent{
 					EventType:    eventpublisher.RobotFailedToMove,
 					Id:           robotId,
+					Warehouse:    s.warehouse,
 					ErrorMessage: err.Error(),
 				})
 			}
 
 			_ = errorChannelClosed
 			if positionChannelClosed {
-				s.eventpublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
-					EventType: eventpublisher.TaskCompleted,
-					Id:        int(taskId),
+				// The robot stayed where it was when none of its moves succeeded
+				if len(trajectory) == 0 {
+					robotState := robot.CurrentState()
+					trajectory = append(trajectory, eventpublisher.RobotData{
+						X:        robotState.X,
+						Y:        robotState.Y,
+						HasCrate: robotState.HasCrate,
+					})
+				}
+
+				eventType := eventpublisher.TaskCompleted
+				if failureReason != "" {
+					eventType = eventpublisher.TaskFailed
+				}
+
+				_ = s.eventpublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+					EventType: eventType,
+					Id:        receivedTaskId,
+					Data: eventpublisher.TaskData{
+						RobotId:       robotId,
+						Trajectory:    trajectory,
+						FailureReason: failureReason,
+					},
 				})
+
+				s.forget(robotId, receivedTaskId)
 				break
 			}
 		}
-	}(taskId, event.Data.RobotId, positionChannel, errorChannel)
+	}(receivedTaskId, robotId, robot, positionChannel, errorChannel)
 }
 
-func (s *taskProcessor) handleTaskCancelledEventRasied(msg *nats.Msg) {
+// forget removes the mapping of a task once its robot is done with it, it can not be cancelled
+// anymore
+func (s *taskProcessor) forget(robotId int64, receivedTaskId int64) {
+	s.taskIdMappingsMutex.Lock()
+	defer s.taskIdMappingsMutex.Unlock()
+
+	taskMappings := s.taskIdMappings[robotId]
+
+	for idx, taskMapping := range taskMappings {
+		if taskMapping.receivedTaskId == receivedTaskId {
+			s.taskIdMappings[robotId] = append(taskMappings[:idx:idx], taskMappings[idx+1:]...)
+
+			break
+		}
+	}
+
+	if len(s.taskIdMappings[robotId]) == 0 {
+		delete(s.taskIdMappings, robotId)
+	}
+}
+
+// publishTaskStarted publishes a Started event unless the task has already started, it returns
+// whether the task has started
+func (s *taskProcessor) publishTaskStarted(started bool, receivedTaskId int64, robotId int64) bool {
+	if started {
+		return true
+	}
+
+	_ = s.eventpublisherService.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskStarted,
+		Id:        receivedTaskId,
+		Data: eventpublisher.TaskData{
+			RobotId: robotId,
+		},
+	})
+
+	return true
+}
+
+// handleTaskRequested tells the api whether a task can be executed, accepted tasks are enqueued
+// straight away so they hold their place in the queue of the robot. The api marks the created
+// event which follows as accepted, every simulator then skips it
+func (s *taskProcessor) handleTaskRequested(msg *robotbroker.Message) *robotbroker.Message {
 	s.logEnter(msg)
 
+	event, acceptance := s.accept(msg)
+	if acceptance.Accepted {
+		s.execute(event, s.robots[event.Data.RobotId])
+	} else {
+		s.logger.Warnf(
+			"Rejected task request from %s. Reason: %s",
+			msg.Subject,
+			acceptance.Reason)
+	}
+
+	reply, err := eventpublisher.NewTaskAcceptanceReply(msg.Subject, acceptance)
+	if err != nil {
+		s.logger.Errorf("Failed to serialize TaskAcceptance reply. Error: %v", err)
+	}
+
+	return reply
+}
+
+// accept decodes a requested task and tells whether it can be executed
+func (s *taskProcessor) accept(msg *robotbroker.Message) (eventpublisher.TaskEvent, eventpublisher.TaskAcceptance) {
 	event := eventpublisher.TaskEvent{}
-	if err := json.Unmarshal(msg.Data, &event); err != nil {
+	if _, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
+		return event, eventpublisher.TaskAcceptance{
+			Reason: fmt.Sprintf("task can not be de-serialized: %v", err),
+		}
+	}
+
+	if event.EventType != eventpublisher.TaskCreated {
+		return event, eventpublisher.TaskAcceptance{
+			Reason: fmt.Sprintf("unexpected event type %s", event.EventType),
+		}
+	}
+
+	if _, found := s.robots[event.Data.RobotId]; !found {
+		return event, eventpublisher.TaskAcceptance{
+			Reason: fmt.Sprintf("robot with Id %d not found", event.Data.RobotId),
+		}
+	}
+
+	if len(event.Data.MoveSequeneces) == 0 {
+		return event, eventpublisher.TaskAcceptance{
+			Reason: "task has no move sequence",
+		}
+	}
+
+	return event, eventpublisher.TaskAcceptance{Accepted: true}
+}
+
+func (s *taskProcessor) handleTaskBatchCreatedEventRasied(msg *robotbroker.Message) error {
+	s.logEnter(msg)
+
+	event := eventpublisher.TaskEvent{}
+	if _, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
 		s.logger.Errorf(
 			"Failed to de-serialize TaskEvent message. Error: %v",
 			err)
 
-		return
+		return robotbroker.Permanent(err)
+	}
+
+	if event.EventType != eventpublisher.TaskBatchCreated {
+		return nil
+	}
+
+	// The batch was enqueued already by the simulator which accepted it
+	if event.Data.Accepted {
+		return nil
+	}
+
+	// None of the tasks is executed unless every one of them can be
+	if reason := s.checkBatch(event); reason != "" {
+		s.logger.Errorf(
+			"Batch %s can not be executed. Reason: %s",
+			event.Data.BatchId,
+			reason)
+
+		return robotbroker.Permanent(
+			fmt.Errorf("batch %s can not be executed: %s", event.Data.BatchId, reason))
+	}
+
+	s.executeBatch(event)
+
+	return nil
+}
+
+// handleTaskBatchRequested tells the api whether every task of a batch can be executed, an
+// accepted batch is enqueued straight away, see handleTaskRequested
+func (s *taskProcessor) handleTaskBatchRequested(msg *robotbroker.Message) *robotbroker.Message {
+	s.logEnter(msg)
+
+	event := eventpublisher.TaskEvent{}
+	acceptance := eventpublisher.TaskAcceptance{}
+
+	if _, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
+		acceptance.Reason = fmt.Sprintf("batch can not be de-serialized: %v", err)
+	} else if event.EventType != eventpublisher.TaskBatchCreated {
+		acceptance.Reason = fmt.Sprintf("unexpected event type %s", event.EventType)
+	} else if acceptance.Reason = s.checkBatch(event); acceptance.Reason == "" {
+		acceptance.Accepted = true
+	}
+
+	if acceptance.Accepted {
+		s.executeBatch(event)
+	} else {
+		s.logger.Warnf(
+			"Rejected batch request from %s. Reason: %s",
+			msg.Subject,
+			acceptance.Reason)
+	}
+
+	reply, err := eventpublisher.NewTaskAcceptanceReply(msg.Subject, acceptance)
+	if err != nil {
+		s.logger.Errorf("Failed to serialize TaskAcceptance reply. Error: %v", err)
+	}
+
+	return reply
+}
+
+// checkBatch tells why the tasks of a batch can not be executed, it returns an empty string
+// when every one of them can
+func (s *taskProcessor) checkBatch(event eventpublisher.TaskEvent) string {
+	if len(event.Data.Tasks) == 0 {
+		return "batch has no task"
+	}
+
+	robotIds := make(map[int64]bool)
+
+	for _, task := range event.Data.Tasks {
+		if _, found := s.robots[task.RobotId]; !found {
+			return fmt.Sprintf("robot with Id %d not found", task.RobotId)
+		}
+
+		// A robot waiting for itself would never start
+		if robotIds[task.RobotId] {
+			return fmt.Sprintf("robot with Id %d is given more than one task", task.RobotId)
+		}
+
+		robotIds[task.RobotId] = true
+
+		if len(task.MoveSequeneces) == 0 {
+			return fmt.Sprintf("task %d has no move sequence", task.Id)
+		}
+	}
+
+	return ""
+}
+
+func (s *taskProcessor) handleTaskCancelledEventRasied(msg *robotbroker.Message) error {
+	s.logEnter(msg)
+
+	event := eventpublisher.TaskEvent{}
+	if _, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
+		s.logger.Errorf(
+			"Failed to de-serialize TaskEvent message. Error: %v",
+			err)
+
+		return robotbroker.Permanent(err)
 	}
 
 	if event.EventType != eventpublisher.TaskCancelled {
-		return
+		return nil
 	}
 
 	s.taskIdMappingsMutex.Lock()
+	defer s.taskIdMappingsMutex.Unlock()
 
-	for robotId, robot := range s.robots {
-		taskMappings := s.taskIdMappings[robotId]
+	s.cancel(event.Id)
 
-		for _, taskMapping := range taskMappings {
-			if taskMapping.receivedTaskId == event.Id {
-				go func(robot warehouse.RobotInterface) {
-					_ = robot.CancelTask(taskMapping.robotTaskId)
-				}(robot)
+	return nil
+}
 
-				break
-			}
-		}
+func (s *taskProcessor) handleTaskBatchCancelledEventRasied(msg *robotbroker.Message) error {
+	s.logEnter(msg)
+
+	event := eventpublisher.TaskEvent{}
+	if _, err := s.eventDecoderService.Decode(
+		msg.Header.Get(eventcodec.HEADER_CONTENT_TYPE),
+		msg.Data,
+		&event); err != nil {
+		s.logger.Errorf(
+			"Failed to de-serialize TaskEvent message. Error: %v",
+			err)
+
+		return robotbroker.Permanent(err)
+	}
+
+	if event.EventType != eventpublisher.TaskBatchCancelled {
+		return nil
 	}
 
+	s.taskIdMappingsMutex.Lock()
+	defer s.taskIdMappingsMutex.Unlock()
+
+	for _, task := range event.Data.Tasks {
+		s.cancel(task.Id)
+	}
+
+	return nil
 }
 
-func (s *taskProcessor) logEnter(msg *nats.Msg) {
-	metadata, err := msg.Metadata()
-	if err != nil {
-		s.logger.Infof("Received message from subject: %s", msg.Subject)
+// cancel cancels a task on the robot executing it, the caller holds taskIdMappingsMutex
+func (s *taskProcessor) cancel(receivedTaskId int64) {
+	for robotId, robot := range s.robots {
+		for _, taskMapping := range s.taskIdMappings[robotId] {
+			if taskMapping.receivedTaskId == receivedTaskId {
+				go func(robot warehouse.RobotInterface, robotTaskId int64) {
+					_ = robot.CancelTask(robotTaskId)
+				}(robot, taskMapping.robotTaskId)
 
-		return
+				return
+			}
+		}
 	}
+}
 
+func (s *taskProcessor) logEnter(msg *robotbroker.Message) {
 	s.logger.Infof(
-		"Stream: %v, Sequence: %v. Received message from subject: %s",
-		metadata.Sequence.Stream,
-		metadata.Sequence.Consumer,
+		"Sequence: %v, Delivery: %v. Received message from subject: %s",
+		msg.Sequence,
+		msg.Deliveries,
 		msg.Subject)
 }
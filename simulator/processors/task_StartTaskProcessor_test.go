@@ -0,0 +1,268 @@
+package processors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	. "github.com/sepisoad/robot-challange/shared/services/eventpublisher/mock"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/simulator/processors"
+	"github.com/sepisoad/robot-challange/simulator/warehouse"
+	. "github.com/sepisoad/robot-challange/simulator/warehouse/mock"
+	"go.uber.org/zap"
+)
+
+func Test_StartTaskProcessor_Should_Enqueue_An_Accepted_Task_Once(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	api := newApi(t, g)
+	robot := NewMockRobotInterface(ctrl)
+	enqueued := expectEnqueueTask(robot, " E", 1)
+
+	startTaskProcessor(ctrl, g, api.broker, robot)
+
+	acceptance, err := api.requester.RequestTask(newTaskCreated(1, 0), time.Second)
+	g.Expect(err).Should(BeNil())
+	g.Expect(acceptance.Accepted).Should(BeTrue())
+	g.Eventually(enqueued).Should(Receive())
+
+	// The created event of the accepted task is delivered twice, the task which follows tells
+	// once both were handled
+	accepted := newTaskCreated(1, 0)
+	accepted.Data.Accepted = true
+
+	g.Expect(api.publisher.PublishTaskEvent(accepted)).Should(Succeed())
+	g.Expect(api.publisher.PublishTaskEvent(accepted)).Should(Succeed())
+
+	enqueued = expectEnqueueTask(robot, " E", 2)
+	g.Expect(api.publisher.PublishTaskEvent(newTaskCreated(2, 0))).Should(Succeed())
+	g.Eventually(enqueued).Should(Receive())
+}
+
+func Test_StartTaskProcessor_Should_Reject_A_Task_It_Can_Not_Execute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	api := newApi(t, g)
+
+	// Nothing is enqueued on the robot
+	startTaskProcessor(ctrl, g, api.broker, NewMockRobotInterface(ctrl))
+
+	acceptance, err := api.requester.RequestTask(newTaskCreated(1, 404), time.Second)
+	g.Expect(err).Should(BeNil())
+	g.Expect(acceptance.Accepted).Should(BeFalse())
+	g.Expect(acceptance.Reason).Should(ContainSubstring("robot with Id 404 not found"))
+
+	withoutMoves := newTaskCreated(2, 0)
+	withoutMoves.Data.MoveSequeneces = nil
+
+	acceptance, err = api.requester.RequestTask(withoutMoves, time.Second)
+	g.Expect(err).Should(BeNil())
+	g.Expect(acceptance.Accepted).Should(BeFalse())
+	g.Expect(acceptance.Reason).Should(Equal("task has no move sequence"))
+}
+
+func Test_StartTaskProcessor_Should_Skip_The_Created_Event_Of_A_Task_Accepted_Before_A_Restart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	api := newApi(t, g)
+	robot := NewMockRobotInterface(ctrl)
+	enqueued := expectEnqueueTask(robot, " E", 1)
+
+	sut := startTaskProcessor(ctrl, g, api.broker, robot)
+
+	acceptance, err := api.requester.RequestTask(newTaskCreated(1, 0), time.Second)
+	g.Expect(err).Should(BeNil())
+	g.Expect(acceptance.Accepted).Should(BeTrue())
+	g.Eventually(enqueued).Should(Receive())
+
+	// The simulator which starts next, or any other replica, knows nothing of the accepted task
+	sut.Stop()
+
+	restartedRobot := NewMockRobotInterface(ctrl)
+	startTaskProcessor(ctrl, g, api.broker, restartedRobot)
+
+	accepted := newTaskCreated(1, 0)
+	accepted.Data.Accepted = true
+
+	g.Expect(api.publisher.PublishTaskEvent(accepted)).Should(Succeed())
+
+	enqueued = expectEnqueueTask(restartedRobot, " E", 2)
+	g.Expect(api.publisher.PublishTaskEvent(newTaskCreated(2, 0))).Should(Succeed())
+	g.Eventually(enqueued).Should(Receive())
+}
+
+func Test_StartTaskProcessor_Should_Cancel_An_Accepted_Task_Which_Is_Withdrawn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	api := newApi(t, g)
+	robot := NewMockRobotInterface(ctrl)
+	enqueued := expectEnqueueTask(robot, " E", 7)
+
+	startTaskProcessor(ctrl, g, api.broker, robot)
+
+	acceptance, err := api.requester.RequestTask(newTaskCreated(1, 0), time.Second)
+	g.Expect(err).Should(BeNil())
+	g.Expect(acceptance.Accepted).Should(BeTrue())
+	g.Eventually(enqueued).Should(Receive())
+
+	cancelled := make(chan struct{})
+
+	robot.
+		EXPECT().
+		CancelTask(int64(7)).
+		DoAndReturn(func(taskId int64) error {
+			close(cancelled)
+
+			return nil
+		})
+
+	// The api could not store the task, its created event is never published
+	g.Expect(api.publisher.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCancelled,
+		Id:        1,
+	})).Should(Succeed())
+	g.Eventually(cancelled).Should(BeClosed())
+}
+
+func Test_StartTaskProcessor_Should_Cancel_The_Tasks_Of_A_Cancelled_Batch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	g := NewGomegaWithT(t)
+
+	api := newApi(t, g)
+	robot := NewMockRobotInterface(ctrl)
+	enqueued := expectEnqueueTask(robot, " E", 7)
+
+	startTaskProcessor(ctrl, g, api.broker, robot)
+
+	g.Expect(api.publisher.PublishTaskEvent(newTaskCreated(1, 0))).Should(Succeed())
+	g.Eventually(enqueued).Should(Receive())
+
+	cancelled := make(chan struct{})
+
+	robot.
+		EXPECT().
+		CancelTask(int64(7)).
+		DoAndReturn(func(taskId int64) error {
+			close(cancelled)
+
+			return nil
+		})
+
+	// The task of robot 0 is cancelled with the other tasks of its batch, in a single event
+	g.Expect(api.publisher.PublishTaskEvent(eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskBatchCancelled,
+		Id:        10,
+		Data: eventpublisher.TaskData{
+			BatchId: "10",
+			Tasks: []eventpublisher.BatchTask{
+				{Id: 1, RobotId: 0},
+				{Id: 2, RobotId: 404},
+			},
+		},
+	})).Should(Succeed())
+	g.Eventually(cancelled).Should(BeClosed())
+}
+
+// api publishes and requests the tasks the way the api does
+type api struct {
+	broker    robotbroker.RobotBrokerInterface
+	publisher eventpublisher.EventPublisherInterface
+	requester eventpublisher.TaskRequesterInterface
+}
+
+func newApi(t *testing.T, g *WithT) api {
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	broker, err := robotbroker.NewInMemoryRobotBrokerService(sugarLogger)
+	g.Expect(err).Should(BeNil())
+	t.Cleanup(broker.Close)
+
+	codec, err := eventcodec.NewCodec(eventcodec.JSON)
+	g.Expect(err).Should(BeNil())
+
+	publisher, err := eventpublisher.NewEventPublisherService(sugarLogger, "api", codec, broker)
+	g.Expect(err).Should(BeNil())
+
+	requester, err := eventpublisher.NewTaskRequesterService(sugarLogger, "api", codec, broker)
+	g.Expect(err).Should(BeNil())
+
+	return api{
+		broker:    broker,
+		publisher: publisher,
+		requester: requester,
+	}
+}
+
+// startTaskProcessor starts processing the tasks of robot 0, the events of the tasks are not
+// expected to be published since the robot never moves
+func startTaskProcessor(
+	ctrl *gomock.Controller,
+	g *WithT,
+	broker robotbroker.RobotBrokerInterface,
+	robot warehouse.RobotInterface) interface{ Stop() } {
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := processors.StartTaskProcessor(
+		sugarLogger,
+		"test",
+		broker,
+		eventDecoderService,
+		map[int64]warehouse.RobotInterface{0: robot},
+		NewMockEventPublisherInterface(ctrl))
+	g.Expect(err).Should(BeNil())
+
+	return sut
+}
+
+// expectEnqueueTask expects commands to be enqueued once on robot, as robotTaskId, and returns
+// a channel which receives once they are
+func expectEnqueueTask(robot *MockRobotInterface, commands string, robotTaskId int64) chan struct{} {
+	enqueued := make(chan struct{}, 1)
+
+	robot.
+		EXPECT().
+		EnqueueTask(commands).
+		DoAndReturn(func(commands string) (int64, chan warehouse.RobotState, chan error) {
+			enqueued <- struct{}{}
+
+			return robotTaskId, make(chan warehouse.RobotState), make(chan error)
+		})
+
+	return enqueued
+}
+
+func newTaskCreated(taskId int64, robotId int64) eventpublisher.TaskEvent {
+	return eventpublisher.TaskEvent{
+		EventType: eventpublisher.TaskCreated,
+		Id:        taskId,
+		Data: eventpublisher.TaskData{
+			RobotId:        robotId,
+			MoveSequeneces: []eventpublisher.MoveRobotRequestMoveSequence{eventpublisher.EAST},
+		},
+	}
+}
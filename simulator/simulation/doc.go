@@ -0,0 +1,7 @@
+/*
+this package places the robots in the warehouse and starts processing the tasks
+so that the simulation can be started by the `start` command as well as by the
+all-in-one binary
+*/
+
+package simulation
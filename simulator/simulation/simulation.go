@@ -0,0 +1,268 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/snowflake"
+	"github.com/sepisoad/robot-challange/shared/services/eventcodec"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/idgenerator"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/simulator/processors"
+	"github.com/sepisoad/robot-challange/simulator/warehouse"
+	"go.uber.org/zap"
+)
+
+// Options configures the simulation
+type Options struct {
+	// Warehouse is the warehouse the simulated robots belong to
+	Warehouse        string
+	TotalRobotNumber int
+	BoardHeight      int
+	BoardWidth       int
+	// Obstacles are the cells robots can not move onto
+	Obstacles []eventpublisher.Cell
+	// SpecialCells are the cells of the board with a purpose
+	SpecialCells []eventpublisher.SpecialCell
+	// EventCodec is the name of the codec events are published with
+	EventCodec string
+	// AsyncPublish publishes the events without waiting for the broker to persist each of
+	// them, the pending events are flushed when the simulation stops
+	AsyncPublish bool
+	// MaxPendingPublishes bounds the number of events waiting to be persisted when
+	// publishing asynchronously
+	MaxPendingPublishes int
+	// CoalesceRobotMoves only publishes the latest position of a robot when the broker falls
+	// behind, it requires AsyncPublish
+	CoalesceRobotMoves bool
+}
+
+// FlushTimeout is how long a stopping simulation waits for its pending events to be persisted
+const FlushTimeout = 10 * time.Second
+
+type stopper interface {
+	Stop()
+}
+
+// Simulation is a running simulation
+type Simulation struct {
+	logger                *zap.SugaredLogger
+	warehouse             string
+	board                 warehouse.BoardInterface
+	robots                map[int64]warehouse.RobotInterface
+	eventpublisherService eventpublisher.EventPublisherInterface
+	taskProcessor         stopper
+	asyncPublisher        eventpublisher.AsyncEventPublisherInterface
+}
+
+// Start places the robots in the warehouse and starts processing the tasks published on
+// the broker
+func Start(
+	logger *zap.SugaredLogger,
+	options Options,
+	robotBrokerService robotbroker.RobotBrokerInterface) (*Simulation, error) {
+	eventCodec, err := eventcodec.NewCodec(options.EventCodec)
+	if err != nil {
+		return nil, err
+	}
+
+	eventDecoderService, err := eventpublisher.NewEventDecoderService()
+	if err != nil {
+		return nil, err
+	}
+
+	snowflakeNode, err := snowflake.NewNode(1)
+	if err != nil {
+		return nil, err
+	}
+
+	idGeneratorService, err := idgenerator.NewIdGeneratorService(snowflakeNode)
+	if err != nil {
+		return nil, err
+	}
+
+	board, err := warehouse.NewBoard(warehouse.Layout{
+		Width:        options.BoardWidth,
+		Height:       options.BoardHeight,
+		Obstacles:    options.Obstacles,
+		SpecialCells: options.SpecialCells,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	simulation := &Simulation{
+		logger:    logger,
+		warehouse: options.Warehouse,
+		board:     board,
+	}
+
+	var eventpublisherService eventpublisher.EventPublisherInterface
+
+	if options.AsyncPublish {
+		simulation.asyncPublisher, err = eventpublisher.NewAsyncEventPublisherService(
+			logger,
+			"simulator",
+			eventCodec,
+			robotBrokerService,
+			eventpublisher.AsyncPublishOptions{
+				MaxPending:         options.MaxPendingPublishes,
+				CoalesceRobotMoves: options.CoalesceRobotMoves,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		eventpublisherService = simulation.asyncPublisher
+	} else {
+		eventpublisherService, err = eventpublisher.NewEventPublisherService(
+			logger,
+			"simulator",
+			eventCodec,
+			robotBrokerService)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	simulation.eventpublisherService = eventpublisherService
+
+	coordinates := getRandomPositions(options.TotalRobotNumber)
+
+	for idx, coord := range coordinates {
+		if err := board.CheckCell(coord.x, coord.y); err != nil {
+			simulation.Stop()
+
+			return nil, fmt.Errorf("can not place robot %d at %d,%d: %w", idx, coord.x, coord.y, err)
+		}
+	}
+
+	// Consumers learn about the board before the robots show up on it
+	if err := simulation.publishLayout(); err != nil {
+		simulation.Stop()
+
+		return nil, err
+	}
+
+	robots := make(map[int64]warehouse.RobotInterface)
+	for idx, coord := range coordinates {
+		robot, err := warehouse.NewRobotOnBoard(
+			logger,
+			options.Warehouse,
+			int64(idx),
+			coord.x,
+			coord.y,
+			board,
+			eventpublisherService,
+			idGeneratorService)
+		if err != nil {
+			simulation.Stop()
+
+			return nil, err
+		}
+
+		robots[int64(idx)] = robot
+	}
+
+	simulation.robots = robots
+
+	taskProcessor, err := processors.StartTaskProcessor(
+		logger,
+		options.Warehouse,
+		robotBrokerService,
+		eventDecoderService,
+		robots,
+		eventpublisherService)
+	if err != nil {
+		simulation.Stop()
+
+		return nil, err
+	}
+
+	simulation.taskProcessor = taskProcessor
+
+	return simulation, nil
+}
+
+// Layout returns the layout of the warehouse
+func (s *Simulation) Layout() warehouse.Layout {
+	return s.board.Layout()
+}
+
+// Reconfigure changes the layout of the warehouse and publishes it, a layout which leaves a robot
+// off the board or on an obstacle is refused
+func (s *Simulation) Reconfigure(layout warehouse.Layout) error {
+	candidate, err := warehouse.NewBoard(layout)
+	if err != nil {
+		return err
+	}
+
+	for id, robot := range s.robots {
+		state := robot.CurrentState()
+		if err := candidate.CheckCell(state.X, state.Y); err != nil {
+			return fmt.Errorf("robot %d at %d,%d would be stranded: %w", id, state.X, state.Y, err)
+		}
+	}
+
+	if err := s.board.Configure(layout); err != nil {
+		return err
+	}
+
+	return s.publishLayout()
+}
+
+// Stop stops processing the tasks and flushes the events which have not been persisted yet
+func (s *Simulation) Stop() {
+	if s.taskProcessor != nil {
+		s.taskProcessor.Stop()
+		s.taskProcessor = nil
+	}
+
+	if s.asyncPublisher != nil {
+		if err := s.asyncPublisher.Close(FlushTimeout); err != nil {
+			s.logger.Error(err)
+		}
+
+		stats := s.asyncPublisher.Stats()
+		s.logger.Infof(
+			"Published %d events, %d persisted, %d failed, %d moves coalesced",
+			stats.Published,
+			stats.Acknowledged,
+			stats.Failed,
+			stats.Coalesced)
+
+		s.asyncPublisher = nil
+	}
+}
+
+func (s *Simulation) publishLayout() error {
+	layout := s.board.Layout()
+
+	return s.eventpublisherService.PublishWarehouseEvent(eventpublisher.WarehouseEvent{
+		EventType: eventpublisher.WarehouseConfigured,
+		Warehouse: s.warehouse,
+		Data: eventpublisher.WarehouseData{
+			Width:        layout.Width,
+			Height:       layout.Height,
+			Obstacles:    layout.Obstacles,
+			SpecialCells: layout.SpecialCells,
+		},
+	})
+}
+
+type coordinate struct {
+	x int
+	y int
+}
+
+func getRandomPositions(max int) []coordinate {
+	list := make([]coordinate, max)
+
+	for i := 0; i < max; i++ {
+		list[i].x = i
+		list[i].y = 0
+	}
+
+	return list
+}
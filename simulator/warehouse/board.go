@@ -0,0 +1,127 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+)
+
+type cell struct {
+	x int
+	y int
+}
+
+type board struct {
+	mutex     *sync.RWMutex
+	layout    Layout
+	obstacles map[cell]bool
+}
+
+// NewBoard creates a concrete instance of BoardInterface with the given layout
+func NewBoard(layout Layout) (BoardInterface, error) {
+	board := &board{
+		mutex: &sync.RWMutex{},
+	}
+
+	if err := board.Configure(layout); err != nil {
+		return nil, err
+	}
+
+	return board, nil
+}
+
+// LoadLayout reads a layout from a JSON file
+func LoadLayout(path string) (Layout, error) {
+	layout := Layout{}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return layout, err
+	}
+
+	if err := json.Unmarshal(buf, &layout); err != nil {
+		return layout, fmt.Errorf("invalid layout %s: %w", path, err)
+	}
+
+	return layout, layout.Validate()
+}
+
+// Validate checks that the board has at least one cell and that every obstacle and special cell
+// is on the board
+func (l Layout) Validate() error {
+	if l.Width < 1 || l.Height < 1 {
+		return fmt.Errorf("invalid board size %dx%d", l.Width, l.Height)
+	}
+
+	for _, obstacle := range l.Obstacles {
+		if !l.Contains(obstacle.X, obstacle.Y) {
+			return fmt.Errorf("obstacle %d,%d is off the board", obstacle.X, obstacle.Y)
+		}
+	}
+
+	for _, specialCell := range l.SpecialCells {
+		if !l.Contains(specialCell.X, specialCell.Y) {
+			return fmt.Errorf("%s cell %d,%d is off the board", specialCell.Kind, specialCell.X, specialCell.Y)
+		}
+
+		switch specialCell.Kind {
+		case eventpublisher.PickupCell, eventpublisher.DropoffCell, eventpublisher.ChargerCell:
+		default:
+			return fmt.Errorf("invalid kind %q of cell %d,%d", specialCell.Kind, specialCell.X, specialCell.Y)
+		}
+	}
+
+	return nil
+}
+
+// Contains reports whether a cell is on the board
+func (l Layout) Contains(x int, y int) bool {
+	return x >= 0 && x < l.Width && y >= 0 && y < l.Height
+}
+
+// Layout returns the layout of the board
+func (s *board) Layout() Layout {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.layout
+}
+
+// Configure replaces the layout of the board
+func (s *board) Configure(layout Layout) error {
+	if err := layout.Validate(); err != nil {
+		return err
+	}
+
+	obstacles := make(map[cell]bool)
+	for _, obstacle := range layout.Obstacles {
+		obstacles[cell{x: obstacle.X, y: obstacle.Y}] = true
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.layout = layout
+	s.obstacles = obstacles
+
+	return nil
+}
+
+// CheckCell returns an error when a robot can not stand on a cell
+func (s *board) CheckCell(x int, y int) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if !s.layout.Contains(x, y) {
+		return ErrHitTheWall
+	}
+
+	if s.obstacles[cell{x: x, y: y}] {
+		return ErrHitAnObstacle
+	}
+
+	return nil
+}
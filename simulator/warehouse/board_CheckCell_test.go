@@ -0,0 +1,43 @@
+package warehouse_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/simulator/warehouse"
+)
+
+func Test_CheckCell_Should_Report_Walls_And_Obstacles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := warehouse.NewBoard(warehouse.Layout{
+		Width:     3,
+		Height:    2,
+		Obstacles: []eventpublisher.Cell{{X: 1, Y: 1}},
+	})
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.CheckCell(0, 0)).Should(Succeed())
+	g.Expect(sut.CheckCell(2, 1)).Should(Succeed())
+	g.Expect(sut.CheckCell(3, 0)).Should(MatchError(warehouse.ErrHitTheWall))
+	g.Expect(sut.CheckCell(0, -1)).Should(MatchError(warehouse.ErrHitTheWall))
+	g.Expect(sut.CheckCell(1, 1)).Should(MatchError(warehouse.ErrHitAnObstacle))
+}
+
+func Test_CheckCell_Should_Follow_The_Layout_Changes(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	sut, err := warehouse.NewBoard(warehouse.Layout{Width: 3, Height: 3})
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.Configure(warehouse.Layout{
+		Width:     5,
+		Height:    3,
+		Obstacles: []eventpublisher.Cell{{X: 0, Y: 0}},
+	})).Should(Succeed())
+
+	g.Expect(sut.CheckCell(4, 2)).Should(Succeed())
+	g.Expect(sut.CheckCell(0, 0)).Should(MatchError(warehouse.ErrHitAnObstacle))
+	g.Expect(sut.Layout().Width).Should(Equal(5))
+}
@@ -0,0 +1,33 @@
+package warehouse_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/simulator/warehouse"
+)
+
+func Test_Configure_Should_Refuse_Invalid_Layouts(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	layout := warehouse.Layout{Width: 4, Height: 4}
+
+	sut, err := warehouse.NewBoard(layout)
+	g.Expect(err).Should(BeNil())
+
+	g.Expect(sut.Configure(warehouse.Layout{Width: 0, Height: 4})).ShouldNot(Succeed())
+	g.Expect(sut.Configure(warehouse.Layout{
+		Width:     4,
+		Height:    4,
+		Obstacles: []eventpublisher.Cell{{X: 4, Y: 0}},
+	})).ShouldNot(Succeed())
+	g.Expect(sut.Configure(warehouse.Layout{
+		Width:        4,
+		Height:       4,
+		SpecialCells: []eventpublisher.SpecialCell{{X: 1, Y: 1, Kind: "Lift"}},
+	})).ShouldNot(Succeed())
+
+	// The board keeps its layout
+	g.Expect(sut.Layout()).Should(Equal(layout))
+}
@@ -1,5 +1,19 @@
 package warehouse
 
+import (
+	"errors"
+	"sync"
+
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+)
+
+var (
+	// ErrHitTheWall is reported when a robot is asked to move off the board
+	ErrHitTheWall = errors.New("robot hit the wall")
+	// ErrHitAnObstacle is reported when a robot is asked to move onto an obstacle
+	ErrHitAnObstacle = errors.New("robot hit an obstacle")
+)
+
 type RobotState struct {
 	X        int
 	Y        int
@@ -11,6 +25,31 @@ type RobotInterface interface {
 		taskId int64,
 		positionChannel chan RobotState,
 		errorChannel chan error)
+	// EnqueueBatchTask enqueues a task of a batch, the robot calls Done on the batch once the
+	// tasks enqueued before are done and starts the task when every robot of the batch did so
+	EnqueueBatchTask(commands string, batch *sync.WaitGroup) (
+		taskId int64,
+		positionChannel chan RobotState,
+		errorChannel chan error)
 	CancelTask(taskId int64) error
 	CurrentState() RobotState
 }
+
+// Layout describes the grid of a warehouse, cells are addressed from 0 to Width - 1 and
+// Height - 1
+type Layout struct {
+	Width        int                          `json:"width"`
+	Height       int                          `json:"height"`
+	Obstacles    []eventpublisher.Cell        `json:"obstacles,omitempty"`
+	SpecialCells []eventpublisher.SpecialCell `json:"specialCells,omitempty"`
+}
+
+// BoardInterface defines contract for the grid robots move on, its layout can change while
+// the robots are moving
+type BoardInterface interface {
+	Layout() Layout
+	// Configure replaces the layout of the board
+	Configure(layout Layout) error
+	// CheckCell returns ErrHitTheWall or ErrHitAnObstacle when a robot can not stand on a cell
+	CheckCell(x int, y int) error
+}
@@ -6,6 +6,7 @@ package mock_warehouse
 
 import (
 	reflect "reflect"
+	sync "sync"
 
 	gomock "github.com/golang/mock/gomock"
 	warehouse "github.com/sepisoad/robot-challange/simulator/warehouse"
@@ -62,6 +63,22 @@ func (mr *MockRobotInterfaceMockRecorder) CurrentState() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentState", reflect.TypeOf((*MockRobotInterface)(nil).CurrentState))
 }
 
+// EnqueueBatchTask mocks base method.
+func (m *MockRobotInterface) EnqueueBatchTask(commands string, batch *sync.WaitGroup) (int64, chan warehouse.RobotState, chan error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueBatchTask", commands, batch)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(chan warehouse.RobotState)
+	ret2, _ := ret[2].(chan error)
+	return ret0, ret1, ret2
+}
+
+// EnqueueBatchTask indicates an expected call of EnqueueBatchTask.
+func (mr *MockRobotInterfaceMockRecorder) EnqueueBatchTask(commands, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueBatchTask", reflect.TypeOf((*MockRobotInterface)(nil).EnqueueBatchTask), commands, batch)
+}
+
 // EnqueueTask mocks base method.
 func (m *MockRobotInterface) EnqueueTask(commands string) (int64, chan warehouse.RobotState, chan error) {
 	m.ctrl.T.Helper()
@@ -77,3 +94,68 @@ func (mr *MockRobotInterfaceMockRecorder) EnqueueTask(commands interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueTask", reflect.TypeOf((*MockRobotInterface)(nil).EnqueueTask), commands)
 }
+
+// MockBoardInterface is a mock of BoardInterface interface.
+type MockBoardInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockBoardInterfaceMockRecorder
+}
+
+// MockBoardInterfaceMockRecorder is the mock recorder for MockBoardInterface.
+type MockBoardInterfaceMockRecorder struct {
+	mock *MockBoardInterface
+}
+
+// NewMockBoardInterface creates a new mock instance.
+func NewMockBoardInterface(ctrl *gomock.Controller) *MockBoardInterface {
+	mock := &MockBoardInterface{ctrl: ctrl}
+	mock.recorder = &MockBoardInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBoardInterface) EXPECT() *MockBoardInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CheckCell mocks base method.
+func (m *MockBoardInterface) CheckCell(x, y int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckCell", x, y)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckCell indicates an expected call of CheckCell.
+func (mr *MockBoardInterfaceMockRecorder) CheckCell(x, y interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckCell", reflect.TypeOf((*MockBoardInterface)(nil).CheckCell), x, y)
+}
+
+// Configure mocks base method.
+func (m *MockBoardInterface) Configure(layout warehouse.Layout) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Configure", layout)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Configure indicates an expected call of Configure.
+func (mr *MockBoardInterfaceMockRecorder) Configure(layout interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Configure", reflect.TypeOf((*MockBoardInterface)(nil).Configure), layout)
+}
+
+// Layout mocks base method.
+func (m *MockBoardInterface) Layout() warehouse.Layout {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Layout")
+	ret0, _ := ret[0].(warehouse.Layout)
+	return ret0
+}
+
+// Layout indicates an expected call of Layout.
+func (mr *MockBoardInterfaceMockRecorder) Layout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Layout", reflect.TypeOf((*MockBoardInterface)(nil).Layout))
+}
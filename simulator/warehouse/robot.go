@@ -1,7 +1,6 @@
 package warehouse
 
 import (
-	"errors"
 	"strings"
 	"sync"
 	"time"
@@ -12,13 +11,15 @@ import (
 )
 
 type robot struct {
-	logger             *zap.SugaredLogger
-	id                 int64
-	x                  int
-	y                  int
-	boardHeight        int
-	boardWidth         int
-	taskIds            map[int64]bool
+	logger  *zap.SugaredLogger
+	id      int64
+	x       int
+	y       int
+	board   BoardInterface
+	taskIds map[int64]bool
+	// lastTask is closed once the task enqueued last is done, tasks are executed in the order
+	// they are enqueued
+	lastTask           chan struct{}
 	moveMutex          *sync.Mutex
 	taskMutex          *sync.Mutex
 	idGeneratorService idgenerator.IdGeneratorInterface
@@ -26,6 +27,7 @@ type robot struct {
 
 func NewRobot(
 	logger *zap.SugaredLogger,
+	warehouse string,
 	id int64,
 	x int,
 	y int,
@@ -35,10 +37,42 @@ func NewRobot(
 	idGeneratorService idgenerator.IdGeneratorInterface) (
 	RobotInterface,
 	error) {
+	board, err := NewBoard(Layout{
+		Width:  boardWidth,
+		Height: boardHeight,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRobotOnBoard(
+		logger,
+		warehouse,
+		id,
+		x,
+		y,
+		board,
+		eventpublisherService,
+		idGeneratorService)
+}
+
+// NewRobotOnBoard creates a robot which moves on a board it may share with other robots
+func NewRobotOnBoard(
+	logger *zap.SugaredLogger,
+	warehouse string,
+	id int64,
+	x int,
+	y int,
+	board BoardInterface,
+	eventpublisherService eventpublisher.EventPublisherInterface,
+	idGeneratorService idgenerator.IdGeneratorInterface) (
+	RobotInterface,
+	error) {
 
 	if err := eventpublisherService.PublishRobotEvent(eventpublisher.RobotEvent{
 		EventType: eventpublisher.RobotMoved,
 		Id:        id,
+		Warehouse: warehouse,
 		Data: eventpublisher.RobotData{
 			X: x,
 			Y: y,
@@ -50,8 +84,7 @@ func NewRobot(
 	return &robot{
 		logger:             logger,
 		id:                 id,
-		boardHeight:        boardHeight,
-		boardWidth:         boardWidth,
+		board:              board,
 		x:                  x,
 		y:                  y,
 		taskIds:            make(map[int64]bool),
@@ -65,10 +98,29 @@ func (s *robot) EnqueueTask(commands string) (
 	int64,
 	chan RobotState,
 	chan error) {
+	return s.enqueue(commands, nil)
+}
+
+func (s *robot) EnqueueBatchTask(commands string, batch *sync.WaitGroup) (
+	int64,
+	chan RobotState,
+	chan error) {
+	return s.enqueue(commands, batch)
+}
+
+// enqueue queues a task behind the tasks enqueued before, a task of a batch waits for the
+// other robots of the batch once its turn comes
+func (s *robot) enqueue(commands string, batch *sync.WaitGroup) (
+	int64,
+	chan RobotState,
+	chan error) {
 
 	s.taskMutex.Lock()
 	taskId := s.idGeneratorService.Generate()
 	s.taskIds[taskId] = false
+	previousTask := s.lastTask
+	done := make(chan struct{})
+	s.lastTask = done
 	s.taskMutex.Unlock()
 
 	positionChannel := make(chan RobotState)
@@ -78,9 +130,20 @@ func (s *robot) EnqueueTask(commands string) (
 		positionChannel chan RobotState,
 		errorChannel chan error) {
 
+		// The next task is let go once the channels of this one are closed
+		defer close(done)
 		defer close(positionChannel)
 		defer close(errorChannel)
 
+		if previousTask != nil {
+			<-previousTask
+		}
+
+		if batch != nil {
+			batch.Done()
+			batch.Wait()
+		}
+
 		s.moveMutex.Lock()
 		defer s.moveMutex.Unlock()
 
@@ -93,41 +156,30 @@ func (s *robot) EnqueueTask(commands string) (
 				return
 			}
 
-			hitTheWall := false
+			x, y := s.x, s.y
 
 			switch moveSequenece {
 			case "S":
-				if s.y-1 == -1 {
-					hitTheWall = true
-				} else {
-					s.y = s.y - 1
-				}
-
+				y = y - 1
 			case "N":
-				if s.y+1 == s.boardHeight {
-					hitTheWall = true
-				} else {
-					s.y = s.y + 1
-				}
-
+				y = y + 1
 			case "W":
-				if s.x-1 == -1 {
-					hitTheWall = true
-				} else {
-					s.x = s.x - 1
-				}
-
+				x = x - 1
 			case "E":
-				if s.x+1 == s.boardWidth {
-					hitTheWall = true
-				} else {
-					s.x = s.x + 1
-				}
+				x = x + 1
+			}
+
+			var err error
+			if x != s.x || y != s.y {
+				err = s.board.CheckCell(x, y)
 			}
 
-			if hitTheWall {
-				errorChannel <- errors.New("robot hit the wall")
+			if err != nil {
+				errorChannel <- err
 			} else {
+				s.x = x
+				s.y = y
+
 				positionChannel <- RobotState{
 					X: s.x,
 					Y: s.y,
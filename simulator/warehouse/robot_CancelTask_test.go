@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	. "github.com/sepisoad/robot-challange/shared/services/eventpublisher/mock"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	. "github.com/sepisoad/robot-challange/shared/services/idgenerator/mock"
 	"github.com/sepisoad/robot-challange/simulator/warehouse"
 	"github.com/golang/mock/gomock"
@@ -39,6 +40,7 @@ func Test_CancelTask_Should_Cancel_Given_Task(t *testing.T) {
 
 	sut, err := warehouse.NewRobot(
 		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
 		0,
 		0,
 		0,
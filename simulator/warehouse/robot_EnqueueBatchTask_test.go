@@ -0,0 +1,88 @@
+package warehouse_test
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/gomega"
+	. "github.com/sepisoad/robot-challange/shared/services/eventpublisher/mock"
+	. "github.com/sepisoad/robot-challange/shared/services/idgenerator/mock"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
+	"github.com/sepisoad/robot-challange/simulator/warehouse"
+	"go.uber.org/zap"
+)
+
+func Test_EnqueueBatchTask_Should_Start_When_Every_Robot_Of_The_Batch_Is_Ready(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEventpublisherService := NewMockEventPublisherInterface(ctrl)
+	mockIdGeneratorService := NewMockIdGeneratorInterface(ctrl)
+
+	mockEventpublisherService.
+		EXPECT().
+		PublishRobotEvent(gomock.Any()).
+		Return(nil).
+		Times(2)
+
+	mockIdGeneratorService.
+		EXPECT().
+		Generate().
+		DoAndReturn(func() int64 {
+			return int64(rand.Intn(10000))
+		}).
+		AnyTimes()
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	busyRobot, err := warehouse.NewRobot(
+		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
+		0,
+		0,
+		0,
+		10,
+		10,
+		mockEventpublisherService,
+		mockIdGeneratorService)
+	g.Expect(err).Should(BeNil())
+
+	idleRobot, err := warehouse.NewRobot(
+		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
+		1,
+		5,
+		5,
+		10,
+		10,
+		mockEventpublisherService,
+		mockIdGeneratorService)
+	g.Expect(err).Should(BeNil())
+
+	_, busyPositionChannel, _ := busyRobot.EnqueueTask("E E E")
+
+	var busyMoves int32
+	go func() {
+		for range busyPositionChannel {
+			atomic.AddInt32(&busyMoves, 1)
+		}
+	}()
+
+	batch := &sync.WaitGroup{}
+	batch.Add(2)
+
+	_, busyBatchPositionChannel, _ := busyRobot.EnqueueBatchTask("N", batch)
+	_, idleBatchPositionChannel, _ := idleRobot.EnqueueBatchTask("N", batch)
+
+	// The idle robot waits for the busy one to finish the task queued before the batch
+	g.Expect(<-idleBatchPositionChannel).Should(Equal(warehouse.RobotState{X: 5, Y: 6}))
+	g.Expect(atomic.LoadInt32(&busyMoves)).Should(Equal(int32(3)))
+	g.Expect(<-busyBatchPositionChannel).Should(Equal(warehouse.RobotState{X: 3, Y: 1}))
+}
@@ -4,7 +4,9 @@ import (
 	"math/rand"
 	"testing"
 
+	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
 	. "github.com/sepisoad/robot-challange/shared/services/eventpublisher/mock"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	. "github.com/sepisoad/robot-challange/shared/services/idgenerator/mock"
 	"github.com/sepisoad/robot-challange/simulator/warehouse"
 	"github.com/golang/mock/gomock"
@@ -39,6 +41,7 @@ func Test_EnqueueTask_Should_Return_Valid_Task_Id(t *testing.T) {
 
 	sut, err := warehouse.NewRobot(
 		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
 		0,
 		0,
 		0,
@@ -79,6 +82,7 @@ func Test_EnqueueTask_Should_Send_Updated_Position_Through_Returned_Poisition_Ch
 
 	sut, err := warehouse.NewRobot(
 		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
 		0,
 		0,
 		0,
@@ -127,6 +131,7 @@ func Test_EnqueueTask_Should_Send_Error_Through_Returned_Error_Channel(t *testin
 
 	sut, err := warehouse.NewRobot(
 		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
 		0,
 		0,
 		0,
@@ -182,6 +187,7 @@ func Test_EnqueueTask_Should_Carry_On_Moving_After_Hit_The_Wall(t *testing.T) {
 
 	sut, err := warehouse.NewRobot(
 		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
 		0,
 		0,
 		0,
@@ -213,3 +219,103 @@ func Test_EnqueueTask_Should_Carry_On_Moving_After_Hit_The_Wall(t *testing.T) {
 	g.Expect(robotState.X).Should(Equal(1))
 	g.Expect(robotState.Y).Should(Equal(1))
 }
+
+func Test_EnqueueTask_Should_Not_Move_Onto_An_Obstacle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEventpublisherService := NewMockEventPublisherInterface(ctrl)
+	mockIdGeneratorService := NewMockIdGeneratorInterface(ctrl)
+
+	mockEventpublisherService.
+		EXPECT().
+		PublishRobotEvent(gomock.Any()).
+		Return(nil)
+
+	mockIdGeneratorService.
+		EXPECT().
+		Generate().
+		Return(int64(rand.Intn(10000)))
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	board, err := warehouse.NewBoard(warehouse.Layout{
+		Width:     10,
+		Height:    10,
+		Obstacles: []eventpublisher.Cell{{X: 1, Y: 0}},
+	})
+	g.Expect(err).Should(BeNil())
+
+	sut, err := warehouse.NewRobotOnBoard(
+		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
+		0,
+		0,
+		0,
+		board,
+		mockEventpublisherService,
+		mockIdGeneratorService)
+	g.Expect(err).Should(BeNil())
+
+	_, positionChannel, errorChannel := sut.EnqueueTask("E N")
+
+	err = <-errorChannel
+	g.Expect(err).Should(MatchError(warehouse.ErrHitAnObstacle))
+
+	robotState := <-positionChannel
+	g.Expect(robotState.X).Should(Equal(0))
+	g.Expect(robotState.Y).Should(Equal(1))
+}
+
+func Test_EnqueueTask_Should_Execute_Tasks_In_The_Order_They_Are_Enqueued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEventpublisherService := NewMockEventPublisherInterface(ctrl)
+	mockIdGeneratorService := NewMockIdGeneratorInterface(ctrl)
+
+	mockEventpublisherService.
+		EXPECT().
+		PublishRobotEvent(gomock.Any()).
+		Return(nil)
+
+	mockIdGeneratorService.
+		EXPECT().
+		Generate().
+		DoAndReturn(func() int64 {
+			return int64(rand.Intn(10000))
+		}).
+		AnyTimes()
+
+	g := NewGomegaWithT(t)
+
+	logger, err := zap.NewDevelopment()
+	sugarLogger := logger.Sugar()
+	g.Expect(err).Should(BeNil())
+
+	sut, err := warehouse.NewRobot(
+		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
+		0,
+		0,
+		0,
+		10,
+		10,
+		mockEventpublisherService,
+		mockIdGeneratorService)
+	g.Expect(err).Should(BeNil())
+
+	positionChannels := make([]chan warehouse.RobotState, 0)
+	for i := 0; i < 5; i++ {
+		_, positionChannel, _ := sut.EnqueueTask("E")
+		positionChannels = append(positionChannels, positionChannel)
+	}
+
+	for i, positionChannel := range positionChannels {
+		g.Expect(<-positionChannel).Should(Equal(warehouse.RobotState{X: i + 1, Y: 0}))
+	}
+}
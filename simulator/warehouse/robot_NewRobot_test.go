@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/sepisoad/robot-challange/shared/services/eventpublisher"
+	"github.com/sepisoad/robot-challange/shared/services/robotbroker"
 	. "github.com/sepisoad/robot-challange/shared/services/eventpublisher/mock"
 	. "github.com/sepisoad/robot-challange/shared/services/idgenerator/mock"
 	"github.com/sepisoad/robot-challange/simulator/warehouse"
@@ -34,6 +35,7 @@ func Test_NewRobot_Should_Publish_Initial_State_Of_Robot(t *testing.T) {
 
 				g.Expect(event.EventType).Should(Equal(eventpublisher.RobotMoved))
 				g.Expect(event.Id).Should(Equal(robotId))
+				g.Expect(event.Warehouse).Should(Equal(robotbroker.DEFAULT_WAREHOUSE))
 				g.Expect(event.ErrorMessage).Should(BeEmpty())
 				g.Expect(event.Data.X).Should(BeZero())
 				g.Expect(event.Data.Y).Should(BeZero())
@@ -47,6 +49,7 @@ func Test_NewRobot_Should_Publish_Initial_State_Of_Robot(t *testing.T) {
 
 	_, err = warehouse.NewRobot(
 		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
 		robotId,
 		0,
 		0,
@@ -79,6 +82,7 @@ func Test_NewRobot_Should_Return_Error_If_Initial_Publishing_Of_Robot_State_Fail
 
 	_, err = warehouse.NewRobot(
 		sugarLogger,
+		robotbroker.DEFAULT_WAREHOUSE,
 		0,
 		0,
 		0,
@@ -1,12 +1,41 @@
 import { Elm } from './Main.elm';
 
+// The dashboard authenticates with the api key or the JSON web token given in the access_token
+// query parameter of its page. The token is kept for the session and taken off the address bar
+const page = new URLSearchParams(window.location.search);
+const token = page.get('access_token') || sessionStorage.getItem('access_token') || '';
+
+if (page.has('access_token')) {
+  sessionStorage.setItem('access_token', token);
+  page.delete('access_token');
+
+  const query = page.toString();
+  window.history.replaceState(null, '', window.location.pathname + (query ? '?' + query : ''));
+}
+
+// JSON web tokens are made of three dot separated parts, anything else is an api key
+const isJwt = token.split('.').length === 3;
+
+// Elm sends its requests with XMLHttpRequest, each of them carries the token
+const open = XMLHttpRequest.prototype.open;
+XMLHttpRequest.prototype.open = function (...args) {
+  open.apply(this, args);
+
+  if (token !== '') {
+    this.setRequestHeader(isJwt ? 'Authorization' : 'X-API-Key', isJwt ? 'Bearer ' + token : token);
+  }
+}
+
 const app = Elm.Main.init({
   node: document.getElementById('ELM-MOUNT'),
   // flags: 'This message is rendered by Description.elm.'
 })
 
-let status = new WebSocket("ws://localhost:8080/ws/robots");
-let tasks = new WebSocket("ws://localhost:8080/ws/tasks");
+// Browsers open the websockets without headers, the token goes in the url
+const accessToken = token !== '' ? '?access_token=' + encodeURIComponent(token) : '';
+
+let status = new WebSocket("ws://localhost:8080/ws/robots" + accessToken);
+let tasks = new WebSocket("ws://localhost:8080/ws/tasks" + accessToken);
 
 status.onmessage = function (event) {
   const data = event.data;
@@ -18,4 +47,3 @@ tasks.onmessage = function (event) {
   console.log("TASKS :_", data);
   app.ports.tasksReceived.send(data);
 }
-